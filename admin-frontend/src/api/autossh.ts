@@ -10,6 +10,10 @@ export interface AutoSSH {
     target_host: string
     target_port: number
     enabled: boolean
+    socks_username?: string
+    bind_address?: string
+    allowed_sources?: string[]
+    public_exposure: boolean
     status: string
     last_error?: string
     last_start_at?: string
@@ -25,6 +29,10 @@ export interface AutoSSHForm {
     target_host: string
     target_port: number
     enabled: boolean
+    socks_username?: string
+    socks_password?: string
+    bind_address?: string
+    allowed_sources?: string[]
 }
 
 export async function createAutoSSH(data: AutoSSHForm) {
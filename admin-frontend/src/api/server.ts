@@ -1,5 +1,7 @@
 import {
     ModelBatchMoveServerForm,
+    ModelCustomMetric,
+    ModelCustomMetricSeries,
     ModelServer,
     ModelServerConfigForm,
     ModelServerForm,
@@ -37,3 +39,20 @@ export const setServerConfig = async (
 ): Promise<ModelServerTaskResponse> => {
     return fetcher<ModelServerTaskResponse>(FetcherMethod.POST, `/api/v1/server/config`, data)
 }
+
+export const getServerCustomMetrics = async (id: number): Promise<ModelCustomMetric[]> => {
+    return fetcher<ModelCustomMetric[]>(FetcherMethod.GET, `/api/v1/server/${id}/custom-metrics`)
+}
+
+export const getServerCustomMetricHistory = async (
+    id: number,
+    name: string,
+    start: number,
+    end: number,
+): Promise<ModelCustomMetricSeries> => {
+    return fetcher<ModelCustomMetricSeries>(
+        FetcherMethod.GET,
+        `/api/v1/server/${id}/custom-metrics/history`,
+        { name, start, end },
+    )
+}
@@ -1,4 +1,9 @@
-import { ModelCreateTerminalResponse } from "@/types"
+import {
+    ModelCreateTerminalBroadcastResponse,
+    ModelCreateTerminalResponse,
+    ModelTerminalBroadcastForm,
+} from "@/types"
+import { TerminalSession } from "@/types/terminal-audit"
 
 import { FetcherMethod, fetcher } from "./api"
 
@@ -7,3 +12,19 @@ export const createTerminal = async (id: number): Promise<ModelCreateTerminalRes
         server_id: id,
     })
 }
+
+// 浏览器断开后仍在等待重连的终端会话
+export const getDetachedTerminalSessions = async (): Promise<TerminalSession[]> => {
+    return fetcher<TerminalSession[]>(FetcherMethod.GET, "/api/v1/terminal/detached")
+}
+
+// 为每台服务器打开一个终端，通过同一个 websocket 广播输入
+export const createTerminalBroadcast = async (
+    data: ModelTerminalBroadcastForm,
+): Promise<ModelCreateTerminalBroadcastResponse> => {
+    return fetcher<ModelCreateTerminalBroadcastResponse>(
+        FetcherMethod.POST,
+        "/api/v1/terminal/broadcast",
+        data,
+    )
+}
@@ -0,0 +1,202 @@
+import { getServerCustomMetricHistory, getServerCustomMetrics } from "@/api/server"
+import { ButtonProps } from "@/components/ui/button"
+import {
+    Dialog,
+    DialogContent,
+    DialogDescription,
+    DialogHeader,
+    DialogTitle,
+    DialogTrigger,
+} from "@/components/ui/dialog"
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@/components/ui/select"
+import { IconButton } from "@/components/xui/icon-button"
+import { ModelCustomMetric, ModelCustomMetricPoint } from "@/types"
+import { ChartLine } from "lucide-react"
+import { useEffect, useState } from "react"
+import { useTranslation } from "react-i18next"
+import { toast } from "sonner"
+
+// 可选的查询范围（秒），由面板按范围选择聚合粒度
+const ranges: Record<string, number> = {
+    "1h": 3600,
+    "6h": 6 * 3600,
+    "24h": 24 * 3600,
+    "7d": 7 * 24 * 3600,
+    "30d": 30 * 24 * 3600,
+}
+
+const chartWidth = 640
+const chartHeight = 240
+const chartPadding = 8
+
+const formatValue = (v: number) =>
+    Math.abs(v) >= 1000 || (v !== 0 && Math.abs(v) < 0.01) ? v.toExponential(2) : v.toFixed(2)
+
+interface MetricChartProps {
+    points: ModelCustomMetricPoint[]
+    start: number
+    end: number
+}
+
+const MetricChart = ({ points, start, end }: MetricChartProps) => {
+    const { t } = useTranslation()
+    if (points.length === 0) {
+        return <p className="text-sm text-muted-foreground">{t("NoData")}</p>
+    }
+
+    let min = Math.min(...points.map((p) => p.value))
+    let max = Math.max(...points.map((p) => p.value_max))
+    if (min === max) {
+        min -= 1
+        max += 1
+    }
+    const x = (ts: number) =>
+        chartPadding + ((ts - start) / Math.max(end - start, 1)) * (chartWidth - chartPadding * 2)
+    const y = (v: number) =>
+        chartHeight - chartPadding - ((v - min) / (max - min)) * (chartHeight - chartPadding * 2)
+    const line = (value: (p: ModelCustomMetricPoint) => number) =>
+        points.map((p, i) => `${i ? "L" : "M"}${x(p.timestamp)},${y(value(p))}`).join(" ")
+
+    return (
+        <div className="grid gap-1">
+            <div className="flex justify-between text-xs text-muted-foreground">
+                <span>max {formatValue(max)}</span>
+                <span>min {formatValue(min)}</span>
+            </div>
+            <svg
+                viewBox={`0 0 ${chartWidth} ${chartHeight}`}
+                className="w-full h-60 rounded-md border"
+                preserveAspectRatio="none"
+            >
+                {/* 聚合数据的桶内最大值 */}
+                <path
+                    d={line((p) => p.value_max)}
+                    fill="none"
+                    className="stroke-muted-foreground"
+                    strokeOpacity={0.4}
+                    strokeWidth={1}
+                    vectorEffect="non-scaling-stroke"
+                />
+                <path
+                    d={line((p) => p.value)}
+                    fill="none"
+                    className="stroke-primary"
+                    strokeWidth={2}
+                    vectorEffect="non-scaling-stroke"
+                />
+            </svg>
+            <div className="flex justify-between text-xs text-muted-foreground">
+                <span>{new Date(start * 1000).toLocaleString()}</span>
+                <span>{new Date(end * 1000).toLocaleString()}</span>
+            </div>
+        </div>
+    )
+}
+
+interface CustomMetricsCardProps extends ButtonProps {
+    sid: number
+    menuItem?: boolean
+}
+
+export const CustomMetricsCard = ({ sid, menuItem = false, ...props }: CustomMetricsCardProps) => {
+    const { t } = useTranslation()
+    const [open, setOpen] = useState(false)
+    const [metrics, setMetrics] = useState<ModelCustomMetric[]>([])
+    const [name, setName] = useState("")
+    const [range, setRange] = useState("1h")
+    const [series, setSeries] = useState<MetricChartProps | null>(null)
+
+    useEffect(() => {
+        if (!open) return
+        getServerCustomMetrics(sid)
+            .then((result) => {
+                setMetrics(result)
+                if (result.length > 0 && !result.some((m) => m.name === name)) {
+                    setName(result[0].name)
+                }
+            })
+            .catch((error: Error) => {
+                console.error(error)
+                toast(t("Error"), { description: error.message })
+            })
+    }, [open, sid])
+
+    useEffect(() => {
+        if (!open || !name) return
+        const end = Math.floor(Date.now() / 1000)
+        getServerCustomMetricHistory(sid, name, end - ranges[range], end)
+            .then((result) =>
+                setSeries({
+                    points: result.points || [],
+                    start: result.start,
+                    end: result.end,
+                }),
+            )
+            .catch((error: Error) => {
+                console.error(error)
+                toast(t("Error"), { description: error.message })
+            })
+    }, [open, sid, name, range])
+
+    const current = metrics.find((m) => m.name === name)
+
+    return (
+        <Dialog open={open} onOpenChange={setOpen}>
+            <DialogTrigger asChild>
+                {menuItem ? (
+                    <button
+                        type="button"
+                        className="flex w-full items-center text-sm px-2 py-2 hover:bg-accent hover:text-accent-foreground"
+                        onClick={() => setOpen(true)}
+                    >
+                        <ChartLine className="h-4 w-4 mr-2" />
+                        <span>{t("CustomMetrics")}</span>
+                    </button>
+                ) : (
+                    <IconButton {...props} icon="chart" />
+                )}
+            </DialogTrigger>
+            <DialogContent className="sm:max-w-3xl">
+                <DialogHeader>
+                    <DialogTitle>{t("CustomMetrics")}</DialogTitle>
+                    <DialogDescription>
+                        {current && `${current.type}: ${formatValue(current.value)}`}
+                    </DialogDescription>
+                </DialogHeader>
+                {metrics.length === 0 ? (
+                    <p className="text-sm text-muted-foreground">{t("NoData")}</p>
+                ) : (
+                    <div className="grid gap-4">
+                        <div className="flex gap-2">
+                            <Select value={name} onValueChange={setName}>
+                                <SelectTrigger className="flex-1">
+                                    <SelectValue />
+                                </SelectTrigger>
+                                <SelectContent>
+                                    {metrics.map((m) => (
+                                        <SelectItem key={m.name} value={m.name}>
+                                            {m.name}
+                                        </SelectItem>
+                                    ))}
+                                </SelectContent>
+                            </Select>
+                            <Select value={range} onValueChange={setRange}>
+                                <SelectTrigger className="w-24">
+                                    <SelectValue />
+                                </SelectTrigger>
+                                <SelectContent>
+                                    {Object.keys(ranges).map((r) => (
+                                        <SelectItem key={r} value={r}>
+                                            {r}
+                                        </SelectItem>
+                                    ))}
+                                </SelectContent>
+                            </Select>
+                        </div>
+                        {series && <MetricChart {...series} />}
+                    </div>
+                )}
+            </DialogContent>
+        </Dialog>
+    )
+}
@@ -0,0 +1,210 @@
+import { createTerminalBroadcast } from "@/api/terminal"
+import { Label } from "@/components/ui/label"
+import { Switch } from "@/components/ui/switch"
+import { ModelCreateTerminalBroadcastResponse } from "@/types"
+import { FitAddon } from "@xterm/addon-fit"
+import { Terminal } from "@xterm/xterm"
+import "@xterm/xterm/css/xterm.css"
+import { useEffect, useRef, useState } from "react"
+import { useTranslation } from "react-i18next"
+import { useSearchParams } from "react-router-dom"
+import { toast } from "sonner"
+
+import { IconButton } from "./xui/icon-button"
+
+// 发往单台服务器的帧：0xfe + 8 字节大端服务器 ID + 原始终端帧，
+// 不带前缀的帧发往所有服务器。输出帧以 8 字节大端服务器 ID 开头
+const targetFrame = 0xfe
+
+const encodeFrame = (serverId: number | null, type: number, payload: Uint8Array) => {
+    const head = serverId === null ? 1 : 10
+    const msg = new Uint8Array(head + payload.length)
+    if (serverId !== null) {
+        msg[0] = targetFrame
+        new DataView(msg.buffer).setBigUint64(1, BigInt(serverId))
+    }
+    msg[head - 1] = type
+    msg.set(payload, head)
+    return msg
+}
+
+interface BroadcastTerminal {
+    terminal: Terminal
+    fitAddon: FitAddon
+}
+
+const BroadcastTerminals = ({
+    broadcast,
+}: {
+    broadcast: ModelCreateTerminalBroadcastResponse
+}) => {
+    const { t } = useTranslation()
+    const containersRef = useRef(new Map<number, HTMLDivElement>())
+    const [broadcastInput, setBroadcastInput] = useState(true)
+    const broadcastInputRef = useRef(broadcastInput)
+    const [closed, setClosed] = useState(false)
+
+    useEffect(() => {
+        broadcastInputRef.current = broadcastInput
+    }, [broadcastInput])
+
+    useEffect(() => {
+        const url = new URL(
+            `/api/v1/ws/terminal/broadcast/${broadcast.broadcast_id}`,
+            window.location.origin,
+        )
+        url.protocol = url.protocol.replace("http", "ws")
+        const ws = new WebSocket(url)
+        ws.binaryType = "arraybuffer"
+
+        const send = (msg: Uint8Array) => {
+            if (ws.readyState === WebSocket.OPEN) ws.send(msg)
+        }
+
+        const encoder = new TextEncoder()
+        const terminals = new Map<number, BroadcastTerminal>()
+        for (const session of broadcast.sessions) {
+            const container = containersRef.current.get(session.server_id)
+            if (!container) continue
+
+            const terminal = new Terminal({ cursorBlink: true, fontSize: 14 })
+            const fitAddon = new FitAddon()
+            terminal.loadAddon(fitAddon)
+            terminal.open(container)
+            const onInput = (payload: Uint8Array) => {
+                send(encodeFrame(broadcastInputRef.current ? null : session.server_id, 0, payload))
+            }
+            terminal.onData((data) => onInput(encoder.encode(data)))
+            terminal.onBinary((data) => onInput(Uint8Array.from(data, (c) => c.charCodeAt(0))))
+            terminals.set(session.server_id, { terminal, fitAddon })
+        }
+
+        // 每台服务器的窗口大小不同，单独发送
+        const resize = () => {
+            terminals.forEach(({ fitAddon }, serverId) => {
+                fitAddon.fit()
+                const dimensions = fitAddon.proposeDimensions()
+                if (!dimensions) return
+                const payload = encoder.encode(
+                    JSON.stringify({ Rows: dimensions.rows, Cols: dimensions.cols }),
+                )
+                send(encodeFrame(serverId, 1, payload))
+            })
+        }
+
+        ws.onopen = resize
+        ws.onmessage = (ev) => {
+            const data = new Uint8Array(ev.data as ArrayBuffer)
+            if (data.length < 8) return
+            const serverId = Number(new DataView(data.buffer).getBigUint64(0))
+            terminals.get(serverId)?.terminal.write(data.subarray(8))
+        }
+        ws.onclose = () => setClosed(true)
+        ws.onerror = (e) => {
+            console.error(e)
+        }
+
+        window.addEventListener("resize", resize)
+        return () => {
+            window.removeEventListener("resize", resize)
+            ws.close()
+            terminals.forEach(({ terminal }) => terminal.dispose())
+        }
+    }, [broadcast])
+
+    return (
+        <>
+            <div className="flex items-center gap-2 mb-4">
+                <Switch
+                    id="broadcast_input"
+                    checked={broadcastInput}
+                    onCheckedChange={setBroadcastInput}
+                />
+                <Label htmlFor="broadcast_input">{t("BroadcastInput")}</Label>
+                {closed && (
+                    <span className="text-sm text-muted-foreground ml-4">
+                        {t("BroadcastTerminalClosed")}
+                    </span>
+                )}
+            </div>
+            {broadcast.failure?.length ? (
+                <p className="text-sm text-red-500 mb-4">
+                    {t("Failure")} [{broadcast.failure.join(",")}]
+                </p>
+            ) : null}
+            <div className="grid gap-4 mb-5 lg:grid-cols-2">
+                {broadcast.sessions.map((session) => (
+                    <div key={session.session_id} className="rounded-md border p-2">
+                        <p className="text-sm font-medium mb-2">
+                            {session.server_name} ({session.server_id})
+                            {session.os_user && ` - ${session.os_user}`}
+                        </p>
+                        <div
+                            className="h-80"
+                            ref={(el) => {
+                                if (el) containersRef.current.set(session.server_id, el)
+                                else containersRef.current.delete(session.server_id)
+                            }}
+                        />
+                    </div>
+                ))}
+            </div>
+        </>
+    )
+}
+
+export const TerminalBroadcastPage = () => {
+    const { t } = useTranslation()
+    const [searchParams] = useSearchParams()
+    const [broadcast, setBroadcast] = useState<ModelCreateTerminalBroadcastResponse | null>(null)
+
+    useEffect(() => {
+        const serverIds = (searchParams.get("servers") || "")
+            .split(",")
+            .map((id) => parseInt(id))
+            .filter((id) => !isNaN(id))
+        const group = parseInt(searchParams.get("group") || "")
+        if (serverIds.length === 0 && isNaN(group)) return
+
+        createTerminalBroadcast({
+            server_ids: serverIds,
+            server_group_id: isNaN(group) ? undefined : group,
+        })
+            .then(setBroadcast)
+            .catch((error: any) => {
+                console.error("Failed to create broadcast terminal:", error)
+                toast(t("Error"), { description: error.message })
+            })
+    }, [searchParams])
+
+    return (
+        <div className="px-8">
+            <div className="flex mt-6 mb-4">
+                <h1 className="flex-1 text-3xl font-bold tracking-tight">
+                    {t("BroadcastTerminal")}
+                </h1>
+            </div>
+            {broadcast ? (
+                <BroadcastTerminals broadcast={broadcast} />
+            ) : (
+                <p>The server does not exist, or have not been connected yet.</p>
+            )}
+        </div>
+    )
+}
+
+export const TerminalBroadcastButton = ({ serverIds }: { serverIds: number[] }) => {
+    return (
+        <IconButton
+            variant="outline"
+            icon="terminal"
+            disabled={serverIds.length === 0}
+            onClick={() => {
+                window.open(
+                    `/dashboard/terminal/broadcast?servers=${serverIds.join(",")}`,
+                    "_blank",
+                )
+            }}
+        />
+    )
+}
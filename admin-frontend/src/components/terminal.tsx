@@ -1,3 +1,4 @@
+import { getDetachedTerminalSessions } from "@/api/terminal"
 import {
     AlertDialog,
     AlertDialogAction,
@@ -7,8 +8,15 @@ import {
     AlertDialogHeader,
     AlertDialogTitle,
 } from "@/components/ui/alert-dialog"
+import {
+    DropdownMenu,
+    DropdownMenuContent,
+    DropdownMenuItem,
+    DropdownMenuTrigger,
+} from "@/components/ui/dropdown-menu"
 import useTerminal from "@/hooks/useTerminal"
 import { sleep } from "@/lib/utils"
+import { TerminalSession } from "@/types/terminal-audit"
 import { AttachAddon } from "@xterm/addon-attach"
 import { FitAddon } from "@xterm/addon-fit"
 import { Terminal } from "@xterm/xterm"
@@ -16,8 +24,7 @@ import "@xterm/xterm/css/xterm.css"
 import { Terminal as TerminalIcon } from "lucide-react"
 import { JSX, forwardRef, useEffect, useImperativeHandle, useRef, useState } from "react"
 import { useTranslation } from "react-i18next"
-import { useParams } from "react-router-dom"
-import { toast } from "sonner"
+import { useParams, useSearchParams } from "react-router-dom"
 
 import { FMCard } from "./fm"
 import { Button } from "./ui/button"
@@ -28,11 +35,15 @@ interface XtermProps {
     setClose: React.Dispatch<React.SetStateAction<boolean>>
 }
 
+// 连接断开后尝试重新挂载到同一个会话，会话已结束时连接会立即失败
+const reconnectDelays = [500, 1000, 2000]
+
 const XtermComponent = forwardRef<HTMLDivElement, XtermProps & JSX.IntrinsicElements["div"]>(
     ({ wsUrl, setClose, ...props }, ref) => {
         const terminalIdRef = useRef<HTMLDivElement>(null)
         const terminalRef = useRef<Terminal | null>(null)
         const wsRef = useRef<WebSocket | null>(null)
+        const [reconnecting, setReconnecting] = useState(false)
 
         useImperativeHandle(ref, () => {
             return {
@@ -43,41 +54,73 @@ const XtermComponent = forwardRef<HTMLDivElement, XtermProps & JSX.IntrinsicElem
             }
         }, [])
 
-        useEffect(() => {
-            return () => {
-                wsRef.current?.close()
-                terminalRef.current?.dispose()
-            }
-        }, [])
+        const fitAddon = useRef(new FitAddon()).current
+        const sendResize = useRef(false)
 
         useEffect(() => {
-            terminalRef.current = new Terminal({
+            if (!terminalIdRef.current) return
+
+            const terminal = new Terminal({
                 cursorBlink: true,
                 fontSize: 16,
             })
-            const url = new URL(wsUrl, window.location.origin)
-            url.protocol = url.protocol.replace("http", "ws")
-            const ws = new WebSocket(url)
-            wsRef.current = ws
-            ws.binaryType = "arraybuffer"
-            ws.onopen = () => {
-                onResize()
-            }
-            ws.onclose = () => {
-                terminalRef.current?.dispose()
-                setClose(true)
+            terminalRef.current = terminal
+            terminal.loadAddon(fitAddon)
+            terminal.open(terminalIdRef.current)
+
+            let disposed = false
+            let attachAddon: AttachAddon | null = null
+            let failures = 0
+
+            const connect = () => {
+                const url = new URL(wsUrl, window.location.origin)
+                url.protocol = url.protocol.replace("http", "ws")
+                const ws = new WebSocket(url)
+                wsRef.current = ws
+                ws.binaryType = "arraybuffer"
+                let opened = false
+                ws.onopen = () => {
+                    opened = true
+                    failures = 0
+                    setReconnecting(false)
+                    // 重新挂载时 agent 会重放回滚缓冲区
+                    terminal.reset()
+                    attachAddon?.dispose()
+                    attachAddon = new AttachAddon(ws)
+                    terminal.loadAddon(attachAddon)
+                    onResize()
+                }
+                ws.onclose = async () => {
+                    if (disposed) return
+                    if (!opened) failures++
+                    if (failures >= reconnectDelays.length) {
+                        setReconnecting(false)
+                        setClose(true)
+                        return
+                    }
+                    setReconnecting(true)
+                    await sleep(reconnectDelays[failures])
+                    // 网络恢复之前不计入失败次数
+                    while (!disposed && !navigator.onLine) {
+                        await sleep(1000)
+                    }
+                    if (!disposed) connect()
+                }
+                ws.onerror = (e) => {
+                    console.error(e)
+                }
             }
-            ws.onerror = (e) => {
-                console.error(e)
-                toast("Websocket error", {
-                    description: "View console for details.",
-                })
+            connect()
+
+            window.addEventListener("resize", onResize)
+            return () => {
+                disposed = true
+                window.removeEventListener("resize", onResize)
+                wsRef.current?.close()
+                terminal.dispose()
             }
         }, [wsUrl])
 
-        const fitAddon = useRef(new FitAddon()).current
-        const sendResize = useRef(false)
-
         const doResize = () => {
             if (!terminalIdRef.current) return
 
@@ -116,30 +159,42 @@ const XtermComponent = forwardRef<HTMLDivElement, XtermProps & JSX.IntrinsicElem
             }
         }
 
-        useEffect(() => {
-            if (!wsRef.current || !terminalIdRef.current || !terminalRef.current) return
-            const attachAddon = new AttachAddon(wsRef.current)
-            terminalRef.current.loadAddon(attachAddon)
-            terminalRef.current.loadAddon(fitAddon)
-            terminalRef.current.open(terminalIdRef.current)
-            window.addEventListener("resize", onResize)
-            return () => {
-                window.removeEventListener("resize", onResize)
-                if (wsRef.current) {
-                    wsRef.current.close()
-                }
-            }
-        }, [wsRef.current, terminalRef.current, terminalIdRef.current])
-
-        return <div ref={terminalIdRef} {...props} />
+        return (
+            <>
+                {reconnecting && (
+                    <p className="text-sm text-muted-foreground mb-2">
+                        Connection lost, reconnecting...
+                    </p>
+                )}
+                <div ref={terminalIdRef} {...props} />
+            </>
+        )
     },
 )
 
 export const TerminalPage = () => {
     const { id } = useParams<{ id: string }>()
+    const [searchParams, setSearchParams] = useSearchParams()
     const [open, setOpen] = useState(false)
-    const terminal = useTerminal(id ? parseInt(id) : undefined)
+    const serverId = id ? parseInt(id) : undefined
+    const terminal = useTerminal(serverId, searchParams.get("session"))
     const terminalIdRef = useRef<HTMLDivElement>(null)
+
+    // 本服务器上等待重连的其他会话
+    const [detached, setDetached] = useState<TerminalSession[]>([])
+    useEffect(() => {
+        if (!serverId) return
+        getDetachedTerminalSessions()
+            .then((sessions) =>
+                setDetached(
+                    sessions.filter(
+                        (s) => s.server_id === serverId && s.stream_id !== terminal?.session_id,
+                    ),
+                ),
+            )
+            .catch((error) => console.error("Failed to fetch detached sessions:", error))
+    }, [serverId, terminal?.session_id])
+
     return (
         <div className="px-8">
             <div className="flex mt-6 mb-4">
@@ -151,6 +206,27 @@ export const TerminalPage = () => {
                             await terminalIdRef.current?.requestFullscreen()
                         }}
                     />
+                    {detached.length > 0 && (
+                        <DropdownMenu>
+                            <DropdownMenuTrigger asChild>
+                                <Button variant="outline">Reattach ({detached.length})</Button>
+                            </DropdownMenuTrigger>
+                            <DropdownMenuContent>
+                                {detached.map((s) => (
+                                    <DropdownMenuItem
+                                        key={s.stream_id}
+                                        onClick={() => {
+                                            setOpen(false)
+                                            setSearchParams({ session: s.stream_id })
+                                        }}
+                                    >
+                                        {new Date(s.started_at).toLocaleString()}
+                                        {s.os_user && ` (${s.os_user})`}
+                                    </DropdownMenuItem>
+                                ))}
+                            </DropdownMenuContent>
+                        </DropdownMenu>
+                    )}
                     <FMCard id={id} />
                 </div>
             </div>
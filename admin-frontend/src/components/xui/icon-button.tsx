@@ -1,6 +1,7 @@
 import { Button, ButtonProps } from "@/components/ui/button"
 import {
     BanIcon,
+    ChartLine,
     Check,
     CircleArrowUp,
     Clipboard,
@@ -41,6 +42,7 @@ export interface IconButtonProps extends ButtonProps {
         | "minus"
         | "user-pen"
         | "more"
+        | "chart"
 }
 
 export const IconButton = forwardRef<HTMLButtonElement, IconButtonProps>((props, ref) => {
@@ -68,6 +70,9 @@ export const IconButton = forwardRef<HTMLButtonElement, IconButtonProps>((props,
                     case "plus": {
                         return <Plus />
                     }
+                    case "chart": {
+                        return <ChartLine />
+                    }
                     case "terminal": {
                         return <Terminal />
                     }
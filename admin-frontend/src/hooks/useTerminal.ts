@@ -2,7 +2,8 @@ import { createTerminal } from "@/api/terminal"
 import { ModelCreateTerminalResponse } from "@/types"
 import { useEffect, useState } from "react"
 
-export default function useTerminal(serverId?: number) {
+// 指定 sessionId 时重新挂载到已有的会话，否则创建新会话
+export default function useTerminal(serverId?: number, sessionId?: string | null) {
     const [terminal, setTerminal] = useState<ModelCreateTerminalResponse | null>(null)
 
     async function fetchTerminal() {
@@ -16,8 +17,12 @@ export default function useTerminal(serverId?: number) {
 
     useEffect(() => {
         if (!serverId) return
+        if (sessionId) {
+            setTerminal({ server_id: serverId, server_name: "", session_id: sessionId })
+            return
+        }
         fetchTerminal()
-    }, [serverId])
+    }, [serverId, sessionId])
 
     return terminal
 }
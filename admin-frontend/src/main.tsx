@@ -17,6 +17,7 @@ import LoginPage from "./routes/login"
 import ServerPage from "./routes/server"
 import ServicePage from "./routes/service"
 import { TerminalPage } from "./components/terminal"
+import { TerminalBroadcastPage } from "./components/terminal-broadcast"
 import DDNSPage from "./routes/ddns"
 import NATPage from "./routes/nat"
 import NotificationGroupPage from "./routes/notification-group"
@@ -119,6 +120,10 @@ const router = createBrowserRouter([
                 path: "/dashboard/terminal/:id",
                 element: <TerminalPage />,
             },
+            {
+                path: "/dashboard/terminal/broadcast",
+                element: <TerminalBroadcastPage />,
+            },
             {
                 path: "/dashboard/notification",
                 element: (
@@ -24,12 +24,22 @@ import {
 } from "@/components/ui/table"
 import { useServer } from "@/hooks/useServer"
 import { ColumnDef, flexRender, getCoreRowModel, useReactTable } from "@tanstack/react-table"
-import { Play, Square, Trash2 } from "lucide-react"
+import { Play, Square, Trash2, TriangleAlert } from "lucide-react"
 import { useCallback, useEffect, useMemo, useState } from "react"
 import { useTranslation } from "react-i18next"
 import { toast } from "sonner"
 import useSWR from "swr"
 
+const emptyForm: AutoSSHForm = {
+    name: "",
+    server_id: 0,
+    mapping_type: "remote",
+    source_port: 0,
+    target_host: "localhost",
+    target_port: 0,
+    enabled: true,
+}
+
 export default function AutoSSHPage() {
     const { t } = useTranslation()
     const { data, mutate, error, isLoading } = useSWR<AutoSSH[]>("/api/v1/autossh", swrFetcher)
@@ -37,15 +47,8 @@ export default function AutoSSHPage() {
 
     const [dialogOpen, setDialogOpen] = useState(false)
     const [editingMapping, setEditingMapping] = useState<AutoSSH | null>(null)
-    const [formData, setFormData] = useState<AutoSSHForm>({
-        name: "",
-        server_id: 0,
-        mapping_type: "remote",
-        source_port: 0,
-        target_host: "localhost",
-        target_port: 0,
-        enabled: true,
-    })
+    const [formData, setFormData] = useState<AutoSSHForm>(emptyForm)
+    const [allowedSources, setAllowedSources] = useState("")
 
     useEffect(() => {
         if (error)
@@ -58,15 +61,8 @@ export default function AutoSSHPage() {
 
     const handleCreate = useCallback(() => {
         setEditingMapping(null)
-        setFormData({
-            name: "",
-            server_id: 0,
-            mapping_type: "remote",
-            source_port: 0,
-            target_host: "localhost",
-            target_port: 0,
-            enabled: true,
-        })
+        setFormData(emptyForm)
+        setAllowedSources("")
         setDialogOpen(true)
     }, [])
 
@@ -80,17 +76,27 @@ export default function AutoSSHPage() {
             target_host: mapping.target_host,
             target_port: mapping.target_port,
             enabled: mapping.enabled,
+            socks_username: mapping.socks_username,
+            bind_address: mapping.bind_address,
         })
+        setAllowedSources(mapping.allowed_sources?.join(", ") || "")
         setDialogOpen(true)
     }, [])
 
     const handleSubmit = useCallback(async () => {
+        const data: AutoSSHForm = {
+            ...formData,
+            allowed_sources: allowedSources
+                .split(/[,\s]+/)
+                .map((s) => s.trim())
+                .filter(Boolean),
+        }
         try {
             if (editingMapping) {
-                await updateAutoSSH(editingMapping.id, formData)
+                await updateAutoSSH(editingMapping.id, data)
                 toast(t("Success"), { description: t("AutoSSH mapping updated") })
             } else {
-                await createAutoSSH(formData)
+                await createAutoSSH(data)
                 toast(t("Success"), { description: t("AutoSSH mapping created") })
             }
             setDialogOpen(false)
@@ -98,7 +104,7 @@ export default function AutoSSHPage() {
         } catch (error: any) {
             toast(t("Error"), { description: error.message })
         }
-    }, [editingMapping, formData, mutate, t])
+    }, [editingMapping, formData, allowedSources, mutate, t])
 
     const handleStart = useCallback(async (id: number) => {
         try {
@@ -174,7 +180,11 @@ export default function AutoSSHPage() {
                 const type = row.original.mapping_type
                 return (
                     <div>
-                        {type === "remote" ? t("RemoteForward") : t("LocalForward")}
+                        {type === "remote"
+                            ? t("RemoteForward")
+                            : type === "dynamic"
+                              ? t("DynamicForward")
+                              : t("LocalForward")}
                     </div>
                 )
             },
@@ -183,6 +193,21 @@ export default function AutoSSHPage() {
             header: t("SourcePort"),
             accessorKey: "source_port",
             accessorFn: (row) => row.source_port,
+            cell: ({ row }) => {
+                const mapping = row.original
+                return (
+                    <div className="flex items-center gap-1">
+                        {mapping.bind_address
+                            ? `${mapping.bind_address}:${mapping.source_port}`
+                            : mapping.source_port}
+                        {mapping.public_exposure && (
+                            <span title={t("PublicExposureWarning")}>
+                                <TriangleAlert className="h-4 w-4 text-yellow-500" />
+                            </span>
+                        )}
+                    </div>
+                )
+            },
         },
         {
             header: t("Target"),
@@ -191,7 +216,9 @@ export default function AutoSSHPage() {
                 const mapping = row.original
                 return (
                     <div className="max-w-32 whitespace-normal break-words">
-                        {mapping.target_host}:{mapping.target_port}
+                        {mapping.mapping_type === "dynamic"
+                            ? "SOCKS5"
+                            : `${mapping.target_host}:${mapping.target_port}`}
                     </div>
                 )
             },
@@ -395,6 +422,7 @@ export default function AutoSSHPage() {
                                 <SelectContent>
                                     <SelectItem value="remote">{t("RemoteForward")}</SelectItem>
                                     <SelectItem value="local">{t("LocalForward")}</SelectItem>
+                                    <SelectItem value="dynamic">{t("DynamicForward")}</SelectItem>
                                 </SelectContent>
                             </Select>
                         </div>
@@ -412,30 +440,96 @@ export default function AutoSSHPage() {
                                 }
                             />
                         </div>
+                        {formData.mapping_type === "dynamic" ? (
+                            <>
+                                <div className="grid gap-2">
+                                    <Label htmlFor="socks_username">{t("SocksUsername")}</Label>
+                                    <Input
+                                        id="socks_username"
+                                        value={formData.socks_username || ""}
+                                        onChange={(e) =>
+                                            setFormData({
+                                                ...formData,
+                                                socks_username: e.target.value,
+                                            })
+                                        }
+                                    />
+                                </div>
+                                <div className="grid gap-2">
+                                    <Label htmlFor="socks_password">{t("SocksPassword")}</Label>
+                                    <Input
+                                        id="socks_password"
+                                        type="password"
+                                        placeholder={
+                                            editingMapping ? t("SocksPasswordHint") : undefined
+                                        }
+                                        value={formData.socks_password || ""}
+                                        onChange={(e) =>
+                                            setFormData({
+                                                ...formData,
+                                                socks_password: e.target.value,
+                                            })
+                                        }
+                                    />
+                                </div>
+                            </>
+                        ) : (
+                            <>
+                                <div className="grid gap-2">
+                                    <Label htmlFor="target_host">{t("TargetHost")}</Label>
+                                    <Input
+                                        id="target_host"
+                                        value={formData.target_host}
+                                        onChange={(e) =>
+                                            setFormData({
+                                                ...formData,
+                                                target_host: e.target.value,
+                                            })
+                                        }
+                                    />
+                                </div>
+                                <div className="grid gap-2">
+                                    <Label htmlFor="target_port">{t("TargetPort")}</Label>
+                                    <Input
+                                        id="target_port"
+                                        type="number"
+                                        value={formData.target_port}
+                                        onChange={(e) =>
+                                            setFormData({
+                                                ...formData,
+                                                target_port: parseInt(e.target.value),
+                                            })
+                                        }
+                                    />
+                                </div>
+                            </>
+                        )}
                         <div className="grid gap-2">
-                            <Label htmlFor="target_host">{t("TargetHost")}</Label>
+                            <Label htmlFor="bind_address">{t("BindAddress")}</Label>
                             <Input
-                                id="target_host"
-                                value={formData.target_host}
+                                id="bind_address"
+                                placeholder={t("BindAddressHint")}
+                                value={formData.bind_address || ""}
                                 onChange={(e) =>
-                                    setFormData({ ...formData, target_host: e.target.value })
+                                    setFormData({ ...formData, bind_address: e.target.value })
                                 }
                             />
                         </div>
                         <div className="grid gap-2">
-                            <Label htmlFor="target_port">{t("TargetPort")}</Label>
+                            <Label htmlFor="allowed_sources">{t("AllowedSources")}</Label>
                             <Input
-                                id="target_port"
-                                type="number"
-                                value={formData.target_port}
-                                onChange={(e) =>
-                                    setFormData({
-                                        ...formData,
-                                        target_port: parseInt(e.target.value),
-                                    })
-                                }
+                                id="allowed_sources"
+                                placeholder={t("AllowedSourcesHint")}
+                                value={allowedSources}
+                                onChange={(e) => setAllowedSources(e.target.value)}
                             />
                         </div>
+                        {editingMapping?.public_exposure && (
+                            <div className="flex items-start gap-2 rounded-md border border-yellow-500 p-3 text-sm text-yellow-600">
+                                <TriangleAlert className="h-4 w-4 shrink-0" />
+                                <span>{t("PublicExposureWarning")}</span>
+                            </div>
+                        )}
                         <div className="flex items-center gap-2">
                             <Switch
                                 id="enabled"
@@ -3,6 +3,7 @@ import { deleteServer, forceUpdateServer } from "@/api/server"
 import { ActionButtonGroup } from "@/components/action-button-group"
 import { BatchMoveServerIcon } from "@/components/batch-move-server-icon"
 import { CopyButton } from "@/components/copy-button"
+import { CustomMetricsCard } from "@/components/custom-metrics"
 import { HeaderButtonGroup } from "@/components/header-button-group"
 import { InstallCommandsMenu } from "@/components/install-commands"
 import { NoteMenu } from "@/components/note-menu"
@@ -10,6 +11,7 @@ import { ServerCard } from "@/components/server"
 import { ServerConfigCard } from "@/components/server-config"
 import { ServerConfigCardBatch } from "@/components/server-config-batch"
 import { TerminalButton } from "@/components/terminal"
+import { TerminalBroadcastButton } from "@/components/terminal-broadcast"
 import { Checkbox } from "@/components/ui/checkbox"
 import {
     DropdownMenu,
@@ -169,6 +171,9 @@ export default function ServerPage() {
                                     <DropdownMenuItem asChild>
                                         <ServerConfigCard sid={s.id} variant="ghost" menuItem />
                                     </DropdownMenuItem>
+                                    <DropdownMenuItem asChild>
+                                        <CustomMetricsCard sid={s.id} variant="ghost" menuItem />
+                                    </DropdownMenuItem>
                                     <DropdownMenuItem asChild>
                                         <InstallCommandsMenu uuid={s.uuid} menuItem />
                                     </DropdownMenuItem>
@@ -241,6 +246,7 @@ export default function ServerPage() {
                             })
                         }}
                     />
+                    <TerminalBroadcastButton serverIds={selectedRows.map((r) => r.original.id)} />
                     <BatchMoveServerIcon serverIds={selectedRows.map((r) => r.original.id)} />
                     <ServerConfigCardBatch
                         sid={selectedRows.map((r) => r.original.id)}
@@ -15,6 +15,10 @@ export interface TerminalSession {
   command_count: number
   recording_path?: string
   recording_enabled: boolean
+  os_user?: string
+  broadcast_id?: string
+  detached_at?: string
+  reattach_count: number
 }
 
 export interface TerminalCommand {
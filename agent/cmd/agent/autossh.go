@@ -0,0 +1,264 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/nezhahq/agent/model"
+	"github.com/nezhahq/agent/pkg/sshtunnel"
+	pb "github.com/nezhahq/agent/proto"
+)
+
+// autosshTunnel 一个 AutoSSH 映射及其隧道
+type autosshTunnel struct {
+	task   model.TaskAutoSSH
+	tunnel *sshtunnel.Tunnel
+}
+
+// AutoSSH 隧道管理
+var (
+	autosshTunnels = make(map[uint64]*autosshTunnel)
+	autosshMutex   sync.RWMutex
+)
+
+func handleAutoSSHTask(task *pb.Task, result *pb.TaskResult) {
+	var taskData model.TaskAutoSSH
+	if err := json.Unmarshal([]byte(task.GetData()), &taskData); err != nil {
+		printf("AutoSSH 任务解析失败: %v", err)
+		result.Data = err.Error()
+		return
+	}
+
+	printf("AutoSSH 任务: action=%s, mapping_id=%d, type=%s, source_port=%d, target=%s:%d",
+		taskData.Action, taskData.MappingID, taskData.MappingType,
+		taskData.SourcePort, taskData.TargetHost, taskData.TargetPort)
+
+	var report model.AutoSSHStatusReport
+	switch taskData.Action {
+	case "start":
+		report = startAutoSSH(&taskData)
+	case "stop":
+		report = stopAutoSSH(taskData.MappingID)
+	case "status":
+		report = checkAutoSSHStatus(taskData.MappingID)
+	case "sync":
+		report = syncAutoSSH(taskData.MappingIDs)
+	default:
+		printf("不支持的 AutoSSH 操作: %s", taskData.Action)
+		report = model.AutoSSHStatusReport{
+			MappingID: taskData.MappingID,
+			Status:    model.AutoSSHStatusError,
+			Error:     fmt.Sprintf("unsupported action: %s", taskData.Action),
+		}
+	}
+
+	data, _ := json.Marshal(report)
+	result.Data = string(data)
+	result.Successful = report.Error == ""
+}
+
+func tunnelConfig(taskData *model.TaskAutoSSH) sshtunnel.Config {
+	return sshtunnel.Config{
+		ID:         taskData.MappingID,
+		Type:       taskData.MappingType,
+		SourcePort: taskData.SourcePort,
+		TargetHost: taskData.TargetHost,
+		TargetPort: taskData.TargetPort,
+		SSHHost:    taskData.SSHHost,
+		Options:    taskData.SSHOptions,
+
+		SocksUsername: taskData.SocksUsername,
+		SocksPassword: taskData.SocksPassword,
+
+		HostKeyFingerprint: taskData.HostKeyFingerprint,
+		PrivateKey:         taskData.PrivateKey,
+		RateLimit:          taskData.RateLimit,
+		BindAddress:        taskData.BindAddress,
+		AllowedSources:     taskData.AllowedSources,
+	}
+}
+
+// autosshReport 根据隧道计数生成状态上报，err 为空时使用最近一次错误
+func autosshReport(mappingID uint64, event string, stats sshtunnel.Stats, err error) model.AutoSSHStatusReport {
+	report := model.AutoSSHStatusReport{
+		MappingID:           mappingID,
+		Event:               event,
+		Status:              model.AutoSSHStatusRunning,
+		Restarts:            stats.Reconnects,
+		Connections:         stats.Connections,
+		ActiveConnections:   stats.ActiveConnections,
+		FailedConnections:   stats.FailedConnections,
+		RejectedConnections: stats.RejectedConnections,
+		BytesIn:             stats.BytesIn,
+		BytesOut:            stats.BytesOut,
+
+		HostKeyFingerprint: stats.HostKeyFingerprint,
+	}
+	if stats.Connected {
+		return report
+	}
+	if err == nil {
+		err = stats.LastError
+	}
+	report.Status = model.AutoSSHStatusError
+	if err != nil {
+		report.Error = err.Error()
+		report.ErrorCode = sshtunnel.ErrorCode(err)
+	} else {
+		report.Error = "tunnel is reconnecting"
+	}
+	return report
+}
+
+// sameAutoSSHTask 比较两个启动任务的隧道配置
+func sameAutoSSHTask(a, b model.TaskAutoSSH) bool {
+	a.Action, b.Action = "", ""
+	a.Restore, b.Restore = false, false
+	return reflect.DeepEqual(a, b)
+}
+
+func startAutoSSH(taskData *model.TaskAutoSSH) model.AutoSSHStatusReport {
+	mappingID := taskData.MappingID
+
+	// 面板重连后恢复映射时，配置未变的隧道继续运行，避免中断已有连接
+	if taskData.Restore {
+		autosshMutex.RLock()
+		cur := autosshTunnels[mappingID]
+		autosshMutex.RUnlock()
+		if cur != nil && sameAutoSSHTask(cur.task, *taskData) {
+			printf("AutoSSH 隧道运行中，无需恢复 (mapping_id=%d)", mappingID)
+			return autosshReport(mappingID, model.AutoSSHEventStatus, cur.tunnel.Stats(), nil)
+		}
+	}
+
+	t := &autosshTunnel{task: *taskData}
+	t.tunnel = sshtunnel.New(tunnelConfig(taskData), func(ev sshtunnel.Event) {
+		onAutoSSHEvent(t, ev)
+	})
+
+	// 如果已经存在，先停止
+	autosshMutex.Lock()
+	old := autosshTunnels[mappingID]
+	autosshTunnels[mappingID] = t
+	autosshMutex.Unlock()
+	if old != nil {
+		old.tunnel.Stop()
+	}
+
+	err := t.tunnel.Start()
+	if err == nil {
+		printf("AutoSSH 隧道已建立 (mapping_id=%d)", mappingID)
+		return autosshReport(mappingID, model.AutoSSHEventStart, t.tunnel.Stats(), nil)
+	}
+
+	printf("AutoSSH 启动失败 (mapping_id=%d): %v", mappingID, err)
+	if sshtunnel.ErrorCode(err) == sshtunnel.ErrCodeConfig {
+		// 配置错误无法通过重连恢复，主机密钥不匹配时保留隧道以便查询状态
+		autosshMutex.Lock()
+		if autosshTunnels[mappingID] == t {
+			delete(autosshTunnels, mappingID)
+		}
+		autosshMutex.Unlock()
+	}
+	return autosshReport(mappingID, model.AutoSSHEventStart, t.tunnel.Stats(), err)
+}
+
+// onAutoSSHEvent 隧道断开或重连成功时主动上报
+func onAutoSSHEvent(t *autosshTunnel, ev sshtunnel.Event) {
+	mappingID := t.task.MappingID
+
+	autosshMutex.RLock()
+	current := autosshTunnels[mappingID] == t
+	autosshMutex.RUnlock()
+	if !current {
+		return
+	}
+
+	event := model.AutoSSHEventExit
+	if ev.Connected {
+		event = model.AutoSSHEventRestart
+		printf("AutoSSH 已重连 (mapping_id=%d, restarts=%d)", mappingID, ev.Stats.Reconnects)
+	} else {
+		printf("AutoSSH 隧道断开 (mapping_id=%d): %v", mappingID, ev.Err)
+	}
+	reportAutoSSHStatus(autosshReport(mappingID, event, ev.Stats, ev.Err))
+}
+
+// reportAutoSSHStatus 通过任务流主动上报映射状态
+func reportAutoSSHStatus(report model.AutoSSHStatusReport) {
+	data, _ := json.Marshal(report)
+	if err := reportTaskResult(&pb.TaskResult{
+		Type:       model.TaskTypeAutoSSH,
+		Data:       string(data),
+		Successful: report.Error == "",
+	}); err != nil {
+		printf("AutoSSH 状态上报失败 (mapping_id=%d): %v", report.MappingID, err)
+	}
+}
+
+func stopAutoSSH(mappingID uint64) model.AutoSSHStatusReport {
+	autosshMutex.Lock()
+	t, exists := autosshTunnels[mappingID]
+	delete(autosshTunnels, mappingID)
+	autosshMutex.Unlock()
+
+	report := model.AutoSSHStatusReport{
+		MappingID: mappingID,
+		Event:     model.AutoSSHEventStop,
+		Status:    model.AutoSSHStatusStopped,
+	}
+
+	if !exists {
+		printf("AutoSSH 隧道不存在 (mapping_id=%d)", mappingID)
+		return report
+	}
+
+	t.tunnel.Stop()
+	printf("AutoSSH 已停止 (mapping_id=%d)", mappingID)
+	return report
+}
+
+// syncAutoSSH 停止面板上已不存在或不应运行的隧道
+func syncAutoSSH(mappingIDs []uint64) model.AutoSSHStatusReport {
+	autosshMutex.RLock()
+	var stale []uint64
+	for id := range autosshTunnels {
+		if !slices.Contains(mappingIDs, id) {
+			stale = append(stale, id)
+		}
+	}
+	autosshMutex.RUnlock()
+
+	for _, id := range stale {
+		stopAutoSSH(id)
+	}
+	if len(stale) > 0 {
+		printf("AutoSSH 已停止 %d 个面板未启用的隧道", len(stale))
+	}
+	return model.AutoSSHStatusReport{
+		Event:  model.AutoSSHEventSync,
+		Status: model.AutoSSHStatusRunning,
+	}
+}
+
+func checkAutoSSHStatus(mappingID uint64) model.AutoSSHStatusReport {
+	autosshMutex.RLock()
+	t, exists := autosshTunnels[mappingID]
+	autosshMutex.RUnlock()
+
+	if !exists {
+		printf("AutoSSH 状态: 未运行 (mapping_id=%d)", mappingID)
+		return model.AutoSSHStatusReport{
+			MappingID: mappingID,
+			Event:     model.AutoSSHEventStatus,
+			Status:    model.AutoSSHStatusStopped,
+		}
+	}
+
+	report := autosshReport(mappingID, model.AutoSSHEventStatus, t.tunnel.Stats(), nil)
+	printf("AutoSSH 状态: %s (mapping_id=%d, connections=%d)", report.Status, mappingID, report.Connections)
+	return report
+}
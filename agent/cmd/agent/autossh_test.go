@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/nezhahq/agent/model"
+	"github.com/nezhahq/agent/pkg/sshtunnel"
+)
+
+func TestSameAutoSSHTask(t *testing.T) {
+	task := model.TaskAutoSSH{
+		Action:      "start",
+		MappingID:   1,
+		MappingType: "local",
+		SourcePort:  8080,
+		TargetHost:  "127.0.0.1",
+		TargetPort:  80,
+		SSHHost:     "tunnel@example.com:22",
+		SSHOptions:  map[string]string{"ServerAliveInterval": "30"},
+	}
+
+	restore := task
+	restore.Restore = true
+	if !sameAutoSSHTask(task, restore) {
+		t.Fatal("restore flag should not affect comparison")
+	}
+
+	changed := restore
+	changed.SSHOptions = map[string]string{"ServerAliveInterval": "60"}
+	if sameAutoSSHTask(task, changed) {
+		t.Fatal("expected changed options to differ")
+	}
+}
+
+func TestSyncAutoSSH(t *testing.T) {
+	autosshMutex.Lock()
+	for _, id := range []uint64{1, 2, 3} {
+		autosshTunnels[id] = &autosshTunnel{
+			task:   model.TaskAutoSSH{MappingID: id},
+			tunnel: sshtunnel.New(sshtunnel.Config{ID: id}, nil),
+		}
+	}
+	autosshMutex.Unlock()
+	t.Cleanup(func() {
+		autosshMutex.Lock()
+		clear(autosshTunnels)
+		autosshMutex.Unlock()
+	})
+
+	report := syncAutoSSH([]uint64{2})
+	if report.Event != model.AutoSSHEventSync {
+		t.Fatalf("expected event %s, but got %s", model.AutoSSHEventSync, report.Event)
+	}
+
+	autosshMutex.RLock()
+	defer autosshMutex.RUnlock()
+	if len(autosshTunnels) != 1 || autosshTunnels[2] == nil {
+		t.Fatalf("expected only mapping 2 to remain, but got %v", autosshTunnels)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/nezhahq/agent/pkg/monitor"
+	"github.com/nezhahq/agent/pkg/statebuf"
+	pb "github.com/nezhahq/agent/proto"
+)
+
+const (
+	backfillSampleInterval = 30 * time.Second // 断线期间的采样间隔
+	backfillDefaultSize    = 2880             // 默认缓存约 24 小时的状态
+	backfillBatchSize      = 100              // 每次补传的状态数
+)
+
+var stateBuffer *statebuf.Buffer
+
+// initBackfill 打开与配置文件同目录的状态缓冲文件，并在断线期间定时采样
+func initBackfill(configPath string) {
+	if agentConfig.DisableBackfill {
+		return
+	}
+	size := int(agentConfig.BackfillBufferSize)
+	if size == 0 {
+		size = backfillDefaultSize
+	}
+	buf, err := statebuf.Open(filepath.Join(filepath.Dir(configPath), "state_backfill.dat"), size)
+	if err != nil {
+		printf("打开状态缓冲文件失败: %v", err)
+		return
+	}
+	stateBuffer = buf
+	go bufferStateDaemon()
+}
+
+func bufferStateDaemon() {
+	for range time.Tick(backfillSampleInterval) {
+		if initialized {
+			continue
+		}
+		monitor.TrackNetworkSpeed()
+		state := monitor.GetState(agentConfig.SkipConnectionCount, agentConfig.SkipProcsCount)
+		state.CustomMetrics = getCustomMetrics()
+		data, err := proto.Marshal(&pb.StateSample{State: state.PB(), Timestamp: time.Now().Unix()})
+		if err == nil {
+			err = stateBuffer.Push(data)
+		}
+		if err != nil {
+			printf("缓存状态失败: %v", err)
+		}
+	}
+}
+
+// uploadBackfill 在上报实时状态前补传断线期间缓存的状态，失败时保留到下次连接，旧版面板不支持时丢弃
+func uploadBackfill() {
+	if stateBuffer == nil || stateBuffer.Len() == 0 {
+		return
+	}
+
+	var uploaded int
+	for stateBuffer.Len() > 0 {
+		records, err := stateBuffer.Peek(backfillBatchSize)
+		if err != nil {
+			printf("读取状态缓冲失败: %v", err)
+			return
+		}
+		req := &pb.StateBackfill{Samples: make([]*pb.StateSample, 0, len(records))}
+		for _, r := range records {
+			var sample pb.StateSample
+			if err := proto.Unmarshal(r, &sample); err == nil {
+				req.Samples = append(req.Samples, &sample)
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), networkTimeOut)
+		_, err = client.ReportStateBackfill(ctx, req)
+		cancel()
+		if status.Code(err) == codes.Unimplemented {
+			stateBuffer.Discard(stateBuffer.Len())
+			return
+		}
+		if err != nil {
+			printf("补传断线期间的状态失败: %v", err)
+			return
+		}
+		if err := stateBuffer.Discard(len(records)); err != nil {
+			printf("清理状态缓冲失败: %v", err)
+			return
+		}
+		uploaded += len(req.Samples)
+	}
+	printf("已补传 %d 条断线期间的状态", uploaded)
+}
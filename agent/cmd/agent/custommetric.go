@@ -0,0 +1,43 @@
+package main
+
+import (
+	"time"
+
+	"github.com/nezhahq/agent/model"
+	"github.com/nezhahq/agent/pkg/custommetric"
+)
+
+var customMetrics = custommetric.NewManager()
+
+// startCustomMetrics 按配置重新启动自定义指标采集器，关闭命令执行时只读取 textfile
+func startCustomMetrics(conf *model.AgentConfig) {
+	collectors := make([]custommetric.Collector, 0, len(conf.CustomMetrics))
+	for _, c := range conf.CustomMetrics {
+		collectors = append(collectors, custommetric.Collector{
+			Name:     c.Name,
+			Command:  c.Command,
+			Textfile: c.Textfile,
+			Interval: time.Duration(c.Interval) * time.Second,
+			Timeout:  time.Duration(c.Timeout) * time.Second,
+		})
+	}
+	disableCommandExecute := conf.DisableCommandExecute
+	customMetrics.Start(collectors, func() bool {
+		return !disableCommandExecute
+	}, func(name string, err error) {
+		printf("自定义指标 %s 采集失败: %v", name, err)
+	})
+}
+
+func getCustomMetrics() []model.CustomMetric {
+	metrics := customMetrics.Metrics()
+	result := make([]model.CustomMetric, 0, len(metrics))
+	for _, m := range metrics {
+		result = append(result, model.CustomMetric{
+			Name:  m.Name,
+			Value: m.Value,
+			Type:  m.Type,
+		})
+	}
+	return result
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/nezhahq/agent/model"
+	"github.com/nezhahq/agent/pkg/docker"
+	"github.com/nezhahq/agent/pkg/pty"
+	pb "github.com/nezhahq/agent/proto"
+)
+
+// 容器状态上报间隔
+const dockerReportInterval = time.Second * 30
+
+var (
+	dockerClient       *docker.Client
+	dockerClientSocket string
+	dockerClientMu     sync.Mutex
+	dockerStatus       atomic.Bool
+	lastReportDocker   time.Time
+)
+
+// getDockerClient 配置重载后套接字地址可能变化
+func getDockerClient() *docker.Client {
+	dockerClientMu.Lock()
+	defer dockerClientMu.Unlock()
+	socket := agentConfig.DockerSocket
+	if socket == "" {
+		socket = docker.DefaultSocket
+	}
+	if dockerClient == nil || dockerClientSocket != socket {
+		dockerClient = docker.NewClient(socket)
+		dockerClientSocket = socket
+	}
+	return dockerClient
+}
+
+// reportDocker 本机没有 Docker 套接字时不上报
+func reportDocker() {
+	if agentConfig.DisableDocker || client == nil || !initialized {
+		return
+	}
+	if !dockerStatus.CompareAndSwap(false, true) {
+		return
+	}
+	defer dockerStatus.Store(false)
+
+	dc := getDockerClient()
+	if !dc.Available() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dockerReportInterval)
+	defer cancel()
+	report := dc.Report(ctx)
+	// 旧版面板不支持容器上报
+	if _, err := client.ReportDocker(ctx, report); err != nil && status.Code(err) != codes.Unimplemented {
+		printf("ReportDocker error: %v", err)
+	}
+}
+
+func handleDockerTask(task *pb.Task, result *pb.TaskResult) {
+	var taskData model.TaskDocker
+	if err := json.Unmarshal([]byte(task.GetData()), &taskData); err != nil {
+		result.Data = err.Error()
+		return
+	}
+
+	data, err := doDockerTask(&taskData)
+	if err != nil {
+		printf("Docker 任务 %s %s 失败: %v", taskData.Action, taskData.ContainerID, err)
+		result.Data = err.Error()
+		return
+	}
+	result.Data = data
+	result.Successful = true
+}
+
+func doDockerTask(taskData *model.TaskDocker) (string, error) {
+	if agentConfig.DisableDocker {
+		return "", errors.New("此 Agent 已禁止容器管理")
+	}
+	// 查看日志与控制容器等同于执行命令
+	if agentConfig.DisableCommandExecute && taskData.Action != model.DockerActionInspect {
+		return "", errors.New("此 Agent 已禁止命令执行")
+	}
+	dc := getDockerClient()
+	if !dc.Available() {
+		return "", errors.New("未找到 Docker 套接字")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	switch taskData.Action {
+	case model.DockerActionStart, model.DockerActionStop, model.DockerActionRestart:
+		if err := dc.Action(ctx, taskData.ContainerID, taskData.Action); err != nil {
+			return "", err
+		}
+		// 尽快让面板看到新的状态
+		go reportDocker()
+		return "", nil
+	case model.DockerActionLogs:
+		return dc.Logs(ctx, taskData.ContainerID, taskData.Tail)
+	case model.DockerActionInspect:
+		detail, err := dc.Inspect(ctx, taskData.ContainerID)
+		if err != nil {
+			return "", err
+		}
+		data, err := json.Marshal(detail)
+		return string(data), err
+	}
+	return "", fmt.Errorf("unsupported action: %s", taskData.Action)
+}
+
+// startContainerTerminal 容器终端同样受 DisableCommandExecute 限制，由 handleTerminalTask 检查
+func startContainerTerminal(containerID string) (pty.IPty, error) {
+	if agentConfig.DisableDocker {
+		return nil, errors.New("此 Agent 已禁止容器管理")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+	return getDockerClient().Exec(ctx, containerID)
+}
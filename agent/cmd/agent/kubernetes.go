@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/nezhahq/agent/pkg/k8s"
+	pb "github.com/nezhahq/agent/proto"
+)
+
+// 集群状态上报间隔
+const kubernetesReportInterval = time.Minute
+
+var (
+	kubeClient           *k8s.Client
+	kubeClientConfig     string
+	kubeClientMu         sync.Mutex
+	kubernetesStatus     atomic.Bool
+	lastReportKubernetes time.Time
+)
+
+// getKubeClient 配置重载后 kubeconfig 路径可能变化，加载失败时下次重试
+func getKubeClient() (*k8s.Client, error) {
+	kubeClientMu.Lock()
+	defer kubeClientMu.Unlock()
+	if kubeClient != nil && kubeClientConfig == agentConfig.KubeConfig {
+		return kubeClient, nil
+	}
+	cfg, err := k8s.LoadConfig(agentConfig.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	kubeClient = k8s.NewClient(cfg)
+	kubeClientConfig = agentConfig.KubeConfig
+	return kubeClient, nil
+}
+
+// reportKubernetes 未开启采集或不在集群内且未指定 kubeconfig 时不上报
+func reportKubernetes() {
+	if !agentConfig.Kubernetes || client == nil || !initialized {
+		return
+	}
+	if agentConfig.KubeConfig == "" && !k8s.InCluster() {
+		return
+	}
+	if !kubernetesStatus.CompareAndSwap(false, true) {
+		return
+	}
+	defer kubernetesStatus.Store(false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), kubernetesReportInterval)
+	defer cancel()
+
+	var report *pb.KubernetesReport
+	kc, err := getKubeClient()
+	if err != nil {
+		report = &pb.KubernetesReport{Error: err.Error()}
+	} else {
+		report = kc.Report(ctx)
+	}
+	// 旧版面板不支持集群上报
+	if _, err := client.ReportKubernetes(ctx, report); err != nil && status.Code(err) != codes.Unimplemented {
+		printf("ReportKubernetes error: %v", err)
+	}
+}
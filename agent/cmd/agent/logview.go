@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/nezhahq/agent/model"
+	"github.com/nezhahq/agent/pkg/logview"
+	pb "github.com/nezhahq/agent/proto"
+)
+
+// 单次搜索的最长耗时
+const logSearchTimeout = time.Minute * 2
+
+// logStream 串行化日志输出与保活消息的发送
+type logStream struct {
+	pb.NezhaService_IOStreamClient
+	mu sync.Mutex
+}
+
+func (s *logStream) Send(data *pb.IOStreamData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.NezhaService_IOStreamClient.Send(data)
+}
+
+func (s *logStream) Write(p []byte) (int, error) {
+	if err := s.Send(&pb.IOStreamData{Data: append([]byte(nil), p...)}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// handleLogTask 先回报是否接受读取，面板据此记录审计，接受后再打开日志流
+func handleLogTask(task *pb.Task, result *pb.TaskResult) {
+	if agentConfig.DisableCommandExecute {
+		result.Data = "此 Agent 已禁止命令执行"
+		return
+	}
+	var logTask model.TaskLog
+	if err := json.Unmarshal([]byte(task.GetData()), &logTask); err != nil {
+		printf("日志任务解析错误: %v", err)
+		result.Data = err.Error()
+		return
+	}
+	if _, err := logview.NewFilter(logTask.Filter, logTask.Regex); err != nil {
+		result.Data = err.Error()
+		return
+	}
+
+	result.Successful = true
+	go streamLogTask(&logTask)
+}
+
+func streamLogTask(logTask *model.TaskLog) {
+	remoteIO, err := client.IOStream(context.Background())
+	if err != nil {
+		printf("日志 IOStream失败: %v", err)
+		return
+	}
+	stream := &logStream{NezhaService_IOStreamClient: remoteIO}
+
+	// 发送 StreamID
+	if err := stream.Send(&pb.IOStreamData{Data: append([]byte{
+		0xff, 0x05, 0xff, 0x05,
+	}, []byte(logTask.StreamID)...)}); err != nil {
+		printf("日志 发送StreamID失败: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go ioStreamKeepAlive(ctx, stream)
+
+	defer func() {
+		errCloseSend := stream.CloseSend()
+		println("Log exit", logTask.StreamID, nil, errCloseSend)
+	}()
+	println("Log init", logTask.StreamID, logTask.Mode, logTask.Path, logTask.Unit)
+
+	// 面板关闭会话后停止读取
+	go func() {
+		defer cancel()
+		for {
+			if _, err := stream.Recv(); err != nil {
+				return
+			}
+		}
+	}()
+
+	if err := doLogTask(ctx, stream, logTask); err != nil && ctx.Err() == nil {
+		printf("日志任务 %s 失败: %v", logTask.StreamID, err)
+		fmt.Fprintf(stream, "错误: %v\n", err)
+	}
+}
+
+func doLogTask(ctx context.Context, w io.Writer, logTask *model.TaskLog) error {
+	filter, err := logview.NewFilter(logTask.Filter, logTask.Regex)
+	if err != nil {
+		return err
+	}
+
+	switch logTask.Mode {
+	case model.LogModeFollow:
+		return logview.Follow(ctx, w, logTask.Path, logTask.Lines, filter)
+	case model.LogModeJournal:
+		return logview.Journal(ctx, w, logTask.Unit, logTask.Lines, filter)
+	case model.LogModeSearch:
+		ctx, cancel := context.WithTimeout(ctx, logSearchTimeout)
+		defer cancel()
+		result, err := logview.Search(ctx, w, logTask.Path, filter, logTask.Limit)
+		if err != nil {
+			return err
+		}
+		msg := fmt.Sprintf("-- 搜索了 %d 个文件，共 %d 行匹配", result.Files, result.Matches)
+		if result.Truncated {
+			msg += "，已达到上限，结果不完整"
+		}
+		_, err = fmt.Fprintln(w, msg+" --")
+		return err
+	}
+	return errors.New("不支持的日志模式: " + logTask.Mode)
+}
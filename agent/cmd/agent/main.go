@@ -73,6 +73,9 @@ var (
 	}
 
 	reloadSigChan = make(chan struct{})
+
+	taskStream   pb.NezhaService_RequestTaskClient // 当前的任务流，用于异步上报
+	taskStreamMu sync.Mutex
 )
 
 var (
@@ -161,6 +164,8 @@ func preRun(configPath string) error {
 
 	monitor.InitConfig(&agentConfig)
 	monitor.CustomEndpoints = agentConfig.CustomIPApi
+	startCustomMetrics(&agentConfig)
+	initBackfill(configPath)
 
 	// 智能初始化审计配置 - 默认启用
 	dashboardURL := agentConfig.AuditDashboardURL
@@ -346,6 +351,7 @@ func run() {
 		geoipReported = geoipReported && prevDashboardBootTime > 0 && dashboardBootTimeReceipt.GetData() == prevDashboardBootTime
 		prevDashboardBootTime = dashboardBootTimeReceipt.GetData()
 		initialized = true
+		uploadBackfill()
 
 		wCtx, wCancel := context.WithCancel(context.Background())
 
@@ -448,6 +454,10 @@ func runService(action string, path string) {
 }
 
 func receiveTasksDaemon(tasks pb.NezhaService_RequestTaskClient, cancel context.CancelFunc) {
+	taskStreamMu.Lock()
+	taskStream = tasks
+	taskStreamMu.Unlock()
+
 	var task *pb.Task
 	var err error
 	for {
@@ -467,7 +477,7 @@ func receiveTasksDaemon(tasks pb.NezhaService_RequestTaskClient, cancel context.
 			}()
 			result := doTask(t)
 			if result != nil {
-				if err := tasks.Send(result); err != nil {
+				if err := sendTaskResult(tasks, result); err != nil {
 					printf("send task result exit: %v", err)
 					cancel()
 				}
@@ -476,6 +486,23 @@ func receiveTasksDaemon(tasks pb.NezhaService_RequestTaskClient, cancel context.
 	}
 }
 
+// sendTaskResult 串行化任务结果的发送，任务处理与异步状态上报共用同一个流
+func sendTaskResult(tasks pb.NezhaService_RequestTaskClient, result *pb.TaskResult) error {
+	taskStreamMu.Lock()
+	defer taskStreamMu.Unlock()
+	return tasks.Send(result)
+}
+
+// reportTaskResult 通过当前的任务流主动上报结果
+func reportTaskResult(result *pb.TaskResult) error {
+	taskStreamMu.Lock()
+	defer taskStreamMu.Unlock()
+	if taskStream == nil {
+		return errors.New("任务流未连接")
+	}
+	return taskStream.Send(result)
+}
+
 func doTask(task *pb.Task) *pb.TaskResult {
 	var result pb.TaskResult
 	result.Id = task.GetId()
@@ -505,8 +532,15 @@ func doTask(task *pb.Task) *pb.TaskResult {
 	case model.TaskTypeApplyConfig:
 		handleApplyConfigTask(task)
 	case model.TaskTypeAutoSSH:
-		handleAutoSSHTask(task)
-		return nil
+		handleAutoSSHTask(task, &result)
+	case model.TaskTypeDocker:
+		handleDockerTask(task, &result)
+	case model.TaskTypeProcess:
+		handleProcessTask(task, &result)
+	case model.TaskTypeSystemd:
+		handleSystemdTask(task, &result)
+	case model.TaskTypeLog:
+		handleLogTask(task, &result)
 	case model.TaskTypeKeepalive:
 	default:
 		printf("不支持的任务: %v", task)
@@ -535,8 +569,10 @@ func reportState(statClient pb.NezhaService_ReportSystemStateClient, host, ip ti
 	}
 	if initialized {
 		monitor.TrackNetworkSpeed()
+		state := monitor.GetState(agentConfig.SkipConnectionCount, agentConfig.SkipProcsCount)
+		state.CustomMetrics = getCustomMetrics()
 		if _, err := doWithTimeout(func() (*pb.Receipt, error) {
-			return nil, statClient.Send(monitor.GetState(agentConfig.SkipConnectionCount, agentConfig.SkipProcsCount).PB())
+			return nil, statClient.Send(state.PB())
 		}, time.Second*10); err != nil {
 			return host, ip, err
 		}
@@ -551,6 +587,20 @@ func reportState(statClient pb.NezhaService_ReportSystemStateClient, host, ip ti
 			host = time.Now()
 		}
 	}
+	// 每30秒上报一次容器状态
+	if time.Since(lastReportDocker) > dockerReportInterval {
+		lastReportDocker = time.Now()
+		go reportDocker()
+	}
+	if time.Since(lastReportSystemd) > systemdReportInterval {
+		lastReportSystemd = time.Now()
+		go reportSystemd()
+	}
+	// 每分钟上报一次集群状态
+	if time.Since(lastReportKubernetes) > kubernetesReportInterval {
+		lastReportKubernetes = time.Now()
+		go reportKubernetes()
+	}
 	// 更新IP信息
 	if time.Since(ip) > time.Second*time.Duration(agentConfig.IPReportPeriod) || !geoipReported {
 		if reportGeoIP(agentConfig.UseIPv6CountryCode, !geoipReported) {
@@ -919,6 +969,7 @@ func handleApplyConfigTask(task *pb.Task) {
 		logger.SetEnable(agentConfig.Debug)
 		monitor.InitConfig(&agentConfig)
 		monitor.CustomEndpoints = agentConfig.CustomIPApi
+		startCustomMetrics(&agentConfig)
 		reloadStatus.Store(false)
 		reloadSigChan <- struct{}{}
 	})
@@ -962,40 +1013,44 @@ func handleTerminalTask(task *pb.Task) {
 	var auditClient *audit.Client
 	var wrapperPath, apiURL string
 
-	log.Printf("DEBUG: audit.IsEnabled() = %v", audit.IsEnabled())
 	if audit.IsEnabled() {
 		cfg := audit.GetConfig()
-		log.Printf("DEBUG: 审计已启用，Dashboard URL: %s", cfg.DashboardURL)
 		// 创建审计客户端
 		auditClient = audit.NewClient(cfg.DashboardURL, cfg.Token)
 
-		// 创建本地 API 服务器
-		auditServer, err = audit.NewServer(auditClient)
-		if err == nil {
-			auditServer.Start()
-			apiURL = auditServer.GetURL()
-			log.Printf("DEBUG: 审计服务器已启动: %s", apiURL)
-
-			// 创建包装器管理器
-			wrapperManager, err = audit.NewWrapperManager()
+		// 容器内无法使用命令包装器，只录像
+		if terminal.ContainerID == "" {
+			// 创建本地 API 服务器
+			auditServer, err = audit.NewServer(auditClient)
 			if err == nil {
-				wrapperPath = wrapperManager.GetWrapperPath()
-				log.Printf("DEBUG: 包装器路径: %s", wrapperPath)
+				auditServer.Start()
+				apiURL = auditServer.GetURL()
+				printf("审计服务器已启动: %s", apiURL)
+
+				// 创建包装器管理器
+				wrapperManager, err = audit.NewWrapperManager()
+				if err == nil {
+					wrapperPath = wrapperManager.GetWrapperPath()
+				} else {
+					printf("创建包装器管理器失败: %v", err)
+				}
 			} else {
-				log.Printf("DEBUG: 创建包装器管理器失败: %v", err)
+				printf("创建审计服务器失败: %v", err)
 			}
-		} else {
-			log.Printf("DEBUG: 创建审计服务器失败: %v", err)
 		}
-	} else {
-		log.Printf("DEBUG: 审计未启用")
 	}
-	log.Printf("DEBUG: wrapperPath=%s, apiURL=%s, streamID=%s", wrapperPath, apiURL, terminal.StreamID)
 
-	// 启动 PTY（带或不带审计）
-	tty, err := pty.StartWithAudit(wrapperPath, apiURL, terminal.StreamID)
+	// 启动 PTY（带或不带审计），指定容器时在容器内启动 shell
+	var tty pty.IPty
+	if terminal.ContainerID != "" {
+		tty, err = startContainerTerminal(terminal.ContainerID)
+	} else {
+		tty, err = pty.StartWithAudit(wrapperPath, apiURL, terminal.StreamID, terminal.OSUser)
+	}
 	if err != nil {
 		printf("Terminal pty.Start失败 %v", err)
+		remoteIO.Send(&pb.IOStreamData{Data: []byte(err.Error())})
+		remoteIO.CloseSend()
 		return
 	}
 
@@ -1011,6 +1066,22 @@ func handleTerminalTask(task *pb.Task) {
 		}
 	}
 
+	// 识别终端内的 trzsz / ZMODEM 文件传输，传输内容不写入录像与回滚缓冲区
+	transfer := audit.NewTransferDetector(func(ev audit.TransferEvent) {
+		if recorder != nil {
+			recorder.WriteMarker(fmt.Sprintf("%s %s started", ev.Protocol, ev.Direction))
+		}
+	}, func(ev audit.TransferEvent) {
+		if recorder != nil {
+			recorder.WriteMarker(fmt.Sprintf("%s %s finished, %d bytes", ev.Protocol, ev.Direction, ev.Bytes))
+		}
+		// ZMODEM 下载由面板接收并记录
+		if auditClient == nil || (ev.Protocol == audit.TransferProtocolZmodem && ev.Direction == audit.TransferDirectionDownload) {
+			return
+		}
+		auditClient.RecordTransfer(terminal.StreamID, ev)
+	})
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -1020,6 +1091,8 @@ func handleTerminalTask(task *pb.Task) {
 		err := tty.Close()
 		errCloseSend := remoteIO.CloseSend()
 
+		transfer.Close()
+
 		// 关闭并上传录像
 		if recorder != nil && auditClient != nil {
 			recordingPath, err := recorder.Close()
@@ -1050,20 +1123,29 @@ func handleTerminalTask(task *pb.Task) {
 	}()
 	println("terminal init", terminal.StreamID)
 
+	// 浏览器断开后 Dashboard 会保留会话，重连时重放回滚缓冲区
+	scrollback := pty.NewScrollback(pty.DefaultScrollbackSize)
+	var sendMu sync.Mutex
+
 	go func() {
 		buf := make([]byte, 10240)
 		for {
 			read, err := tty.Read(buf)
 			if err != nil {
+				sendMu.Lock()
 				remoteIO.Send(&pb.IOStreamData{Data: []byte(err.Error())})
 				remoteIO.CloseSend()
+				sendMu.Unlock()
 				return
 			}
+			sendMu.Lock()
+			output := writeScrollback(transfer, scrollback, buf[:read])
+			remoteIO.Send(&pb.IOStreamData{Data: buf[:read]})
+			sendMu.Unlock()
 			// 记录输出到录像
-			if recorder != nil {
-				recorder.WriteOutput(buf[:read])
+			if recorder != nil && len(output) > 0 {
+				recorder.WriteOutput(output)
 			}
-			remoteIO.Send(&pb.IOStreamData{Data: buf[:read]})
 		}
 	}()
 
@@ -1078,8 +1160,9 @@ func handleTerminalTask(task *pb.Task) {
 		switch remoteData.Data[0] {
 		case 0:
 			// 记录输入到录像
-			if recorder != nil {
-				recorder.WriteInput(remoteData.Data[1:])
+			input := transfer.Input(remoteData.Data[1:])
+			if recorder != nil && len(input) > 0 {
+				recorder.WriteInput(input)
 			}
 			tty.Write(remoteData.Data[1:])
 		case 1:
@@ -1090,10 +1173,25 @@ func handleTerminalTask(task *pb.Task) {
 				continue
 			}
 			tty.Setsize(resizeMessage.Cols, resizeMessage.Rows)
+		case 2:
+			// 浏览器重新连接，重放回滚缓冲区
+			sendMu.Lock()
+			remoteIO.Send(&pb.IOStreamData{Data: scrollback.Bytes()})
+			sendMu.Unlock()
 		}
 	}
 }
 
+// writeScrollback 只把文件传输之外的输出写入回滚缓冲区并返回这部分输出，
+// 重放的内容包含传输数据时面板会再次识别为新的传输
+func writeScrollback(transfer *audit.TransferDetector, scrollback *pty.Scrollback, p []byte) []byte {
+	output := transfer.Output(p)
+	if len(output) > 0 {
+		scrollback.Write(output)
+	}
+	return output
+}
+
 func handleNATTask(task *pb.Task) {
 	if agentConfig.DisableNat {
 		println("This server has disabled NAT traversal")
@@ -1121,6 +1219,11 @@ func handleNATTask(task *pb.Task) {
 		return
 	}
 
+	if nat.Network != "" {
+		handlePortForward(&nat, remoteIO)
+		return
+	}
+
 	conn, err := net.Dial("tcp", nat.Host)
 	if err != nil {
 		printf("NAT Dial %s 失败：%s", nat.Host, err)
@@ -1258,161 +1361,3 @@ func doWithTimeout[T any](fn func() (T, error), timeout time.Duration) (T, error
 	return t, err
 }
 
-// AutoSSH 进程管理
-var (
-	autosshProcesses = make(map[uint64]*exec.Cmd)
-	autosshMutex     sync.RWMutex
-)
-
-func handleAutoSSHTask(task *pb.Task) {
-	var taskData model.TaskAutoSSH
-	if err := json.Unmarshal([]byte(task.GetData()), &taskData); err != nil {
-		printf("AutoSSH 任务解析失败: %v", err)
-		return
-	}
-
-	printf("AutoSSH 任务: action=%s, mapping_id=%d, type=%s, source_port=%d, target=%s:%d",
-		taskData.Action, taskData.MappingID, taskData.MappingType, 
-		taskData.SourcePort, taskData.TargetHost, taskData.TargetPort)
-
-	switch taskData.Action {
-	case "start":
-		startAutoSSH(&taskData)
-	case "stop":
-		stopAutoSSH(taskData.MappingID)
-	case "status":
-		checkAutoSSHStatus(taskData.MappingID)
-	default:
-		printf("不支持的 AutoSSH 操作: %s", taskData.Action)
-	}
-}
-
-func startAutoSSH(taskData *model.TaskAutoSSH) {
-	autosshMutex.Lock()
-	defer autosshMutex.Unlock()
-
-	// 如果已经存在，先停止
-	if cmd, exists := autosshProcesses[taskData.MappingID]; exists {
-		if cmd.Process != nil {
-			cmd.Process.Kill()
-		}
-		delete(autosshProcesses, taskData.MappingID)
-	}
-
-	// 构建 autossh 命令参数
-	args := []string{
-		"-M", "0", // 不使用监控端口
-		"-o", "ServerAliveInterval=30",
-		"-o", "ServerAliveCountMax=3",
-		"-o", "StrictHostKeyChecking=no",
-		"-o", "UserKnownHostsFile=/dev/null",
-		"-N", // 不执行远程命令
-		"-T", // 禁用伪终端分配
-	}
-
-	// 添加自定义 SSH 选项
-	for key, value := range taskData.SSHOptions {
-		args = append(args, "-o", fmt.Sprintf("%s=%s", key, value))
-	}
-
-	// 根据映射类型添加端口转发参数
-	if taskData.MappingType == "remote" {
-		// 远程转发: -R [源端口]:[目标主机]:[目标端口]
-		args = append(args, "-R", fmt.Sprintf("%d:%s:%d",
-			taskData.SourcePort, taskData.TargetHost, taskData.TargetPort))
-	} else {
-		// 本地转发: -L [绑定地址]:[源端口]:[目标主机]:[目标端口]
-		// 绑定到 0.0.0.0 以允许外部访问
-		args = append(args, "-L", fmt.Sprintf("0.0.0.0:%d:%s:%d",
-			taskData.SourcePort, taskData.TargetHost, taskData.TargetPort))
-	}
-
-	// 使用配置的 SSH 服务器地址
-	sshHost := taskData.SSHHost
-	if sshHost == "" {
-		printf("AutoSSH 启动失败: SSH 服务器地址未配置")
-		return
-	}
-
-	// 解析 SSH 服务器地址格式: user@host:port
-	// 分离端口
-	var host, port string
-	if idx := strings.LastIndex(sshHost, ":"); idx != -1 {
-		host = sshHost[:idx]
-		port = sshHost[idx+1:]
-		// 添加端口参数
-		args = append(args, "-p", port)
-	} else {
-		host = sshHost
-	}
-
-	args = append(args, host)
-
-	// 创建命令
-	cmd := exec.Command("autossh", args...)
-	cmd.Env = os.Environ()
-
-	// 添加调试输出
-	printf("AutoSSH 命令: autossh %v", args)
-
-	// 启动进程
-	if err := cmd.Start(); err != nil {
-		printf("AutoSSH 启动失败 (mapping_id=%d): %v", taskData.MappingID, err)
-		return
-	}
-
-	// 保存进程
-	autosshProcesses[taskData.MappingID] = cmd
-	printf("AutoSSH 启动成功 (mapping_id=%d, pid=%d)", taskData.MappingID, cmd.Process.Pid)
-
-	// 在后台监控进程状态
-	go func() {
-		err := cmd.Wait()
-		autosshMutex.Lock()
-		delete(autosshProcesses, taskData.MappingID)
-		autosshMutex.Unlock()
-		if err != nil {
-			printf("AutoSSH 进程退出 (mapping_id=%d): %v", taskData.MappingID, err)
-		} else {
-			printf("AutoSSH 进程正常退出 (mapping_id=%d)", taskData.MappingID)
-		}
-	}()
-}
-
-func stopAutoSSH(mappingID uint64) {
-	autosshMutex.Lock()
-	defer autosshMutex.Unlock()
-
-	cmd, exists := autosshProcesses[mappingID]
-	if !exists {
-		printf("AutoSSH 进程不存在 (mapping_id=%d)", mappingID)
-		return
-	}
-
-	if cmd.Process != nil {
-		if err := cmd.Process.Kill(); err != nil {
-			printf("AutoSSH 停止失败 (mapping_id=%d): %v", mappingID, err)
-		} else {
-			printf("AutoSSH 已停止 (mapping_id=%d)", mappingID)
-		}
-	}
-
-	delete(autosshProcesses, mappingID)
-}
-
-func checkAutoSSHStatus(mappingID uint64) {
-	autosshMutex.RLock()
-	defer autosshMutex.RUnlock()
-
-	cmd, exists := autosshProcesses[mappingID]
-	if !exists {
-		printf("AutoSSH 状态: 未运行 (mapping_id=%d)", mappingID)
-		return
-	}
-
-	if cmd.Process != nil {
-		printf("AutoSSH 状态: 运行中 (mapping_id=%d, pid=%d)", mappingID, cmd.Process.Pid)
-	} else {
-		printf("AutoSSH 状态: 异常 (mapping_id=%d)", mappingID)
-	}
-}
@@ -1,10 +1,16 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"net"
 	"os"
 	"testing"
+
+	"github.com/nezhahq/agent/model"
+	"github.com/nezhahq/agent/pkg/audit"
+	"github.com/nezhahq/agent/pkg/pty"
+	pb "github.com/nezhahq/agent/proto"
 )
 
 func TestLookupIP(t *testing.T) {
@@ -32,3 +38,44 @@ func TestLookupIP(t *testing.T) {
 		t.Errorf("ResolveIPAddr failed: %v", err)
 	}
 }
+
+func TestWriteScrollbackSkipsTransfer(t *testing.T) {
+	transfer := audit.NewTransferDetector(nil, nil)
+	scrollback := pty.NewScrollback(pty.DefaultScrollbackSize)
+
+	writeScrollback(transfer, scrollback, []byte("$ sz a.bin\r\n**\x18B00000000000000\r\x8a\x11"))
+	writeScrollback(transfer, scrollback, []byte("**\x18B0a000000000000\r\x8a*\x18Cbinary\x00\x01"))
+	// 面板回复的 ZFIN 经终端输入写入
+	transfer.Input([]byte("**\x18B0800000000022d\r\x8a"))
+	writeScrollback(transfer, scrollback, []byte("**\x18B0800000000022d\r\x8a"))
+	writeScrollback(transfer, scrollback, []byte("OO$ "))
+	if transfer.Active() {
+		t.Fatal("transfer should be finished")
+	}
+
+	// 重新连接后重放的内容不能再次触发传输
+	replay := scrollback.Bytes()
+	if bytes.Contains(replay, []byte("\x18B0")) || bytes.Contains(replay, []byte("binary")) {
+		t.Fatalf("transfer data kept in scrollback: %q", replay)
+	}
+	if !bytes.Equal(replay, []byte("$ sz a.bin\r\n$ ")) {
+		t.Fatalf("unexpected scrollback: %q", replay)
+	}
+	replayed := audit.NewTransferDetector(nil, nil)
+	replayed.Output(replay)
+	if replayed.Active() {
+		t.Fatal("replayed scrollback started a new transfer")
+	}
+}
+
+func TestHandleLogTaskRefused(t *testing.T) {
+	agentConfig.DisableCommandExecute = true
+	defer func() { agentConfig.DisableCommandExecute = false }()
+
+	// 被拒绝时直接回报失败，不打开日志流
+	var result pb.TaskResult
+	handleLogTask(&pb.Task{Type: model.TaskTypeLog, Data: `{"stream_id":"s","mode":"journal","unit":"nginx.service"}`}, &result)
+	if result.Successful || result.Data == "" {
+		t.Fatalf("expected log task to be refused, got %+v", &result)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+
+	"github.com/nezhahq/agent/model"
+	pb "github.com/nezhahq/agent/proto"
+)
+
+// UDP 数据报在流中以 2 字节长度前缀分帧
+const portForwardMaxDatagram = 65535
+
+// handlePortForward 将面板端口转发的连接接到目标地址，与 HTTP NAT 不同，出错时不向流中写入错误信息
+func handlePortForward(nat *model.TaskNAT, remoteIO pb.NezhaService_IOStreamClient) {
+	if nat.Network != "tcp" && nat.Network != "udp" {
+		printf("端口转发不支持的协议: %s", nat.Network)
+		remoteIO.CloseSend()
+		return
+	}
+
+	conn, err := net.Dial(nat.Network, nat.Host)
+	if err != nil {
+		printf("端口转发 Dial %s/%s 失败：%s", nat.Host, nat.Network, err)
+		remoteIO.CloseSend()
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go ioStreamKeepAlive(ctx, remoteIO)
+
+	defer func() {
+		err := conn.Close()
+		errCloseSend := remoteIO.CloseSend()
+		println("端口转发 exit", nat.StreamID, err, errCloseSend)
+	}()
+	println("端口转发 init", nat.StreamID, nat.Network, nat.Host)
+
+	udp := nat.Network == "udp"
+	go func() {
+		defer cancel()
+		buf := make([]byte, portForwardMaxDatagram)
+		for {
+			read, err := conn.Read(buf)
+			if err != nil {
+				remoteIO.CloseSend()
+				return
+			}
+			data := buf[:read]
+			if udp {
+				data = encodeUDPFrame(data)
+			}
+			if err := remoteIO.Send(&pb.IOStreamData{Data: data}); err != nil {
+				return
+			}
+		}
+	}()
+
+	var frames udpFrameReader
+	for {
+		remoteData, err := remoteIO.Recv()
+		if err != nil {
+			return
+		}
+		if !udp {
+			if _, err := conn.Write(remoteData.Data); err != nil {
+				return
+			}
+			continue
+		}
+		for _, datagram := range frames.Feed(remoteData.Data) {
+			conn.Write(datagram)
+		}
+	}
+}
+
+func encodeUDPFrame(datagram []byte) []byte {
+	frame := make([]byte, 2+len(datagram))
+	binary.BigEndian.PutUint16(frame, uint16(len(datagram)))
+	copy(frame[2:], datagram)
+	return frame
+}
+
+// udpFrameReader 从流数据中取出完整的数据报，不完整的帧留到下次
+type udpFrameReader struct {
+	partial []byte
+}
+
+func (r *udpFrameReader) Feed(data []byte) [][]byte {
+	r.partial = append(r.partial, data...)
+	var datagrams [][]byte
+	for len(r.partial) >= 2 {
+		size := int(binary.BigEndian.Uint16(r.partial))
+		if len(r.partial) < 2+size {
+			break
+		}
+		datagrams = append(datagrams, r.partial[2:2+size:2+size])
+		r.partial = r.partial[2+size:]
+	}
+	if len(r.partial) == 0 {
+		r.partial = nil
+	}
+	return datagrams
+}
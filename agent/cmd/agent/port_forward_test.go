@@ -0,0 +1,31 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestUDPFrameReader(t *testing.T) {
+	stream := append(encodeUDPFrame([]byte("hello")), encodeUDPFrame([]byte{})...)
+	stream = append(stream, encodeUDPFrame([]byte("world"))...)
+
+	var r udpFrameReader
+	var got [][]byte
+	// 逐字节送入，模拟帧被拆分到多个 IOStreamData 中
+	for i := range stream {
+		got = append(got, r.Feed(stream[i:i+1])...)
+	}
+
+	want := [][]byte{[]byte("hello"), {}, []byte("world")}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d datagrams, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if !bytes.Equal(got[i], want[i]) {
+			t.Errorf("datagram %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+	if r.partial != nil {
+		t.Errorf("expected no pending bytes, got %v", r.partial)
+	}
+}
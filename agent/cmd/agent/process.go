@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nezhahq/agent/model"
+	"github.com/nezhahq/agent/pkg/proc"
+	pb "github.com/nezhahq/agent/proto"
+)
+
+func handleProcessTask(task *pb.Task, result *pb.TaskResult) {
+	var taskData model.TaskProcess
+	if err := json.Unmarshal([]byte(task.GetData()), &taskData); err != nil {
+		result.Data = err.Error()
+		return
+	}
+
+	data, err := doProcessTask(&taskData)
+	if err != nil {
+		printf("进程任务 %s %d 失败: %v", taskData.Action, taskData.PID, err)
+		result.Data = err.Error()
+		return
+	}
+	result.Data = data
+	result.Successful = true
+}
+
+func doProcessTask(taskData *model.TaskProcess) (string, error) {
+	switch taskData.Action {
+	case model.ProcessActionList:
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*20)
+		defer cancel()
+		procs, err := proc.Top(ctx, taskData.Sort, taskData.Limit)
+		if err != nil {
+			return "", err
+		}
+		data, err := json.Marshal(procs)
+		return string(data), err
+	case model.ProcessActionSignal:
+		// 向进程发送信号等同于执行命令
+		if agentConfig.DisableCommandExecute {
+			return "", errors.New("此 Agent 已禁止命令执行")
+		}
+		printf("向进程 %d 发送信号 %s", taskData.PID, taskData.Signal)
+		return "", proc.Signal(taskData.PID, taskData.Signal)
+	}
+	return "", fmt.Errorf("unsupported action: %s", taskData.Action)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/nezhahq/agent/model"
+	"github.com/nezhahq/agent/pkg/systemd"
+	pb "github.com/nezhahq/agent/proto"
+)
+
+// systemd 单元状态上报间隔
+const systemdReportInterval = time.Second * 30
+
+var (
+	systemdStatus     atomic.Bool
+	lastReportSystemd time.Time
+)
+
+// reportSystemd 非 systemd 主机不上报
+func reportSystemd() {
+	if agentConfig.DisableSystemd || client == nil || !initialized || !systemd.Available() {
+		return
+	}
+	if !systemdStatus.CompareAndSwap(false, true) {
+		return
+	}
+	defer systemdStatus.Store(false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), systemdReportInterval)
+	defer cancel()
+	report := systemd.Report(ctx, agentConfig.SystemdUnits)
+	// 旧版面板不支持 systemd 上报
+	if _, err := client.ReportSystemd(ctx, report); err != nil && status.Code(err) != codes.Unimplemented {
+		printf("ReportSystemd error: %v", err)
+	}
+}
+
+func handleSystemdTask(task *pb.Task, result *pb.TaskResult) {
+	var taskData model.TaskSystemd
+	if err := json.Unmarshal([]byte(task.GetData()), &taskData); err != nil {
+		result.Data = err.Error()
+		return
+	}
+
+	if err := doSystemdTask(&taskData); err != nil {
+		printf("systemd 任务 %s %s 失败: %v", taskData.Action, taskData.Unit, err)
+		result.Data = err.Error()
+		return
+	}
+	result.Successful = true
+}
+
+func doSystemdTask(taskData *model.TaskSystemd) error {
+	if agentConfig.DisableSystemd {
+		return errors.New("此 Agent 已禁止 systemd 管理")
+	}
+	// 控制单元等同于执行命令
+	if agentConfig.DisableCommandExecute {
+		return errors.New("此 Agent 已禁止命令执行")
+	}
+	if !systemd.Available() {
+		return errors.New("此主机未使用 systemd")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*2)
+	defer cancel()
+	if err := systemd.Action(ctx, taskData.Unit, taskData.Action); err != nil {
+		return err
+	}
+	// 尽快让面板看到新的状态
+	go reportSystemd()
+	return nil
+}
@@ -43,6 +43,15 @@ type AgentConfig struct {
 	IPReportPeriod              uint32          `koanf:"ip_report_period" json:"ip_report_period"`               // IP上报周期
 	SelfUpdatePeriod            uint32          `koanf:"self_update_period" json:"self_update_period"`           // 自动更新周期
 	CustomIPApi                 []string        `koanf:"custom_ip_api" json:"custom_ip_api,omitempty"`           // 自定义 IP API                      // 重载间隔
+	DisableDocker               bool            `koanf:"disable_docker" json:"disable_docker"`                   // 关闭容器监控与管理
+	DockerSocket                string          `koanf:"docker_socket" json:"docker_socket,omitempty"`           // Docker Engine API 套接字，默认 /var/run/docker.sock
+	Kubernetes                  bool            `koanf:"kubernetes" json:"kubernetes"`                           // 采集 Kubernetes 集群状态
+	KubeConfig                  string          `koanf:"kubeconfig" json:"kubeconfig,omitempty"`                 // kubeconfig 路径，为空时使用集群内的 ServiceAccount
+	CustomMetrics               []CustomMetricConfig `koanf:"custom_metrics" json:"custom_metrics,omitempty"`   // 自定义指标采集器
+	DisableSystemd              bool            `koanf:"disable_systemd" json:"disable_systemd"`                 // 关闭 systemd 单元监控与管理
+	SystemdUnits                []string        `koanf:"systemd_units" json:"systemd_units,omitempty"`           // 上报的 systemd 单元，支持通配符，为空时上报全部 service
+	DisableBackfill             bool            `koanf:"disable_backfill" json:"disable_backfill"`               // 关闭断线期间的状态缓存与补传
+	BackfillBufferSize          uint32          `koanf:"backfill_buffer_size" json:"backfill_buffer_size,omitempty"` // 断线期间最多缓存的状态数，每 30 秒一条，默认 2880
 
 	// 审计配置
 	AuditEnabled      bool   `koanf:"audit_enabled" json:"audit_enabled"`           // 是否启用终端审计
@@ -128,6 +137,15 @@ func (c *AgentConfig) Save() error {
 	return os.WriteFile(c.filePath, data, 0600)
 }
 
+// CustomMetricConfig 自定义指标采集器，Command 与 Textfile 二选一
+type CustomMetricConfig struct {
+	Name     string `koanf:"name" json:"name"`                   // 命令只输出一个数值时作为指标名称
+	Command  string `koanf:"command" json:"command,omitempty"`   // 执行的命令，输出单个数值或 Prometheus 文本格式
+	Textfile string `koanf:"textfile" json:"textfile,omitempty"` // Prometheus textfile 路径，支持通配符
+	Interval uint32 `koanf:"interval" json:"interval,omitempty"` // 采集间隔（秒），默认 60
+	Timeout  uint32 `koanf:"timeout" json:"timeout,omitempty"`   // 命令超时（秒），默认 10
+}
+
 func ValidateConfig(c *AgentConfig, isRemoteEdit bool) error {
 	if c.ReportDelay == 0 {
 		c.ReportDelay = 3
@@ -143,6 +161,12 @@ func ValidateConfig(c *AgentConfig, isRemoteEdit bool) error {
 		return errors.New("report-delay ranges from 1-4")
 	}
 
+	for i, m := range c.CustomMetrics {
+		if (m.Command == "") == (m.Textfile == "") {
+			return fmt.Errorf("custom_metrics[%d]: exactly one of command and textfile must be specified", i)
+		}
+	}
+
 	if !isRemoteEdit {
 		if c.Server == "" {
 			return errors.New("server address should not be empty")
@@ -21,3 +21,21 @@ func TestJsonUnmarshalConfig(t *testing.T) {
 		t.Errorf("json unmarshal failed: %v", conf.Debug)
 	}
 }
+
+func TestValidateCustomMetrics(t *testing.T) {
+	cases := []struct {
+		metric CustomMetricConfig
+		ok     bool
+	}{
+		{CustomMetricConfig{Name: "queue", Command: "echo 1"}, true},
+		{CustomMetricConfig{Textfile: "/var/lib/node_exporter/*.prom"}, true},
+		{CustomMetricConfig{Name: "empty"}, false},
+		{CustomMetricConfig{Command: "echo 1", Textfile: "/tmp/a.prom"}, false},
+	}
+	for _, c := range cases {
+		conf := AgentConfig{CustomMetrics: []CustomMetricConfig{c.metric}}
+		if err := ValidateConfig(&conf, true); (err == nil) != c.ok {
+			t.Errorf("ValidateConfig(%+v) = %v", c.metric, err)
+		}
+	}
+}
@@ -9,6 +9,47 @@ type SensorTemperature struct {
 	Temperature float64
 }
 
+// MountState 单个挂载点的空间与 inode 使用情况
+type MountState struct {
+	Mountpoint  string
+	Device      string
+	Fstype      string
+	Total       uint64
+	Used        uint64
+	InodesTotal uint64
+	InodesUsed  uint64
+}
+
+// DiskIOState 块设备每秒的读写字节数与次数
+type DiskIOState struct {
+	Device     string
+	ReadSpeed  uint64
+	WriteSpeed uint64
+	ReadIOPS   uint64
+	WriteIOPS  uint64
+}
+
+// NICState 单个网卡的速度与累计计数，ErrorRate 为每秒收发错误数
+type NICState struct {
+	Name        string
+	InSpeed     uint64
+	OutSpeed    uint64
+	InTransfer  uint64
+	OutTransfer uint64
+	InErrors    uint64
+	OutErrors   uint64
+	InDrops     uint64
+	OutDrops    uint64
+	ErrorRate   float64
+}
+
+// CustomMetric 自定义采集器上报的指标，Name 包含标签
+type CustomMetric struct {
+	Name  string
+	Value float64
+	Type  string
+}
+
 type HostState struct {
 	CPU            float64
 	MemUsed        uint64
@@ -27,6 +68,10 @@ type HostState struct {
 	ProcessCount   uint64
 	Temperatures   []SensorTemperature
 	GPU            []float64
+	Mounts         []MountState
+	DiskIO         []DiskIOState
+	NICs           []NICState
+	CustomMetrics  []CustomMetric
 }
 
 func (s *HostState) PB() *pb.State {
@@ -38,6 +83,55 @@ func (s *HostState) PB() *pb.State {
 		})
 	}
 
+	mounts := make([]*pb.State_Mount, 0, len(s.Mounts))
+	for _, m := range s.Mounts {
+		mounts = append(mounts, &pb.State_Mount{
+			Mountpoint:  m.Mountpoint,
+			Device:      m.Device,
+			Fstype:      m.Fstype,
+			Total:       m.Total,
+			Used:        m.Used,
+			InodesTotal: m.InodesTotal,
+			InodesUsed:  m.InodesUsed,
+		})
+	}
+
+	diskIO := make([]*pb.State_DiskIO, 0, len(s.DiskIO))
+	for _, d := range s.DiskIO {
+		diskIO = append(diskIO, &pb.State_DiskIO{
+			Device:     d.Device,
+			ReadSpeed:  d.ReadSpeed,
+			WriteSpeed: d.WriteSpeed,
+			ReadIops:   d.ReadIOPS,
+			WriteIops:  d.WriteIOPS,
+		})
+	}
+
+	nics := make([]*pb.State_NIC, 0, len(s.NICs))
+	for _, n := range s.NICs {
+		nics = append(nics, &pb.State_NIC{
+			Name:        n.Name,
+			InSpeed:     n.InSpeed,
+			OutSpeed:    n.OutSpeed,
+			InTransfer:  n.InTransfer,
+			OutTransfer: n.OutTransfer,
+			InErrors:    n.InErrors,
+			OutErrors:   n.OutErrors,
+			InDrops:     n.InDrops,
+			OutDrops:    n.OutDrops,
+			ErrorRate:   n.ErrorRate,
+		})
+	}
+
+	customMetrics := make([]*pb.State_CustomMetric, 0, len(s.CustomMetrics))
+	for _, m := range s.CustomMetrics {
+		customMetrics = append(customMetrics, &pb.State_CustomMetric{
+			Name:  m.Name,
+			Value: m.Value,
+			Type:  m.Type,
+		})
+	}
+
 	return &pb.State{
 		Cpu:            s.CPU,
 		MemUsed:        s.MemUsed,
@@ -56,6 +150,10 @@ func (s *HostState) PB() *pb.State {
 		ProcessCount:   s.ProcessCount,
 		Temperatures:   ts,
 		Gpu:            s.GPU,
+		Mounts:         mounts,
+		DiskIo:         diskIO,
+		Nics:           nics,
+		CustomMetrics:  customMetrics,
 	}
 }
 
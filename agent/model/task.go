@@ -16,28 +16,140 @@ const (
 	TaskTypeReportConfig
 	TaskTypeApplyConfig
 	TaskTypeAutoSSH
+	TaskTypeTerminalCommand
+	TaskTypeCommandCheck
+	TaskTypeDocker
+	TaskTypeProcess
+	TaskTypeSystemd
+	TaskTypeLog
 )
 
 type TerminalTask struct {
-	StreamID string
+	StreamID    string
+	OSUser      string // 以该系统用户身份启动终端，为空时使用 Agent 运行用户
+	ContainerID string `json:",omitempty"` // 不为空时在该容器内打开终端
 }
 
 type TaskNAT struct {
 	StreamID string
 	Host     string
+	Network  string `json:",omitempty"` // 为空时转发 HTTP 请求，tcp/udp 时为端口转发
 }
 
 type TaskFM struct {
 	StreamID string
 }
 
+// TaskDocker.Action
+const (
+	DockerActionStart   = "start"
+	DockerActionStop    = "stop"
+	DockerActionRestart = "restart"
+	DockerActionLogs    = "logs"
+	DockerActionInspect = "inspect"
+)
+
+type TaskDocker struct {
+	Action      string `json:"action"`
+	ContainerID string `json:"container_id"`
+	Tail        int    `json:"tail,omitempty"` // logs 返回的最大行数
+}
+
+// TaskProcess.Action
+const (
+	ProcessActionList   = "list"
+	ProcessActionSignal = "signal"
+)
+
+type TaskProcess struct {
+	Action string `json:"action"`
+	Sort   string `json:"sort,omitempty"`  // cpu 或 memory
+	Limit  int    `json:"limit,omitempty"` // list 返回的最大进程数
+	PID    int32  `json:"pid,omitempty"`
+	Signal string `json:"signal,omitempty"` // 如 TERM、KILL
+}
+
+// TaskSystemd.Action 取值见 pkg/systemd
+type TaskSystemd struct {
+	Action string `json:"action"`
+	Unit   string `json:"unit"`
+}
+
+// TaskLog.Mode
+const (
+	LogModeFollow  = "follow"  // 跟随文件
+	LogModeJournal = "journal" // 跟随 systemd 单元的日志
+	LogModeSearch  = "search"  // 在文件及其轮转文件中搜索
+)
+
+type TaskLog struct {
+	StreamID string `json:"stream_id"`
+	Mode     string `json:"mode"`
+	Path     string `json:"path,omitempty"`
+	Unit     string `json:"unit,omitempty"`
+	Filter   string `json:"filter,omitempty"`
+	Regex    bool   `json:"regex,omitempty"`
+	Lines    int    `json:"lines,omitempty"` // 跟随前输出的历史行数
+	Limit    int    `json:"limit,omitempty"` // search 返回的最大行数
+}
+
 type TaskAutoSSH struct {
-	Action      string            `json:"action"` // start, stop, status
+	Action      string            `json:"action"` // start, stop, status, sync
 	MappingID   uint64            `json:"mapping_id"`
-	MappingType string            `json:"mapping_type"` // local or remote
+	MappingType string            `json:"mapping_type"` // local, remote or dynamic
 	SourcePort  int               `json:"source_port"`
 	TargetHost  string            `json:"target_host"`
 	TargetPort  int               `json:"target_port"`
 	SSHHost     string            `json:"ssh_host"`      // SSH 服务器地址，格式：user@host:port
 	SSHOptions  map[string]string `json:"ssh_options,omitempty"`
+
+	// 动态转发（SOCKS5）的认证信息，为空时不需要认证
+	SocksUsername string `json:"socks_username,omitempty"`
+	SocksPassword string `json:"socks_password,omitempty"`
+
+	HostKeyFingerprint string `json:"host_key_fingerprint,omitempty"` // 固定的 SSH 服务器主机密钥指纹，为空时不校验
+	PrivateKey         string `json:"private_key,omitempty"`          // 面板下发的隧道私钥，只保存在内存中
+	RateLimit          uint64 `json:"rate_limit,omitempty"`           // 每个方向的限速（字节/秒），0 为不限速
+
+	BindAddress    string   `json:"bind_address,omitempty"`    // local 与 dynamic 映射的监听地址
+	AllowedSources []string `json:"allowed_sources,omitempty"` // 只接受来自这些 CIDR 的连接
+
+	Restore    bool     `json:"restore,omitempty"`     // 恢复任务，配置相同的隧道仍在运行时不重启
+	MappingIDs []uint64 `json:"mapping_ids,omitempty"` // sync 时应当运行的映射，其余隧道将被停止
+}
+
+const (
+	AutoSSHStatusStopped = "stopped"
+	AutoSSHStatusRunning = "running"
+	AutoSSHStatusError   = "error"
+)
+
+// AutoSSHStatusReport.Event
+const (
+	AutoSSHEventStart   = "start"
+	AutoSSHEventStop    = "stop"
+	AutoSSHEventStatus  = "status"
+	AutoSSHEventExit    = "exit"
+	AutoSSHEventRestart = "restart"
+	AutoSSHEventSync    = "sync"
+)
+
+// AutoSSHStatusReport 通过任务流回报给 Dashboard 的映射状态
+type AutoSSHStatusReport struct {
+	MappingID uint64 `json:"mapping_id"`
+	Event     string `json:"event,omitempty"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	PID       int    `json:"pid,omitempty"`
+	Restarts  int    `json:"restarts,omitempty"`
+
+	ErrorCode           string `json:"error_code,omitempty"` // 出错阶段：config、dial、handshake、auth、listen、keepalive、closed
+	Connections         uint64 `json:"connections,omitempty"`
+	ActiveConnections   int64  `json:"active_connections,omitempty"`
+	FailedConnections   uint64 `json:"failed_connections,omitempty"`
+	RejectedConnections uint64 `json:"rejected_connections,omitempty"`
+	BytesIn             uint64 `json:"bytes_in,omitempty"`
+	BytesOut            uint64 `json:"bytes_out,omitempty"`
+
+	HostKeyFingerprint string `json:"host_key_fingerprint,omitempty"` // 实际连接到的主机密钥指纹
 }
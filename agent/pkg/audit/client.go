@@ -35,6 +35,17 @@ type CommandRecordRequest struct {
 	ExitCode   int    `json:"exit_code"`
 }
 
+// TransferRecordRequest 文件传输记录请求
+type TransferRecordRequest struct {
+	StreamID  string    `json:"stream_id"`
+	Protocol  string    `json:"protocol"`
+	Direction string    `json:"direction"`
+	Bytes     int64     `json:"bytes"`
+	StartedAt time.Time `json:"started_at"`
+	EndedAt   time.Time `json:"ended_at"`
+	Aborted   bool      `json:"aborted"`
+}
+
 // CommonResponse 通用响应
 type CommonResponse struct {
 	Success bool   `json:"success"`
@@ -138,3 +149,39 @@ func (c *Client) RecordCommand(streamID, command, workingDir string, exitCode in
 		defer resp.Body.Close()
 	}()
 }
+
+// RecordTransfer 记录终端内的文件传输（异步）
+func (c *Client) RecordTransfer(streamID string, ev TransferEvent) {
+	go func() {
+		req := TransferRecordRequest{
+			StreamID:  streamID,
+			Protocol:  ev.Protocol,
+			Direction: ev.Direction,
+			Bytes:     ev.Bytes,
+			StartedAt: ev.StartedAt,
+			EndedAt:   ev.EndedAt,
+			Aborted:   ev.Aborted,
+		}
+
+		data, err := json.Marshal(req)
+		if err != nil {
+			return
+		}
+
+		httpReq, err := http.NewRequest("POST", c.dashboardURL+"/api/v1/terminal/record-transfer", bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+
+		httpReq.Header.Set("Content-Type", "application/json")
+		if c.token != "" {
+			httpReq.Header.Set("Authorization", "Bearer "+c.token)
+		}
+
+		resp, err := c.httpClient.Do(httpReq)
+		if err != nil {
+			return
+		}
+		defer resp.Body.Close()
+	}()
+}
@@ -100,6 +100,11 @@ func (r *Recorder) WriteInput(data []byte) error {
 	return r.writeEvent("i", data)
 }
 
+// WriteMarker 写入标记事件，用于标注录像中被省略的片段
+func (r *Recorder) WriteMarker(label string) error {
+	return r.writeEvent("m", []byte(label))
+}
+
 // writeEvent 写入事件
 func (r *Recorder) writeEvent(eventType string, data []byte) error {
 	r.mu.Lock()
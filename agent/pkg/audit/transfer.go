@@ -0,0 +1,233 @@
+package audit
+
+import (
+	"bytes"
+	"sync"
+	"time"
+)
+
+const (
+	TransferProtocolZmodem = "zmodem"
+	TransferProtocolTrzsz  = "trzsz"
+
+	TransferDirectionDownload = "download" // 服务器 -> 浏览器（sz / tsz）
+	TransferDirectionUpload   = "upload"   // 浏览器 -> 服务器（rz / trz）
+)
+
+var (
+	zmodemZRQINIT = []byte("**\x18B00") // sz 发起
+	zmodemZRINIT  = []byte("**\x18B01") // rz 发起
+	zmodemZFIN    = []byte("**\x18B08")
+	zmodemAbort   = []byte("\x18\x18\x18\x18\x18")
+	zmodemOver    = []byte("OO")
+
+	trzszDownload = []byte("::TRZSZ:TRANSFER:S:")
+	trzszDirDown  = []byte("::TRZSZ:TRANSFER:D:")
+	trzszUpload   = []byte("::TRZSZ:TRANSFER:R:")
+	trzszExit     = [][]byte{[]byte("#EXIT:"), []byte("#FAIL:"), []byte("#fail:")}
+)
+
+const (
+	// 跨数据块匹配握手标记时保留的尾部长度
+	transferTailSize = 24
+	// ZMODEM 十六进制头中类型之后的长度
+	zmodemHexHeaderTail = 14
+)
+
+// TransferEvent 一次终端内文件传输
+type TransferEvent struct {
+	Protocol  string
+	Direction string
+	Bytes     int64
+	StartedAt time.Time
+	EndedAt   time.Time
+	Aborted   bool
+}
+
+// TransferDetector 识别终端流中的 trzsz / ZMODEM 传输，传输期间的二进制数据不写入录像
+type TransferDetector struct {
+	mu      sync.Mutex
+	active  *TransferEvent
+	zfinOut bool
+	zfinIn  bool
+	skipOut bool // 传输结束后跳过紧随的 "OO"
+	skipIn  bool
+	tailOut []byte
+	tailIn  []byte
+
+	onStart func(TransferEvent)
+	onEnd   func(TransferEvent)
+}
+
+func NewTransferDetector(onStart, onEnd func(TransferEvent)) *TransferDetector {
+	return &TransferDetector{onStart: onStart, onEnd: onEnd}
+}
+
+// Output 处理终端输出，返回应写入录像的部分
+func (d *TransferDetector) Output(p []byte) []byte {
+	return d.process(p, true)
+}
+
+// Input 处理用户输入，返回应写入录像的部分
+func (d *TransferDetector) Input(p []byte) []byte {
+	return d.process(p, false)
+}
+
+// Active 当前是否正在传输文件
+func (d *TransferDetector) Active() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.active != nil
+}
+
+// Close 会话结束时仍未完成的传输记为中断
+func (d *TransferDetector) Close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.active != nil {
+		d.finish(true)
+	}
+}
+
+func (d *TransferDetector) process(p []byte, output bool) []byte {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.active == nil {
+		p = d.skipOver(p, output)
+	}
+
+	tail := &d.tailIn
+	if output {
+		tail = &d.tailOut
+	}
+	window := append(append([]byte(nil), *tail...), p...)
+	offset := len(*tail)
+	if len(window) > transferTailSize {
+		*tail = append((*tail)[:0], window[len(window)-transferTailSize:]...)
+	} else {
+		*tail = append((*tail)[:0], window...)
+	}
+
+	if d.active == nil {
+		idx, protocol, direction := detectTransferStart(window, output)
+		if idx < 0 {
+			return p
+		}
+		start := max(idx-offset, 0)
+		d.active = &TransferEvent{
+			Protocol:  protocol,
+			Direction: direction,
+			StartedAt: time.Now(),
+		}
+		d.zfinOut, d.zfinIn = false, false
+		if d.onStart != nil {
+			d.onStart(*d.active)
+		}
+		record := append([]byte(nil), p[:start]...)
+		// 同一数据块中可能已经包含结束标记
+		return append(record, d.consume(p[start:], window[idx:], output)...)
+	}
+
+	return d.consume(p, window, output)
+}
+
+// consume 统计传输中的数据并检查结束标记，返回结束标记之后应写入录像的部分
+func (d *TransferDetector) consume(p, window []byte, output bool) []byte {
+	end := -1
+	aborted := false
+	protocol := d.active.Protocol
+
+	switch protocol {
+	case TransferProtocolZmodem:
+		if i := bytes.Index(window, zmodemAbort); i >= 0 {
+			end, aborted = i+len(zmodemAbort), true
+		} else {
+			if i := bytes.Index(window, zmodemZFIN); i >= 0 {
+				if output {
+					d.zfinOut = true
+				} else {
+					d.zfinIn = true
+				}
+				if d.zfinOut && d.zfinIn {
+					// 跳过 ZFIN 头剩余的 12 位十六进制、CR、LF 与可选的 XON
+					end = min(i+len(zmodemZFIN)+zmodemHexHeaderTail, len(window))
+					if end < len(window) && window[end] == 0x11 {
+						end++
+					}
+				}
+			}
+		}
+	case TransferProtocolTrzsz:
+		if !output {
+			for _, marker := range trzszExit {
+				if i := bytes.Index(window, marker); i >= 0 {
+					end = len(window)
+					aborted = !bytes.Equal(marker, trzszExit[0])
+					break
+				}
+			}
+		}
+	}
+
+	if end < 0 {
+		d.active.Bytes += int64(len(p))
+		return nil
+	}
+
+	rest := max(len(p)-(len(window)-end), 0)
+	d.active.Bytes += int64(rest)
+	d.finish(aborted)
+	if protocol == TransferProtocolZmodem && !aborted {
+		d.skipOut, d.skipIn = true, true
+	}
+	return d.skipOver(p[rest:], output)
+}
+
+func (d *TransferDetector) finish(aborted bool) {
+	ev := *d.active
+	ev.EndedAt = time.Now()
+	ev.Aborted = aborted
+	d.active = nil
+	d.tailOut, d.tailIn = d.tailOut[:0], d.tailIn[:0]
+	if d.onEnd != nil {
+		d.onEnd(ev)
+	}
+}
+
+// skipOver 丢弃 ZMODEM 结束后的 "OO"
+func (d *TransferDetector) skipOver(p []byte, output bool) []byte {
+	skip := &d.skipIn
+	if output {
+		skip = &d.skipOut
+	}
+	if !*skip || len(p) == 0 {
+		return p
+	}
+	*skip = false
+	return bytes.TrimPrefix(p, zmodemOver)
+}
+
+func detectTransferStart(window []byte, output bool) (int, string, string) {
+	// 传输只能由服务器上的 sz/rz/tsz/trz 发起
+	if !output {
+		return -1, "", ""
+	}
+	candidates := []struct {
+		marker    []byte
+		protocol  string
+		direction string
+	}{
+		{zmodemZRQINIT, TransferProtocolZmodem, TransferDirectionDownload},
+		{zmodemZRINIT, TransferProtocolZmodem, TransferDirectionUpload},
+		{trzszDownload, TransferProtocolTrzsz, TransferDirectionDownload},
+		{trzszDirDown, TransferProtocolTrzsz, TransferDirectionDownload},
+		{trzszUpload, TransferProtocolTrzsz, TransferDirectionUpload},
+	}
+	for _, c := range candidates {
+		if i := bytes.Index(window, c.marker); i >= 0 {
+			return i, c.protocol, c.direction
+		}
+	}
+	return -1, "", ""
+}
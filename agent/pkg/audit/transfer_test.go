@@ -0,0 +1,92 @@
+package audit
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTransferDetectorZmodem(t *testing.T) {
+	var started, ended []TransferEvent
+	d := NewTransferDetector(func(ev TransferEvent) {
+		started = append(started, ev)
+	}, func(ev TransferEvent) {
+		ended = append(ended, ev)
+	})
+
+	if got := d.Output([]byte("$ ls\r\n")); !bytes.Equal(got, []byte("$ ls\r\n")) {
+		t.Fatalf("normal output should be recorded, got %q", got)
+	}
+
+	// sz 发起下载，握手前的内容保留
+	got := d.Output([]byte("$ sz a.bin\r\n**\x18B00000000000000\r\x8a\x11"))
+	if !bytes.Equal(got, []byte("$ sz a.bin\r\n")) {
+		t.Fatalf("expected prompt before handshake, got %q", got)
+	}
+	if !d.Active() || len(started) != 1 || started[0].Direction != TransferDirectionDownload {
+		t.Fatalf("expected zmodem download to start, got %+v", started)
+	}
+
+	if got := d.Output([]byte("\x00\x01\x02binary")); len(got) != 0 {
+		t.Fatalf("binary output should not be recorded, got %q", got)
+	}
+	if got := d.Input([]byte("**\x18B0100000023be50\r\x8a")); len(got) != 0 {
+		t.Fatalf("binary input should not be recorded, got %q", got)
+	}
+
+	d.Output([]byte("**\x18B0800000000022d\r\x8a"))
+	if !d.Active() {
+		t.Fatal("transfer should wait for ZFIN from both sides")
+	}
+	if got := d.Input([]byte("**\x18B0800000000022d\r\x8a")); len(got) != 0 {
+		t.Fatalf("ZFIN should not be recorded, got %q", got)
+	}
+	if d.Active() || len(ended) != 1 || ended[0].Aborted {
+		t.Fatalf("expected transfer to finish, got %+v", ended)
+	}
+
+	if got := d.Output([]byte("OO$ ")); !bytes.Equal(got, []byte("$ ")) {
+		t.Fatalf("expected trailing OO to be dropped, got %q", got)
+	}
+}
+
+func TestTransferDetectorAbort(t *testing.T) {
+	var ended []TransferEvent
+	d := NewTransferDetector(nil, func(ev TransferEvent) {
+		ended = append(ended, ev)
+	})
+
+	// 握手标记被拆分在两个数据块中
+	d.Output([]byte("rz waiting to receive.**\x18"))
+	d.Output([]byte("B0100000023be50\r\x8a\x11"))
+	if !d.Active() {
+		t.Fatal("expected split handshake to be detected")
+	}
+
+	got := d.Output([]byte("\x18\x18\x18\x18\x18\x08\x08\x08\x08\x08$ "))
+	if d.Active() || len(ended) != 1 || !ended[0].Aborted || ended[0].Direction != TransferDirectionUpload {
+		t.Fatalf("expected aborted upload, got %+v", ended)
+	}
+	if !bytes.Equal(got, []byte("\x08\x08\x08\x08\x08$ ")) {
+		t.Fatalf("expected output after abort to be recorded, got %q", got)
+	}
+}
+
+func TestTransferDetectorTrzsz(t *testing.T) {
+	var ended []TransferEvent
+	d := NewTransferDetector(nil, func(ev TransferEvent) {
+		ended = append(ended, ev)
+	})
+
+	d.Output([]byte("\x1b7\x07::TRZSZ:TRANSFER:R:1.1.6:0000000000000\r\n"))
+	if !d.Active() {
+		t.Fatal("expected trzsz upload to start")
+	}
+	d.Input([]byte("#DATA:eJwLycgsVgCi\n"))
+	d.Input([]byte("#EXIT:U2F2ZWQgMSBmaWxl\n"))
+	if d.Active() || len(ended) != 1 || ended[0].Protocol != TransferProtocolTrzsz || ended[0].Aborted {
+		t.Fatalf("expected trzsz upload to finish, got %+v", ended)
+	}
+	if ended[0].Bytes == 0 {
+		t.Fatal("expected transferred bytes to be counted")
+	}
+}
@@ -10,23 +10,46 @@ import (
 //go:embed wrapper.sh
 var wrapperScript string
 
-// WrapperManager 包装器管理器
+// WrapperManager 包装器管理器，每个终端会话使用独立的包装器文件
 type WrapperManager struct {
+	dir         string
 	wrapperPath string
 }
 
-// NewWrapperManager 创建包装器管理器
+// NewWrapperManager 在私有目录中创建包装器脚本。
+// 目录与脚本均属于 Agent 运行用户，其他用户只能读取脚本，无法替换或修改，
+// 目录权限为 0711 以便降权后的终端用户读取已知路径的脚本，但不能列出或创建文件
 func NewWrapperManager() (*WrapperManager, error) {
-	// 创建临时目录
-	tmpDir := os.TempDir()
-	wrapperPath := filepath.Join(tmpDir, fmt.Sprintf("nezha-audit-wrapper-%d.sh", os.Getpid()))
+	dir, err := os.MkdirTemp("", "nezha-audit-")
+	if err != nil {
+		return nil, fmt.Errorf("create wrapper dir: %w", err)
+	}
+	if err := os.Chmod(dir, 0711); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("chmod wrapper dir: %w", err)
+	}
 
-	// 写入包装器脚本
-	if err := os.WriteFile(wrapperPath, []byte(wrapperScript), 0700); err != nil {
+	wrapperPath := filepath.Join(dir, "wrapper.sh")
+	f, err := os.OpenFile(wrapperPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL|openNoFollow, 0644)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("write wrapper script: %w", err)
+	}
+	// 不受 umask 影响
+	err = f.Chmod(0644)
+	if err == nil {
+		_, err = f.WriteString(wrapperScript)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.RemoveAll(dir)
 		return nil, fmt.Errorf("write wrapper script: %w", err)
 	}
 
 	return &WrapperManager{
+		dir:         dir,
 		wrapperPath: wrapperPath,
 	}, nil
 }
@@ -38,8 +61,8 @@ func (w *WrapperManager) GetWrapperPath() string {
 
 // Cleanup 清理包装器文件
 func (w *WrapperManager) Cleanup() error {
-	if w.wrapperPath != "" {
-		return os.Remove(w.wrapperPath)
+	if w.dir != "" {
+		return os.RemoveAll(w.dir)
 	}
 	return nil
 }
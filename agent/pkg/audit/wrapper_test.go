@@ -0,0 +1,47 @@
+//go:build !windows
+
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWrapperManager(t *testing.T) {
+	a, err := NewWrapperManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewWrapperManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Cleanup()
+
+	if a.GetWrapperPath() == b.GetWrapperPath() {
+		t.Fatal("sessions share the same wrapper")
+	}
+
+	info, err := os.Lstat(a.GetWrapperPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.Mode().IsRegular() || info.Mode().Perm() != 0644 {
+		t.Errorf("unexpected wrapper mode: %v", info.Mode())
+	}
+	dir, err := os.Stat(filepath.Dir(a.GetWrapperPath()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir.Mode().Perm() != 0711 {
+		t.Errorf("unexpected wrapper dir mode: %v", dir.Mode())
+	}
+
+	if err := a.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Dir(a.GetWrapperPath())); !os.IsNotExist(err) {
+		t.Errorf("wrapper dir not removed: %v", err)
+	}
+}
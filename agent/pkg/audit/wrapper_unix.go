@@ -0,0 +1,7 @@
+//go:build !windows
+
+package audit
+
+import "syscall"
+
+const openNoFollow = syscall.O_NOFOLLOW
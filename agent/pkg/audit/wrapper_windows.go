@@ -0,0 +1,5 @@
+//go:build windows
+
+package audit
+
+const openNoFollow = 0
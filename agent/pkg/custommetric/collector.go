@@ -0,0 +1,251 @@
+package custommetric
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nezhahq/agent/pkg/processgroup"
+)
+
+const (
+	DefaultInterval = time.Minute
+	MinInterval     = 5 * time.Second
+	DefaultTimeout  = 10 * time.Second
+
+	// 单个采集器与全部采集器最多保留的指标数，以及读取的输出大小
+	maxMetrics      = 500
+	maxTotalMetrics = 1000
+	maxOutputSize   = 1 << 20
+)
+
+var errDisabled = errors.New("command execution is disabled")
+
+// Collector 执行命令或读取 textfile 获取指标
+type Collector struct {
+	Name     string
+	Command  string
+	Textfile string // 支持通配符，匹配的文件合并为一组指标
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+// Collect 命令输出只有一个数值时以 Name 作为指标名称，否则按 Prometheus 文本格式解析
+func (c *Collector) Collect(ctx context.Context, allowCommand bool) ([]Metric, error) {
+	var metrics []Metric
+	switch {
+	case c.Command != "":
+		if !allowCommand {
+			return nil, errDisabled
+		}
+		out, err := c.run(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if v, err := strconv.ParseFloat(strings.TrimSpace(string(out)), 64); err == nil && c.Name != "" {
+			metrics = []Metric{{Name: c.Name, Value: v, Type: TypeGauge}}
+		} else if metrics, err = Parse(bytes.NewReader(out)); err != nil {
+			return nil, err
+		}
+	case c.Textfile != "":
+		files, err := filepath.Glob(c.Textfile)
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no file matches %s", c.Textfile)
+		}
+		for _, file := range files {
+			data, err := readLimited(file)
+			if err != nil {
+				return nil, err
+			}
+			m, err := Parse(bytes.NewReader(data))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+			metrics = append(metrics, m...)
+		}
+	default:
+		return nil, errors.New("either command or textfile is required")
+	}
+
+	if len(metrics) > maxMetrics {
+		metrics = metrics[:maxMetrics]
+	}
+	return metrics, nil
+}
+
+func (c *Collector) run(ctx context.Context) ([]byte, error) {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	pg, err := processgroup.NewProcessExitGroup()
+	if err != nil {
+		return nil, err
+	}
+	cmd := processgroup.NewCommand(c.Command)
+	var out bytes.Buffer
+	cmd.Stdout = &limitedWriter{w: &out, n: maxOutputSize}
+	cmd.Env = os.Environ()
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	pg.AddProcess(cmd)
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err = <-done:
+	case <-time.After(timeout):
+		pg.Dispose()
+		return nil, fmt.Errorf("command timed out after %s", timeout)
+	case <-ctx.Done():
+		pg.Dispose()
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func readLimited(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(&limitedReader{f: f, n: maxOutputSize})
+	return buf.Bytes(), err
+}
+
+type limitedWriter struct {
+	w *bytes.Buffer
+	n int
+}
+
+// Write 超出部分被丢弃，避免命令因管道写满而阻塞
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if remain := l.n - l.w.Len(); remain > 0 {
+		l.w.Write(p[:min(len(p), remain)])
+	}
+	return len(p), nil
+}
+
+type limitedReader struct {
+	f *os.File
+	n int
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		return 0, errors.New("file is larger than 1 MiB")
+	}
+	n, err := l.f.Read(p[:min(len(p), l.n)])
+	l.n -= n
+	return n, err
+}
+
+// Manager 按各自的间隔运行采集器并保存最近一次的结果
+type Manager struct {
+	mu      sync.Mutex
+	results map[int][]Metric
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func NewManager() *Manager {
+	return &Manager{results: make(map[int][]Metric)}
+}
+
+// Start 停止之前的采集器并启动新的，allowCommand 在每次采集时检查
+func (m *Manager) Start(collectors []Collector, allowCommand func() bool, onError func(name string, err error)) {
+	m.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.mu.Lock()
+	m.results = make(map[int][]Metric)
+	m.cancel = cancel
+	m.mu.Unlock()
+
+	for i, c := range collectors {
+		interval := c.Interval
+		if interval <= 0 {
+			interval = DefaultInterval
+		}
+		interval = max(interval, MinInterval)
+
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				metrics, err := c.Collect(ctx, allowCommand())
+				if ctx.Err() != nil {
+					return
+				}
+				if err != nil && onError != nil {
+					onError(c.Name, err)
+				}
+				// 采集失败时清除该采集器的指标，面板不再显示过期的数值
+				m.set(i, metrics)
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}()
+	}
+}
+
+func (m *Manager) Stop() {
+	m.mu.Lock()
+	cancel := m.cancel
+	m.cancel = nil
+	m.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		m.wg.Wait()
+	}
+}
+
+func (m *Manager) set(i int, metrics []Metric) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results[i] = metrics
+}
+
+// Metrics 返回各采集器最近一次的指标，同名指标只保留第一个，总数不超过 maxTotalMetrics
+func (m *Manager) Metrics() []Metric {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var all []Metric
+	seen := make(map[string]bool)
+	for _, i := range slices.Sorted(maps.Keys(m.results)) {
+		for _, metric := range m.results[i] {
+			if len(all) >= maxTotalMetrics {
+				return all
+			}
+			if !seen[metric.Name] {
+				seen[metric.Name] = true
+				all = append(all, metric)
+			}
+		}
+	}
+	return all
+}
@@ -0,0 +1,131 @@
+package custommetric
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleText = `# HELP queue_depth Jobs waiting in the queue.
+# TYPE queue_depth gauge
+queue_depth{queue="mail",env="prod"} 42
+queue_depth{queue="sms"} 3 1700000000000
+# TYPE jobs_total counter
+jobs_total 1.5e3
+active_users 17
+# TYPE latency_seconds histogram
+latency_seconds_bucket{le="0.1"} 5
+latency_seconds_sum 1.2
+broken_value NaN
+escaped{path="C:\\tmp",msg="say \"hi\""} 1
+`
+
+func TestParse(t *testing.T) {
+	metrics, err := Parse(strings.NewReader(sampleText))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Metric{
+		{`queue_depth{env="prod",queue="mail"}`, 42, TypeGauge},
+		{`queue_depth{queue="sms"}`, 3, TypeGauge},
+		{"jobs_total", 1500, TypeCounter},
+		{"active_users", 17, TypeGauge},
+		{`escaped{msg="say \"hi\"",path="C:\\tmp"}`, 1, TypeGauge},
+	}
+	if len(metrics) != len(want) {
+		t.Fatalf("metrics = %+v", metrics)
+	}
+	for i := range want {
+		if metrics[i] != want[i] {
+			t.Errorf("metrics[%d] = %+v, want %+v", i, metrics[i], want[i])
+		}
+	}
+
+	for _, bad := range []string{"1abc 1", `m{l="x} 1`, "m abc", `m{l=x} 1`} {
+		if _, err := Parse(strings.NewReader(bad)); err == nil {
+			t.Errorf("Parse(%q) should fail", bad)
+		}
+	}
+}
+
+func TestCollectTextfile(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.prom"), []byte("a 1\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "b.prom"), []byte("b{x=\"y\"} 2\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "c.txt"), []byte("c 3\n"), 0644)
+
+	c := &Collector{Textfile: filepath.Join(dir, "*.prom")}
+	metrics, err := c.Collect(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metrics) != 2 || metrics[0].Name != "a" || metrics[1].Name != `b{x="y"}` {
+		t.Fatalf("unexpected metrics: %+v", metrics)
+	}
+
+	c = &Collector{Textfile: filepath.Join(dir, "missing-*.prom")}
+	if _, err := c.Collect(context.Background(), false); err == nil {
+		t.Fatal("missing textfile should fail")
+	}
+}
+
+func TestCollectCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sh is not available")
+	}
+	c := &Collector{Name: "active_users", Command: "echo 12"}
+	if _, err := c.Collect(context.Background(), false); err == nil {
+		t.Fatal("command should be refused when command execution is disabled")
+	}
+	metrics, err := c.Collect(context.Background(), true)
+	if err != nil || len(metrics) != 1 || metrics[0].Name != "active_users" || metrics[0].Value != 12 {
+		t.Fatalf("unexpected metrics: %+v, %v", metrics, err)
+	}
+
+	c = &Collector{Command: `printf 'queue_depth{queue="mail"} 5\n'`}
+	metrics, err = c.Collect(context.Background(), true)
+	if err != nil || len(metrics) != 1 || metrics[0].Name != `queue_depth{queue="mail"}` {
+		t.Fatalf("unexpected metrics: %+v, %v", metrics, err)
+	}
+
+	c = &Collector{Command: "sleep 5", Timeout: 100 * time.Millisecond}
+	if _, err := c.Collect(context.Background(), true); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestManager(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "m.prom")
+	os.WriteFile(file, []byte("m 1\n"), 0644)
+
+	m := NewManager()
+	m.Start([]Collector{
+		{Textfile: file},
+		{Textfile: file}, // 重复的指标只保留一个
+		{Textfile: filepath.Join(dir, "missing.prom")},
+	}, func() bool { return false }, nil)
+	defer m.Stop()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		metrics := m.Metrics()
+		if len(metrics) == 1 && metrics[0].Name == "m" && metrics[0].Value == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("unexpected metrics: %+v", metrics)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// 重新启动后丢弃之前的结果
+	m.Start(nil, func() bool { return false }, nil)
+	if metrics := m.Metrics(); len(metrics) != 0 {
+		t.Fatalf("unexpected metrics: %+v", metrics)
+	}
+}
@@ -0,0 +1,187 @@
+package custommetric
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	TypeGauge   = "gauge"
+	TypeCounter = "counter"
+)
+
+// Metric Name 包含按名称排序的标签，如 queue_depth{queue="mail"}
+type Metric struct {
+	Name  string
+	Value float64
+	Type  string
+}
+
+// Parse 解析 Prometheus 文本格式，只保留 gauge、counter 与未声明类型的样本
+func Parse(r io.Reader) ([]Metric, error) {
+	types := make(map[string]string)
+	var metrics []Metric
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			fields := strings.Fields(line)
+			if len(fields) >= 4 && fields[1] == "TYPE" {
+				types[fields[2]] = strings.ToLower(fields[3])
+			}
+			continue
+		}
+
+		name, value, err := parseSample(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			continue
+		}
+		family := name
+		if i := strings.IndexByte(name, '{'); i >= 0 {
+			family = name[:i]
+		}
+		typ, ok := types[family]
+		if !ok {
+			for _, suffix := range []string{"_bucket", "_sum", "_count"} {
+				if base, found := strings.CutSuffix(family, suffix); found {
+					if t := types[base]; t == "histogram" || t == "summary" {
+						typ = t
+					}
+				}
+			}
+		}
+		switch typ {
+		case TypeCounter:
+		case TypeGauge, "", "untyped":
+			typ = TypeGauge
+		default:
+			// histogram、summary 的分桶与分位数不适合作为单个指标
+			continue
+		}
+		metrics = append(metrics, Metric{Name: name, Value: value, Type: typ})
+	}
+	return metrics, scanner.Err()
+}
+
+// parseSample 解析 name{label="value",...} value [timestamp]，返回规范化的名称
+func parseSample(line string) (string, float64, error) {
+	end := strings.IndexAny(line, "{ \t")
+	if end <= 0 {
+		return "", 0, fmt.Errorf("invalid sample: %q", line)
+	}
+	name := line[:end]
+	if !validName(name) {
+		return "", 0, fmt.Errorf("invalid metric name: %q", name)
+	}
+	rest := line[end:]
+
+	var labels [][2]string
+	if rest[0] == '{' {
+		var err error
+		labels, rest, err = parseLabels(rest[1:])
+		if err != nil {
+			return "", 0, err
+		}
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 || len(fields) > 2 {
+		return "", 0, fmt.Errorf("invalid sample: %q", line)
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid value: %q", fields[0])
+	}
+	return Key(name, labels), value, nil
+}
+
+func parseLabels(s string) ([][2]string, string, error) {
+	var labels [][2]string
+	for {
+		s = strings.TrimLeft(s, " \t")
+		if strings.HasPrefix(s, "}") {
+			return labels, s[1:], nil
+		}
+		eq := strings.IndexByte(s, '=')
+		if eq <= 0 {
+			return nil, "", fmt.Errorf("invalid labels: %q", s)
+		}
+		label := strings.TrimSpace(s[:eq])
+		s = strings.TrimLeft(s[eq+1:], " \t")
+		if !validName(label) || !strings.HasPrefix(s, `"`) {
+			return nil, "", fmt.Errorf("invalid label: %q", label)
+		}
+
+		var value strings.Builder
+		i := 1
+		for ; i < len(s) && s[i] != '"'; i++ {
+			if s[i] == '\\' && i+1 < len(s) {
+				i++
+				switch s[i] {
+				case 'n':
+					value.WriteByte('\n')
+				default:
+					value.WriteByte(s[i])
+				}
+				continue
+			}
+			value.WriteByte(s[i])
+		}
+		if i >= len(s) {
+			return nil, "", fmt.Errorf("unterminated label value: %q", label)
+		}
+		labels = append(labels, [2]string{label, value.String()})
+
+		s = strings.TrimLeft(s[i+1:], " \t")
+		if strings.HasPrefix(s, ",") {
+			s = s[1:]
+		}
+	}
+}
+
+// Key 生成带标签的指标名称，标签按名称排序，与 Prometheus 的写法一致
+func Key(name string, labels [][2]string) string {
+	if len(labels) == 0 {
+		return name
+	}
+	slices.SortFunc(labels, func(a, b [2]string) int {
+		return strings.Compare(a[0], b[0])
+	})
+	var b strings.Builder
+	b.WriteString(name)
+	b.WriteByte('{')
+	for i, l := range labels {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(l[0])
+		b.WriteString(`="`)
+		b.WriteString(strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(l[1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func validName(name string) bool {
+	for i, c := range name {
+		if c == '_' || c == ':' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (i > 0 && c >= '0' && c <= '9') {
+			continue
+		}
+		return false
+	}
+	return name != ""
+}
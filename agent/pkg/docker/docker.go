@@ -0,0 +1,588 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/nezhahq/agent/proto"
+)
+
+const DefaultSocket = "/var/run/docker.sock"
+
+// 同时采集状态的容器数
+const statsConcurrency = 8
+
+// 日志最多返回的行数
+const MaxLogTail = 5000
+
+var containerIDRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,127}$`)
+
+// ValidContainerID 容器 ID 或名称会拼接到请求路径中，只允许安全的字符
+func ValidContainerID(id string) bool {
+	return containerIDRegexp.MatchString(id)
+}
+
+type cpuSample struct {
+	total  uint64
+	system uint64
+}
+
+// Client 通过 unix 套接字访问 Docker Engine API
+type Client struct {
+	socket string
+	http   *http.Client
+
+	mu      sync.Mutex
+	prevCPU map[string]cpuSample // 上次采集的 CPU 用量，用于计算单次采集的使用率
+}
+
+func NewClient(socket string) *Client {
+	if socket == "" {
+		socket = DefaultSocket
+	}
+	return &Client{
+		socket: socket,
+		http: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, "unix", socket)
+				},
+				MaxIdleConns: 4,
+			},
+		},
+		prevCPU: make(map[string]cpuSample),
+	}
+}
+
+// Available 套接字存在时才认为本机运行了 Docker
+func (c *Client) Available() bool {
+	_, err := os.Stat(c.socket)
+	return err == nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body any) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, "http://docker"+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		return nil, apiError(resp)
+	}
+	return resp, nil
+}
+
+func apiError(resp *http.Response) error {
+	var msg struct {
+		Message string `json:"message"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if json.Unmarshal(data, &msg) == nil && msg.Message != "" {
+		return fmt.Errorf("docker: %s", msg.Message)
+	}
+	return fmt.Errorf("docker: %s", resp.Status)
+}
+
+func (c *Client) get(ctx context.Context, path string, v any) error {
+	resp, err := c.do(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+type apiContainer struct {
+	ID      string   `json:"Id"`
+	Names   []string `json:"Names"`
+	Image   string   `json:"Image"`
+	State   string   `json:"State"`
+	Status  string   `json:"Status"`
+	Created int64    `json:"Created"`
+	Ports   []struct {
+		IP          string `json:"IP"`
+		PrivatePort uint16 `json:"PrivatePort"`
+		PublicPort  uint16 `json:"PublicPort"`
+		Type        string `json:"Type"`
+	} `json:"Ports"`
+}
+
+type apiImage struct {
+	ID         string   `json:"Id"`
+	RepoTags   []string `json:"RepoTags"`
+	Size       int64    `json:"Size"`
+	Created    int64    `json:"Created"`
+	Containers int64    `json:"Containers"`
+}
+
+type apiInspect struct {
+	ID           string `json:"Id"`
+	Name         string `json:"Name"`
+	Created      string `json:"Created"`
+	RestartCount uint64 `json:"RestartCount"`
+	State        struct {
+		Status     string `json:"Status"`
+		Running    bool   `json:"Running"`
+		ExitCode   int    `json:"ExitCode"`
+		Error      string `json:"Error"`
+		StartedAt  string `json:"StartedAt"`
+		FinishedAt string `json:"FinishedAt"`
+		Health     *struct {
+			Status        string `json:"Status"`
+			FailingStreak int    `json:"FailingStreak"`
+			Log           []struct {
+				Start    string `json:"Start"`
+				ExitCode int    `json:"ExitCode"`
+				Output   string `json:"Output"`
+			} `json:"Log"`
+		} `json:"Health"`
+	} `json:"State"`
+	Config struct {
+		Image      string            `json:"Image"`
+		Cmd        []string          `json:"Cmd"`
+		Entrypoint []string          `json:"Entrypoint"`
+		Tty        bool              `json:"Tty"`
+		Labels     map[string]string `json:"Labels"`
+	} `json:"Config"`
+	HostConfig struct {
+		RestartPolicy struct {
+			Name string `json:"Name"`
+		} `json:"RestartPolicy"`
+	} `json:"HostConfig"`
+	Mounts []struct {
+		Type        string `json:"Type"`
+		Source      string `json:"Source"`
+		Destination string `json:"Destination"`
+		RW          bool   `json:"RW"`
+	} `json:"Mounts"`
+	NetworkSettings struct {
+		Networks map[string]struct {
+			IPAddress string `json:"IPAddress"`
+		} `json:"Networks"`
+	} `json:"NetworkSettings"`
+}
+
+type apiStats struct {
+	CPUStats struct {
+		CPUUsage struct {
+			TotalUsage  uint64   `json:"total_usage"`
+			PercpuUsage []uint64 `json:"percpu_usage"`
+		} `json:"cpu_usage"`
+		SystemUsage uint64 `json:"system_cpu_usage"`
+		OnlineCPUs  uint32 `json:"online_cpus"`
+	} `json:"cpu_stats"`
+	MemoryStats struct {
+		Usage uint64            `json:"usage"`
+		Limit uint64            `json:"limit"`
+		Stats map[string]uint64 `json:"stats"`
+	} `json:"memory_stats"`
+	Networks map[string]struct {
+		RxBytes uint64 `json:"rx_bytes"`
+		TxBytes uint64 `json:"tx_bytes"`
+	} `json:"networks"`
+}
+
+// Report 采集容器与镜像，单个容器采集失败时只缺少该容器的资源用量
+func (c *Client) Report(ctx context.Context) *pb.DockerReport {
+	var report pb.DockerReport
+
+	var version struct {
+		Version string `json:"Version"`
+	}
+	if err := c.get(ctx, "/version", &version); err != nil {
+		report.Error = err.Error()
+		return &report
+	}
+	report.EngineVersion = version.Version
+
+	var containers []apiContainer
+	if err := c.get(ctx, "/containers/json?all=1", &containers); err != nil {
+		report.Error = err.Error()
+		return &report
+	}
+
+	report.Containers = make([]*pb.DockerContainer, len(containers))
+	alive := make(map[string]bool, len(containers))
+	sem := make(chan struct{}, statsConcurrency)
+	var wg sync.WaitGroup
+	for i := range containers {
+		alive[containers[i].ID] = true
+		report.Containers[i] = convertContainer(&containers[i])
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(pc *pb.DockerContainer) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			c.fillContainer(ctx, pc)
+		}(report.Containers[i])
+	}
+	wg.Wait()
+
+	c.mu.Lock()
+	for id := range c.prevCPU {
+		if !alive[id] {
+			delete(c.prevCPU, id)
+		}
+	}
+	c.mu.Unlock()
+
+	var images []apiImage
+	if err := c.get(ctx, "/images/json", &images); err != nil {
+		report.Error = err.Error()
+		return &report
+	}
+	for _, img := range images {
+		report.Images = append(report.Images, &pb.DockerImage{
+			Id:         img.ID,
+			Tags:       img.RepoTags,
+			Size:       uint64(max(img.Size, 0)),
+			Created:    img.Created,
+			Containers: uint64(max(img.Containers, 0)),
+		})
+	}
+	return &report
+}
+
+func convertContainer(ac *apiContainer) *pb.DockerContainer {
+	pc := &pb.DockerContainer{
+		Id:      ac.ID,
+		Image:   ac.Image,
+		State:   ac.State,
+		Status:  ac.Status,
+		Created: ac.Created,
+	}
+	if len(ac.Names) > 0 {
+		pc.Name = strings.TrimPrefix(ac.Names[0], "/")
+	}
+	for _, p := range ac.Ports {
+		port := fmt.Sprintf("%d/%s", p.PrivatePort, p.Type)
+		if p.PublicPort != 0 {
+			port = fmt.Sprintf("%s->%s", net.JoinHostPort(p.IP, strconv.Itoa(int(p.PublicPort))), port)
+		}
+		pc.Ports = append(pc.Ports, port)
+	}
+	return pc
+}
+
+func (c *Client) fillContainer(ctx context.Context, pc *pb.DockerContainer) {
+	var inspect apiInspect
+	if err := c.get(ctx, "/containers/"+pc.Id+"/json", &inspect); err != nil {
+		return
+	}
+	pc.RestartCount = inspect.RestartCount
+	if inspect.State.Health != nil {
+		pc.Health = inspect.State.Health.Status
+	}
+	if inspect.State.Running {
+		pc.StartedAt = parseTime(inspect.State.StartedAt)
+	} else {
+		return
+	}
+
+	// one-shot 时不等待第二次采样，CPU 使用率与上一次上报的数据比较
+	var stats apiStats
+	if err := c.get(ctx, "/containers/"+pc.Id+"/stats?stream=false&one-shot=true", &stats); err != nil {
+		return
+	}
+	cur := cpuSample{total: stats.CPUStats.CPUUsage.TotalUsage, system: stats.CPUStats.SystemUsage}
+	c.mu.Lock()
+	prev, ok := c.prevCPU[pc.Id]
+	c.prevCPU[pc.Id] = cur
+	c.mu.Unlock()
+	if ok && cur.total >= prev.total && cur.system > prev.system {
+		cpus := stats.CPUStats.OnlineCPUs
+		if cpus == 0 {
+			cpus = uint32(max(len(stats.CPUStats.CPUUsage.PercpuUsage), 1))
+		}
+		pc.Cpu = float64(cur.total-prev.total) / float64(cur.system-prev.system) * float64(cpus) * 100
+	}
+
+	// 与 docker stats 一致，内存用量不计算可回收的页缓存
+	pc.MemUsed = stats.MemoryStats.Usage
+	cache := stats.MemoryStats.Stats["inactive_file"]
+	if v, ok := stats.MemoryStats.Stats["total_inactive_file"]; ok {
+		cache = v
+	} else if cache == 0 {
+		cache = stats.MemoryStats.Stats["cache"]
+	}
+	if cache < pc.MemUsed {
+		pc.MemUsed -= cache
+	}
+	pc.MemLimit = stats.MemoryStats.Limit
+	for _, n := range stats.Networks {
+		pc.NetInTransfer += n.RxBytes
+		pc.NetOutTransfer += n.TxBytes
+	}
+}
+
+func parseTime(s string) int64 {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil || t.Year() < 2000 {
+		return 0
+	}
+	return t.Unix()
+}
+
+// Action 启动、停止或重启容器
+func (c *Client) Action(ctx context.Context, id, action string) error {
+	if !ValidContainerID(id) {
+		return errors.New("invalid container id")
+	}
+	switch action {
+	case "start", "stop", "restart":
+	default:
+		return fmt.Errorf("unsupported action: %s", action)
+	}
+	resp, err := c.do(ctx, http.MethodPost, "/containers/"+id+"/"+action, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// ContainerDetail 容器详情，不包含可能带有密钥的环境变量
+type ContainerDetail struct {
+	ID            string            `json:"id"`
+	Name          string            `json:"name"`
+	Image         string            `json:"image"`
+	Command       []string          `json:"command,omitempty"`
+	Created       int64             `json:"created"`
+	Status        string            `json:"status"`
+	ExitCode      int               `json:"exit_code"`
+	Error         string            `json:"error,omitempty"`
+	StartedAt     int64             `json:"started_at,omitempty"`
+	FinishedAt    int64             `json:"finished_at,omitempty"`
+	RestartCount  uint64            `json:"restart_count"`
+	RestartPolicy string            `json:"restart_policy,omitempty"`
+	Health        string            `json:"health,omitempty"`
+	FailingStreak int               `json:"failing_streak,omitempty"`
+	HealthLog     []string          `json:"health_log,omitempty"`
+	Mounts        []string          `json:"mounts,omitempty"`
+	Networks      map[string]string `json:"networks,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+}
+
+func (c *Client) Inspect(ctx context.Context, id string) (*ContainerDetail, error) {
+	if !ValidContainerID(id) {
+		return nil, errors.New("invalid container id")
+	}
+	var inspect apiInspect
+	if err := c.get(ctx, "/containers/"+id+"/json", &inspect); err != nil {
+		return nil, err
+	}
+	detail := &ContainerDetail{
+		ID:            inspect.ID,
+		Name:          strings.TrimPrefix(inspect.Name, "/"),
+		Image:         inspect.Config.Image,
+		Command:       slices.Concat(inspect.Config.Entrypoint, inspect.Config.Cmd),
+		Created:       parseTime(inspect.Created),
+		Status:        inspect.State.Status,
+		ExitCode:      inspect.State.ExitCode,
+		Error:         inspect.State.Error,
+		StartedAt:     parseTime(inspect.State.StartedAt),
+		FinishedAt:    parseTime(inspect.State.FinishedAt),
+		RestartCount:  inspect.RestartCount,
+		RestartPolicy: inspect.HostConfig.RestartPolicy.Name,
+		Labels:        inspect.Config.Labels,
+	}
+	if h := inspect.State.Health; h != nil {
+		detail.Health = h.Status
+		detail.FailingStreak = h.FailingStreak
+		for _, l := range h.Log {
+			detail.HealthLog = append(detail.HealthLog, fmt.Sprintf("%s exit=%d %s", l.Start, l.ExitCode, strings.TrimSpace(l.Output)))
+		}
+	}
+	for _, m := range inspect.Mounts {
+		mode := "ro"
+		if m.RW {
+			mode = "rw"
+		}
+		detail.Mounts = append(detail.Mounts, fmt.Sprintf("%s:%s:%s", m.Source, m.Destination, mode))
+	}
+	if len(inspect.NetworkSettings.Networks) > 0 {
+		detail.Networks = make(map[string]string)
+		for name, n := range inspect.NetworkSettings.Networks {
+			detail.Networks[name] = n.IPAddress
+		}
+	}
+	return detail, nil
+}
+
+// Logs 返回容器最近的日志，标准输出与标准错误合并
+func (c *Client) Logs(ctx context.Context, id string, tail int) (string, error) {
+	if !ValidContainerID(id) {
+		return "", errors.New("invalid container id")
+	}
+	if tail <= 0 || tail > MaxLogTail {
+		tail = MaxLogTail
+	}
+	var inspect apiInspect
+	if err := c.get(ctx, "/containers/"+id+"/json", &inspect); err != nil {
+		return "", err
+	}
+	q := url.Values{}
+	q.Set("stdout", "1")
+	q.Set("stderr", "1")
+	q.Set("timestamps", "1")
+	q.Set("tail", strconv.Itoa(tail))
+	resp, err := c.do(ctx, http.MethodGet, "/containers/"+id+"/logs?"+q.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if inspect.Config.Tty {
+		data, err := io.ReadAll(resp.Body)
+		return string(data), err
+	}
+	return demux(resp.Body)
+}
+
+// demux 解析未分配 TTY 时的多路复用日志流，每帧以 8 字节头开始
+func demux(r io.Reader) (string, error) {
+	var out strings.Builder
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF {
+				return out.String(), nil
+			}
+			return out.String(), err
+		}
+		size := binary.BigEndian.Uint32(header[4:])
+		if _, err := io.CopyN(&out, r, int64(size)); err != nil {
+			return out.String(), err
+		}
+	}
+}
+
+// Exec 在容器内启动交互式 shell
+func (c *Client) Exec(ctx context.Context, id string) (*ExecSession, error) {
+	if !ValidContainerID(id) {
+		return nil, errors.New("invalid container id")
+	}
+	resp, err := c.do(ctx, http.MethodPost, "/containers/"+id+"/exec", map[string]any{
+		"AttachStdin":  true,
+		"AttachStdout": true,
+		"AttachStderr": true,
+		"Tty":          true,
+		"Env":          []string{"TERM=xterm"},
+		"Cmd":          []string{"/bin/sh", "-c", "if command -v bash >/dev/null 2>&1; then exec bash; else exec sh; fi"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var created struct {
+		ID string `json:"Id"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&created)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	// exec start 需要接管连接，不能经过 http.Client
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", c.socket)
+	if err != nil {
+		return nil, err
+	}
+	body := `{"Detach":false,"Tty":true}`
+	req := fmt.Sprintf("POST /exec/%s/start HTTP/1.1\r\nHost: docker\r\nContent-Type: application/json\r\n"+
+		"Connection: Upgrade\r\nUpgrade: tcp\r\nContent-Length: %d\r\n\r\n%s", created.ID, len(body), body)
+	if _, err := conn.Write([]byte(req)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	br := bufio.NewReader(conn)
+	startResp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if startResp.StatusCode != http.StatusSwitchingProtocols && startResp.StatusCode != http.StatusOK {
+		defer conn.Close()
+		return nil, apiError(startResp)
+	}
+	return &ExecSession{client: c, id: created.ID, conn: conn, reader: br}, nil
+}
+
+// ExecSession 容器内的终端，实现 pty.IPty
+type ExecSession struct {
+	client *Client
+	id     string
+	conn   net.Conn
+	reader *bufio.Reader
+
+	mu         sync.Mutex
+	cols, rows uint16
+}
+
+func (e *ExecSession) Read(p []byte) (int, error) {
+	return e.reader.Read(p)
+}
+
+func (e *ExecSession) Write(p []byte) (int, error) {
+	return e.conn.Write(p)
+}
+
+func (e *ExecSession) Getsize() (uint16, uint16, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.cols == 0 || e.rows == 0 {
+		return 80, 24, nil
+	}
+	return e.cols, e.rows, nil
+}
+
+func (e *ExecSession) Setsize(cols, rows uint32) error {
+	e.mu.Lock()
+	e.cols, e.rows = uint16(cols), uint16(rows)
+	e.mu.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := e.client.do(ctx, http.MethodPost, fmt.Sprintf("/exec/%s/resize?h=%d&w=%d", e.id, rows, cols), nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (e *ExecSession) Close() error {
+	return e.conn.Close()
+}
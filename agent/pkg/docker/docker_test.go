@@ -0,0 +1,219 @@
+package docker
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeEngine 在 unix 套接字上模拟 Docker Engine API
+type fakeEngine struct {
+	mu       sync.Mutex
+	cpuTotal uint64
+	system   uint64
+	actions  []string
+	resized  string
+}
+
+func (f *fakeEngine) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	writeJSON := func(v any) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(v)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.URL.Path == "/version":
+		writeJSON(map[string]any{"Version": "27.1.0"})
+	case r.URL.Path == "/containers/json":
+		writeJSON([]map[string]any{
+			{"Id": "aaa", "Names": []string{"/web"}, "Image": "nginx", "State": "running", "Status": "Up 1 hour (healthy)", "Created": 100,
+				"Ports": []map[string]any{{"IP": "0.0.0.0", "PrivatePort": 80, "PublicPort": 8080, "Type": "tcp"}}},
+			{"Id": "bbb", "Names": []string{"/job"}, "Image": "busybox", "State": "exited", "Status": "Exited (1)", "Created": 200},
+		})
+	case r.URL.Path == "/containers/aaa/json":
+		writeJSON(map[string]any{
+			"Id": "aaa", "Name": "/web", "RestartCount": 3,
+			"State": map[string]any{"Status": "running", "Running": true, "StartedAt": "2024-01-02T03:04:05.000Z",
+				"Health": map[string]any{"Status": "unhealthy", "FailingStreak": 2}},
+			"Config": map[string]any{"Image": "nginx", "Tty": false, "Cmd": []string{"nginx"}, "Env": []string{"SECRET=1"}},
+		})
+	case r.URL.Path == "/containers/bbb/json":
+		writeJSON(map[string]any{"Id": "bbb", "State": map[string]any{"Status": "exited"}, "Config": map[string]any{"Tty": true}})
+	case r.URL.Path == "/containers/aaa/stats":
+		f.cpuTotal += 50
+		f.system += 100
+		writeJSON(map[string]any{
+			"cpu_stats":    map[string]any{"cpu_usage": map[string]any{"total_usage": f.cpuTotal}, "system_cpu_usage": f.system, "online_cpus": 2},
+			"memory_stats": map[string]any{"usage": 1000, "limit": 4000, "stats": map[string]any{"inactive_file": 200}},
+			"networks":     map[string]any{"eth0": map[string]any{"rx_bytes": 10, "tx_bytes": 20}, "eth1": map[string]any{"rx_bytes": 1, "tx_bytes": 2}},
+		})
+	case r.URL.Path == "/images/json":
+		writeJSON([]map[string]any{{"Id": "sha256:1", "RepoTags": []string{"nginx:latest"}, "Size": 1234, "Created": 50, "Containers": 1}})
+	case r.Method == http.MethodPost && (r.URL.Path == "/containers/aaa/start" || r.URL.Path == "/containers/aaa/restart"):
+		f.actions = append(f.actions, strings.TrimPrefix(r.URL.Path, "/containers/aaa/"))
+		w.WriteHeader(http.StatusNoContent)
+	case r.URL.Path == "/containers/aaa/logs":
+		for _, frame := range []struct {
+			stream byte
+			data   string
+		}{{1, "out line\n"}, {2, "err line\n"}} {
+			header := make([]byte, 8)
+			header[0] = frame.stream
+			binary.BigEndian.PutUint32(header[4:], uint32(len(frame.data)))
+			w.Write(header)
+			w.Write([]byte(frame.data))
+		}
+	case r.URL.Path == "/containers/bbb/logs":
+		io.WriteString(w, "tty output\n")
+	case r.URL.Path == "/containers/aaa/exec":
+		w.WriteHeader(http.StatusCreated)
+		writeJSON(map[string]any{"Id": "exec1"})
+	case r.URL.Path == "/exec/exec1/start":
+		io.Copy(io.Discard, r.Body)
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		buf.WriteString("HTTP/1.1 101 UPGRADED\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n")
+		buf.Flush()
+		go func() {
+			defer conn.Close()
+			io.Copy(conn, buf)
+		}()
+	case r.URL.Path == "/exec/exec1/resize":
+		f.resized = r.URL.RawQuery
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		writeJSON(map[string]any{"message": "No such container"})
+	}
+}
+
+func newFakeEngine(t *testing.T) (*fakeEngine, *Client) {
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skip("unix socket not supported:", err)
+	}
+	engine := &fakeEngine{}
+	srv := httptest.NewUnstartedServer(engine)
+	srv.Listener = l
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return engine, NewClient(socket)
+}
+
+func TestReport(t *testing.T) {
+	_, c := newFakeEngine(t)
+	if !c.Available() {
+		t.Fatal("socket should be available")
+	}
+	ctx := context.Background()
+
+	report := c.Report(ctx)
+	if report.GetError() != "" {
+		t.Fatal(report.GetError())
+	}
+	if report.GetEngineVersion() != "27.1.0" || len(report.GetContainers()) != 2 || len(report.GetImages()) != 1 {
+		t.Fatalf("unexpected report: %v", report)
+	}
+	web := report.GetContainers()[0]
+	if web.GetName() != "web" || web.GetRestartCount() != 3 || web.GetHealth() != "unhealthy" {
+		t.Fatalf("unexpected container: %v", web)
+	}
+	if web.GetMemUsed() != 800 || web.GetMemLimit() != 4000 || web.GetNetInTransfer() != 11 || web.GetNetOutTransfer() != 22 {
+		t.Fatalf("unexpected usage: %v", web)
+	}
+	if len(web.GetPorts()) != 1 || web.GetPorts()[0] != "0.0.0.0:8080->80/tcp" {
+		t.Fatalf("unexpected ports: %v", web.GetPorts())
+	}
+	if web.GetStartedAt() == 0 || web.GetCpu() != 0 {
+		t.Fatalf("first sample should have no cpu usage: %v", web)
+	}
+	if job := report.GetContainers()[1]; job.GetState() != "exited" || job.GetStartedAt() != 0 {
+		t.Fatalf("unexpected container: %v", job)
+	}
+
+	// 第二次采集与第一次的差值：50/100 * 2 核
+	report = c.Report(ctx)
+	if cpu := report.GetContainers()[0].GetCpu(); cpu != 100 {
+		t.Fatalf("cpu = %v, want 100", cpu)
+	}
+}
+
+func TestActionAndLogs(t *testing.T) {
+	engine, c := newFakeEngine(t)
+	ctx := context.Background()
+
+	if err := c.Action(ctx, "aaa", "restart"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Action(ctx, "aaa", "kill"); err == nil {
+		t.Fatal("unsupported action should fail")
+	}
+	if err := c.Action(ctx, "../aaa", "start"); err == nil {
+		t.Fatal("invalid id should fail")
+	}
+	if err := c.Action(ctx, "ccc", "start"); err == nil || !strings.Contains(err.Error(), "No such container") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(engine.actions) != 1 || engine.actions[0] != "restart" {
+		t.Fatalf("unexpected actions: %v", engine.actions)
+	}
+
+	logs, err := c.Logs(ctx, "aaa", 100)
+	if err != nil || logs != "out line\nerr line\n" {
+		t.Fatalf("logs = %q, %v", logs, err)
+	}
+	logs, err = c.Logs(ctx, "bbb", 100)
+	if err != nil || logs != "tty output\n" {
+		t.Fatalf("logs = %q, %v", logs, err)
+	}
+
+	detail, err := c.Inspect(ctx, "aaa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail.Name != "web" || detail.Health != "unhealthy" || detail.FailingStreak != 2 || len(detail.Command) != 1 {
+		t.Fatalf("unexpected detail: %+v", detail)
+	}
+}
+
+func TestExec(t *testing.T) {
+	engine, c := newFakeEngine(t)
+
+	session, err := c.Exec(context.Background(), "aaa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	if _, err := session.Write([]byte("echo\n")); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(session, buf); err != nil || string(buf) != "echo\n" {
+		t.Fatalf("read %q, %v", buf, err)
+	}
+
+	if err := session.Setsize(120, 40); err != nil {
+		t.Fatal(err)
+	}
+	if cols, rows, _ := session.Getsize(); cols != 120 || rows != 40 {
+		t.Fatalf("size = %dx%d", cols, rows)
+	}
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+	if engine.resized != "h=40&w=120" {
+		t.Fatalf("resize query = %q", engine.resized)
+	}
+}
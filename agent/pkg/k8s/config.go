@@ -0,0 +1,219 @@
+package k8s
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+	inClusterName     = "in-cluster"
+)
+
+// Config 访问 API Server 所需的信息
+type Config struct {
+	Name      string // 集群名称，取 kubeconfig 的当前上下文
+	Server    string
+	Token     string
+	TokenFile string // 集群内的 token 会定期轮换，每次请求时重新读取
+
+	TLS *tls.Config
+}
+
+type kubeconfig struct {
+	CurrentContext string `json:"current-context"`
+	Clusters       []struct {
+		Name    string `json:"name"`
+		Cluster struct {
+			Server                   string `json:"server"`
+			CertificateAuthority     string `json:"certificate-authority"`
+			CertificateAuthorityData string `json:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `json:"insecure-skip-tls-verify"`
+			TLSServerName            string `json:"tls-server-name"`
+		} `json:"cluster"`
+	} `json:"clusters"`
+	Contexts []struct {
+		Name    string `json:"name"`
+		Context struct {
+			Cluster string `json:"cluster"`
+			User    string `json:"user"`
+		} `json:"context"`
+	} `json:"contexts"`
+	Users []struct {
+		Name string `json:"name"`
+		User struct {
+			Token                 string `json:"token"`
+			TokenFile             string `json:"tokenFile"`
+			ClientCertificate     string `json:"client-certificate"`
+			ClientCertificateData string `json:"client-certificate-data"`
+			ClientKey             string `json:"client-key"`
+			ClientKeyData         string `json:"client-key-data"`
+		} `json:"user"`
+	} `json:"users"`
+}
+
+// LoadConfig 指定 kubeconfig 时使用其当前上下文，否则尝试集群内的 ServiceAccount
+func LoadConfig(kubeconfigPath string) (*Config, error) {
+	if kubeconfigPath != "" {
+		return loadKubeconfig(kubeconfigPath)
+	}
+	return loadInCluster()
+}
+
+// InCluster 判断 agent 是否运行在 Pod 中
+func InCluster() bool {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") == "" {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(serviceAccountDir, "token"))
+	return err == nil
+}
+
+func loadInCluster() (*Config, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, errors.New("not running in a kubernetes cluster")
+	}
+	ca, err := os.ReadFile(filepath.Join(serviceAccountDir, "ca.crt"))
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("invalid service account ca.crt")
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	return &Config{
+		Name:      inClusterName,
+		Server:    "https://" + host + ":" + port,
+		TokenFile: filepath.Join(serviceAccountDir, "token"),
+		TLS:       &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+	}, nil
+}
+
+func loadKubeconfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var kc kubeconfig
+	if err := yaml.Unmarshal(data, &kc); err != nil {
+		return nil, err
+	}
+	// kubeconfig 中的相对路径相对于文件所在目录
+	resolve := func(p string) string {
+		if p == "" || filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(filepath.Dir(path), p)
+	}
+
+	ctxName := kc.CurrentContext
+	if ctxName == "" && len(kc.Contexts) > 0 {
+		ctxName = kc.Contexts[0].Name
+	}
+	var clusterName, userName string
+	found := false
+	for _, c := range kc.Contexts {
+		if c.Name == ctxName {
+			clusterName, userName, found = c.Context.Cluster, c.Context.User, true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("context %q not found in kubeconfig", ctxName)
+	}
+
+	cfg := &Config{Name: ctxName, TLS: &tls.Config{MinVersion: tls.VersionTLS12}}
+	found = false
+	for _, c := range kc.Clusters {
+		if c.Name != clusterName {
+			continue
+		}
+		found = true
+		cfg.Server = strings.TrimSuffix(c.Cluster.Server, "/")
+		cfg.TLS.InsecureSkipVerify = c.Cluster.InsecureSkipTLSVerify
+		cfg.TLS.ServerName = c.Cluster.TLSServerName
+		ca, err := readData(c.Cluster.CertificateAuthorityData, resolve(c.Cluster.CertificateAuthority))
+		if err != nil {
+			return nil, err
+		}
+		if len(ca) > 0 {
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(ca) {
+				return nil, errors.New("invalid certificate authority in kubeconfig")
+			}
+			cfg.TLS.RootCAs = pool
+		}
+	}
+	if !found || cfg.Server == "" {
+		return nil, fmt.Errorf("cluster %q not found in kubeconfig", clusterName)
+	}
+
+	for _, u := range kc.Users {
+		if u.Name != userName {
+			continue
+		}
+		cfg.Token = u.User.Token
+		cfg.TokenFile = resolve(u.User.TokenFile)
+		cert, err := readData(u.User.ClientCertificateData, resolve(u.User.ClientCertificate))
+		if err != nil {
+			return nil, err
+		}
+		key, err := readData(u.User.ClientKeyData, resolve(u.User.ClientKey))
+		if err != nil {
+			return nil, err
+		}
+		if len(cert) > 0 && len(key) > 0 {
+			pair, err := tls.X509KeyPair(cert, key)
+			if err != nil {
+				return nil, err
+			}
+			cfg.TLS.Certificates = []tls.Certificate{pair}
+		}
+	}
+	return cfg, nil
+}
+
+// readData 优先使用内嵌的 base64 数据，否则读取文件
+func readData(b64, path string) ([]byte, error) {
+	if b64 != "" {
+		return base64.StdEncoding.DecodeString(b64)
+	}
+	if path != "" {
+		return os.ReadFile(path)
+	}
+	return nil, nil
+}
+
+func (c *Config) token() string {
+	if c.TokenFile != "" {
+		if data, err := os.ReadFile(c.TokenFile); err == nil {
+			return strings.TrimSpace(string(data))
+		}
+	}
+	return c.Token
+}
+
+func (c *Config) httpClient() *http.Client {
+	return &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: c.TLS,
+			MaxIdleConns:    4,
+		},
+	}
+}
@@ -0,0 +1,456 @@
+package k8s
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	pb "github.com/nezhahq/agent/proto"
+)
+
+// 每次上报的最近事件数
+const maxEvents = 100
+
+// 单次 List 请求返回的最大对象数，超出部分通过 continue 翻页
+const listPageSize = 500
+
+// Client 只读访问 API Server，所需权限为 nodes、namespaces、pods、events、
+// deployments、statefulsets、daemonsets 的 list，以及可选的 metrics.k8s.io
+type Client struct {
+	cfg  *Config
+	http *http.Client
+}
+
+func NewClient(cfg *Config) *Client {
+	return &Client{cfg: cfg, http: cfg.httpClient()}
+}
+
+type apiError struct {
+	status int
+	msg    string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("kubernetes: %d %s", e.status, e.msg)
+}
+
+func (c *Client) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.cfg.Server+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if token := c.cfg.token(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var status struct {
+			Message string `json:"message"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(data, &status) != nil || status.Message == "" {
+			status.Message = http.StatusText(resp.StatusCode)
+		}
+		return &apiError{status: resp.StatusCode, msg: status.Message}
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+type objectMeta struct {
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace"`
+	CreationTimestamp time.Time         `json:"creationTimestamp"`
+	Labels            map[string]string `json:"labels"`
+	OwnerReferences   []struct {
+		Kind       string `json:"kind"`
+		Name       string `json:"name"`
+		Controller bool   `json:"controller"`
+	} `json:"ownerReferences"`
+}
+
+type listMeta struct {
+	Continue string `json:"continue"`
+}
+
+// list 读取全部分页，每页的 items 交给 fn 解码
+func (c *Client) list(ctx context.Context, path string, fn func(items json.RawMessage) error) error {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	next := ""
+	for {
+		p := fmt.Sprintf("%s%slimit=%d", path, sep, listPageSize)
+		if next != "" {
+			p += "&continue=" + url.QueryEscape(next)
+		}
+		var page struct {
+			Metadata listMeta        `json:"metadata"`
+			Items    json.RawMessage `json:"items"`
+		}
+		if err := c.get(ctx, p, &page); err != nil {
+			return err
+		}
+		if err := fn(page.Items); err != nil {
+			return err
+		}
+		if page.Metadata.Continue == "" {
+			return nil
+		}
+		next = page.Metadata.Continue
+	}
+}
+
+// Report 采集集群状态，metrics-server 不可用时缺少资源用量
+func (c *Client) Report(ctx context.Context) *pb.KubernetesReport {
+	report := &pb.KubernetesReport{Cluster: c.cfg.Name}
+
+	var version struct {
+		GitVersion string `json:"gitVersion"`
+	}
+	if err := c.get(ctx, "/version", &version); err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	report.Version = version.GitVersion
+
+	steps := []func(context.Context, *pb.KubernetesReport) error{
+		c.collectNodes, c.collectNamespaces, c.collectWorkloads, c.collectPods, c.collectEvents,
+	}
+	var errs []string
+	for _, step := range steps {
+		if err := step(ctx, report); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	c.collectMetrics(ctx, report)
+	report.Error = strings.Join(errs, "; ")
+	return report
+}
+
+func (c *Client) collectNodes(ctx context.Context, report *pb.KubernetesReport) error {
+	return c.list(ctx, "/api/v1/nodes", func(raw json.RawMessage) error {
+		var items []struct {
+			Metadata objectMeta `json:"metadata"`
+			Spec     struct {
+				Unschedulable bool `json:"unschedulable"`
+			} `json:"spec"`
+			Status struct {
+				Capacity    map[string]string `json:"capacity"`
+				Allocatable map[string]string `json:"allocatable"`
+				Conditions  []struct {
+					Type   string `json:"type"`
+					Status string `json:"status"`
+				} `json:"conditions"`
+				Addresses []struct {
+					Type    string `json:"type"`
+					Address string `json:"address"`
+				} `json:"addresses"`
+				NodeInfo struct {
+					KubeletVersion string `json:"kubeletVersion"`
+				} `json:"nodeInfo"`
+			} `json:"status"`
+		}
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return err
+		}
+		for _, n := range items {
+			node := &pb.KubeNode{
+				Name:              n.Metadata.Name,
+				Unschedulable:     n.Spec.Unschedulable,
+				KubeletVersion:    n.Status.NodeInfo.KubeletVersion,
+				CpuCapacity:       ParseMilliCPU(n.Status.Capacity["cpu"]),
+				MemoryCapacity:    ParseBytes(n.Status.Capacity["memory"]),
+				CpuAllocatable:    ParseMilliCPU(n.Status.Allocatable["cpu"]),
+				MemoryAllocatable: ParseBytes(n.Status.Allocatable["memory"]),
+				Created:           n.Metadata.CreationTimestamp.Unix(),
+			}
+			for _, cond := range n.Status.Conditions {
+				if cond.Type == "Ready" {
+					node.Ready = cond.Status == "True"
+				}
+			}
+			for _, addr := range n.Status.Addresses {
+				if addr.Type == "InternalIP" && node.InternalIp == "" {
+					node.InternalIp = addr.Address
+				}
+			}
+			for label := range n.Metadata.Labels {
+				if role, ok := strings.CutPrefix(label, "node-role.kubernetes.io/"); ok && role != "" {
+					node.Roles = append(node.Roles, role)
+				}
+			}
+			slices.Sort(node.Roles)
+			report.Nodes = append(report.Nodes, node)
+		}
+		return nil
+	})
+}
+
+func (c *Client) collectNamespaces(ctx context.Context, report *pb.KubernetesReport) error {
+	return c.list(ctx, "/api/v1/namespaces", func(raw json.RawMessage) error {
+		var items []struct {
+			Metadata objectMeta `json:"metadata"`
+			Status   struct {
+				Phase string `json:"phase"`
+			} `json:"status"`
+		}
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return err
+		}
+		for _, ns := range items {
+			report.Namespaces = append(report.Namespaces, &pb.KubeNamespace{
+				Name:    ns.Metadata.Name,
+				Phase:   ns.Status.Phase,
+				Created: ns.Metadata.CreationTimestamp.Unix(),
+			})
+		}
+		return nil
+	})
+}
+
+func (c *Client) collectWorkloads(ctx context.Context, report *pb.KubernetesReport) error {
+	kinds := []struct {
+		kind, path string
+	}{
+		{"Deployment", "/apis/apps/v1/deployments"},
+		{"StatefulSet", "/apis/apps/v1/statefulsets"},
+		{"DaemonSet", "/apis/apps/v1/daemonsets"},
+	}
+	for _, k := range kinds {
+		err := c.list(ctx, k.path, func(raw json.RawMessage) error {
+			var items []struct {
+				Metadata objectMeta `json:"metadata"`
+				Spec     struct {
+					Replicas *uint32 `json:"replicas"`
+				} `json:"spec"`
+				Status struct {
+					Replicas          uint32 `json:"replicas"`
+					ReadyReplicas     uint32 `json:"readyReplicas"`
+					AvailableReplicas uint32 `json:"availableReplicas"`
+					UpdatedReplicas   uint32 `json:"updatedReplicas"`
+
+					// DaemonSet
+					DesiredNumberScheduled uint32 `json:"desiredNumberScheduled"`
+					NumberReady            uint32 `json:"numberReady"`
+					NumberAvailable        uint32 `json:"numberAvailable"`
+					UpdatedNumberScheduled uint32 `json:"updatedNumberScheduled"`
+				} `json:"status"`
+			}
+			if err := json.Unmarshal(raw, &items); err != nil {
+				return err
+			}
+			for _, w := range items {
+				wl := &pb.KubeWorkload{
+					Kind:      k.kind,
+					Namespace: w.Metadata.Namespace,
+					Name:      w.Metadata.Name,
+					Created:   w.Metadata.CreationTimestamp.Unix(),
+				}
+				if k.kind == "DaemonSet" {
+					wl.Desired = w.Status.DesiredNumberScheduled
+					wl.Ready = w.Status.NumberReady
+					wl.Available = w.Status.NumberAvailable
+					wl.Updated = w.Status.UpdatedNumberScheduled
+				} else {
+					wl.Desired = w.Status.Replicas
+					if w.Spec.Replicas != nil {
+						wl.Desired = *w.Spec.Replicas
+					}
+					wl.Ready = w.Status.ReadyReplicas
+					wl.Available = w.Status.AvailableReplicas
+					wl.Updated = w.Status.UpdatedReplicas
+				}
+				report.Workloads = append(report.Workloads, wl)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) collectPods(ctx context.Context, report *pb.KubernetesReport) error {
+	return c.list(ctx, "/api/v1/pods", func(raw json.RawMessage) error {
+		var items []struct {
+			Metadata objectMeta `json:"metadata"`
+			Spec     struct {
+				NodeName   string `json:"nodeName"`
+				Containers []struct {
+					Resources struct {
+						Requests map[string]string `json:"requests"`
+					} `json:"resources"`
+				} `json:"containers"`
+			} `json:"spec"`
+			Status struct {
+				Phase             string `json:"phase"`
+				Reason            string `json:"reason"`
+				PodIP             string `json:"podIP"`
+				ContainerStatuses []struct {
+					Ready        bool   `json:"ready"`
+					RestartCount uint32 `json:"restartCount"`
+					State        struct {
+						Waiting *struct {
+							Reason string `json:"reason"`
+						} `json:"waiting"`
+						Terminated *struct {
+							Reason string `json:"reason"`
+						} `json:"terminated"`
+					} `json:"state"`
+				} `json:"containerStatuses"`
+			} `json:"status"`
+		}
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return err
+		}
+		for _, p := range items {
+			pod := &pb.KubePod{
+				Namespace:       p.Metadata.Namespace,
+				Name:            p.Metadata.Name,
+				Node:            p.Spec.NodeName,
+				Phase:           p.Status.Phase,
+				Reason:          p.Status.Reason,
+				Ip:              p.Status.PodIP,
+				TotalContainers: uint32(len(p.Spec.Containers)),
+				Created:         p.Metadata.CreationTimestamp.Unix(),
+			}
+			for _, o := range p.Metadata.OwnerReferences {
+				if o.Controller {
+					pod.OwnerKind, pod.OwnerName = o.Kind, o.Name
+				}
+			}
+			for _, ct := range p.Spec.Containers {
+				pod.CpuRequest += ParseMilliCPU(ct.Resources.Requests["cpu"])
+				pod.MemoryRequest += ParseBytes(ct.Resources.Requests["memory"])
+			}
+			// 与 kubectl get pods 一致，容器的等待或退出原因优先于 Pod 的原因
+			for _, cs := range p.Status.ContainerStatuses {
+				pod.Restarts += cs.RestartCount
+				if cs.Ready {
+					pod.ReadyContainers++
+				}
+				if w := cs.State.Waiting; w != nil && w.Reason != "" {
+					pod.Reason = w.Reason
+				} else if t := cs.State.Terminated; t != nil && t.Reason != "" && pod.Reason == "" {
+					pod.Reason = t.Reason
+				}
+			}
+			report.Pods = append(report.Pods, pod)
+		}
+		return nil
+	})
+}
+
+func (c *Client) collectEvents(ctx context.Context, report *pb.KubernetesReport) error {
+	var events []*pb.KubeEvent
+	err := c.list(ctx, "/api/v1/events", func(raw json.RawMessage) error {
+		var items []struct {
+			Metadata       objectMeta `json:"metadata"`
+			Type           string     `json:"type"`
+			Reason         string     `json:"reason"`
+			Message        string     `json:"message"`
+			Count          uint32     `json:"count"`
+			LastTimestamp  *time.Time `json:"lastTimestamp"`
+			EventTime      *time.Time `json:"eventTime"`
+			InvolvedObject struct {
+				Kind string `json:"kind"`
+				Name string `json:"name"`
+			} `json:"involvedObject"`
+		}
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return err
+		}
+		for _, e := range items {
+			last := e.Metadata.CreationTimestamp
+			if e.LastTimestamp != nil && !e.LastTimestamp.IsZero() {
+				last = *e.LastTimestamp
+			} else if e.EventTime != nil && !e.EventTime.IsZero() {
+				last = *e.EventTime
+			}
+			events = append(events, &pb.KubeEvent{
+				Namespace:  e.Metadata.Namespace,
+				Type:       e.Type,
+				Reason:     e.Reason,
+				Message:    e.Message,
+				ObjectKind: e.InvolvedObject.Kind,
+				ObjectName: e.InvolvedObject.Name,
+				Count:      max(e.Count, 1),
+				LastSeen:   last.Unix(),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	slices.SortStableFunc(events, func(a, b *pb.KubeEvent) int {
+		return cmp.Compare(b.LastSeen, a.LastSeen)
+	})
+	if len(events) > maxEvents {
+		events = events[:maxEvents]
+	}
+	report.Events = events
+	return nil
+}
+
+// collectMetrics 未安装 metrics-server 时忽略
+func (c *Client) collectMetrics(ctx context.Context, report *pb.KubernetesReport) {
+	var nodeMetrics struct {
+		Items []struct {
+			Metadata objectMeta        `json:"metadata"`
+			Usage    map[string]string `json:"usage"`
+		} `json:"items"`
+	}
+	if err := c.get(ctx, "/apis/metrics.k8s.io/v1beta1/nodes", &nodeMetrics); err == nil {
+		usage := make(map[string]map[string]string, len(nodeMetrics.Items))
+		for _, m := range nodeMetrics.Items {
+			usage[m.Metadata.Name] = m.Usage
+		}
+		for _, n := range report.Nodes {
+			if u, ok := usage[n.Name]; ok {
+				n.CpuUsage = ParseMilliCPU(u["cpu"])
+				n.MemoryUsage = ParseBytes(u["memory"])
+			}
+		}
+	}
+
+	var podMetrics struct {
+		Items []struct {
+			Metadata   objectMeta `json:"metadata"`
+			Containers []struct {
+				Usage map[string]string `json:"usage"`
+			} `json:"containers"`
+		} `json:"items"`
+	}
+	if err := c.get(ctx, "/apis/metrics.k8s.io/v1beta1/pods", &podMetrics); err == nil {
+		type usage struct{ cpu, mem uint64 }
+		pods := make(map[string]usage, len(podMetrics.Items))
+		for _, m := range podMetrics.Items {
+			var u usage
+			for _, ct := range m.Containers {
+				u.cpu += ParseMilliCPU(ct.Usage["cpu"])
+				u.mem += ParseBytes(ct.Usage["memory"])
+			}
+			pods[m.Metadata.Namespace+"/"+m.Metadata.Name] = u
+		}
+		for _, p := range report.Pods {
+			if u, ok := pods[p.Namespace+"/"+p.Name]; ok {
+				p.CpuUsage, p.MemoryUsage = u.cpu, u.mem
+			}
+		}
+	}
+}
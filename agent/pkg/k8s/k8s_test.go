@@ -0,0 +1,232 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const fakeToken = "test-token"
+
+// fakeAPIServer 返回固定的集群对象，pods 分两页返回
+func fakeAPIServer(t *testing.T, withMetrics bool) *httptest.Server {
+	objects := map[string]string{
+		"/version": `{"gitVersion":"v1.30.2"}`,
+		"/api/v1/nodes": `{"metadata":{},"items":[
+			{"metadata":{"name":"node-1","creationTimestamp":"2024-01-01T00:00:00Z","labels":{"node-role.kubernetes.io/control-plane":""}},
+			 "status":{"capacity":{"cpu":"4","memory":"8Gi"},"allocatable":{"cpu":"3800m","memory":"7Gi"},
+			  "conditions":[{"type":"MemoryPressure","status":"False"},{"type":"Ready","status":"True"}],
+			  "addresses":[{"type":"InternalIP","address":"10.0.0.1"}],"nodeInfo":{"kubeletVersion":"v1.30.2"}}},
+			{"metadata":{"name":"node-2"},"spec":{"unschedulable":true},"status":{"conditions":[{"type":"Ready","status":"Unknown"}]}}]}`,
+		"/api/v1/namespaces": `{"metadata":{},"items":[{"metadata":{"name":"default"},"status":{"phase":"Active"}},{"metadata":{"name":"old"},"status":{"phase":"Terminating"}}]}`,
+		"/apis/apps/v1/deployments": `{"metadata":{},"items":[{"metadata":{"name":"web","namespace":"default"},"spec":{"replicas":3},
+			"status":{"replicas":3,"readyReplicas":2,"availableReplicas":2,"updatedReplicas":3}}]}`,
+		"/apis/apps/v1/statefulsets": `{"metadata":{},"items":[]}`,
+		"/apis/apps/v1/daemonsets": `{"metadata":{},"items":[{"metadata":{"name":"agent","namespace":"kube-system"},
+			"status":{"desiredNumberScheduled":2,"numberReady":1,"numberAvailable":1,"updatedNumberScheduled":2}}]}`,
+		"/api/v1/events": `{"metadata":{},"items":[
+			{"metadata":{"name":"e1","namespace":"default","creationTimestamp":"2024-01-01T00:00:00Z"},"type":"Normal","reason":"Pulled","message":"pulled","involvedObject":{"kind":"Pod","name":"web-1"}},
+			{"metadata":{"name":"e2","namespace":"default"},"type":"Warning","reason":"BackOff","message":"back-off","count":5,
+			 "lastTimestamp":"2024-01-02T00:00:00Z","involvedObject":{"kind":"Pod","name":"web-2"}}]}`,
+		"/apis/metrics.k8s.io/v1beta1/nodes": `{"items":[{"metadata":{"name":"node-1"},"usage":{"cpu":"1500000000n","memory":"2Gi"}}]}`,
+		"/apis/metrics.k8s.io/v1beta1/pods": `{"items":[{"metadata":{"name":"web-1","namespace":"default"},
+			"containers":[{"usage":{"cpu":"100m","memory":"64Mi"}},{"usage":{"cpu":"50m","memory":"16Mi"}}]}]}`,
+	}
+	podPages := []string{
+		`{"metadata":{"continue":"page 2"},"items":[{"metadata":{"name":"web-1","namespace":"default",
+			"ownerReferences":[{"kind":"ReplicaSet","name":"web-abc","controller":true}]},
+			"spec":{"nodeName":"node-1","containers":[{"resources":{"requests":{"cpu":"250m","memory":"128Mi"}}},{"resources":{"requests":{"cpu":"0.5"}}}]},
+			"status":{"phase":"Running","podIP":"10.1.0.5","containerStatuses":[{"ready":true,"restartCount":1,"state":{"running":{}}},{"ready":true,"restartCount":0,"state":{"running":{}}}]}}]}`,
+		`{"metadata":{},"items":[
+			{"metadata":{"name":"web-2","namespace":"default"},"spec":{"nodeName":"node-1","containers":[{}]},
+			 "status":{"phase":"Running","containerStatuses":[{"ready":false,"restartCount":7,"state":{"waiting":{"reason":"CrashLoopBackOff"}}}]}},
+			{"metadata":{"name":"web-3","namespace":"default"},"spec":{"containers":[{}]},"status":{"phase":"Pending","reason":"Unschedulable"}}]}`,
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+fakeToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"message": "Unauthorized"})
+			return
+		}
+		if r.URL.Path == "/api/v1/pods" {
+			if r.URL.Query().Get("continue") == "page 2" {
+				w.Write([]byte(podPages[1]))
+			} else {
+				w.Write([]byte(podPages[0]))
+			}
+			return
+		}
+		body, ok := objects[r.URL.Path]
+		if !ok || (!withMetrics && strings.HasPrefix(r.URL.Path, "/apis/metrics.k8s.io/")) {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"message": "the server could not find the requested resource"})
+			return
+		}
+		w.Write([]byte(body))
+	}))
+}
+
+func writeKubeconfig(t *testing.T, server, token string) string {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "token"), []byte(token+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	kubeconfig := `apiVersion: v1
+kind: Config
+current-context: test
+clusters:
+- name: other
+  cluster:
+    server: https://other.example.com
+- name: fake
+  cluster:
+    server: ` + server + `/
+contexts:
+- name: test
+  context:
+    cluster: fake
+    user: tester
+users:
+- name: tester
+  user:
+    tokenFile: token
+`
+	path := filepath.Join(dir, "config")
+	if err := os.WriteFile(path, []byte(kubeconfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReport(t *testing.T) {
+	srv := fakeAPIServer(t, true)
+	defer srv.Close()
+
+	cfg, err := LoadConfig(writeKubeconfig(t, srv.URL, fakeToken))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Name != "test" || cfg.Server != srv.URL {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+
+	report := NewClient(cfg).Report(context.Background())
+	if report.GetError() != "" {
+		t.Fatal(report.GetError())
+	}
+	if report.GetCluster() != "test" || report.GetVersion() != "v1.30.2" {
+		t.Fatalf("unexpected report: %v", report)
+	}
+
+	nodes := report.GetNodes()
+	if len(nodes) != 2 {
+		t.Fatalf("nodes = %d", len(nodes))
+	}
+	n1 := nodes[0]
+	if !n1.GetReady() || n1.GetCpuCapacity() != 4000 || n1.GetCpuAllocatable() != 3800 || n1.GetMemoryCapacity() != 8<<30 ||
+		n1.GetInternalIp() != "10.0.0.1" || len(n1.GetRoles()) != 1 || n1.GetRoles()[0] != "control-plane" {
+		t.Fatalf("unexpected node: %v", n1)
+	}
+	if n1.GetCpuUsage() != 1500 || n1.GetMemoryUsage() != 2<<30 {
+		t.Fatalf("unexpected node usage: %v", n1)
+	}
+	if nodes[1].GetReady() || !nodes[1].GetUnschedulable() {
+		t.Fatalf("unexpected node: %v", nodes[1])
+	}
+
+	if len(report.GetNamespaces()) != 2 || report.GetNamespaces()[1].GetPhase() != "Terminating" {
+		t.Fatalf("unexpected namespaces: %v", report.GetNamespaces())
+	}
+
+	workloads := report.GetWorkloads()
+	if len(workloads) != 2 {
+		t.Fatalf("workloads = %v", workloads)
+	}
+	if w := workloads[0]; w.GetKind() != "Deployment" || w.GetDesired() != 3 || w.GetReady() != 2 {
+		t.Fatalf("unexpected workload: %v", w)
+	}
+	if w := workloads[1]; w.GetKind() != "DaemonSet" || w.GetDesired() != 2 || w.GetReady() != 1 {
+		t.Fatalf("unexpected workload: %v", w)
+	}
+
+	pods := report.GetPods()
+	if len(pods) != 3 {
+		t.Fatalf("pods = %d, want 3 across two pages", len(pods))
+	}
+	web1 := pods[0]
+	if web1.GetCpuRequest() != 750 || web1.GetMemoryRequest() != 128<<20 || web1.GetRestarts() != 1 ||
+		web1.GetReadyContainers() != 2 || web1.GetTotalContainers() != 2 || web1.GetOwnerKind() != "ReplicaSet" {
+		t.Fatalf("unexpected pod: %v", web1)
+	}
+	if web1.GetCpuUsage() != 150 || web1.GetMemoryUsage() != 80<<20 {
+		t.Fatalf("unexpected pod usage: %v", web1)
+	}
+	if web2 := pods[1]; web2.GetReason() != "CrashLoopBackOff" || web2.GetRestarts() != 7 {
+		t.Fatalf("unexpected pod: %v", web2)
+	}
+	if web3 := pods[2]; web3.GetPhase() != "Pending" || web3.GetReason() != "Unschedulable" {
+		t.Fatalf("unexpected pod: %v", web3)
+	}
+
+	events := report.GetEvents()
+	if len(events) != 2 || events[0].GetReason() != "BackOff" || events[0].GetCount() != 5 || events[1].GetCount() != 1 {
+		t.Fatalf("events should be sorted by last seen: %v", events)
+	}
+}
+
+func TestReportWithoutMetrics(t *testing.T) {
+	srv := fakeAPIServer(t, false)
+	defer srv.Close()
+
+	cfg, err := LoadConfig(writeKubeconfig(t, srv.URL, fakeToken))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := NewClient(cfg).Report(context.Background())
+	if report.GetError() != "" || len(report.GetPods()) != 3 || report.GetPods()[0].GetCpuUsage() != 0 {
+		t.Fatalf("missing metrics-server should be ignored: %v", report)
+	}
+}
+
+func TestReportUnauthorized(t *testing.T) {
+	srv := fakeAPIServer(t, true)
+	defer srv.Close()
+
+	cfg, err := LoadConfig(writeKubeconfig(t, srv.URL, "wrong"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := NewClient(cfg).Report(context.Background())
+	if !strings.Contains(report.GetError(), "401 Unauthorized") || len(report.GetNodes()) != 0 {
+		t.Fatalf("unexpected report: %v", report)
+	}
+}
+
+func TestLoadConfigMissingContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	os.WriteFile(path, []byte("current-context: nope\ncontexts: []\n"), 0600)
+	if _, err := LoadConfig(path); err == nil {
+		t.Fatal("missing context should fail")
+	}
+}
+
+func TestParseQuantity(t *testing.T) {
+	cpu := map[string]uint64{"": 0, "250m": 250, "2": 2000, "0.5": 500, "1500000000n": 1500, "100u": 0, "bad": 0}
+	for q, want := range cpu {
+		if got := ParseMilliCPU(q); got != want {
+			t.Errorf("ParseMilliCPU(%q) = %d, want %d", q, got, want)
+		}
+	}
+	mem := map[string]uint64{"128Mi": 128 << 20, "1Gi": 1 << 30, "1G": 1e9, "512k": 512000, "1e3": 1000, "1024": 1024, "-1Ki": 0}
+	for q, want := range mem {
+		if got := ParseBytes(q); got != want {
+			t.Errorf("ParseBytes(%q) = %d, want %d", q, got, want)
+		}
+	}
+}
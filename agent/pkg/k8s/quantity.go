@@ -0,0 +1,49 @@
+package k8s
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+var quantitySuffixes = []struct {
+	suffix string
+	factor float64
+}{
+	{"Ki", 1 << 10}, {"Mi", 1 << 20}, {"Gi", 1 << 30}, {"Ti", 1 << 40}, {"Pi", 1 << 50}, {"Ei", 1 << 60},
+	{"n", 1e-9}, {"u", 1e-6}, {"m", 1e-3},
+	{"k", 1e3}, {"M", 1e6}, {"G", 1e9}, {"T", 1e12}, {"P", 1e15}, {"E", 1e18},
+}
+
+// parseQuantity 解析 Kubernetes 资源数量，如 250m、1.5、128Mi、1e3，无法解析时返回 0
+func parseQuantity(q string) float64 {
+	q = strings.TrimSpace(q)
+	if q == "" {
+		return 0
+	}
+	factor := 1.0
+	for _, s := range quantitySuffixes {
+		if num, ok := strings.CutSuffix(q, s.suffix); ok {
+			// 1e3 这样的科学计数法以数字结尾，不会被误认为后缀
+			if _, err := strconv.ParseFloat(num, 64); err == nil {
+				q, factor = num, s.factor
+				break
+			}
+		}
+	}
+	v, err := strconv.ParseFloat(q, 64)
+	if err != nil || v < 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+		return 0
+	}
+	return v * factor
+}
+
+// ParseMilliCPU 返回 CPU 的毫核数
+func ParseMilliCPU(q string) uint64 {
+	return uint64(math.Round(parseQuantity(q) * 1000))
+}
+
+// ParseBytes 返回内存的字节数
+func ParseBytes(q string) uint64 {
+	return uint64(math.Round(parseQuantity(q)))
+}
@@ -0,0 +1,293 @@
+package logview
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/nezhahq/agent/pkg/systemd"
+)
+
+const (
+	DefaultLines = 100
+	MaxLines     = 5000
+
+	DefaultSearchLimit = 1000
+	MaxSearchLimit     = 10000
+
+	// 搜索时最多读取的文件数与字节数
+	maxSearchFiles = 20
+	maxSearchBytes = 512 << 20
+
+	// 跟随时最多回溯的字节数
+	maxTailBytes = 4 << 20
+
+	// 单行超出部分被截断
+	maxLineLength = 8 << 10
+
+	followInterval = 500 * time.Millisecond
+)
+
+var (
+	errNotRegular = errors.New("not a regular file")
+	errSymlink    = errors.New("symbolic links are not allowed")
+)
+
+// Filter 为空时匹配所有行
+type Filter struct {
+	substr string
+	re     *regexp.Regexp
+}
+
+func NewFilter(pattern string, regex bool) (*Filter, error) {
+	if !regex {
+		return &Filter{substr: pattern}, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return &Filter{re: re}, nil
+}
+
+func (f *Filter) Match(line string) bool {
+	if f == nil {
+		return true
+	}
+	if f.re != nil {
+		return f.re.MatchString(line)
+	}
+	return strings.Contains(line, f.substr)
+}
+
+// CleanPath 只接受绝对路径
+func CleanPath(path string) (string, error) {
+	if path == "" || !filepath.IsAbs(path) {
+		return "", fmt.Errorf("path must be absolute: %s", path)
+	}
+	return filepath.Clean(path), nil
+}
+
+func truncateLine(line string) string {
+	if len(line) > maxLineLength {
+		return line[:maxLineLength] + "…"
+	}
+	return line
+}
+
+// Follow 先输出文件末尾 lines 行中匹配的行，再持续输出新增的行，文件被轮转或截断后从头读取新文件
+func Follow(ctx context.Context, w io.Writer, path string, lines int, filter *Filter) error {
+	path, err := CleanPath(path)
+	if err != nil {
+		return err
+	}
+	if lines <= 0 {
+		lines = DefaultLines
+	}
+	lines = min(lines, MaxLines)
+
+	f, info, err := openRegular(path)
+	if err != nil {
+		return err
+	}
+	defer func() { f.Close() }()
+
+	offset, err := tailOffset(f, info.Size(), lines)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	buf := make([]byte, 64<<10)
+	var partial []byte
+	ticker := time.NewTicker(followInterval)
+	defer ticker.Stop()
+	for {
+		for {
+			n, err := f.ReadAt(buf, offset)
+			offset += int64(n)
+			partial = writeLines(bw, append(partial, buf[:n]...), filter)
+			if err == io.EOF || n == 0 {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if ctx.Err() != nil {
+				return nil
+			}
+		}
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		// 文件被轮转或截断
+		if cur, err := os.Stat(path); err == nil && (!os.SameFile(cur, info) || cur.Size() < offset) {
+			nf, ninfo, err := openRegular(path)
+			if err != nil {
+				continue
+			}
+			f.Close()
+			f, info, offset, partial = nf, ninfo, 0, nil
+		}
+	}
+}
+
+// Journal 通过 journalctl 跟随 systemd 单元的日志
+func Journal(ctx context.Context, w io.Writer, unit string, lines int, filter *Filter) error {
+	if !systemd.ValidUnit(unit) {
+		return fmt.Errorf("invalid unit: %s", unit)
+	}
+	if lines <= 0 {
+		lines = DefaultLines
+	}
+	lines = min(lines, MaxLines)
+
+	cmd := exec.CommandContext(ctx, "journalctl", "--no-pager", "--output=short-iso", "--follow",
+		"--lines", fmt.Sprint(lines), "--unit", unit)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !filter.Match(line) {
+			continue
+		}
+		if _, err := io.WriteString(w, truncateLine(line)+"\n"); err != nil {
+			cmd.Process.Kill()
+			cmd.Wait()
+			return err
+		}
+	}
+	err = cmd.Wait()
+	if ctx.Err() != nil {
+		return nil
+	}
+	if msg := strings.TrimSpace(stderr.String()); msg != "" {
+		return errors.New(msg)
+	}
+	return err
+}
+
+// openRegular 拒绝路径中任何一级为符号链接的文件，
+// 面板只按路径授权，跟随符号链接会读取到授权范围之外的文件
+func openRegular(path string) (*os.File, os.FileInfo, error) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !samePath(resolved, path) {
+		return nil, nil, errSymlink
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	if !info.Mode().IsRegular() {
+		f.Close()
+		return nil, nil, errNotRegular
+	}
+	// 检查与打开之间文件被替换为符号链接
+	if linfo, err := os.Lstat(path); err != nil || !os.SameFile(info, linfo) {
+		f.Close()
+		return nil, nil, errSymlink
+	}
+	return f, info, nil
+}
+
+func samePath(a, b string) bool {
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// tailOffset 从文件末尾向前查找倒数第 lines 行的起点
+func tailOffset(f *os.File, size int64, lines int) (int64, error) {
+	const chunk = 32 << 10
+	offset := size
+	var newlines int
+	buf := make([]byte, chunk)
+	for offset > 0 {
+		n := int64(chunk)
+		if offset < n {
+			n = offset
+		}
+		offset -= n
+		if _, err := f.ReadAt(buf[:n], offset); err != nil && err != io.EOF {
+			return 0, err
+		}
+		for i := n - 1; i >= 0; i-- {
+			if buf[i] != '\n' {
+				continue
+			}
+			// 文件末尾的换行不计入
+			if offset+i == size-1 {
+				continue
+			}
+			newlines++
+			if newlines == lines {
+				return offset + i + 1, nil
+			}
+		}
+		if size-offset > maxTailBytes {
+			return offset, nil
+		}
+	}
+	return 0, nil
+}
+
+// writeLines 输出完整的行，返回末尾不完整的部分
+func writeLines(w io.Writer, data []byte, filter *Filter) []byte {
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		line := string(bytes.TrimRight(data[:i], "\r"))
+		data = data[i+1:]
+		if filter.Match(line) {
+			io.WriteString(w, truncateLine(line)+"\n")
+		}
+	}
+	// 过长的不完整行直接截断输出
+	if len(data) > maxLineLength {
+		line := string(data)
+		if filter.Match(line) {
+			io.WriteString(w, truncateLine(line)+"\n")
+		}
+		return nil
+	}
+	return bytes.Clone(data)
+}
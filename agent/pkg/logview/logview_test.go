@@ -0,0 +1,192 @@
+package logview
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestFilter(t *testing.T) {
+	f, err := NewFilter("error", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.Match("an error occurred") || f.Match("all good") {
+		t.Error("substring filter mismatch")
+	}
+
+	f, err = NewFilter(`^\d{3} `, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.Match("500 GET /") || f.Match("GET / 500") {
+		t.Error("regex filter mismatch")
+	}
+
+	if _, err := NewFilter("(", true); err == nil {
+		t.Error("expected invalid regex error")
+	}
+	if _, err := CleanPath("var/log/syslog"); err == nil {
+		t.Error("expected relative path to be rejected")
+	}
+}
+
+func TestFollow(t *testing.T) {
+	path := filepath.Join(tempDir(t), "app.log")
+	if err := os.WriteFile(path, []byte("one\nerror two\nthree\nerror four\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	filter, _ := NewFilter("error", false)
+	ctx, cancel := context.WithCancel(context.Background())
+	var out syncBuffer
+	done := make(chan error)
+	go func() { done <- Follow(ctx, &out, path, 3, filter) }()
+
+	waitFor(t, &out, "error two\nerror four\n")
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("five\nerror six\n")
+	f.Close()
+	waitFor(t, &out, "error two\nerror four\nerror six\n")
+
+	// 轮转后从新文件开头读取
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("error seven\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, &out, "error two\nerror four\nerror six\nerror seven\n")
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func waitFor(t *testing.T, out *syncBuffer, want string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if out.String() == want {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("got %q, want %q", out.String(), want)
+}
+
+func TestSearch(t *testing.T) {
+	dir := tempDir(t)
+	path := filepath.Join(dir, "app.log")
+	now := time.Now()
+	write := func(name, content string, age time.Duration) {
+		p := filepath.Join(dir, name)
+		if strings.HasSuffix(name, ".gz") {
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			gz.Write([]byte(content))
+			gz.Close()
+			content = buf.String()
+		}
+		if err := os.WriteFile(p, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		os.Chtimes(p, now.Add(-age), now.Add(-age))
+	}
+	write("app.log", "ok\nerror a\n", 0)
+	write("app.log.1", "error b\n", time.Hour)
+	write("app.log.2.gz", "ok\nerror c\n", 2*time.Hour)
+	write("app.log.3.xz", "error d\n", 3*time.Hour)
+	write("other.log", "error e\n", 0)
+
+	filter, _ := NewFilter("error", false)
+	var out bytes.Buffer
+	result, err := Search(context.Background(), &out, path, filter, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "app.log:2: error a\napp.log.1:1: error b\napp.log.2.gz:2: error c\n"
+	if out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+	if result.Files != 3 || result.Matches != 3 || result.Truncated {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	out.Reset()
+	result, err = Search(context.Background(), &out, path, filter, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Matches != 2 || !result.Truncated {
+		t.Errorf("unexpected limited result: %+v", result)
+	}
+}
+
+func TestSymlink(t *testing.T) {
+	dir := tempDir(t)
+	secret := filepath.Join(dir, "secret")
+	if err := os.WriteFile(secret, []byte("password\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	logs := filepath.Join(dir, "logs")
+	if err := os.Mkdir(logs, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(secret, filepath.Join(logs, "app.log")); err != nil {
+		t.Skip(err)
+	}
+	if err := os.Symlink(dir, filepath.Join(logs, "parent")); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for _, p := range []string{filepath.Join(logs, "app.log"), filepath.Join(logs, "parent", "secret")} {
+		var out bytes.Buffer
+		if err := Follow(ctx, &out, p, 10, nil); err == nil {
+			t.Errorf("Follow(%s) should be refused", p)
+		}
+		result, _ := Search(ctx, &out, p, nil, 0)
+		if result.Matches != 0 || strings.Contains(out.String(), "password") {
+			t.Errorf("Search(%s) read through a symlink: %q", p, out.String())
+		}
+	}
+}
+
+// tempDir 临时目录本身可能位于符号链接下，如 macOS 的 /var
+func tempDir(t *testing.T) string {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
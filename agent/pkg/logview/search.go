@@ -0,0 +1,194 @@
+package logview
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+type SearchResult struct {
+	Files     int  // 已搜索的文件数
+	Matches   int  // 匹配的行数
+	Truncated bool // 达到行数、文件数或字节数上限而提前结束
+}
+
+// Search 在文件及其轮转文件（如 app.log.1、app.log.2.gz、app.log-20240101）中搜索匹配的行，
+// 当前文件最先搜索，其余按修改时间从新到旧，每行以 文件名:行号: 开头
+func Search(ctx context.Context, w io.Writer, path string, filter *Filter, limit int) (SearchResult, error) {
+	var result SearchResult
+	path, err := CleanPath(path)
+	if err != nil {
+		return result, err
+	}
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	limit = min(limit, MaxSearchLimit)
+
+	files, err := RotatedFiles(path)
+	if err != nil {
+		return result, err
+	}
+	if len(files) > maxSearchFiles {
+		files, result.Truncated = files[:maxSearchFiles], true
+	}
+
+	bw := bufio.NewWriter(w)
+	defer bw.Flush()
+	budget := int64(maxSearchBytes)
+	for _, file := range files {
+		if ctx.Err() != nil {
+			result.Truncated = true
+			break
+		}
+		matches, read, err := searchFile(ctx, bw, file, filter, limit-result.Matches, budget)
+		if err != nil {
+			fmt.Fprintf(bw, "%s: %v\n", filepath.Base(file), err)
+			continue
+		}
+		result.Files++
+		result.Matches += matches
+		budget -= read
+		if result.Matches >= limit || budget <= 0 {
+			result.Truncated = true
+			break
+		}
+	}
+	return result, bw.Flush()
+}
+
+// RotatedFiles 返回 path 本身及同目录下的轮转文件
+func RotatedFiles(path string) ([]string, error) {
+	dir, base := filepath.Split(path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	type candidate struct {
+		path    string
+		current bool
+		modTime int64
+	}
+	var candidates []candidate
+	for _, e := range entries {
+		name := e.Name()
+		if name != base && !strings.HasPrefix(name, base+".") && !strings.HasPrefix(name, base+"-") {
+			continue
+		}
+		// 只支持未压缩与 gzip 压缩的文件
+		if ext := filepath.Ext(name); name != base && slices.Contains([]string{".bz2", ".xz", ".zst", ".lz4", ".zip"}, ext) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		candidates = append(candidates, candidate{
+			path:    filepath.Join(dir, name),
+			current: name == base,
+			modTime: info.ModTime().UnixNano(),
+		})
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no such file: %s", path)
+	}
+
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		if a.current != b.current {
+			if a.current {
+				return -1
+			}
+			return 1
+		}
+		if a.modTime != b.modTime {
+			if a.modTime > b.modTime {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.path, b.path)
+	})
+	files := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		files = append(files, c.path)
+	}
+	return files, nil
+}
+
+// searchFile 返回匹配的行数与读取的字节数（gzip 文件为解压后的字节数）
+func searchFile(ctx context.Context, w io.Writer, path string, filter *Filter, limit int, budget int64) (int, int64, error) {
+	f, _, err := openRegular(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return 0, 0, err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	counter := &countingReader{r: io.LimitReader(r, budget)}
+
+	name := filepath.Base(path)
+	br := bufio.NewReaderSize(counter, 64<<10)
+	var matches, lineno int
+	for matches < limit {
+		line, err := readLine(br)
+		if line == "" && err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return matches, counter.n, err
+		}
+		lineno++
+		if filter.Match(line) {
+			fmt.Fprintf(w, "%s:%d: %s\n", name, lineno, truncateLine(line))
+			matches++
+		}
+		// 每读取一定行数检查一次是否已取消
+		if lineno%4096 == 0 && ctx.Err() != nil {
+			break
+		}
+	}
+	return matches, counter.n, nil
+}
+
+// readLine 读取一行，超长部分被丢弃
+func readLine(br *bufio.Reader) (string, error) {
+	var sb strings.Builder
+	for {
+		chunk, isPrefix, err := br.ReadLine()
+		if sb.Len() < maxLineLength+1 {
+			sb.Write(chunk[:min(len(chunk), maxLineLength+1-sb.Len())])
+		}
+		if err != nil {
+			return sb.String(), err
+		}
+		if !isPrefix {
+			return sb.String(), nil
+		}
+	}
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -3,7 +3,9 @@ package disk
 import (
 	"context"
 	"os/exec"
+	"path/filepath"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -120,3 +122,75 @@ func getDevices(ctx context.Context) (map[string]string, error) {
 
 	return devices, nil
 }
+
+// Mount 单个挂载点的空间与 inode 使用情况
+type Mount struct {
+	Mountpoint  string
+	Device      string
+	Fstype      string
+	Total       uint64
+	Used        uint64
+	InodesTotal uint64
+	InodesUsed  uint64
+}
+
+// GetMounts 与 GetState 统计相同的挂载点，按挂载路径排序
+func GetMounts(ctx context.Context) ([]Mount, error) {
+	devices, err := getDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 白名单只包含挂载路径，从分区列表中找到对应的设备
+	deviceOf := make(map[string]string)
+	if partitions, err := psDisk.PartitionsWithContext(ctx, false); err == nil {
+		for _, p := range partitions {
+			deviceOf[p.Mountpoint] = p.Device
+		}
+	}
+
+	mounts := make([]Mount, 0, len(devices))
+	for _, mountPath := range devices {
+		usage, err := psDisk.UsageWithContext(ctx, mountPath)
+		if err != nil {
+			continue
+		}
+		mounts = append(mounts, Mount{
+			Mountpoint:  mountPath,
+			Device:      deviceOf[mountPath],
+			Fstype:      usage.Fstype,
+			Total:       usage.Total,
+			Used:        usage.Used,
+			InodesTotal: usage.InodesTotal,
+			InodesUsed:  usage.InodesUsed,
+		})
+	}
+	slices.SortFunc(mounts, func(a, b Mount) int {
+		return strings.Compare(a.Mountpoint, b.Mountpoint)
+	})
+	return mounts, nil
+}
+
+// GetIOCounters 返回挂载点所在块设备的 I/O 计数器，Key 为设备名
+func GetIOCounters(ctx context.Context, mounts []Mount) (map[string]psDisk.IOCountersStat, error) {
+	counters, err := psDisk.IOCountersWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(mounts))
+	for _, m := range mounts {
+		if m.Device != "" {
+			names[filepath.Base(m.Device)] = true
+		}
+	}
+
+	ret := make(map[string]psDisk.IOCountersStat, len(names))
+	for key, c := range counters {
+		// LVM 等设备在 diskstats 中为 dm-N，标签才是 /dev/mapper 下的名称
+		if names[key] || (c.Label != "" && names[c.Label]) {
+			ret[key] = c
+		}
+	}
+	return ret, nil
+}
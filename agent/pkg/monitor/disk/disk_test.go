@@ -0,0 +1,34 @@
+package disk
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestGetMounts(t *testing.T) {
+	root := "/"
+	if dir := os.Getenv("SystemDrive"); dir != "" {
+		root = dir + `\`
+	}
+	ctx := context.WithValue(context.Background(), DiskKey, []string{root, "/nonexistent-mountpoint"})
+	mounts, err := GetMounts(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mounts) != 1 {
+		t.Fatalf("unexpected mounts: %+v", mounts)
+	}
+	m := mounts[0]
+	if m.Mountpoint != root || m.Total == 0 || m.Used > m.Total || m.InodesUsed > m.InodesTotal {
+		t.Fatalf("unexpected mount: %+v", m)
+	}
+
+	used, err := GetState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if used == 0 {
+		t.Fatal("used should not be zero")
+	}
+}
@@ -3,12 +3,16 @@ package monitor
 import (
 	"context"
 	"runtime"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	psDisk "github.com/shirou/gopsutil/v4/disk"
 	"github.com/shirou/gopsutil/v4/host"
 	"github.com/shirou/gopsutil/v4/mem"
+	"github.com/shirou/gopsutil/v4/net"
 	"github.com/shirou/gopsutil/v4/process"
 
 	"github.com/nezhahq/agent/model"
@@ -34,6 +38,13 @@ var (
 	netInSpeed, netOutSpeed, netInTransfer, netOutTransfer, lastUpdateNetStats uint64
 	cachedBootTime                                                             time.Time
 	temperatureStat                                                            []model.SensorTemperature
+
+	nicStats        []model.NICState
+	lastNICCounters map[string]net.IOCountersStat
+
+	diskIOStats      []model.DiskIOState
+	lastDiskCounters map[string]psDisk.IOCountersStat
+	lastUpdateDiskIO time.Time
 )
 
 // 获取设备数据的最大尝试次数
@@ -153,6 +164,7 @@ func GetState(skipConnectionCount bool, skipProcsCount bool) *model.HostState {
 	}
 
 	ret.DiskUsed = getDiskUsed()
+	ret.Mounts, ret.DiskIO = getMounts()
 
 	loadStat := tryStat(context.Background(), Load, load.GetState)
 	ret.Load1 = loadStat.Load1
@@ -180,6 +192,7 @@ func GetState(skipConnectionCount bool, skipProcsCount bool) *model.HostState {
 
 	ret.NetInTransfer, ret.NetOutTransfer = netInTransfer, netOutTransfer
 	ret.NetInSpeed, ret.NetOutSpeed = netInSpeed, netOutSpeed
+	ret.NICs = nicStats
 	ret.Uptime = uint64(time.Since(cachedBootTime).Seconds())
 
 	if !skipConnectionCount {
@@ -194,16 +207,38 @@ func TrackNetworkSpeed() {
 	var innerNetInTransfer, innerNetOutTransfer uint64
 
 	ctx := context.WithValue(context.Background(), nic.NICKey, agentConfig.NICAllowlist)
-	nc, err := nic.GetState(ctx)
+	nc, err := nic.GetInterfaces(ctx)
 	if err != nil {
 		return
 	}
 
-	innerNetInTransfer = nc[0]
-	innerNetOutTransfer = nc[1]
-
 	now := uint64(time.Now().Unix())
 	diff := util.SubUintChecked(now, lastUpdateNetStats)
+
+	prevStats := make(map[string]model.NICState, len(nicStats))
+	for _, n := range nicStats {
+		prevStats[n.Name] = n
+	}
+	stats := make([]model.NICState, 0, len(nc))
+	counters := make(map[string]net.IOCountersStat, len(nc))
+	for _, v := range nc {
+		innerNetInTransfer += v.BytesRecv
+		innerNetOutTransfer += v.BytesSent
+		counters[v.Name] = v
+
+		stat := prevStats[v.Name]
+		stat.Name = v.Name
+		stat.InTransfer, stat.OutTransfer = v.BytesRecv, v.BytesSent
+		stat.InErrors, stat.OutErrors = v.Errin, v.Errout
+		stat.InDrops, stat.OutDrops = v.Dropin, v.Dropout
+		if prev, ok := lastNICCounters[v.Name]; ok && diff > 0 {
+			stat.InSpeed = util.SubUintChecked(v.BytesRecv, prev.BytesRecv) / diff
+			stat.OutSpeed = util.SubUintChecked(v.BytesSent, prev.BytesSent) / diff
+			stat.ErrorRate = float64(util.SubUintChecked(v.Errin+v.Errout, prev.Errin+prev.Errout)) / float64(diff)
+		}
+		stats = append(stats, stat)
+	}
+
 	if diff > 0 {
 		netInSpeed = util.SubUintChecked(innerNetInTransfer, netInTransfer) / diff
 		netOutSpeed = util.SubUintChecked(innerNetOutTransfer, netOutTransfer) / diff
@@ -211,6 +246,8 @@ func TrackNetworkSpeed() {
 	netInTransfer = innerNetInTransfer
 	netOutTransfer = innerNetOutTransfer
 	lastUpdateNetStats = now
+	nicStats = stats
+	lastNICCounters = counters
 }
 
 func getDiskTotal() uint64 {
@@ -227,6 +264,51 @@ func getDiskUsed() uint64 {
 	return used
 }
 
+// getMounts 挂载点使用情况，以及与上次采集相比的块设备读写速度
+func getMounts() ([]model.MountState, []model.DiskIOState) {
+	ctx := context.WithValue(context.Background(), disk.DiskKey, agentConfig.HardDrivePartitionAllowlist)
+	mounts, err := disk.GetMounts(ctx)
+	if err != nil {
+		return nil, nil
+	}
+
+	ret := make([]model.MountState, 0, len(mounts))
+	for _, m := range mounts {
+		ret = append(ret, model.MountState(m))
+	}
+
+	counters, err := disk.GetIOCounters(ctx, mounts)
+	if err != nil {
+		return ret, nil
+	}
+	now := time.Now()
+	elapsed := now.Sub(lastUpdateDiskIO).Seconds()
+	if elapsed >= 1 {
+		stats := make([]model.DiskIOState, 0, len(counters))
+		for name, c := range counters {
+			prev, ok := lastDiskCounters[name]
+			if !ok {
+				continue
+			}
+			stats = append(stats, model.DiskIOState{
+				Device:     name,
+				ReadSpeed:  uint64(float64(util.SubUintChecked(c.ReadBytes, prev.ReadBytes)) / elapsed),
+				WriteSpeed: uint64(float64(util.SubUintChecked(c.WriteBytes, prev.WriteBytes)) / elapsed),
+				ReadIOPS:   uint64(float64(util.SubUintChecked(c.ReadCount, prev.ReadCount)) / elapsed),
+				WriteIOPS:  uint64(float64(util.SubUintChecked(c.WriteCount, prev.WriteCount)) / elapsed),
+			})
+		}
+		slices.SortFunc(stats, func(a, b model.DiskIOState) int {
+			return strings.Compare(a.Device, b.Device)
+		})
+		diskIOStats = stats
+		lastDiskCounters = counters
+		lastUpdateDiskIO = now
+	}
+
+	return ret, diskIOStats
+}
+
 func getConns() (tcpConnCount, udpConnCount uint64) {
 	connStat, err := conn.GetState(context.Background())
 	if err != nil {
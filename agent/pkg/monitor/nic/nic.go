@@ -21,6 +21,21 @@ var (
 
 func GetState(ctx context.Context) ([]uint64, error) {
 	var netInTransfer, netOutTransfer uint64
+	nc, err := GetInterfaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range nc {
+		netInTransfer += v.BytesRecv
+		netOutTransfer += v.BytesSent
+	}
+
+	return []uint64{netInTransfer, netOutTransfer}, nil
+}
+
+// GetInterfaces 返回参与流量统计的网卡计数器
+func GetInterfaces(ctx context.Context) ([]net.IOCountersStat, error) {
 	nc, err := net.IOCountersWithContext(ctx, true)
 	if err != nil {
 		return nil, err
@@ -28,6 +43,7 @@ func GetState(ctx context.Context) ([]uint64, error) {
 
 	allowList, _ := ctx.Value(NICKey).(map[string]bool)
 
+	ret := make([]net.IOCountersStat, 0, len(nc))
 	for _, v := range nc {
 		if defaultMatcher.Contains([]byte(v.Name)) && !allowList[v.Name] {
 			continue
@@ -35,9 +51,8 @@ func GetState(ctx context.Context) ([]uint64, error) {
 		if len(allowList) > 0 && !allowList[v.Name] {
 			continue
 		}
-		netInTransfer += v.BytesRecv
-		netOutTransfer += v.BytesSent
+		ret = append(ret, v)
 	}
 
-	return []uint64{netInTransfer, netOutTransfer}, nil
+	return ret, nil
 }
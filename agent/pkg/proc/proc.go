@@ -0,0 +1,150 @@
+package proc
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+const (
+	SortCPU    = "cpu"
+	SortMemory = "memory"
+
+	DefaultLimit = 20
+	MaxLimit     = 100
+
+	// 计算 CPU 占用的采样间隔
+	sampleInterval = time.Second
+	maxCmdline     = 1024
+)
+
+// Process CPU 为采样间隔内的占用率，单核满载为 100
+type Process struct {
+	PID       int32   `json:"pid"`
+	PPID      int32   `json:"ppid"`
+	Name      string  `json:"name"`
+	User      string  `json:"user"`
+	Cmdline   string  `json:"cmdline"`
+	RSS       uint64  `json:"rss"`
+	CPU       float64 `json:"cpu"`
+	StartTime int64   `json:"start_time"`
+}
+
+type sample struct {
+	p   *process.Process
+	cpu float64
+	rss uint64
+}
+
+// Top 返回 CPU 或内存占用最高的进程
+func Top(ctx context.Context, sortBy string, limit int) ([]*Process, error) {
+	if sortBy != SortMemory {
+		sortBy = SortCPU
+	}
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	limit = min(limit, MaxLimit)
+
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]*sample, 0, len(procs))
+	before := make(map[int32]float64, len(procs))
+	if sortBy == SortCPU {
+		for _, p := range procs {
+			if t, err := p.TimesWithContext(ctx); err == nil {
+				before[p.Pid] = t.User + t.System
+			}
+		}
+	}
+	start := time.Now()
+	if sortBy == SortCPU {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(sampleInterval):
+		}
+	}
+	elapsed := time.Since(start).Seconds()
+
+	for _, p := range procs {
+		s := &sample{p: p}
+		if m, err := p.MemoryInfoWithContext(ctx); err == nil {
+			s.rss = m.RSS
+		} else if sortBy == SortMemory {
+			// 已退出或无权限读取的进程
+			continue
+		}
+		if sortBy == SortCPU {
+			prev, ok := before[p.Pid]
+			t, err := p.TimesWithContext(ctx)
+			if !ok || err != nil {
+				continue
+			}
+			s.cpu = max(t.User+t.System-prev, 0) / elapsed * 100
+		}
+		samples = append(samples, s)
+	}
+
+	slices.SortStableFunc(samples, func(a, b *sample) int {
+		if sortBy == SortMemory {
+			return cmp.Compare(b.rss, a.rss)
+		}
+		if c := cmp.Compare(b.cpu, a.cpu); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.rss, a.rss)
+	})
+
+	result := make([]*Process, 0, limit)
+	for _, s := range samples[:min(limit, len(samples))] {
+		p := &Process{PID: s.p.Pid, RSS: s.rss, CPU: s.cpu}
+		p.PPID, _ = s.p.PpidWithContext(ctx)
+		p.Name, _ = s.p.NameWithContext(ctx)
+		p.User, _ = s.p.UsernameWithContext(ctx)
+		p.Cmdline, _ = s.p.CmdlineWithContext(ctx)
+		if len(p.Cmdline) > maxCmdline {
+			p.Cmdline = p.Cmdline[:maxCmdline]
+		}
+		if created, err := s.p.CreateTimeWithContext(ctx); err == nil {
+			p.StartTime = created / 1000
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+// Signal 向进程发送信号，拒绝 init 与 agent 自身
+func Signal(pid int32, name string) error {
+	if pid <= 1 || int(pid) == os.Getpid() {
+		return fmt.Errorf("refusing to signal pid %d", pid)
+	}
+	sig, ok := signals[strings.TrimPrefix(strings.ToUpper(name), "SIG")]
+	if !ok {
+		return fmt.Errorf("unsupported signal: %s", name)
+	}
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		if errors.Is(err, process.ErrorProcessNotRunning) {
+			return fmt.Errorf("process %d not found", pid)
+		}
+		return err
+	}
+	return sendSignal(p, sig)
+}
+
+// ValidSignal 判断当前系统是否支持该信号
+func ValidSignal(name string) bool {
+	_, ok := signals[strings.TrimPrefix(strings.ToUpper(name), "SIG")]
+	return ok
+}
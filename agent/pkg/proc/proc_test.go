@@ -0,0 +1,72 @@
+package proc
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestTop(t *testing.T) {
+	ctx := context.Background()
+	procs, err := Top(ctx, SortMemory, MaxLimit+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(procs) == 0 || len(procs) > MaxLimit {
+		t.Fatalf("unexpected process count: %d", len(procs))
+	}
+	for i := 1; i < len(procs); i++ {
+		if procs[i].RSS > procs[i-1].RSS {
+			t.Fatalf("processes should be sorted by rss: %+v", procs)
+		}
+	}
+
+	procs, err = Top(ctx, SortCPU, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(procs) == 0 || len(procs) > 3 {
+		t.Fatalf("unexpected process count: %d", len(procs))
+	}
+	if procs[0].StartTime == 0 || procs[0].Name == "" {
+		t.Fatalf("unexpected process: %+v", procs[0])
+	}
+}
+
+func TestSignal(t *testing.T) {
+	if err := Signal(1, "TERM"); err == nil {
+		t.Fatal("signaling init should be refused")
+	}
+	if err := Signal(int32(os.Getpid()), "KILL"); err == nil {
+		t.Fatal("signaling the agent should be refused")
+	}
+	if err := Signal(1234, "WINCH"); err == nil || ValidSignal("WINCH") {
+		t.Fatal("unsupported signal should be refused")
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("sleep is not available")
+	}
+
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skip(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	if err := Signal(int32(cmd.Process.Pid), "sigterm"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("process should be terminated by signal")
+		}
+	case <-time.After(5 * time.Second):
+		cmd.Process.Kill()
+		t.Fatal("process was not terminated")
+	}
+}
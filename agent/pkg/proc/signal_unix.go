@@ -0,0 +1,25 @@
+//go:build !windows
+
+package proc
+
+import (
+	"syscall"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+var signals = map[string]syscall.Signal{
+	"HUP":  syscall.SIGHUP,
+	"INT":  syscall.SIGINT,
+	"QUIT": syscall.SIGQUIT,
+	"KILL": syscall.SIGKILL,
+	"TERM": syscall.SIGTERM,
+	"USR1": syscall.SIGUSR1,
+	"USR2": syscall.SIGUSR2,
+	"STOP": syscall.SIGSTOP,
+	"CONT": syscall.SIGCONT,
+}
+
+func sendSignal(p *process.Process, sig syscall.Signal) error {
+	return p.SendSignal(sig)
+}
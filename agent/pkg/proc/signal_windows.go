@@ -0,0 +1,19 @@
+//go:build windows
+
+package proc
+
+import (
+	"syscall"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// Windows 不支持信号，TERM 与 KILL 均结束进程
+var signals = map[string]syscall.Signal{
+	"KILL": syscall.SIGKILL,
+	"TERM": syscall.SIGTERM,
+}
+
+func sendSignal(p *process.Process, _ syscall.Signal) error {
+	return p.Kill()
+}
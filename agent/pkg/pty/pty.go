@@ -4,8 +4,11 @@ package pty
 
 import (
 	"errors"
+	"fmt"
+	"log"
 	"os"
 	"os/exec"
+	"strings"
 	"syscall"
 
 	opty "github.com/creack/pty"
@@ -25,41 +28,84 @@ func DownloadDependency() error {
 }
 
 func Start() (IPty, error) {
-	return StartWithAudit("", "", "")
+	return StartWithAudit("", "", "", "")
 }
 
-// StartWithAudit 启动带审计功能的 PTY
-func StartWithAudit(wrapperPath, apiURL, streamID string) (IPty, error) {
+// StartWithAudit 启动带审计功能的 PTY，osUser 不为空时以该系统用户身份启动登录 Shell
+func StartWithAudit(wrapperPath, apiURL, streamID, osUser string) (IPty, error) {
+	var login *loginUser
+	if osUser != "" {
+		var err error
+		if login, err = lookupLoginUser(osUser); err != nil {
+			return nil, fmt.Errorf("查找终端用户 %s 失败: %w", osUser, err)
+		}
+	}
+
 	var shellPath string
+	if login != nil && login.shell != "" && isUsableShell(login.shell) {
+		shellPath = login.shell
+	}
 	for _, sh := range defaultShells {
-		shellPath, _ = exec.LookPath(sh)
 		if shellPath != "" {
 			break
 		}
+		shellPath, _ = exec.LookPath(sh)
 	}
 	if shellPath == "" {
 		return nil, errors.New("没有可用终端")
 	}
 
-	var cmd *exec.Cmd
+	baseEnv := os.Environ()
+	if login != nil {
+		baseEnv = login.env()
+	}
+
+	// 审计包装器依赖 bash 的 --rcfile，用户的登录 Shell 不是 bash 时也使用 bash 启动
+	var bashPath string
 	if wrapperPath != "" && apiURL != "" && streamID != "" {
+		if bashPath, _ = exec.LookPath("bash"); bashPath == "" {
+			log.Printf("未找到 bash，终端 %s 将不进行命令审计", streamID)
+		}
+	}
+
+	var cmd *exec.Cmd
+	if bashPath != "" {
 		// 使用审计包装器 - 让 bash 在启动时 source 配置文件
-		cmd = exec.Command(shellPath, "--rcfile", wrapperPath) // #nosec
-		cmd.Env = append(os.Environ(),
+		cmd = exec.Command(bashPath, "--rcfile", wrapperPath) // #nosec
+		cmd.Env = append(baseEnv,
 			"TERM=xterm",
 			"NEZHA_AUDIT_API_URL="+apiURL,
 			"NEZHA_STREAM_ID="+streamID,
 		)
+	} else if login != nil {
+		// 以登录 Shell 方式启动，加载用户自己的 profile
+		cmd = exec.Command(shellPath, "-l") // #nosec
+		cmd.Env = append(baseEnv, "TERM=xterm")
 	} else {
 		// 直接使用原始 Shell
 		cmd = exec.Command(shellPath) // #nosec
-		cmd.Env = append(os.Environ(), "TERM=xterm")
+		cmd.Env = append(baseEnv, "TERM=xterm")
 	}
 
-	tty, err := opty.Start(cmd)
+	attrs := &syscall.SysProcAttr{Setsid: true, Setctty: true}
+	if login != nil {
+		cmd.Dir = login.home
+		attrs.Credential = login.cred
+	}
+
+	tty, err := opty.StartWithAttrs(cmd, nil, attrs)
 	return &Pty{tty: tty, cmd: cmd}, err
 }
 
+// isUsableShell 判断用户的登录 Shell 是否可以用于交互
+func isUsableShell(shell string) bool {
+	if strings.HasSuffix(shell, "/nologin") || strings.HasSuffix(shell, "/false") {
+		return false
+	}
+	_, err := os.Stat(shell)
+	return err == nil
+}
+
 func (pty *Pty) Write(p []byte) (n int, err error) {
 	return pty.tty.Write(p)
 }
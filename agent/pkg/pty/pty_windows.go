@@ -3,6 +3,7 @@
 package pty
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -37,6 +38,14 @@ func Start() (IPty, error) {
 	return &Pty{tty: tty}, err
 }
 
+// StartWithAudit Windows 下暂不支持审计包装器与指定终端用户
+func StartWithAudit(wrapperPath, apiURL, streamID, osUser string) (IPty, error) {
+	if osUser != "" {
+		return nil, errors.New("Windows 不支持指定终端用户")
+	}
+	return Start()
+}
+
 func (pty *Pty) Write(p []byte) (n int, err error) {
 	return pty.tty.Write(p)
 }
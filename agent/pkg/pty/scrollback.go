@@ -0,0 +1,58 @@
+package pty
+
+import "sync"
+
+// DefaultScrollbackSize 终端回滚缓冲区默认大小
+const DefaultScrollbackSize = 256 * 1024
+
+// Scrollback 保存终端最近的输出，用于浏览器重连后重放
+type Scrollback struct {
+	mu   sync.Mutex
+	buf  []byte
+	pos  int
+	full bool
+}
+
+func NewScrollback(size int) *Scrollback {
+	if size <= 0 {
+		size = DefaultScrollbackSize
+	}
+	return &Scrollback{buf: make([]byte, size)}
+}
+
+func (s *Scrollback) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := len(p)
+	if n >= len(s.buf) {
+		copy(s.buf, p[n-len(s.buf):])
+		s.pos = 0
+		s.full = true
+		return n, nil
+	}
+
+	c := copy(s.buf[s.pos:], p)
+	if c < n {
+		copy(s.buf, p[c:])
+		s.full = true
+	}
+	s.pos = (s.pos + n) % len(s.buf)
+	if s.pos == 0 {
+		s.full = true
+	}
+	return n, nil
+}
+
+// Bytes 按写入顺序返回缓冲区中的内容
+func (s *Scrollback) Bytes() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.full {
+		return append([]byte(nil), s.buf[:s.pos]...)
+	}
+	out := make([]byte, 0, len(s.buf))
+	out = append(out, s.buf[s.pos:]...)
+	return append(out, s.buf[:s.pos]...)
+}
@@ -0,0 +1,30 @@
+package pty
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestScrollback(t *testing.T) {
+	cases := []struct {
+		size   int
+		writes []string
+		expect string
+	}{
+		{8, []string{"abc"}, "abc"},
+		{8, []string{"abcd", "efgh"}, "abcdefgh"},
+		{8, []string{"abcde", "fghij"}, "cdefghij"},
+		{8, []string{"abcdefghijkl"}, "efghijkl"},
+		{8, []string{"ab", "cdefghijklmn", "op"}, "ijklmnop"},
+	}
+
+	for i, c := range cases {
+		sb := NewScrollback(c.size)
+		for _, w := range c.writes {
+			sb.Write([]byte(w))
+		}
+		if got := sb.Bytes(); !bytes.Equal(got, []byte(c.expect)) {
+			t.Fatalf("case %d: expected %q, but got %q", i, c.expect, got)
+		}
+	}
+}
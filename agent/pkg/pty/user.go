@@ -0,0 +1,89 @@
+//go:build !windows
+
+package pty
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// loginUser 终端登录用户信息
+type loginUser struct {
+	name  string
+	home  string
+	shell string
+	cred  *syscall.Credential
+}
+
+// lookupLoginUser 查找系统用户并生成降权所需的凭据
+func lookupLoginUser(name string) (*loginUser, error) {
+	u, err := user.Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("无效的 uid %s: %w", u.Uid, err)
+	}
+	gid, err := strconv.ParseUint(u.Gid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("无效的 gid %s: %w", u.Gid, err)
+	}
+
+	cred := &syscall.Credential{Uid: uint32(uid), Gid: uint32(gid)}
+	if groupIds, err := u.GroupIds(); err == nil {
+		for _, g := range groupIds {
+			if id, err := strconv.ParseUint(g, 10, 32); err == nil {
+				cred.Groups = append(cred.Groups, uint32(id))
+			}
+		}
+	}
+
+	return &loginUser{
+		name:  u.Username,
+		home:  u.HomeDir,
+		shell: lookupUserShell(u.Username),
+		cred:  cred,
+	}, nil
+}
+
+// env 返回登录用户的基础环境变量
+func (u *loginUser) env() []string {
+	env := []string{
+		"HOME=" + u.home,
+		"USER=" + u.name,
+		"LOGNAME=" + u.name,
+		"PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin",
+	}
+	if u.shell != "" {
+		env = append(env, "SHELL="+u.shell)
+	}
+	if lang := os.Getenv("LANG"); lang != "" {
+		env = append(env, "LANG="+lang)
+	}
+	return env
+}
+
+// lookupUserShell 从 /etc/passwd 读取用户的登录 Shell
+func lookupUserShell(name string) string {
+	f, err := os.Open("/etc/passwd")
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ":")
+		if len(fields) == 7 && fields[0] == name {
+			return fields[6]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,231 @@
+package sshtunnel
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+const (
+	TypeLocal   = "local"   // 本地转发，等价于 ssh -L
+	TypeRemote  = "remote"  // 远程转发，等价于 ssh -R
+	TypeDynamic = "dynamic" // 动态转发，等价于 ssh -D，在本地提供 SOCKS5 代理
+)
+
+const (
+	defaultSSHPort          = "22"
+	defaultAliveInterval    = 30 * time.Second
+	defaultAliveCountMax    = 3
+	defaultConnectTimeout   = 15 * time.Second
+	defaultBindAddress      = "0.0.0.0"
+	defaultKnownIdentityDir = ".ssh"
+)
+
+var defaultIdentityFiles = []string{"id_ed25519", "id_ecdsa", "id_rsa"}
+
+// Config 一条隧道的配置，字段与 TaskAutoSSH 对应
+type Config struct {
+	ID         uint64
+	Type       string
+	SourcePort int
+	TargetHost string
+	TargetPort int
+	SSHHost    string            // user@host:port
+	Options    map[string]string // 兼容 ssh -o 的选项，如 ServerAliveInterval、IdentityFile
+
+	// 动态转发的 SOCKS5 认证，为空时不需要认证
+	SocksUsername string
+	SocksPassword string
+
+	// 固定的主机密钥指纹（SHA256:...），为空时接受任意主机密钥并记录其指纹
+	HostKeyFingerprint string
+	// 面板下发的私钥，优先于本机的密钥
+	PrivateKey string
+	// 每个方向的限速（字节/秒），所有连接共享，为 0 时不限速
+	RateLimit uint64
+	// local 与 dynamic 映射的监听地址，为空时为 0.0.0.0
+	BindAddress string
+	// 允许连接的来源 CIDR 或 IP，为空时不限制
+	AllowedSources []string
+}
+
+// endpoint 解析后的 SSH 服务器地址
+type endpoint struct {
+	user string
+	addr string
+}
+
+func parseSSHHost(sshHost string) (*endpoint, error) {
+	if sshHost == "" {
+		return nil, errors.New("SSH 服务器地址未配置")
+	}
+
+	var ep endpoint
+	host := sshHost
+	if idx := strings.LastIndex(host, "@"); idx != -1 {
+		ep.user = host[:idx]
+		host = host[idx+1:]
+	}
+	if ep.user == "" {
+		if u, err := user.Current(); err == nil {
+			ep.user = u.Username
+		}
+	}
+
+	if h, p, err := net.SplitHostPort(host); err == nil {
+		ep.addr = net.JoinHostPort(h, p)
+	} else {
+		ep.addr = net.JoinHostPort(strings.Trim(host, "[]"), defaultSSHPort)
+	}
+	return &ep, nil
+}
+
+func (c *Config) option(key string) string {
+	for k, v := range c.Options {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return ""
+}
+
+func (c *Config) durationOption(key string, def time.Duration) time.Duration {
+	if v, err := strconv.Atoi(c.option(key)); err == nil && v > 0 {
+		return time.Duration(v) * time.Second
+	}
+	return def
+}
+
+func (c *Config) intOption(key string, def int) int {
+	if v, err := strconv.Atoi(c.option(key)); err == nil && v > 0 {
+		return v
+	}
+	return def
+}
+
+func (c *Config) validate() error {
+	if c.Type != TypeLocal && c.Type != TypeRemote && c.Type != TypeDynamic {
+		return fmt.Errorf("不支持的映射类型: %s", c.Type)
+	}
+	if c.SourcePort <= 0 || c.SourcePort > 65535 {
+		return errors.New("端口范围无效")
+	}
+	if c.PrivateKey != "" {
+		if _, err := ssh.ParsePrivateKey([]byte(c.PrivateKey)); err != nil {
+			return fmt.Errorf("私钥无效: %w", err)
+		}
+	}
+	if c.BindAddress != "" && c.BindAddress != "localhost" {
+		if _, err := netip.ParseAddr(c.BindAddress); err != nil {
+			return fmt.Errorf("监听地址无效: %s", c.BindAddress)
+		}
+	}
+	if _, err := c.allowedPrefixes(); err != nil {
+		return err
+	}
+	if c.Type == TypeDynamic {
+		// 目标由 SOCKS5 客户端指定
+		if c.SocksUsername == "" && c.SocksPassword != "" {
+			return errors.New("SOCKS5 用户名未配置")
+		}
+		return nil
+	}
+	if c.TargetPort <= 0 || c.TargetPort > 65535 {
+		return errors.New("端口范围无效")
+	}
+	if c.TargetHost == "" {
+		return errors.New("目标地址未配置")
+	}
+	return nil
+}
+
+func (c *Config) targetAddr() string {
+	return net.JoinHostPort(c.TargetHost, strconv.Itoa(c.TargetPort))
+}
+
+// sourceAddr remote 映射监听在 SSH 服务器上，绑定地址须与 authorized_keys 的 permitlisten 一致
+func (c *Config) sourceAddr() string {
+	bind := c.BindAddress
+	if bind == "" || c.Type == TypeRemote {
+		bind = defaultBindAddress
+	}
+	return net.JoinHostPort(bind, strconv.Itoa(c.SourcePort))
+}
+
+func (c *Config) allowedPrefixes() ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(c.AllowedSources))
+	for _, s := range c.AllowedSources {
+		if p, err := netip.ParsePrefix(s); err == nil {
+			prefixes = append(prefixes, p.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return nil, fmt.Errorf("来源地址无效: %s", s)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// authMethods 依次尝试面板下发的私钥、IdentityFile 选项、~/.ssh 下的默认私钥以及 ssh-agent
+func (c *Config) authMethods() ([]ssh.AuthMethod, func()) {
+	var signers []ssh.Signer
+	if c.PrivateKey != "" {
+		if signer, err := ssh.ParsePrivateKey([]byte(c.PrivateKey)); err == nil {
+			signers = append(signers, signer)
+		}
+	}
+
+	var files []string
+	if f := c.option("IdentityFile"); f != "" {
+		files = append(files, expandHome(f))
+	} else if home, err := os.UserHomeDir(); err == nil {
+		for _, name := range defaultIdentityFiles {
+			files = append(files, filepath.Join(home, defaultKnownIdentityDir, name))
+		}
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		if signer, err := ssh.ParsePrivateKey(data); err == nil {
+			signers = append(signers, signer)
+		}
+	}
+
+	var methods []ssh.AuthMethod
+	if len(signers) > 0 {
+		methods = append(methods, ssh.PublicKeys(signers...))
+	}
+
+	cleanup := func() {}
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+			cleanup = func() { conn.Close() }
+		}
+	}
+	return methods, cleanup
+}
+
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
@@ -0,0 +1,49 @@
+package sshtunnel
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+
+	"golang.org/x/time/rate"
+)
+
+// meteredWriter 按写入实时累计字节数，设置了限速时等待令牌后再写入
+type meteredWriter struct {
+	ctx     context.Context
+	w       io.Writer
+	n       *atomic.Uint64
+	limiter *rate.Limiter
+}
+
+func (m *meteredWriter) Write(p []byte) (int, error) {
+	var written int
+	for len(p) > 0 {
+		chunk := p
+		if m.limiter != nil {
+			if burst := m.limiter.Burst(); len(chunk) > burst {
+				chunk = chunk[:burst]
+			}
+			if err := m.limiter.WaitN(m.ctx, len(chunk)); err != nil {
+				return written, err
+			}
+		}
+		n, err := m.w.Write(chunk)
+		written += n
+		m.n.Add(uint64(n))
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// newLimiter 限速为 0 时不限制，突发量为一秒的流量
+func newLimiter(bytesPerSecond uint64) *rate.Limiter {
+	if bytesPerSecond == 0 {
+		return nil
+	}
+	burst := int(min(bytesPerSecond, 1<<30))
+	return rate.NewLimiter(rate.Limit(bytesPerSecond), burst)
+}
@@ -0,0 +1,32 @@
+package sshtunnel
+
+import (
+	"bytes"
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMeteredWriter(t *testing.T) {
+	var buf bytes.Buffer
+	var n atomic.Uint64
+	w := &meteredWriter{ctx: context.Background(), w: &buf, n: &n, limiter: newLimiter(1000)}
+
+	start := time.Now()
+	written, err := w.Write(make([]byte, 2500))
+	if err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	if written != 2500 || n.Load() != 2500 || buf.Len() != 2500 {
+		t.Fatalf("expected 2500 bytes, but got written=%d counted=%d buffered=%d", written, n.Load(), buf.Len())
+	}
+	// 突发 1000 字节后剩余 1500 字节需要约 1.5 秒
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Fatalf("expected rate limit to slow down the write, but took %s", elapsed)
+	}
+
+	if newLimiter(0) != nil {
+		t.Fatal("expected no limiter for zero rate")
+	}
+}
@@ -0,0 +1,480 @@
+package sshtunnel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/time/rate"
+)
+
+// 错误阶段，随状态一起上报给 Dashboard
+const (
+	ErrCodeConfig    = "config"
+	ErrCodeDial      = "dial"
+	ErrCodeHandshake = "handshake"
+	ErrCodeAuth      = "auth"
+	ErrCodeHostKey   = "hostkey"
+	ErrCodeListen    = "listen"
+	ErrCodeKeepalive = "keepalive"
+	ErrCodeClosed    = "closed"
+)
+
+const (
+	minBackoff = time.Second
+	maxBackoff = 2 * time.Minute
+	// 连接保持超过该时长后重置退避
+	stableDuration = time.Minute
+)
+
+// Error 带阶段信息的隧道错误
+type Error struct {
+	Code string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %v", e.Code, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func newError(code string, err error) *Error {
+	return &Error{Code: code, Err: err}
+}
+
+var errHostKeyMismatch = errors.New("host key mismatch")
+
+// ErrorCode 返回错误的阶段，非隧道错误返回空字符串
+func ErrorCode(err error) string {
+	var te *Error
+	if errors.As(err, &te) {
+		return te.Code
+	}
+	return ""
+}
+
+// Permanent 重连无法恢复的错误，隧道不再重试
+func Permanent(err error) bool {
+	code := ErrorCode(err)
+	return code == ErrCodeConfig || code == ErrCodeHostKey
+}
+
+// Stats 隧道计数
+type Stats struct {
+	Connected           bool
+	Reconnects          int    // 建立成功后的重连次数
+	Connections         uint64 // 累计转发的连接数
+	ActiveConnections   int64
+	FailedConnections   uint64 // 转发到目标失败的连接数
+	RejectedConnections uint64 // 被来源白名单拒绝的连接数
+	BytesIn             uint64 // 目标 -> 来源
+	BytesOut            uint64 // 来源 -> 目标
+	LastError           error
+
+	HostKeyFingerprint string // 最近一次握手时服务器的主机密钥指纹
+}
+
+// Event 隧道状态变化
+type Event struct {
+	Connected bool
+	Err       error // 断开原因
+	Stats     Stats
+}
+
+// Tunnel 基于 Go SSH 客户端的端口转发，断开后按指数退避重连
+type Tunnel struct {
+	cfg     Config
+	onEvent func(Event)
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu         sync.Mutex
+	started    bool
+	connected  bool
+	everUp     bool
+	reconnects int
+	lastErr    error
+	hostKey    string
+
+	connections atomic.Uint64
+	active      atomic.Int64
+	failed      atomic.Uint64
+	rejected    atomic.Uint64
+	bytesIn     atomic.Uint64
+	bytesOut    atomic.Uint64
+
+	limitIn  *rate.Limiter
+	limitOut *rate.Limiter
+	allowed  []netip.Prefix
+}
+
+func New(cfg Config, onEvent func(Event)) *Tunnel {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Tunnel{
+		cfg:     cfg,
+		onEvent: onEvent,
+		ctx:     ctx,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+
+		limitIn:  newLimiter(cfg.RateLimit),
+		limitOut: newLimiter(cfg.RateLimit),
+	}
+}
+
+// Start 同步完成第一次连接并返回结果，之后在后台保持隧道，失败时自动重连
+func (t *Tunnel) Start() error {
+	if err := t.cfg.validate(); err != nil {
+		return t.fail(newError(ErrCodeConfig, err))
+	}
+	ep, err := parseSSHHost(t.cfg.SSHHost)
+	if err != nil {
+		return t.fail(newError(ErrCodeConfig, err))
+	}
+	t.allowed, _ = t.cfg.allowedPrefixes()
+
+	t.mu.Lock()
+	t.started = true
+	t.mu.Unlock()
+
+	first := make(chan error, 1)
+	go t.loop(ep, first)
+	return <-first
+}
+
+func (t *Tunnel) fail(err error) error {
+	t.mu.Lock()
+	t.lastErr = err
+	t.mu.Unlock()
+	return err
+}
+
+// Stop 关闭隧道并等待后台协程退出
+func (t *Tunnel) Stop() {
+	t.cancel()
+	t.mu.Lock()
+	started := t.started
+	t.mu.Unlock()
+	if started {
+		<-t.done
+	}
+}
+
+func (t *Tunnel) Stats() Stats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.statsLocked()
+}
+
+func (t *Tunnel) statsLocked() Stats {
+	return Stats{
+		Connected:           t.connected,
+		Reconnects:          t.reconnects,
+		Connections:         t.connections.Load(),
+		ActiveConnections:   t.active.Load(),
+		FailedConnections:   t.failed.Load(),
+		RejectedConnections: t.rejected.Load(),
+		BytesIn:             t.bytesIn.Load(),
+		BytesOut:            t.bytesOut.Load(),
+		LastError:           t.lastErr,
+
+		HostKeyFingerprint: t.hostKey,
+	}
+}
+
+func (t *Tunnel) loop(ep *endpoint, first chan<- error) {
+	defer close(t.done)
+
+	backoff := minBackoff
+	for {
+		client, listener, err := t.connect(ep)
+		// 第一次连接的结果由 Start 直接返回，不再触发事件
+		notify := first == nil
+		if first != nil {
+			first <- err
+			first = nil
+		}
+
+		if err == nil {
+			connectedAt := time.Now()
+			t.setConnected(true, nil, notify)
+
+			err = t.serve(client, listener)
+			client.Close()
+			listener.Close()
+			notify = true
+
+			if time.Since(connectedAt) > stableDuration {
+				backoff = minBackoff
+			}
+		}
+
+		if t.ctx.Err() != nil {
+			t.setConnected(false, nil, false)
+			return
+		}
+		t.setConnected(false, err, notify)
+		if Permanent(err) {
+			return
+		}
+
+		select {
+		case <-t.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+func (t *Tunnel) setConnected(connected bool, err error, notify bool) {
+	t.mu.Lock()
+	if connected {
+		if t.everUp {
+			t.reconnects++
+		}
+		t.everUp = true
+	}
+	t.connected = connected
+	if err != nil {
+		t.lastErr = err
+	}
+	ev := Event{Connected: connected, Err: err, Stats: t.statsLocked()}
+	t.mu.Unlock()
+
+	if notify && t.onEvent != nil && t.ctx.Err() == nil {
+		t.onEvent(ev)
+	}
+}
+
+func (t *Tunnel) connect(ep *endpoint) (*ssh.Client, net.Listener, error) {
+	methods, cleanup := t.cfg.authMethods()
+	defer cleanup()
+
+	config := &ssh.ClientConfig{
+		User:            ep.user,
+		Auth:            methods,
+		HostKeyCallback: t.checkHostKey,
+		Timeout:         t.cfg.durationOption("ConnectTimeout", defaultConnectTimeout),
+	}
+
+	dialer := net.Dialer{Timeout: config.Timeout}
+	conn, err := dialer.DialContext(t.ctx, "tcp", ep.addr)
+	if err != nil {
+		return nil, nil, newError(ErrCodeDial, err)
+	}
+
+	// 停止隧道时中断正在进行的握手
+	stopAbort := context.AfterFunc(t.ctx, func() { conn.Close() })
+	defer stopAbort()
+
+	conn.SetDeadline(time.Now().Add(config.Timeout))
+	c, chans, reqs, err := ssh.NewClientConn(conn, ep.addr, config)
+	if err != nil {
+		conn.Close()
+		if errors.Is(err, errHostKeyMismatch) {
+			return nil, nil, newError(ErrCodeHostKey, err)
+		}
+		if strings.Contains(err.Error(), "unable to authenticate") {
+			return nil, nil, newError(ErrCodeAuth, err)
+		}
+		return nil, nil, newError(ErrCodeHandshake, err)
+	}
+	conn.SetDeadline(time.Time{})
+	client := ssh.NewClient(c, chans, reqs)
+
+	var listener net.Listener
+	if t.cfg.Type == TypeRemote {
+		listener, err = client.Listen("tcp", t.cfg.sourceAddr())
+	} else {
+		listener, err = net.Listen("tcp", t.cfg.sourceAddr())
+	}
+	if err != nil {
+		client.Close()
+		return nil, nil, newError(ErrCodeListen, err)
+	}
+
+	return client, listener, nil
+}
+
+// checkHostKey 记录服务器主机密钥指纹，配置了固定指纹时不一致则拒绝连接
+func (t *Tunnel) checkHostKey(_ string, _ net.Addr, key ssh.PublicKey) error {
+	fp := ssh.FingerprintSHA256(key)
+
+	t.mu.Lock()
+	t.hostKey = fp
+	t.mu.Unlock()
+
+	if t.cfg.HostKeyFingerprint == "" || t.cfg.HostKeyFingerprint == fp {
+		return nil
+	}
+	return fmt.Errorf("%w: expected %s, got %s", errHostKeyMismatch, t.cfg.HostKeyFingerprint, fp)
+}
+
+// serve 转发连接直到 SSH 连接断开或隧道被停止
+func (t *Tunnel) serve(client *ssh.Client, listener net.Listener) error {
+	errCh := make(chan error, 3)
+
+	go func() {
+		errCh <- newError(ErrCodeClosed, client.Wait())
+	}()
+	go func() {
+		errCh <- t.keepalive(client)
+	}()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				errCh <- newError(ErrCodeListen, err)
+				return
+			}
+			if !t.allowSource(conn.RemoteAddr()) {
+				t.rejected.Add(1)
+				conn.Close()
+				continue
+			}
+			go t.forward(client, conn)
+		}
+	}()
+
+	select {
+	case <-t.ctx.Done():
+		return nil
+	case err := <-errCh:
+		return err
+	}
+}
+
+// allowSource 来源白名单为空时允许所有连接
+func (t *Tunnel) allowSource(addr net.Addr) bool {
+	if len(t.allowed) == 0 {
+		return true
+	}
+	ap, err := netip.ParseAddrPort(addr.String())
+	if err != nil {
+		return false
+	}
+	ip := ap.Addr().Unmap()
+	for _, p := range t.allowed {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Tunnel) keepalive(client *ssh.Client) error {
+	interval := t.cfg.durationOption("ServerAliveInterval", defaultAliveInterval)
+	countMax := t.cfg.intOption("ServerAliveCountMax", defaultAliveCountMax)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	missed := 0
+	for {
+		select {
+		case <-t.ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		replied := make(chan error, 1)
+		go func() {
+			_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+			replied <- err
+		}()
+
+		select {
+		case err := <-replied:
+			if err != nil {
+				return newError(ErrCodeKeepalive, err)
+			}
+			missed = 0
+		case <-time.After(interval):
+			missed++
+			if missed >= countMax {
+				return newError(ErrCodeKeepalive, fmt.Errorf("no response after %d keepalives", missed))
+			}
+		}
+	}
+}
+
+// forward 本地转发时经 SSH 连接目标，远程转发时从本机连接目标，动态转发时连接 SOCKS5 请求的地址
+func (t *Tunnel) forward(client *ssh.Client, src net.Conn) {
+	defer src.Close()
+
+	var dst net.Conn
+	var err error
+	switch t.cfg.Type {
+	case TypeRemote:
+		dst, err = net.DialTimeout("tcp", t.cfg.targetAddr(), defaultConnectTimeout)
+	case TypeDynamic:
+		dst, err = t.dialSocks5(client, src)
+	default:
+		dst, err = client.Dial("tcp", t.cfg.targetAddr())
+	}
+	if err != nil {
+		t.failed.Add(1)
+		return
+	}
+	defer dst.Close()
+
+	t.connections.Add(1)
+	t.active.Add(1)
+	defer t.active.Add(-1)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		io.Copy(&meteredWriter{ctx: t.ctx, w: dst, n: &t.bytesOut, limiter: t.limitOut}, src)
+		closeWrite(dst)
+	}()
+	go func() {
+		defer wg.Done()
+		io.Copy(&meteredWriter{ctx: t.ctx, w: src, n: &t.bytesIn, limiter: t.limitIn}, dst)
+		closeWrite(src)
+	}()
+	wg.Wait()
+}
+
+func (t *Tunnel) dialSocks5(client *ssh.Client, src net.Conn) (net.Conn, error) {
+	src.SetDeadline(time.Now().Add(defaultConnectTimeout))
+	addr, err := socks5Handshake(src, t.cfg.SocksUsername, t.cfg.SocksPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	dst, err := client.Dial("tcp", addr)
+	if err != nil {
+		socks5Reply(src, socks5ReplyHostUnreachable)
+		return nil, err
+	}
+	if err := socks5Reply(src, socks5ReplySucceeded); err != nil {
+		dst.Close()
+		return nil, err
+	}
+	src.SetDeadline(time.Time{})
+	return dst, nil
+}
+
+func closeWrite(conn net.Conn) {
+	if cw, ok := conn.(interface{ CloseWrite() error }); ok {
+		cw.CloseWrite()
+		return
+	}
+	conn.Close()
+}
@@ -0,0 +1,429 @@
+package sshtunnel
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/net/proxy"
+)
+
+// startTestServer 启动只接受 authorized 公钥、支持 direct-tcpip 的 SSH 服务器
+func startTestServer(t *testing.T, authorized ssh.PublicKey) (string, ssh.PublicKey) {
+	t.Helper()
+
+	_, hostPriv, _ := ed25519.GenerateKey(rand.Reader)
+	hostSigner, err := ssh.NewSignerFromKey(hostPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) == string(authorized.Marshal()) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("unknown key")
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestConn(conn, config)
+		}
+	}()
+	return ln.Addr().String(), hostSigner.PublicKey()
+}
+
+func serveTestConn(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for nc := range chans {
+		if nc.ChannelType() != "direct-tcpip" {
+			nc.Reject(ssh.UnknownChannelType, "unsupported")
+			continue
+		}
+		var payload struct {
+			Host     string
+			Port     uint32
+			OrigHost string
+			OrigPort uint32
+		}
+		if err := ssh.Unmarshal(nc.ExtraData(), &payload); err != nil {
+			nc.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		target, err := net.Dial("tcp", net.JoinHostPort(payload.Host, strconv.Itoa(int(payload.Port))))
+		if err != nil {
+			nc.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		ch, chReqs, err := nc.Accept()
+		if err != nil {
+			target.Close()
+			continue
+		}
+		go ssh.DiscardRequests(chReqs)
+		go func() {
+			defer ch.Close()
+			defer target.Close()
+			go func() {
+				io.Copy(target, ch)
+				target.(*net.TCPConn).CloseWrite()
+			}()
+			io.Copy(ch, target)
+		}()
+	}
+}
+
+func writeTestKey(t *testing.T) (string, ssh.PublicKey) {
+	t.Helper()
+
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatal(err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path, sshPub
+}
+
+// startEchoServer 目标服务
+func startEchoServer(t *testing.T) int {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func freePort(t *testing.T) int {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+// waitStats 等待计数满足条件，转发协程结束前计数可能尚未更新
+func waitStats(t *testing.T, tunnel *Tunnel, ok func(Stats) bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		stats := tunnel.Stats()
+		if ok(stats) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("unexpected stats: %+v", stats)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestTunnelLocalForward(t *testing.T) {
+	keyPath, pub := writeTestKey(t)
+	sshAddr, _ := startTestServer(t, pub)
+	targetPort := startEchoServer(t)
+	sourcePort := freePort(t)
+
+	tunnel := New(Config{
+		ID:         1,
+		Type:       TypeLocal,
+		SourcePort: sourcePort,
+		TargetHost: "127.0.0.1",
+		TargetPort: targetPort,
+		SSHHost:    "tester@" + sshAddr,
+		Options:    map[string]string{"IdentityFile": keyPath},
+	}, nil)
+	if err := tunnel.Start(); err != nil {
+		t.Fatalf("start tunnel: %v", err)
+	}
+	defer tunnel.Stop()
+
+	conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(sourcePort)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(conn, buf); err != nil || string(buf) != "ping" {
+		t.Fatalf("expected echo through tunnel, got %q, %v", buf, err)
+	}
+	conn.Close()
+
+	waitStats(t, tunnel, func(s Stats) bool {
+		return s.Connected && s.Connections == 1 && s.ActiveConnections == 0 && s.BytesOut == 4 && s.BytesIn == 4
+	})
+}
+
+func TestTunnelAllowedSources(t *testing.T) {
+	keyPath, pub := writeTestKey(t)
+	sshAddr, _ := startTestServer(t, pub)
+	targetPort := startEchoServer(t)
+	sourcePort := freePort(t)
+
+	tunnel := New(Config{
+		Type:           TypeLocal,
+		SourcePort:     sourcePort,
+		TargetHost:     "127.0.0.1",
+		TargetPort:     targetPort,
+		SSHHost:        "tester@" + sshAddr,
+		Options:        map[string]string{"IdentityFile": keyPath},
+		BindAddress:    "127.0.0.1",
+		AllowedSources: []string{"10.0.0.0/8"},
+	}, nil)
+	if err := tunnel.Start(); err != nil {
+		t.Fatalf("start tunnel: %v", err)
+	}
+	defer tunnel.Stop()
+
+	conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(sourcePort)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	conn.Write([]byte("ping"))
+	if _, err := conn.Read(make([]byte, 4)); err == nil {
+		t.Fatal("expected connection from a source outside the allowlist to be closed")
+	}
+
+	waitStats(t, tunnel, func(s Stats) bool {
+		return s.RejectedConnections == 1 && s.Connections == 0
+	})
+
+	bad := New(Config{
+		Type:           TypeLocal,
+		SourcePort:     sourcePort,
+		TargetHost:     "127.0.0.1",
+		TargetPort:     targetPort,
+		SSHHost:        "tester@" + sshAddr,
+		AllowedSources: []string{"not-a-cidr"},
+	}, nil)
+	if err := bad.Start(); ErrorCode(err) != ErrCodeConfig {
+		t.Fatalf("expected config error, got %v", err)
+	}
+}
+
+func TestTunnelDynamicForward(t *testing.T) {
+	keyPath, pub := writeTestKey(t)
+	sshAddr, _ := startTestServer(t, pub)
+	targetAddr := net.JoinHostPort("127.0.0.1", strconv.Itoa(startEchoServer(t)))
+	sourcePort := freePort(t)
+
+	tunnel := New(Config{
+		Type:          TypeDynamic,
+		SourcePort:    sourcePort,
+		SSHHost:       "tester@" + sshAddr,
+		Options:       map[string]string{"IdentityFile": keyPath},
+		SocksUsername: "user",
+		SocksPassword: "secret",
+	}, nil)
+	if err := tunnel.Start(); err != nil {
+		t.Fatalf("start tunnel: %v", err)
+	}
+	defer tunnel.Stop()
+
+	socksAddr := net.JoinHostPort("127.0.0.1", strconv.Itoa(sourcePort))
+
+	// 密码错误
+	dialer, _ := proxy.SOCKS5("tcp", socksAddr, &proxy.Auth{User: "user", Password: "wrong"}, proxy.Direct)
+	if conn, err := dialer.Dial("tcp", targetAddr); err == nil {
+		conn.Close()
+		t.Fatal("expected socks5 auth to fail")
+	}
+
+	dialer, _ = proxy.SOCKS5("tcp", socksAddr, &proxy.Auth{User: "user", Password: "secret"}, proxy.Direct)
+	conn, err := dialer.Dial("tcp", targetAddr)
+	if err != nil {
+		t.Fatalf("dial through socks5: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(conn, buf); err != nil || string(buf) != "ping" {
+		t.Fatalf("expected echo through socks5, got %q, %v", buf, err)
+	}
+
+	waitStats(t, tunnel, func(s Stats) bool {
+		return s.Connections == 1 && s.FailedConnections == 1
+	})
+}
+
+func TestTunnelErrors(t *testing.T) {
+	keyPath, _ := writeTestKey(t)
+	_, otherPub := writeTestKey(t)
+	sshAddr, _ := startTestServer(t, otherPub)
+
+	cfg := Config{
+		Type:       TypeLocal,
+		SourcePort: freePort(t),
+		TargetHost: "127.0.0.1",
+		TargetPort: 80,
+		SSHHost:    "tester@" + sshAddr,
+		Options:    map[string]string{"IdentityFile": keyPath},
+	}
+
+	tunnel := New(cfg, nil)
+	err := tunnel.Start()
+	tunnel.Stop()
+	if ErrorCode(err) != ErrCodeAuth {
+		t.Fatalf("expected auth error, got %v", err)
+	}
+
+	cfg.Type = "unknown"
+	tunnel = New(cfg, nil)
+	err = tunnel.Start()
+	tunnel.Stop()
+	if ErrorCode(err) != ErrCodeConfig {
+		t.Fatalf("expected config error, got %v", err)
+	}
+}
+
+func TestTunnelHostKeyPinning(t *testing.T) {
+	keyPath, pub := writeTestKey(t)
+	sshAddr, hostKey := startTestServer(t, pub)
+
+	cfg := Config{
+		Type:       TypeLocal,
+		SourcePort: freePort(t),
+		TargetHost: "127.0.0.1",
+		TargetPort: 80,
+		SSHHost:    "tester@" + sshAddr,
+		Options:    map[string]string{"IdentityFile": keyPath},
+	}
+
+	// 未固定指纹时记录服务器的指纹
+	tunnel := New(cfg, nil)
+	if err := tunnel.Start(); err != nil {
+		t.Fatalf("start tunnel: %v", err)
+	}
+	if got := tunnel.Stats().HostKeyFingerprint; got != ssh.FingerprintSHA256(hostKey) {
+		t.Fatalf("expected host key fingerprint to be recorded, got %q", got)
+	}
+	tunnel.Stop()
+
+	cfg.SourcePort = freePort(t)
+	cfg.HostKeyFingerprint = ssh.FingerprintSHA256(hostKey)
+	tunnel = New(cfg, nil)
+	if err := tunnel.Start(); err != nil {
+		t.Fatalf("start tunnel with pinned host key: %v", err)
+	}
+	tunnel.Stop()
+
+	_, otherHostKey := writeTestKey(t)
+	cfg.SourcePort = freePort(t)
+	cfg.HostKeyFingerprint = ssh.FingerprintSHA256(otherHostKey)
+	tunnel = New(cfg, nil)
+	err := tunnel.Start()
+	if ErrorCode(err) != ErrCodeHostKey || !Permanent(err) {
+		t.Fatalf("expected host key mismatch, got %v", err)
+	}
+	// 不一致时不再重连，Stop 应立即返回
+	tunnel.Stop()
+}
+
+func TestTunnelDeliveredKey(t *testing.T) {
+	keyPath, pub := writeTestKey(t)
+	sshAddr, _ := startTestServer(t, pub)
+	privateKey, err := os.ReadFile(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := Config{
+		Type:       TypeLocal,
+		SourcePort: freePort(t),
+		TargetHost: "127.0.0.1",
+		TargetPort: 80,
+		SSHHost:    "tester@" + sshAddr,
+		// 不读取本机密钥
+		Options:    map[string]string{"IdentityFile": filepath.Join(t.TempDir(), "missing")},
+		PrivateKey: string(privateKey),
+	}
+	tunnel := New(cfg, nil)
+	if err := tunnel.Start(); err != nil {
+		t.Fatalf("start tunnel with delivered key: %v", err)
+	}
+	tunnel.Stop()
+
+	cfg.PrivateKey = "invalid"
+	tunnel = New(cfg, nil)
+	err = tunnel.Start()
+	tunnel.Stop()
+	if ErrorCode(err) != ErrCodeConfig {
+		t.Fatalf("expected config error for invalid key, got %v", err)
+	}
+}
+
+func TestParseSSHHost(t *testing.T) {
+	cases := map[string]string{
+		"root@example.com":      "example.com:22",
+		"root@example.com:2222": "example.com:2222",
+		"root@[::1]:2222":       "[::1]:2222",
+		"root@[::1]":            "[::1]:22",
+	}
+	for in, want := range cases {
+		ep, err := parseSSHHost(in)
+		if err != nil || ep.addr != want || ep.user != "root" {
+			t.Errorf("parseSSHHost(%q) = %+v, %v, want %s", in, ep, err, want)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package statebuf
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"sync"
+)
+
+// 单条记录的最大长度，超出时视为文件损坏
+const maxRecordSize = 4 << 20
+
+// Buffer 保存在磁盘上的有界队列，超出容量时丢弃最早的记录
+//
+// 文件由若干条 [4 字节长度][数据] 组成，内存中只保存各条记录的偏移量
+type Buffer struct {
+	mu      sync.Mutex
+	f       *os.File
+	path    string
+	max     int
+	offsets []int64 // 各条记录的起始偏移量
+	head    int     // 第一条未被丢弃的记录
+	size    int64   // 文件的有效长度
+}
+
+// Open 打开或创建缓冲文件，末尾不完整的记录会被截断
+func Open(path string, max int) (*Buffer, error) {
+	if max < 1 {
+		return nil, errors.New("buffer size must be positive")
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	b := &Buffer{f: f, path: path, max: max}
+	if err := b.load(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return b, nil
+}
+
+func (b *Buffer) load() error {
+	r := bufio.NewReader(b.f)
+	var header [4]byte
+	for {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			break
+		}
+		n := int64(binary.BigEndian.Uint32(header[:]))
+		if n > maxRecordSize {
+			break
+		}
+		if _, err := r.Discard(int(n)); err != nil {
+			break
+		}
+		b.offsets = append(b.offsets, b.size)
+		b.size += 4 + n
+	}
+	if err := b.f.Truncate(b.size); err != nil {
+		return err
+	}
+	b.head = max(len(b.offsets)-b.max, 0)
+	return b.compact()
+}
+
+// Len 返回缓存的记录数
+func (b *Buffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.offsets) - b.head
+}
+
+// Push 追加一条记录
+func (b *Buffer) Push(data []byte) error {
+	if len(data) > maxRecordSize {
+		return errors.New("record is too large")
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	buf := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(buf, uint32(len(data)))
+	copy(buf[4:], data)
+	if _, err := b.f.WriteAt(buf, b.size); err != nil {
+		return err
+	}
+	b.offsets = append(b.offsets, b.size)
+	b.size += int64(len(buf))
+
+	if len(b.offsets)-b.head > b.max {
+		b.head = len(b.offsets) - b.max
+	}
+	// 被丢弃的记录积累到一定数量后再重写文件
+	if b.head >= b.max/2 {
+		return b.compact()
+	}
+	return nil
+}
+
+// Peek 按写入顺序返回最早的 n 条记录
+func (b *Buffer) Peek(n int) ([][]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	n = min(n, len(b.offsets)-b.head)
+	records := make([][]byte, 0, n)
+	for i := b.head; i < b.head+n; i++ {
+		end := b.size
+		if i+1 < len(b.offsets) {
+			end = b.offsets[i+1]
+		}
+		data := make([]byte, end-b.offsets[i]-4)
+		if _, err := b.f.ReadAt(data, b.offsets[i]+4); err != nil {
+			return nil, err
+		}
+		records = append(records, data)
+	}
+	return records, nil
+}
+
+// Discard 丢弃最早的 n 条记录
+func (b *Buffer) Discard(n int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.head = min(b.head+n, len(b.offsets))
+	return b.compact()
+}
+
+// compact 将未丢弃的记录移到文件开头
+func (b *Buffer) compact() error {
+	if b.head == 0 {
+		return nil
+	}
+	if b.head == len(b.offsets) {
+		b.offsets, b.head, b.size = nil, 0, 0
+		return b.f.Truncate(0)
+	}
+
+	start := b.offsets[b.head]
+	tmp, err := os.OpenFile(b.path+".tmp", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(tmp, io.NewSectionReader(b.f, start, b.size-start))
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	// Windows 下无法覆盖已打开的文件
+	b.f.Close()
+	if err := os.Rename(tmp.Name(), b.path); err != nil {
+		os.Remove(tmp.Name())
+		b.f, _ = os.OpenFile(b.path, os.O_RDWR, 0600)
+		return err
+	}
+
+	offsets := make([]int64, 0, len(b.offsets)-b.head)
+	for _, off := range b.offsets[b.head:] {
+		offsets = append(offsets, off-start)
+	}
+	b.offsets, b.head, b.size = offsets, 0, b.size-start
+	b.f, err = os.OpenFile(b.path, os.O_RDWR, 0600)
+	return err
+}
+
+func (b *Buffer) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.f.Close()
+}
@@ -0,0 +1,84 @@
+package statebuf
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func push(t *testing.T, b *Buffer, from, to int) {
+	t.Helper()
+	for i := from; i < to; i++ {
+		if err := b.Push([]byte(fmt.Sprintf("state-%d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func expect(t *testing.T, b *Buffer, n int, first string) {
+	t.Helper()
+	if b.Len() != n {
+		t.Fatalf("expected %d records, got %d", n, b.Len())
+	}
+	records, err := b.Peek(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || string(records[0]) != first {
+		t.Fatalf("expected first record %s, got %q", first, records)
+	}
+}
+
+func TestBuffer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.buf")
+	b, err := Open(path, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 超出容量后丢弃最早的记录
+	push(t, b, 0, 25)
+	expect(t, b, 10, "state-15")
+	records, err := b.Peek(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 10 || string(records[9]) != "state-24" {
+		t.Fatalf("unexpected records: %q", records)
+	}
+
+	if err := b.Discard(4); err != nil {
+		t.Fatal(err)
+	}
+	expect(t, b, 6, "state-19")
+	b.Close()
+
+	// 重新打开后保留未上传的记录，末尾不完整的记录被截断
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte{0, 0, 0, 9, 'x'})
+	f.Close()
+
+	b, err = Open(path, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(t, b, 6, "state-19")
+	push(t, b, 25, 27)
+	expect(t, b, 8, "state-19")
+	records, _ = b.Peek(8)
+	if string(records[7]) != "state-26" {
+		t.Fatalf("unexpected last record: %q", records[7])
+	}
+
+	if err := b.Discard(8); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() != 0 || b.Len() != 0 {
+		t.Fatalf("buffer should be empty: %v %v", info, err)
+	}
+	b.Close()
+}
@@ -0,0 +1,185 @@
+package systemd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+
+	pb "github.com/nezhahq/agent/proto"
+)
+
+// Action
+const (
+	ActionStart   = "start"
+	ActionStop    = "stop"
+	ActionRestart = "restart"
+	ActionEnable  = "enable"
+	ActionDisable = "disable"
+)
+
+// 单次上报的最大单元数
+const maxUnits = 1000
+
+var unitNameRe = regexp.MustCompile(`^[A-Za-z0-9:_.@\\-]+$`)
+
+type Unit struct {
+	Name          string
+	Description   string
+	LoadState     string // loaded, not-found, masked
+	ActiveState   string // active, inactive, failed, activating, deactivating
+	SubState      string // running, exited, dead 等
+	UnitFileState string // enabled, disabled, static, masked 等
+}
+
+// Available 只有以 systemd 启动的 Linux 主机才会上报
+func Available() bool {
+	if runtime.GOOS != "linux" {
+		return false
+	}
+	if _, err := os.Stat("/run/systemd/system"); err != nil {
+		return false
+	}
+	_, err := exec.LookPath("systemctl")
+	return err == nil
+}
+
+// ValidUnit 单元名称不能以 - 开头，避免被当作 systemctl 的参数
+func ValidUnit(name string) bool {
+	return name != "" && len(name) <= 256 && !strings.HasPrefix(name, "-") && unitNameRe.MatchString(name)
+}
+
+func ValidAction(action string) bool {
+	switch action {
+	case ActionStart, ActionStop, ActionRestart, ActionEnable, ActionDisable:
+		return true
+	}
+	return false
+}
+
+// ListUnits 列出匹配 patterns 的单元，为空时列出全部 service
+func ListUnits(ctx context.Context, patterns []string) ([]Unit, error) {
+	for _, p := range patterns {
+		if strings.HasPrefix(p, "-") {
+			return nil, fmt.Errorf("invalid unit pattern: %s", p)
+		}
+	}
+	args := []string{"--no-legend", "--no-pager", "--plain", "--all"}
+	if len(patterns) == 0 {
+		args = append(args, "--type=service")
+	}
+
+	out, err := systemctl(ctx, append(append([]string{"list-units"}, args...), patterns...)...)
+	if err != nil {
+		return nil, err
+	}
+	units := parseListUnits(out)
+	if len(units) == 0 {
+		return units, nil
+	}
+
+	// 启用状态只影响展示，获取失败时忽略
+	if out, err := systemctl(ctx, append(append([]string{"list-unit-files"}, args[:3]...), unitNames(units)...)...); err == nil {
+		states := parseListUnitFiles(out)
+		for i := range units {
+			units[i].UnitFileState = states[units[i].Name]
+		}
+	}
+	return units, nil
+}
+
+// Action 对单元执行 start、stop、restart、enable 或 disable
+func Action(ctx context.Context, unit, action string) error {
+	if !ValidUnit(unit) {
+		return fmt.Errorf("invalid unit: %s", unit)
+	}
+	if !ValidAction(action) {
+		return fmt.Errorf("unsupported action: %s", action)
+	}
+	_, err := systemctl(ctx, action, "--no-ask-password", "--", unit)
+	return err
+}
+
+// Report 采集失败时只上报错误
+func Report(ctx context.Context, patterns []string) *pb.SystemdReport {
+	units, err := ListUnits(ctx, patterns)
+	if err != nil {
+		return &pb.SystemdReport{Error: err.Error()}
+	}
+	report := &pb.SystemdReport{Units: make([]*pb.SystemdUnit, 0, len(units))}
+	for _, u := range units {
+		report.Units = append(report.Units, &pb.SystemdUnit{
+			Name:          u.Name,
+			Description:   u.Description,
+			LoadState:     u.LoadState,
+			ActiveState:   u.ActiveState,
+			SubState:      u.SubState,
+			UnitFileState: u.UnitFileState,
+		})
+	}
+	return report
+}
+
+func systemctl(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "systemctl", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", errors.New(msg)
+		}
+		return "", err
+	}
+	return stdout.String(), nil
+}
+
+// parseListUnits 解析 list-units --plain --no-legend 的输出：UNIT LOAD ACTIVE SUB DESCRIPTION
+func parseListUnits(out string) []Unit {
+	var units []Unit
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		// 失败的单元前可能带有 ● 标记
+		if len(fields) > 0 && !ValidUnit(fields[0]) {
+			fields = fields[1:]
+		}
+		if len(fields) < 4 || !ValidUnit(fields[0]) {
+			continue
+		}
+		units = append(units, Unit{
+			Name:        fields[0],
+			LoadState:   fields[1],
+			ActiveState: fields[2],
+			SubState:    fields[3],
+			Description: strings.Join(fields[4:], " "),
+		})
+		if len(units) >= maxUnits {
+			break
+		}
+	}
+	return units
+}
+
+// parseListUnitFiles 解析 list-unit-files --no-legend 的输出：UNIT FILE STATE [PRESET]
+func parseListUnitFiles(out string) map[string]string {
+	states := make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 {
+			states[fields[0]] = fields[1]
+		}
+	}
+	return states
+}
+
+func unitNames(units []Unit) []string {
+	names := make([]string, 0, len(units))
+	for _, u := range units {
+		names = append(names, u.Name)
+	}
+	return names
+}
@@ -0,0 +1,42 @@
+package systemd
+
+import "testing"
+
+func TestParseListUnits(t *testing.T) {
+	out := `nginx.service                 loaded    active   running Nginx HTTP Server
+● php-fpm.service             loaded    failed   failed  The PHP FastCGI Process Manager
+getty@tty1.service            loaded    active   running Getty on tty1
+foo.service                   not-found inactive dead    foo.service
+`
+	units := parseListUnits(out)
+	if len(units) != 4 {
+		t.Fatalf("expected 4 units, got %d: %+v", len(units), units)
+	}
+	if u := units[0]; u.Name != "nginx.service" || u.ActiveState != "active" || u.SubState != "running" || u.Description != "Nginx HTTP Server" {
+		t.Fatalf("unexpected unit: %+v", u)
+	}
+	if u := units[1]; u.Name != "php-fpm.service" || u.ActiveState != "failed" {
+		t.Fatalf("unexpected failed unit: %+v", u)
+	}
+	if units[3].LoadState != "not-found" {
+		t.Fatalf("unexpected unit: %+v", units[3])
+	}
+
+	states := parseListUnitFiles("nginx.service enabled enabled\nphp-fpm.service disabled vendor preset: disabled\n")
+	if states["nginx.service"] != "enabled" || states["php-fpm.service"] != "disabled" {
+		t.Fatalf("unexpected unit file states: %v", states)
+	}
+}
+
+func TestValidUnit(t *testing.T) {
+	for _, name := range []string{"nginx.service", "getty@tty1.service", `dev-disk-by\x2duuid.device`, "sshd"} {
+		if !ValidUnit(name) {
+			t.Errorf("%s should be valid", name)
+		}
+	}
+	for _, name := range []string{"", "--now", "-nginx", "nginx service", "nginx;reboot", "a/b.service"} {
+		if ValidUnit(name) {
+			t.Errorf("%q should be invalid", name)
+		}
+	}
+}
@@ -19,12 +19,16 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	NezhaService_ReportSystemState_FullMethodName = "/proto.NezhaService/ReportSystemState"
-	NezhaService_ReportSystemInfo_FullMethodName  = "/proto.NezhaService/ReportSystemInfo"
-	NezhaService_RequestTask_FullMethodName       = "/proto.NezhaService/RequestTask"
-	NezhaService_IOStream_FullMethodName          = "/proto.NezhaService/IOStream"
-	NezhaService_ReportGeoIP_FullMethodName       = "/proto.NezhaService/ReportGeoIP"
-	NezhaService_ReportSystemInfo2_FullMethodName = "/proto.NezhaService/ReportSystemInfo2"
+	NezhaService_ReportSystemState_FullMethodName   = "/proto.NezhaService/ReportSystemState"
+	NezhaService_ReportSystemInfo_FullMethodName    = "/proto.NezhaService/ReportSystemInfo"
+	NezhaService_RequestTask_FullMethodName         = "/proto.NezhaService/RequestTask"
+	NezhaService_IOStream_FullMethodName            = "/proto.NezhaService/IOStream"
+	NezhaService_ReportGeoIP_FullMethodName         = "/proto.NezhaService/ReportGeoIP"
+	NezhaService_ReportSystemInfo2_FullMethodName   = "/proto.NezhaService/ReportSystemInfo2"
+	NezhaService_ReportDocker_FullMethodName        = "/proto.NezhaService/ReportDocker"
+	NezhaService_ReportKubernetes_FullMethodName    = "/proto.NezhaService/ReportKubernetes"
+	NezhaService_ReportStateBackfill_FullMethodName = "/proto.NezhaService/ReportStateBackfill"
+	NezhaService_ReportSystemd_FullMethodName       = "/proto.NezhaService/ReportSystemd"
 )
 
 // NezhaServiceClient is the client API for NezhaService service.
@@ -37,6 +41,10 @@ type NezhaServiceClient interface {
 	IOStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[IOStreamData, IOStreamData], error)
 	ReportGeoIP(ctx context.Context, in *GeoIP, opts ...grpc.CallOption) (*GeoIP, error)
 	ReportSystemInfo2(ctx context.Context, in *Host, opts ...grpc.CallOption) (*Uint64Receipt, error)
+	ReportDocker(ctx context.Context, in *DockerReport, opts ...grpc.CallOption) (*Receipt, error)
+	ReportKubernetes(ctx context.Context, in *KubernetesReport, opts ...grpc.CallOption) (*Receipt, error)
+	ReportStateBackfill(ctx context.Context, in *StateBackfill, opts ...grpc.CallOption) (*Receipt, error)
+	ReportSystemd(ctx context.Context, in *SystemdReport, opts ...grpc.CallOption) (*Receipt, error)
 }
 
 type nezhaServiceClient struct {
@@ -116,6 +124,46 @@ func (c *nezhaServiceClient) ReportSystemInfo2(ctx context.Context, in *Host, op
 	return out, nil
 }
 
+func (c *nezhaServiceClient) ReportDocker(ctx context.Context, in *DockerReport, opts ...grpc.CallOption) (*Receipt, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Receipt)
+	err := c.cc.Invoke(ctx, NezhaService_ReportDocker_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nezhaServiceClient) ReportKubernetes(ctx context.Context, in *KubernetesReport, opts ...grpc.CallOption) (*Receipt, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Receipt)
+	err := c.cc.Invoke(ctx, NezhaService_ReportKubernetes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nezhaServiceClient) ReportStateBackfill(ctx context.Context, in *StateBackfill, opts ...grpc.CallOption) (*Receipt, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Receipt)
+	err := c.cc.Invoke(ctx, NezhaService_ReportStateBackfill_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nezhaServiceClient) ReportSystemd(ctx context.Context, in *SystemdReport, opts ...grpc.CallOption) (*Receipt, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Receipt)
+	err := c.cc.Invoke(ctx, NezhaService_ReportSystemd_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NezhaServiceServer is the server API for NezhaService service.
 // All implementations should embed UnimplementedNezhaServiceServer
 // for forward compatibility.
@@ -126,6 +174,10 @@ type NezhaServiceServer interface {
 	IOStream(grpc.BidiStreamingServer[IOStreamData, IOStreamData]) error
 	ReportGeoIP(context.Context, *GeoIP) (*GeoIP, error)
 	ReportSystemInfo2(context.Context, *Host) (*Uint64Receipt, error)
+	ReportDocker(context.Context, *DockerReport) (*Receipt, error)
+	ReportKubernetes(context.Context, *KubernetesReport) (*Receipt, error)
+	ReportStateBackfill(context.Context, *StateBackfill) (*Receipt, error)
+	ReportSystemd(context.Context, *SystemdReport) (*Receipt, error)
 }
 
 // UnimplementedNezhaServiceServer should be embedded to have
@@ -153,6 +205,18 @@ func (UnimplementedNezhaServiceServer) ReportGeoIP(context.Context, *GeoIP) (*Ge
 func (UnimplementedNezhaServiceServer) ReportSystemInfo2(context.Context, *Host) (*Uint64Receipt, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ReportSystemInfo2 not implemented")
 }
+func (UnimplementedNezhaServiceServer) ReportDocker(context.Context, *DockerReport) (*Receipt, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportDocker not implemented")
+}
+func (UnimplementedNezhaServiceServer) ReportKubernetes(context.Context, *KubernetesReport) (*Receipt, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportKubernetes not implemented")
+}
+func (UnimplementedNezhaServiceServer) ReportStateBackfill(context.Context, *StateBackfill) (*Receipt, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportStateBackfill not implemented")
+}
+func (UnimplementedNezhaServiceServer) ReportSystemd(context.Context, *SystemdReport) (*Receipt, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportSystemd not implemented")
+}
 func (UnimplementedNezhaServiceServer) testEmbeddedByValue() {}
 
 // UnsafeNezhaServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -248,6 +312,78 @@ func _NezhaService_ReportSystemInfo2_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NezhaService_ReportDocker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DockerReport)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NezhaServiceServer).ReportDocker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NezhaService_ReportDocker_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NezhaServiceServer).ReportDocker(ctx, req.(*DockerReport))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NezhaService_ReportKubernetes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KubernetesReport)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NezhaServiceServer).ReportKubernetes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NezhaService_ReportKubernetes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NezhaServiceServer).ReportKubernetes(ctx, req.(*KubernetesReport))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NezhaService_ReportStateBackfill_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StateBackfill)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NezhaServiceServer).ReportStateBackfill(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NezhaService_ReportStateBackfill_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NezhaServiceServer).ReportStateBackfill(ctx, req.(*StateBackfill))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NezhaService_ReportSystemd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SystemdReport)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NezhaServiceServer).ReportSystemd(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NezhaService_ReportSystemd_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NezhaServiceServer).ReportSystemd(ctx, req.(*SystemdReport))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // NezhaService_ServiceDesc is the grpc.ServiceDesc for NezhaService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -267,6 +403,22 @@ var NezhaService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ReportSystemInfo2",
 			Handler:    _NezhaService_ReportSystemInfo2_Handler,
 		},
+		{
+			MethodName: "ReportDocker",
+			Handler:    _NezhaService_ReportDocker_Handler,
+		},
+		{
+			MethodName: "ReportKubernetes",
+			Handler:    _NezhaService_ReportKubernetes_Handler,
+		},
+		{
+			MethodName: "ReportStateBackfill",
+			Handler:    _NezhaService_ReportStateBackfill_Handler,
+		},
+		{
+			MethodName: "ReportSystemd",
+			Handler:    _NezhaService_ReportSystemd_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
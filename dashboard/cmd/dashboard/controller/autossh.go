@@ -2,8 +2,8 @@ package controller
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/goccy/go-json"
@@ -15,11 +15,16 @@ import (
 )
 
 // checkPortConflict 检查端口是否已被占用
+// local 与 dynamic 映射都监听 agent 所在服务器的端口，remote 映射监听共用的 SSH 服务器端口
 func checkPortConflict(serverID uint64, mappingType string, sourcePort int, excludeID uint64) error {
 	var count int64
-	query := singleton.DB.Model(&model.AutoSSH{}).
-		Where("server_id = ? AND mapping_type = ? AND source_port = ?",
-			serverID, mappingType, sourcePort)
+	query := singleton.DB.Model(&model.AutoSSH{}).Where("source_port = ?", sourcePort)
+	if mappingType == model.AutoSSHMappingTypeRemote {
+		query = query.Where("mapping_type = ?", model.AutoSSHMappingTypeRemote)
+	} else {
+		query = query.Where("server_id = ? AND mapping_type IN ?", serverID,
+			[]string{model.AutoSSHMappingTypeLocal, model.AutoSSHMappingTypeDynamic})
+	}
 
 	if excludeID > 0 {
 		query = query.Where("id != ?", excludeID)
@@ -30,10 +35,83 @@ func checkPortConflict(serverID uint64, mappingType string, sourcePort int, excl
 	}
 
 	if count > 0 {
+		if mappingType == model.AutoSSHMappingTypeRemote {
+			return singleton.Localizer.ErrorT(
+				"port %d is already in use for remote mapping on the SSH server", sourcePort)
+		}
 		return singleton.Localizer.ErrorT(
 			"port %d is already in use for %s mapping on this server",
 			sourcePort, mappingType)
 	}
+
+	// 启用内置隧道服务器时检查端口的实际监听情况
+	if mappingType == model.AutoSSHMappingTypeRemote && singleton.AutoSSHServerShared != nil {
+		if err := singleton.AutoSSHServerShared.CheckPort(sourcePort, excludeID); err != nil {
+			return singleton.Localizer.ErrorT(
+				"port %d is not available on the SSH server: %v", sourcePort, err)
+		}
+	}
+	return nil
+}
+
+// validateAutoSSHForm 校验映射类型与端口，dynamic 映射不需要目标地址
+func validateAutoSSHForm(af *model.AutoSSHForm) error {
+	if af.SourcePort < 1 || af.SourcePort > 65535 {
+		return singleton.Localizer.ErrorT("invalid port")
+	}
+
+	switch af.MappingType {
+	case model.AutoSSHMappingTypeLocal, model.AutoSSHMappingTypeRemote:
+		if af.TargetHost == "" || af.TargetPort < 1 || af.TargetPort > 65535 {
+			return singleton.Localizer.ErrorT("target host and port are required")
+		}
+		if !model.IsValidTargetHost(af.TargetHost) {
+			return singleton.Localizer.ErrorT("invalid target host")
+		}
+		af.SocksUsername, af.SocksPassword = "", ""
+	case model.AutoSSHMappingTypeDynamic:
+		af.TargetHost, af.TargetPort = "", 0
+		if af.SocksUsername == "" && af.SocksPassword != "" {
+			return singleton.Localizer.ErrorT("socks username is required when a password is set")
+		}
+	default:
+		return singleton.Localizer.ErrorT("invalid mapping type")
+	}
+
+	// remote 映射的绑定地址只对内置隧道服务器生效（为空时仅本机），外部 sshd 由 authorized_keys 限定
+	if !model.IsValidBindAddress(af.BindAddress) {
+		return singleton.Localizer.ErrorT("invalid bind address")
+	}
+	if len(af.AllowedSources) > model.AutoSSHMaxAllowedSources {
+		return singleton.Localizer.ErrorT("too many allowed sources")
+	}
+	if _, err := model.ParseAllowedSources(af.AllowedSources); err != nil {
+		return err
+	}
+	return nil
+}
+
+// applyAutoSSHForm 将表单写入映射
+func applyAutoSSHForm(a *model.AutoSSH, af *model.AutoSSHForm) error {
+	allowedSources, err := json.Marshal(af.AllowedSources)
+	if err != nil {
+		return err
+	}
+
+	a.Name = af.Name
+	a.ServerID = af.ServerID
+	a.MappingType = af.MappingType
+	a.SourcePort = af.SourcePort
+	a.TargetHost = af.TargetHost
+	a.TargetPort = af.TargetPort
+	a.SocksUsername = af.SocksUsername
+	a.SocksPassword = af.SocksPassword
+	a.RateLimit = af.RateLimit
+	a.BindAddress = af.BindAddress
+	a.AllowedSources = af.AllowedSources
+	a.AllowedSourcesRaw = string(allowedSources)
+	a.Enabled = af.Enabled
+	a.PublicExposure = a.IsPubliclyExposed()
 	return nil
 }
 
@@ -82,6 +160,13 @@ func listAutoSSH(c *gin.Context) ([]*model.AutoSSH, error) {
 	if err := copier.Copy(&mappings, &slist); err != nil {
 		return nil, err
 	}
+	for _, a := range mappings {
+		a.Traffic = singleton.TunnelStatsShared.Traffic(model.TunnelKindAutoSSH, a.ID)
+		a.PublicExposure = a.IsPubliclyExposed()
+		if singleton.AutoSSHServerShared != nil {
+			a.Listener = singleton.AutoSSHServerShared.ListenerState(a.ID)
+		}
+	}
 
 	// 如果不是管理员，只返回有权限的服务器上的 AutoSSH
 	if user.Role != model.RoleAdmin {
@@ -134,6 +219,10 @@ func createAutoSSH(c *gin.Context) (uint64, error) {
 		return 0, err
 	}
 
+	if err := validateAutoSSHForm(&af); err != nil {
+		return 0, err
+	}
+
 	if _, ok := singleton.ServerShared.Get(af.ServerID); ok {
 		if !checkServerPermission(c, af.ServerID) {
 			return 0, singleton.Localizer.ErrorT("permission denied")
@@ -148,13 +237,9 @@ func createAutoSSH(c *gin.Context) (uint64, error) {
 	uid := getUid(c)
 
 	a.UserID = uid
-	a.Name = af.Name
-	a.ServerID = af.ServerID
-	a.MappingType = af.MappingType
-	a.SourcePort = af.SourcePort
-	a.TargetHost = af.TargetHost
-	a.TargetPort = af.TargetPort
-	a.Enabled = af.Enabled
+	if err := applyAutoSSHForm(&a, &af); err != nil {
+		return 0, err
+	}
 	a.Status = model.AutoSSHStatusStopped
 
 	if err := singleton.DB.Create(&a).Error; err != nil {
@@ -162,6 +247,7 @@ func createAutoSSH(c *gin.Context) (uint64, error) {
 	}
 
 	singleton.AutoSSHShared.Update(&a)
+	singleton.AutoSSHShared.SyncAuthorizedKeys()
 
 	// 如果启用，自动启动
 	if a.Enabled {
@@ -196,6 +282,10 @@ func updateAutoSSH(c *gin.Context) (any, error) {
 		return nil, err
 	}
 
+	if err := validateAutoSSHForm(&af); err != nil {
+		return nil, err
+	}
+
 	if _, ok := singleton.ServerShared.Get(af.ServerID); ok {
 		if !checkServerPermission(c, af.ServerID) {
 			return nil, singleton.Localizer.ErrorT("permission denied")
@@ -216,25 +306,30 @@ func updateAutoSSH(c *gin.Context) (any, error) {
 		return nil, err
 	}
 
+	// 密码不会返回给前端，留空表示沿用原密码，清空用户名即取消认证
+	if af.SocksUsername != "" && af.SocksPassword == "" {
+		af.SocksPassword = a.SocksPassword
+	}
+
 	// 如果配置变更，先停止旧的
 	if a.Enabled && (a.MappingType != af.MappingType || a.SourcePort != af.SourcePort ||
-		a.TargetHost != af.TargetHost || a.TargetPort != af.TargetPort) {
+		a.TargetHost != af.TargetHost || a.TargetPort != af.TargetPort ||
+		a.SocksUsername != af.SocksUsername || a.SocksPassword != af.SocksPassword ||
+		a.RateLimit != af.RateLimit || a.BindAddress != af.BindAddress ||
+		!slices.Equal(a.AllowedSources, af.AllowedSources)) {
 		stopAutoSSHMapping(&a)
 	}
 
-	a.Name = af.Name
-	a.ServerID = af.ServerID
-	a.MappingType = af.MappingType
-	a.SourcePort = af.SourcePort
-	a.TargetHost = af.TargetHost
-	a.TargetPort = af.TargetPort
-	a.Enabled = af.Enabled
+	if err := applyAutoSSHForm(&a, &af); err != nil {
+		return 0, err
+	}
 
 	if err := singleton.DB.Save(&a).Error; err != nil {
 		return 0, newGormError("%v", err)
 	}
 
 	singleton.AutoSSHShared.Update(&a)
+	singleton.AutoSSHShared.SyncAuthorizedKeys()
 
 	// 如果启用，启动新的
 	if a.Enabled {
@@ -345,6 +440,8 @@ func batchDeleteAutoSSH(c *gin.Context) (any, error) {
 	}
 
 	singleton.AutoSSHShared.Delete(ids)
+	singleton.TunnelStatsShared.Delete(model.TunnelKindAutoSSH, ids)
+	singleton.AutoSSHShared.SyncAuthorizedKeys()
 	return nil, nil
 }
 
@@ -356,37 +453,25 @@ func startAutoSSHMapping(a *model.AutoSSH) {
 		return
 	}
 
-	taskData, err := json.Marshal(model.TaskAutoSSH{
-		Action:      "start",
-		MappingID:   a.ID,
-		MappingType: a.MappingType,
-		SourcePort:  a.SourcePort,
-		TargetHost:  a.TargetHost,
-		TargetPort:  a.TargetPort,
-		SSHHost:     singleton.Conf.AutoSSHHost,
-		SSHOptions: map[string]string{
-			"ServerAliveInterval": "30",
-			"ServerAliveCountMax": "3",
-		},
-	})
+	task, err := singleton.AutoSSHShared.StartTask(a, false)
 	if err != nil {
 		updateAutoSSHStatus(a.ID, model.AutoSSHStatusError, fmt.Sprintf("marshal task error: %v", err))
 		return
 	}
 
-	if err := server.TaskStream.Send(&proto.Task{
-		Type: model.TaskTypeAutoSSH,
-		Data: string(taskData),
-	}); err != nil {
+	// 先标记为启动中，agent 的回报可能在 Send 返回之前到达，实际运行状态以回报为准
+	updateAutoSSHStatus(a.ID, model.AutoSSHStatusStarting, "")
+	if err := server.TaskStream.Send(task); err != nil {
 		updateAutoSSHStatus(a.ID, model.AutoSSHStatusError, fmt.Sprintf("send task error: %v", err))
-		return
 	}
-
-	updateAutoSSHStatus(a.ID, model.AutoSSHStatusRunning, "")
 }
 
 // stopAutoSSHMapping 停止 AutoSSH 端口映射
 func stopAutoSSHMapping(a *model.AutoSSH) {
+	if singleton.AutoSSHServerShared != nil {
+		singleton.AutoSSHServerShared.CloseForward(a.ID)
+	}
+
 	server, ok := singleton.ServerShared.Get(a.ServerID)
 	if !ok || server.TaskStream == nil {
 		updateAutoSSHStatus(a.ID, model.AutoSSHStatusStopped, "")
@@ -411,17 +496,5 @@ func stopAutoSSHMapping(a *model.AutoSSH) {
 
 // updateAutoSSHStatus 更新 AutoSSH 状态
 func updateAutoSSHStatus(id uint64, status string, errorMsg string) {
-	var a model.AutoSSH
-	if err := singleton.DB.First(&a, id).Error; err != nil {
-		return
-	}
-
-	a.Status = status
-	a.LastError = errorMsg
-	if status == model.AutoSSHStatusRunning {
-		a.LastStartAt = time.Now()
-	}
-
-	singleton.DB.Save(&a)
-	singleton.AutoSSHShared.Update(&a)
+	singleton.AutoSSHShared.UpdateStatus(id, status, errorMsg)
 }
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/service/singleton"
+)
+
+// List AutoSSH keys
+// @Summary List AutoSSH keys
+// @Description List the tunnel key pairs generated for servers, private keys are never returned
+// @Security BearerAuth
+// @Tags admin required
+// @Produce json
+// @Success 200 {object} model.CommonResponse[[]model.AutoSSHKey]
+// @Router /autossh/key [get]
+func listAutoSSHKeys(c *gin.Context) ([]*model.AutoSSHKey, error) {
+	var keys []*model.AutoSSHKey
+	if err := singleton.DB.Order("server_id").Find(&keys).Error; err != nil {
+		return nil, newGormError("%v", err)
+	}
+	return keys, nil
+}
+
+// Rotate AutoSSH key
+// @Summary Rotate AutoSSH key
+// @Description Generate a new tunnel key pair for a server and restart its mappings with it
+// @Security BearerAuth
+// @Tags admin required
+// @param server_id path uint true "Server ID"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[model.AutoSSHKey]
+// @Router /autossh/key/{server_id}/rotate [post]
+func rotateAutoSSHKey(c *gin.Context) (*model.AutoSSHKey, error) {
+	serverID, err := strconv.ParseUint(c.Param("server_id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := singleton.ServerShared.Get(serverID); !ok {
+		return nil, singleton.Localizer.ErrorT("server id %d does not exist", serverID)
+	}
+
+	key, err := singleton.AutoSSHShared.RotateKey(serverID)
+	if err != nil {
+		return nil, newGormError("%v", err)
+	}
+
+	restartAutoSSHMappings(serverID)
+	return key, nil
+}
+
+// Revoke AutoSSH key
+// @Summary Revoke AutoSSH key
+// @Description Remove a server's tunnel key from authorized_keys and restart its mappings without it
+// @Security BearerAuth
+// @Tags admin required
+// @param server_id path uint true "Server ID"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[any]
+// @Router /autossh/key/{server_id}/revoke [post]
+func revokeAutoSSHKey(c *gin.Context) (any, error) {
+	serverID, err := strconv.ParseUint(c.Param("server_id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := singleton.AutoSSHShared.RevokeKey(serverID); err != nil {
+		return nil, newGormError("%v", err)
+	}
+
+	// 重启隧道以断开仍在使用旧密钥的连接
+	restartAutoSSHMappings(serverID)
+	return nil, nil
+}
+
+// Get AutoSSH authorized_keys
+// @Summary Get AutoSSH authorized_keys
+// @Description Get the authorized_keys content for the tunnel host, each key restricted to its server's mappings
+// @Security BearerAuth
+// @Tags admin required
+// @Produce json
+// @Success 200 {object} model.CommonResponse[string]
+// @Router /autossh/authorized-keys [get]
+func getAutoSSHAuthorizedKeys(c *gin.Context) (string, error) {
+	return singleton.AutoSSHShared.AuthorizedKeys(), nil
+}
+
+// Get AutoSSH server
+// @Summary Get AutoSSH server
+// @Description Get the built-in tunnel server state, including live listeners of remote mappings
+// @Security BearerAuth
+// @Tags admin required
+// @Produce json
+// @Success 200 {object} model.CommonResponse[model.AutoSSHServerInfo]
+// @Router /autossh/server [get]
+func getAutoSSHServer(c *gin.Context) (*model.AutoSSHServerInfo, error) {
+	if singleton.AutoSSHServerShared == nil {
+		return &model.AutoSSHServerInfo{}, nil
+	}
+	return singleton.AutoSSHServerShared.Info(), nil
+}
+
+// restartAutoSSHMappings 用服务器当前的密钥重新下发未停止的映射
+func restartAutoSSHMappings(serverID uint64) {
+	// 断开内置隧道服务器上使用旧密钥的连接
+	if singleton.AutoSSHServerShared != nil {
+		singleton.AutoSSHServerShared.DisconnectServer(serverID)
+	}
+	for _, a := range singleton.AutoSSHShared.GetSortedList() {
+		if a.ServerID != serverID || a.Status == model.AutoSSHStatusStopped {
+			continue
+		}
+		go startAutoSSHMapping(a)
+	}
+}
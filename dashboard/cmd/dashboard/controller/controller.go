@@ -54,6 +54,7 @@ func routers(r *gin.Engine, frontendDist fs.FS) {
 	r.HEAD("/nezha-agent", commonHandler(downloadAgentBinary))
 	r.GET("/install.sh", commonHandler(downloadInstallScript))
 	r.HEAD("/install.sh", commonHandler(downloadInstallScript))
+	r.GET("/metrics", prometheusMetrics)
 
 	authMiddleware, err := jwt.New(initParams())
 	if err != nil {
@@ -70,11 +71,13 @@ func routers(r *gin.Engine, frontendDist fs.FS) {
 	api.POST("/terminal/check-command", commonHandler(checkCommand))
 	api.POST("/terminal/record-command", commonHandler(recordCommand))
 	api.POST("/terminal/upload-recording", commonHandler(uploadRecording))
+	api.POST("/terminal/record-transfer", commonHandler(recordTransfer))
 
 	fallbackAuthMw := fallbackAuthMiddleware(authMiddleware)
 	fallbackAuth := api.Group("", fallbackAuthMw)
 	fallbackAuth.GET("/setting", commonHandler(listConfig))
 	fallbackAuth.GET("/oauth2/callback", commonHandler(oauth2callback(authMiddleware)))
+	fallbackAuth.GET("/nat/:id/authorize", authorizeNAT)
 
 	authMw := authMiddleware.MiddlewareFunc()
 	optionalAuthMw := utils.IfOr(singleton.Conf.ForceAuth, authMw, fallbackAuthMw)
@@ -93,6 +96,9 @@ func routers(r *gin.Engine, frontendDist fs.FS) {
 
 	auth.POST("/terminal", commonHandler(createTerminal))
 	auth.GET("/ws/terminal/:id", commonHandler(terminalStream))
+	auth.GET("/terminal/detached", commonHandler(listDetachedTerminalSessions))
+	auth.POST("/terminal/broadcast", commonHandler(createTerminalBroadcast))
+	auth.GET("/ws/terminal/broadcast/:id", commonHandler(terminalBroadcastStream))
 	auth.GET("/terminal/recording/:session_id", func(c *gin.Context) {
 		auth, ok := c.Get(model.CtxKeyAuthorizedUser)
 		if !ok {
@@ -110,14 +116,24 @@ func routers(r *gin.Engine, frontendDist fs.FS) {
 	auth.GET("/terminal/recording-stream/:session_id", adminHandler(streamRecording))
 	auth.GET("/terminal/sessions", adminHandler(listTerminalSessions))
 	auth.GET("/terminal/commands", adminHandler(listTerminalCommands))
+	auth.GET("/terminal/transfers", adminHandler(listTerminalTransfers))
+	auth.GET("/terminal/transfers/:id/file", downloadTerminalTransfer)
 	auth.GET("/terminal/blacklist", adminHandler(listTerminalBlacklist))
 	auth.POST("/terminal/blacklist", adminHandler(createTerminalBlacklist))
 	auth.PATCH("/terminal/blacklist/:id", adminHandler(updateTerminalBlacklist))
 	auth.DELETE("/terminal/blacklist/:id", adminHandler(deleteTerminalBlacklist))
+	auth.GET("/terminal/user-mapping", adminHandler(listTerminalUserMapping))
+	auth.POST("/terminal/user-mapping", adminHandler(createTerminalUserMapping))
+	auth.DELETE("/terminal/user-mapping/:id", adminHandler(deleteTerminalUserMapping))
 
 	auth.GET("/file", commonHandler(createFM))
 	auth.GET("/ws/file/:id", commonHandler(fmStream))
 
+	auth.GET("/log/access-rule", adminHandler(listLogAccessRule))
+	auth.POST("/log/access-rule", adminHandler(createLogAccessRule))
+	auth.DELETE("/log/access-rule/:id", adminHandler(deleteLogAccessRule))
+	auth.GET("/ws/log/:id", commonHandler(logStream))
+
 	auth.GET("/profile", commonHandler(getProfile))
 	auth.POST("/profile", commonHandler(updateProfile))
 	auth.POST("/oauth2/:provider/unbind", commonHandler(unbindOauth2))
@@ -144,12 +160,32 @@ func routers(r *gin.Engine, frontendDist fs.FS) {
 
 	auth.GET("/server", commonHandler(listServer))
 	auth.PATCH("/server/:id", commonHandler(updateServer))
+	auth.GET("/server/:id/metrics", commonHandler(listServerMetrics))
+	auth.GET("/server/:id/custom-metrics", commonHandler(listServerCustomMetrics))
+	auth.GET("/server/:id/custom-metrics/history", commonHandler(listServerCustomMetricHistory))
+	auth.GET("/server/:id/container", commonHandler(listServerContainer))
+	auth.GET("/server/:id/container/:cid", commonHandler(getServerContainer))
+	auth.GET("/server/:id/container/:cid/log", commonHandler(tailServerContainerLog))
+	auth.POST("/server/:id/container/:cid/action", commonHandler(serverContainerAction))
+	auth.GET("/server/:id/process", commonHandler(listServerProcess))
+	auth.POST("/server/:id/process/:pid/signal", commonHandler(signalServerProcess))
+	auth.GET("/server/:id/systemd", commonHandler(listServerSystemdUnit))
+	auth.POST("/server/:id/systemd/:unit/action", commonHandler(serverSystemdAction))
+	auth.POST("/server/:id/log", commonHandler(createLogSession))
 	auth.GET("/server/config/:id", commonHandler(getServerConfig))
 	auth.POST("/server/config", commonHandler(setServerConfig))
 	auth.POST("/batch-delete/server", commonHandler(batchDeleteServer))
 	auth.POST("/batch-move/server", commonHandler(batchMoveServer))
 	auth.POST("/force-update/server", commonHandler(forceUpdateServer))
 
+	auth.GET("/kubernetes", commonHandler(listKubeCluster))
+	auth.GET("/kubernetes/:id", commonHandler(getKubeCluster))
+	auth.GET("/kubernetes/:id/node", commonHandler(listKubeNode))
+	auth.GET("/kubernetes/:id/namespace", commonHandler(listKubeNamespace))
+	auth.GET("/kubernetes/:id/workload", commonHandler(listKubeWorkload))
+	auth.GET("/kubernetes/:id/pod", pCommonHandler(listKubePod))
+	auth.GET("/kubernetes/:id/event", commonHandler(listKubeEvent))
+
 	auth.GET("/notification", listHandler(listNotification))
 	auth.POST("/notification", commonHandler(createNotification))
 	auth.PATCH("/notification/:id", commonHandler(updateNotification))
@@ -175,14 +211,28 @@ func routers(r *gin.Engine, frontendDist fs.FS) {
 	auth.GET("/nat", listHandler(listNAT))
 	auth.POST("/nat", commonHandler(createNAT))
 	auth.PATCH("/nat/:id", commonHandler(updateNAT))
+	auth.GET("/nat/:id/transfer", commonHandler(listNATTransfer))
+	auth.GET("/nat/:id/log", pCommonHandler(listNATRequestLog))
 	auth.POST("/batch-delete/nat", commonHandler(batchDeleteNAT))
 
+	auth.GET("/port-forward", listHandler(listPortForward))
+	auth.POST("/port-forward", adminHandler(createPortForward))
+	auth.PATCH("/port-forward/:id", adminHandler(updatePortForward))
+	auth.GET("/port-forward/:id/transfer", commonHandler(listPortForwardTransfer))
+	auth.POST("/batch-delete/port-forward", commonHandler(batchDeletePortForward))
+
 	auth.GET("/autossh", listHandler(listAutoSSH))
 	auth.POST("/autossh", commonHandler(createAutoSSH))
 	auth.PATCH("/autossh/:id", commonHandler(updateAutoSSH))
 	auth.POST("/autossh/:id/start", commonHandler(startAutoSSHHandler))
 	auth.POST("/autossh/:id/stop", commonHandler(stopAutoSSHHandler))
+	auth.GET("/autossh/:id/transfer", commonHandler(listAutoSSHTransfer))
 	auth.POST("/batch-delete/autossh", commonHandler(batchDeleteAutoSSH))
+	auth.GET("/autossh/key", adminHandler(listAutoSSHKeys))
+	auth.POST("/autossh/key/:server_id/rotate", adminHandler(rotateAutoSSHKey))
+	auth.POST("/autossh/key/:server_id/revoke", adminHandler(revokeAutoSSHKey))
+	auth.GET("/autossh/authorized-keys", adminHandler(getAutoSSHAuthorizedKeys))
+	auth.GET("/autossh/server", adminHandler(getAutoSSHServer))
 
 	auth.GET("/waf", pCommonHandler(listBlockedAddress))
 	auth.POST("/batch-delete/waf", adminHandler(batchDeleteBlockedAddress))
@@ -378,6 +428,7 @@ func fallbackToFrontend(frontendDist fs.FS) func(*gin.Context) {
 		regexp.MustCompile(`^/dashboard/settings/online-user$`),
 		regexp.MustCompile(`^/dashboard/settings/waf$`),
 		regexp.MustCompile(`^/dashboard/settings/terminal-audit$`),
+		regexp.MustCompile(`^/dashboard/terminal/broadcast$`),
 	}
 
 	getFallbackStatusCode := func(path string) int {
@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/service/singleton"
+)
+
+// 容器日志默认返回的行数
+const dockerDefaultLogTail = 200
+
+// List containers of server
+// @Summary List containers of server
+// @Security BearerAuth
+// @Schemes
+// @Description List containers and images last reported by the agent
+// @Tags auth required
+// @param id path uint true "Server ID"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[model.DockerState]
+// @Router /server/{id}/container [get]
+func listServerContainer(c *gin.Context) (*model.DockerState, error) {
+	server, err := getAuthorizedServer(c)
+	if err != nil {
+		return nil, err
+	}
+	if server.Docker == nil {
+		return &model.DockerState{Containers: []*model.DockerContainer{}, Images: []*model.DockerImage{}}, nil
+	}
+	return server.Docker, nil
+}
+
+// Get container detail
+// @Summary Get container detail
+// @Security BearerAuth
+// @Schemes
+// @Description Get the last reported state of a container along with live details from the agent
+// @Tags auth required
+// @param id path uint true "Server ID"
+// @param cid path string true "Container ID"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[model.DockerContainerInfo]
+// @Router /server/{id}/container/{cid} [get]
+func getServerContainer(c *gin.Context) (*model.DockerContainerInfo, error) {
+	server, container, err := getDockerContainer(c)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &model.DockerContainerInfo{Container: container}
+	data, err := singleton.DockerShared.Call(server, &model.TaskDocker{
+		Action:      model.DockerActionInspect,
+		ContainerID: container.ID,
+	})
+	if err != nil {
+		info.Error = err.Error()
+		return info, nil
+	}
+	var detail model.DockerContainerDetail
+	if err := json.Unmarshal([]byte(data), &detail); err != nil {
+		info.Error = err.Error()
+		return info, nil
+	}
+	info.Detail = &detail
+	return info, nil
+}
+
+// Start, stop or restart container
+// @Summary Start, stop or restart container
+// @Security BearerAuth
+// @Schemes
+// @Description Start, stop or restart a container through the agent
+// @Tags auth required
+// @Accept json
+// @param id path uint true "Server ID"
+// @param cid path string true "Container ID"
+// @param request body model.DockerActionForm true "Action"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[any]
+// @Router /server/{id}/container/{cid}/action [post]
+func serverContainerAction(c *gin.Context) (any, error) {
+	var form model.DockerActionForm
+	if err := c.ShouldBindJSON(&form); err != nil {
+		return nil, err
+	}
+	switch form.Action {
+	case model.DockerActionStart, model.DockerActionStop, model.DockerActionRestart:
+	default:
+		return nil, singleton.Localizer.ErrorT("invalid action")
+	}
+
+	server, container, err := getDockerContainer(c)
+	if err != nil {
+		return nil, err
+	}
+
+	user := c.MustGet(model.CtxKeyAuthorizedUser).(*model.User)
+	log.Printf("NEZHA>> User %s %s container %s (%s) on server %d", user.Username, form.Action, container.Name, container.ID, server.ID)
+
+	_, err = singleton.DockerShared.Call(server, &model.TaskDocker{
+		Action:      form.Action,
+		ContainerID: container.ID,
+	})
+	recordAuditedAction(user, server.ID, "docker "+form.Action+" "+container.Name, err)
+	if err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// Tail container logs
+// @Summary Tail container logs
+// @Security BearerAuth
+// @Schemes
+// @Description Get the last lines of stdout and stderr of a container
+// @Tags auth required
+// @param id path uint true "Server ID"
+// @param cid path string true "Container ID"
+// @param tail query int false "Number of lines, 200 by default, at most 5000"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[string]
+// @Router /server/{id}/container/{cid}/log [get]
+func tailServerContainerLog(c *gin.Context) (string, error) {
+	tail := dockerDefaultLogTail
+	if v := c.Query("tail"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 5000 {
+			return "", singleton.Localizer.ErrorT("invalid tail")
+		}
+		tail = n
+	}
+
+	server, container, err := getDockerContainer(c)
+	if err != nil {
+		return "", err
+	}
+
+	return singleton.DockerShared.Call(server, &model.TaskDocker{
+		Action:      model.DockerActionLogs,
+		ContainerID: container.ID,
+		Tail:        tail,
+	})
+}
+
+func getAuthorizedServer(c *gin.Context) (*model.Server, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	server, ok := singleton.ServerShared.Get(id)
+	if !ok || server == nil {
+		return nil, singleton.Localizer.ErrorT("server not found")
+	}
+	if !checkServerPermission(c, id) {
+		return nil, singleton.Localizer.ErrorT("permission denied")
+	}
+	return server, nil
+}
+
+// getDockerContainer 只允许操作 agent 上报过的容器
+func getDockerContainer(c *gin.Context) (*model.Server, *model.DockerContainer, error) {
+	server, err := getAuthorizedServer(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	container, err := lookupContainer(server, c.Param("cid"))
+	if err != nil {
+		return nil, nil, err
+	}
+	return server, container, nil
+}
+
+func lookupContainer(server *model.Server, id string) (*model.DockerContainer, error) {
+	if state := server.Docker; state != nil && id != "" {
+		if container := state.Container(id); container != nil {
+			return container, nil
+		}
+	}
+	return nil, singleton.Localizer.ErrorT("container not found")
+}
@@ -0,0 +1,196 @@
+package controller
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/service/singleton"
+)
+
+// List Kubernetes clusters
+// @Summary List Kubernetes clusters
+// @Security BearerAuth
+// @Schemes
+// @Description List clusters reported by agents with the Kubernetes collector enabled
+// @Tags auth required
+// @Produce json
+// @Success 200 {object} model.CommonResponse[[]model.KubeClusterSummary]
+// @Router /kubernetes [get]
+func listKubeCluster(c *gin.Context) ([]*model.KubeClusterSummary, error) {
+	clusters := make([]*model.KubeClusterSummary, 0)
+	for _, server := range singleton.ServerShared.GetSortedList() {
+		cluster := server.Kubernetes
+		if cluster == nil || !checkServerPermission(c, server.ID) {
+			continue
+		}
+		clusters = append(clusters, cluster.Summary(server))
+	}
+	return clusters, nil
+}
+
+// Get Kubernetes cluster summary
+// @Summary Get Kubernetes cluster summary
+// @Security BearerAuth
+// @Schemes
+// @Description Get the summary of the cluster reported by a server
+// @Tags auth required
+// @param id path uint true "Server ID"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[model.KubeClusterSummary]
+// @Router /kubernetes/{id} [get]
+func getKubeCluster(c *gin.Context) (*model.KubeClusterSummary, error) {
+	server, cluster, err := getKubeClusterOfServer(c)
+	if err != nil {
+		return nil, err
+	}
+	return cluster.Summary(server), nil
+}
+
+// List Kubernetes nodes
+// @Summary List Kubernetes nodes
+// @Security BearerAuth
+// @Schemes
+// @Description List nodes of a cluster
+// @Tags auth required
+// @param id path uint true "Server ID"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[[]model.KubeNode]
+// @Router /kubernetes/{id}/node [get]
+func listKubeNode(c *gin.Context) ([]*model.KubeNode, error) {
+	_, cluster, err := getKubeClusterOfServer(c)
+	if err != nil {
+		return nil, err
+	}
+	return cluster.Nodes, nil
+}
+
+// List Kubernetes namespaces
+// @Summary List Kubernetes namespaces
+// @Security BearerAuth
+// @Schemes
+// @Description List namespaces of a cluster
+// @Tags auth required
+// @param id path uint true "Server ID"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[[]model.KubeNamespace]
+// @Router /kubernetes/{id}/namespace [get]
+func listKubeNamespace(c *gin.Context) ([]*model.KubeNamespace, error) {
+	_, cluster, err := getKubeClusterOfServer(c)
+	if err != nil {
+		return nil, err
+	}
+	return cluster.Namespaces, nil
+}
+
+// List Kubernetes workloads
+// @Summary List Kubernetes workloads
+// @Security BearerAuth
+// @Schemes
+// @Description List deployments, statefulsets and daemonsets of a cluster
+// @Tags auth required
+// @param id path uint true "Server ID"
+// @param namespace query string false "Namespace"
+// @param kind query string false "Deployment, StatefulSet or DaemonSet"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[[]model.KubeWorkload]
+// @Router /kubernetes/{id}/workload [get]
+func listKubeWorkload(c *gin.Context) ([]*model.KubeWorkload, error) {
+	_, cluster, err := getKubeClusterOfServer(c)
+	if err != nil {
+		return nil, err
+	}
+	namespace, kind := c.Query("namespace"), c.Query("kind")
+	return slices.DeleteFunc(slices.Clone(cluster.Workloads), func(w *model.KubeWorkload) bool {
+		return (namespace != "" && w.Namespace != namespace) || (kind != "" && !strings.EqualFold(w.Kind, kind))
+	}), nil
+}
+
+// List Kubernetes pods
+// @Summary List Kubernetes pods
+// @Security BearerAuth
+// @Schemes
+// @Description List pods of a cluster. Owner matches the controlling object such as a ReplicaSet name
+// @Tags auth required
+// @param id path uint true "Server ID"
+// @param namespace query string false "Namespace"
+// @param node query string false "Node name"
+// @param phase query string false "Pod phase"
+// @param owner query string false "Owner name"
+// @param limit query int false "Page limit, 100 by default"
+// @param offset query int false "Page offset"
+// @Produce json
+// @Success 200 {object} model.PaginatedResponse[[]model.KubePod, model.KubePod]
+// @Router /kubernetes/{id}/pod [get]
+func listKubePod(c *gin.Context) (*model.Value[[]*model.KubePod], error) {
+	_, cluster, err := getKubeClusterOfServer(c)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit < 1 {
+		limit = 100
+	}
+	offset, err := strconv.Atoi(c.Query("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	namespace, node, phase, owner := c.Query("namespace"), c.Query("node"), c.Query("phase"), c.Query("owner")
+	pods := slices.DeleteFunc(slices.Clone(cluster.Pods), func(p *model.KubePod) bool {
+		return (namespace != "" && p.Namespace != namespace) ||
+			(node != "" && p.Node != node) ||
+			(phase != "" && !strings.EqualFold(p.Phase, phase)) ||
+			(owner != "" && p.OwnerName != owner)
+	})
+
+	total := len(pods)
+	pods = pods[min(offset, total):min(offset+limit, total)]
+	return &model.Value[[]*model.KubePod]{
+		Value: pods,
+		Pagination: model.Pagination{
+			Offset: offset,
+			Limit:  limit,
+			Total:  int64(total),
+		},
+	}, nil
+}
+
+// List Kubernetes events
+// @Summary List Kubernetes events
+// @Security BearerAuth
+// @Schemes
+// @Description List the most recent events of a cluster, newest first
+// @Tags auth required
+// @param id path uint true "Server ID"
+// @param namespace query string false "Namespace"
+// @param type query string false "Normal or Warning"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[[]model.KubeEvent]
+// @Router /kubernetes/{id}/event [get]
+func listKubeEvent(c *gin.Context) ([]*model.KubeEvent, error) {
+	_, cluster, err := getKubeClusterOfServer(c)
+	if err != nil {
+		return nil, err
+	}
+	namespace, typ := c.Query("namespace"), c.Query("type")
+	return slices.DeleteFunc(slices.Clone(cluster.Events), func(e *model.KubeEvent) bool {
+		return (namespace != "" && e.Namespace != namespace) || (typ != "" && !strings.EqualFold(e.Type, typ))
+	}), nil
+}
+
+func getKubeClusterOfServer(c *gin.Context) (*model.Server, *model.KubeCluster, error) {
+	server, err := getAuthorizedServer(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	cluster := server.Kubernetes
+	if cluster == nil {
+		return nil, nil, singleton.Localizer.ErrorT("cluster not found")
+	}
+	return server, cluster, nil
+}
@@ -0,0 +1,301 @@
+package controller
+
+import (
+	"log"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/hashicorp/go-uuid"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/pkg/websocketx"
+	"github.com/nezhahq/nezha/service/rpc"
+	"github.com/nezhahq/nezha/service/singleton"
+)
+
+// List log access rules
+// @Summary List log access rules
+// @Description List the log files and systemd units members may read
+// @Security BearerAuth
+// @Tags admin required
+// @Produce json
+// @Success 200 {object} model.CommonResponse[[]model.LogAccessRule]
+// @Router /log/access-rule [get]
+func listLogAccessRule(c *gin.Context) ([]*model.LogAccessRule, error) {
+	var rules []*model.LogAccessRule
+	if err := singleton.DB.Order("user_id, server_id").Find(&rules).Error; err != nil {
+		return nil, newGormError("%v", err)
+	}
+	return rules, nil
+}
+
+// Create or update log access rule
+// @Summary Create or update log access rule
+// @Description Allow a member to read log files and systemd units on a server, server_id 0 applies to all servers
+// @Security BearerAuth
+// @Tags admin required
+// @Accept json
+// @Param request body model.LogAccessRuleForm true "Log Access Rule"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[uint64]
+// @Router /log/access-rule [post]
+func createLogAccessRule(c *gin.Context) (uint64, error) {
+	var rf model.LogAccessRuleForm
+	if err := c.ShouldBindJSON(&rf); err != nil {
+		return 0, err
+	}
+
+	paths := make([]string, 0, len(rf.Paths))
+	for _, p := range rf.Paths {
+		cleaned, ok := model.CleanLogPath(p)
+		if _, err := path.Match(cleaned, ""); !ok || err != nil {
+			return 0, singleton.Localizer.ErrorT("invalid path: %s", p)
+		}
+		paths = append(paths, cleaned)
+	}
+	for _, u := range rf.Units {
+		if _, err := path.Match(u, ""); err != nil || u == "" || strings.HasPrefix(u, "-") {
+			return 0, singleton.Localizer.ErrorT("invalid unit: %s", u)
+		}
+	}
+	if err := singleton.DB.First(&model.User{}, rf.UserID).Error; err != nil {
+		return 0, singleton.Localizer.ErrorT("user id %d does not exist", rf.UserID)
+	}
+	if rf.ServerID != 0 {
+		if _, ok := singleton.ServerShared.Get(rf.ServerID); !ok {
+			return 0, singleton.Localizer.ErrorT("server id %d does not exist", rf.ServerID)
+		}
+	}
+
+	var rule model.LogAccessRule
+	singleton.DB.Where("user_id = ? AND server_id = ?", rf.UserID, rf.ServerID).First(&rule)
+	rule.UserID = rf.UserID
+	rule.ServerID = rf.ServerID
+	rule.Paths = paths
+	rule.Units = rf.Units
+	if rule.Units == nil {
+		rule.Units = []string{}
+	}
+
+	if err := singleton.DB.Save(&rule).Error; err != nil {
+		return 0, newGormError("%v", err)
+	}
+
+	return rule.ID, nil
+}
+
+// Delete log access rule
+// @Summary Delete log access rule
+// @Description Delete a log access rule
+// @Security BearerAuth
+// @Tags admin required
+// @Param id path uint true "Rule ID"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[any]
+// @Router /log/access-rule/{id} [delete]
+func deleteLogAccessRule(c *gin.Context) (any, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := singleton.DB.Delete(&model.LogAccessRule{}, id).Error; err != nil {
+		return nil, newGormError("%v", err)
+	}
+
+	return nil, nil
+}
+
+// Create log session
+// @Summary Create log session
+// @Security BearerAuth
+// @Schemes
+// @Description Follow a log file or systemd unit, or search a log file and its rotated files on the agent. Members need a log access rule, every attempt is recorded in the terminal command audit
+// @Tags auth required
+// @Accept json
+// @param id path uint true "Server ID"
+// @param request body model.LogForm true "Log Request"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[model.CreateLogResponse]
+// @Router /server/{id}/log [post]
+func createLogSession(c *gin.Context) (*model.CreateLogResponse, error) {
+	var form model.LogForm
+	if err := c.ShouldBindJSON(&form); err != nil {
+		return nil, err
+	}
+
+	switch form.Mode {
+	case model.LogModeFollow, model.LogModeSearch:
+		cleaned, ok := model.CleanLogPath(form.Path)
+		if !ok {
+			return nil, singleton.Localizer.ErrorT("invalid path: %s", form.Path)
+		}
+		form.Path, form.Unit = cleaned, ""
+	case model.LogModeJournal:
+		if !model.ValidLogUnit(form.Unit) {
+			return nil, singleton.Localizer.ErrorT("invalid unit: %s", form.Unit)
+		}
+		form.Path = ""
+	default:
+		return nil, singleton.Localizer.ErrorT("invalid mode")
+	}
+	if form.Regex {
+		if _, err := regexp.Compile(form.Filter); err != nil {
+			return nil, err
+		}
+	}
+
+	server, err := getAuthorizedServer(c)
+	if err != nil {
+		return nil, err
+	}
+	if server.TaskStream == nil {
+		return nil, singleton.Localizer.ErrorT("server not found or not connected")
+	}
+
+	user := c.MustGet(model.CtxKeyAuthorizedUser).(*model.User)
+	log.Printf("NEZHA>> User %s reads log on server %d: %s", user.Username, server.ID, logCommand(&form))
+
+	sessionID, err := startLogSession(user, server, &form)
+	// 被拒绝的访问同样记录
+	recordAuditedAction(user, server.ID, logCommand(&form), err)
+	if err != nil {
+		return nil, err
+	}
+	return &model.CreateLogResponse{SessionID: sessionID}, nil
+}
+
+func startLogSession(user *model.User, server *model.Server, form *model.LogForm) (string, error) {
+	if err := checkLogAccess(user, server.ID, form); err != nil {
+		return "", err
+	}
+
+	streamId, err := uuid.GenerateUUID()
+	if err != nil {
+		return "", err
+	}
+
+	rpc.NezhaHandlerSingleton.CreateStream(streamId)
+
+	// 等待 agent 确认，被 agent 拒绝的读取不会打开日志流
+	if err := singleton.LogShared.Start(server, &model.TaskLog{
+		StreamID: streamId,
+		Mode:     form.Mode,
+		Path:     form.Path,
+		Unit:     form.Unit,
+		Filter:   form.Filter,
+		Regex:    form.Regex,
+		Lines:    form.Lines,
+		Limit:    form.Limit,
+	}); err != nil {
+		rpc.NezhaHandlerSingleton.CloseStream(streamId)
+		return "", err
+	}
+	return streamId, nil
+}
+
+// checkLogAccess 管理员可以读取任何日志；普通用户只能读取规则允许的日志，服务器级规则优先于全局规则
+func checkLogAccess(user *model.User, serverID uint64, form *model.LogForm) error {
+	if user.Role.IsAdmin() {
+		return nil
+	}
+
+	var rules []model.LogAccessRule
+	if err := singleton.DB.Where("user_id = ? AND server_id IN ?", user.ID, []uint64{0, serverID}).
+		Order("server_id DESC").Limit(1).Find(&rules).Error; err != nil {
+		return newGormError("%v", err)
+	}
+	if len(rules) == 0 {
+		return singleton.Localizer.ErrorT("permission denied")
+	}
+
+	if form.Mode == model.LogModeJournal {
+		if !rules[0].AllowUnit(form.Unit) {
+			return singleton.Localizer.ErrorT("permission denied")
+		}
+		return nil
+	}
+	if !rules[0].AllowPath(form.Path) {
+		return singleton.Localizer.ErrorT("permission denied")
+	}
+	return nil
+}
+
+// logCommand 以等价的命令记录审计
+func logCommand(form *model.LogForm) string {
+	var grep string
+	if form.Filter != "" {
+		flag := "-F"
+		if form.Regex {
+			flag = "-E"
+		}
+		grep = "grep " + flag + " " + strconv.Quote(form.Filter)
+	}
+
+	switch form.Mode {
+	case model.LogModeJournal:
+		cmd := "journalctl -f -u " + form.Unit
+		if grep != "" {
+			cmd += " | " + grep
+		}
+		return cmd
+	case model.LogModeSearch:
+		if grep == "" {
+			grep = "cat"
+		}
+		return "z" + grep + " " + form.Path + "*"
+	default:
+		cmd := "tail -f " + form.Path
+		if grep != "" {
+			cmd += " | " + grep
+		}
+		return cmd
+	}
+}
+
+// Start log stream
+// @Summary Start log stream
+// @Description Start log stream
+// @Tags auth required
+// @Param id path string true "Stream UUID"
+// @Success 200 {object} model.CommonResponse[any]
+// @Router /ws/log/{id} [get]
+func logStream(c *gin.Context) (any, error) {
+	streamId := c.Param("id")
+	if _, err := rpc.NezhaHandlerSingleton.GetStream(streamId); err != nil {
+		return nil, err
+	}
+	defer rpc.NezhaHandlerSingleton.CloseStream(streamId)
+
+	wsConn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return nil, newWsError("%v", err)
+	}
+	defer wsConn.Close()
+	conn := websocketx.NewConn(wsConn)
+
+	go func() {
+		// PING 保活
+		for {
+			if err = conn.WriteMessage(websocket.PingMessage, []byte{}); err != nil {
+				return
+			}
+			time.Sleep(time.Second * 10)
+		}
+	}()
+
+	if err = rpc.NezhaHandlerSingleton.UserConnected(streamId, conn); err != nil {
+		return nil, newWsError("%v", err)
+	}
+
+	if err = rpc.NezhaHandlerSingleton.StartStream(streamId, time.Second*10); err != nil {
+		return nil, newWsError("%v", err)
+	}
+
+	return nil, newWsError("")
+}
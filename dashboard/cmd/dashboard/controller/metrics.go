@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/service/singleton"
+)
+
+// Query server metrics
+// @Summary Query server metrics
+// @Security BearerAuth
+// @Schemes
+// @Description Query stored host metrics of a server in a time range. Aggregated points hold the average of each bucket, *_max fields hold the maximum
+// @Tags auth required
+// @param id path uint true "Server ID"
+// @param start query int false "Start time in unix seconds, 1 hour before end by default"
+// @param end query int false "End time in unix seconds, now by default"
+// @param resolution query string false "raw, 1m, 1h, 1d or auto (default)"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[model.MetricSeries]
+// @Router /server/{id}/metrics [get]
+func listServerMetrics(c *gin.Context) (*model.MetricSeries, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := singleton.ServerShared.Get(id); !ok {
+		return nil, singleton.Localizer.ErrorT("server not found")
+	}
+	if !checkServerPermission(c, id) {
+		return nil, singleton.Localizer.ErrorT("permission denied")
+	}
+
+	start, end, res, err := parseMetricRange(c)
+	if err != nil {
+		return nil, err
+	}
+
+	points, err := singleton.MetricsShared.Query(id, res, start, end)
+	if err != nil {
+		return nil, newGormError("%v", err)
+	}
+	return &model.MetricSeries{
+		ServerID:   id,
+		Resolution: singleton.MetricResolutionName(res),
+		Start:      start.Unix(),
+		End:        end.Unix(),
+		Points:     points,
+	}, nil
+}
+
+// List custom metrics of server
+// @Summary List custom metrics of server
+// @Security BearerAuth
+// @Schemes
+// @Description List the latest values reported by the custom collectors of the agent
+// @Tags auth required
+// @param id path uint true "Server ID"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[[]model.CustomMetric]
+// @Router /server/{id}/custom-metrics [get]
+func listServerCustomMetrics(c *gin.Context) ([]model.CustomMetric, error) {
+	server, err := getAuthorizedServer(c)
+	if err != nil {
+		return nil, err
+	}
+	if server.State == nil || server.State.CustomMetrics == nil {
+		return []model.CustomMetric{}, nil
+	}
+	return server.State.CustomMetrics, nil
+}
+
+// Query custom metric history
+// @Summary Query custom metric history
+// @Security BearerAuth
+// @Schemes
+// @Description Query stored values of a custom metric in a time range. The name includes labels, e.g. queue_depth{queue="mail"}
+// @Tags auth required
+// @param id path uint true "Server ID"
+// @param name query string true "Metric name"
+// @param start query int false "Start time in unix seconds, 1 hour before end by default"
+// @param end query int false "End time in unix seconds, now by default"
+// @param resolution query string false "raw, 1m, 1h, 1d or auto (default)"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[model.CustomMetricSeries]
+// @Router /server/{id}/custom-metrics/history [get]
+func listServerCustomMetricHistory(c *gin.Context) (*model.CustomMetricSeries, error) {
+	server, err := getAuthorizedServer(c)
+	if err != nil {
+		return nil, err
+	}
+	name := c.Query("name")
+	if name == "" {
+		return nil, singleton.Localizer.ErrorT("metric name is required")
+	}
+
+	start, end, res, err := parseMetricRange(c)
+	if err != nil {
+		return nil, err
+	}
+
+	points, err := singleton.MetricsShared.QueryCustom(server.ID, name, res, start, end)
+	if err != nil {
+		return nil, newGormError("%v", err)
+	}
+	return &model.CustomMetricSeries{
+		ServerID:   server.ID,
+		Name:       name,
+		Resolution: singleton.MetricResolutionName(res),
+		Start:      start.Unix(),
+		End:        end.Unix(),
+		Points:     points,
+	}, nil
+}
+
+func parseMetricRange(c *gin.Context) (time.Time, time.Time, int, error) {
+	end := time.Now()
+	if v := c.Query("end"); v != "" {
+		ts, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return time.Time{}, time.Time{}, 0, singleton.Localizer.ErrorT("invalid time range")
+		}
+		end = time.Unix(ts, 0)
+	}
+	start := end.Add(-time.Hour)
+	if v := c.Query("start"); v != "" {
+		ts, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return time.Time{}, time.Time{}, 0, singleton.Localizer.ErrorT("invalid time range")
+		}
+		start = time.Unix(ts, 0)
+	}
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, 0, singleton.Localizer.ErrorT("invalid time range")
+	}
+
+	res, err := singleton.ParseMetricResolution(c.Query("resolution"), start, end)
+	if err != nil {
+		return time.Time{}, time.Time{}, 0, singleton.Localizer.ErrorT("invalid resolution")
+	}
+
+	return start, end, res, nil
+}
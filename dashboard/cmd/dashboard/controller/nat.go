@@ -1,17 +1,27 @@
 package controller
 
 import (
+	"net/http"
+	"net/url"
+	"regexp"
 	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
 	"github.com/jinzhu/copier"
+	"golang.org/x/crypto/bcrypt"
 
+	"github.com/nezhahq/nezha/cmd/dashboard/controller/waf"
 	"github.com/nezhahq/nezha/model"
 	"github.com/nezhahq/nezha/pkg/utils"
 	"github.com/nezhahq/nezha/service/singleton"
 )
 
+// 国家/地区代码，与 geoip 返回的格式一致
+var countryCodeRegex = regexp.MustCompile(`^[a-z]{2}$`)
+
 // List NAT Profiles
 // @Summary List NAT profiles
 // @Schemes
@@ -30,6 +40,9 @@ func listNAT(c *gin.Context) ([]*model.NAT, error) {
 	if err := copier.Copy(&n, &slist); err != nil {
 		return nil, err
 	}
+	for _, profile := range n {
+		profile.Traffic = singleton.TunnelStatsShared.Traffic(model.TunnelKindNAT, profile.ID)
+	}
 
 	return n, nil
 }
@@ -62,11 +75,9 @@ func createNAT(c *gin.Context) (uint64, error) {
 	uid := getUid(c)
 
 	n.UserID = uid
-	n.Enabled = nf.Enabled
-	n.Name = nf.Name
-	n.Domain = nf.Domain
-	n.Host = nf.Host
-	n.ServerID = nf.ServerID
+	if err := applyNATForm(&n, &nf); err != nil {
+		return 0, err
+	}
 
 	if err := singleton.DB.Create(&n).Error; err != nil {
 		return 0, newGormError("%v", err)
@@ -116,11 +127,9 @@ func updateNAT(c *gin.Context) (any, error) {
 		return nil, singleton.Localizer.ErrorT("permission denied")
 	}
 
-	n.Enabled = nf.Enabled
-	n.Name = nf.Name
-	n.Domain = nf.Domain
-	n.Host = nf.Host
-	n.ServerID = nf.ServerID
+	if err := applyNATForm(&n, &nf); err != nil {
+		return nil, err
+	}
 
 	if err := singleton.DB.Save(&n).Error; err != nil {
 		return 0, newGormError("%v", err)
@@ -158,6 +167,173 @@ func batchDeleteNAT(c *gin.Context) (any, error) {
 		return nil, newGormError("%v", err)
 	}
 
+	singleton.DB.Delete(&model.NATRequestLog{}, "nat_id in (?)", n)
+
 	singleton.NATShared.Delete(n)
+	singleton.TunnelStatsShared.Delete(model.TunnelKindNAT, n)
 	return nil, nil
 }
+
+// List NAT request logs
+// @Summary List NAT request logs
+// @Security BearerAuth
+// @Schemes
+// @Description List requests proxied or denied by a NAT profile, newest first
+// @Tags auth required
+// @param id path uint true "Profile ID"
+// @Param limit query uint false "Page limit"
+// @Param offset query uint false "Page offset"
+// @Produce json
+// @Success 200 {object} model.PaginatedResponse[[]model.NATRequestLog, model.NATRequestLog]
+// @Router /nat/{id}/log [get]
+func listNATRequestLog(c *gin.Context) (*model.Value[[]*model.NATRequestLog], error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	n := singleton.NATShared.GetNATConfigByDomain(singleton.NATShared.GetDomain(id))
+	if n == nil {
+		return nil, singleton.Localizer.ErrorT("profile id %d does not exist", id)
+	}
+	if !n.HasPermission(c) {
+		return nil, singleton.Localizer.ErrorT("permission denied")
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit < 1 {
+		limit = 25
+	}
+
+	offset, err := strconv.Atoi(c.Query("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	var logs []*model.NATRequestLog
+	if err := singleton.DB.Where("nat_id = ?", id).Order("id DESC").Limit(limit).Offset(offset).Find(&logs).Error; err != nil {
+		return nil, newGormError("%v", err)
+	}
+
+	var total int64
+	if err := singleton.DB.Model(&model.NATRequestLog{}).Where("nat_id = ?", id).Count(&total).Error; err != nil {
+		return nil, newGormError("%v", err)
+	}
+
+	return &model.Value[[]*model.NATRequestLog]{
+		Value: logs,
+		Pagination: model.Pagination{
+			Offset: offset,
+			Limit:  limit,
+			Total:  total,
+		},
+	}, nil
+}
+
+// Authorize NAT access
+// @Summary Authorize NAT access
+// @Schemes
+// @Description Used by NAT profiles that require a dashboard login. Redirects back to the NAT domain with a one-time ticket
+// @Tags common
+// @param id path uint true "Profile ID"
+// @param redirect query string true "URL on the NAT domain to return to"
+// @Success 302
+// @Router /nat/{id}/authorize [get]
+func authorizeNAT(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		waf.ShowBlockPage(c, err)
+		return
+	}
+	n := singleton.NATShared.GetNATConfigByDomain(singleton.NATShared.GetDomain(id))
+	if n == nil || !n.RequireLogin {
+		waf.ShowBlockPage(c, singleton.Localizer.ErrorT("profile id %d does not exist", id))
+		return
+	}
+
+	// 只允许跳转回此 NAT 的域名
+	redirect, err := url.Parse(c.Query("redirect"))
+	if err != nil || (redirect.Scheme != "http" && redirect.Scheme != "https") || redirect.Host != n.Domain {
+		waf.ShowBlockPage(c, singleton.Localizer.ErrorT("invalid redirect url"))
+		return
+	}
+
+	auth, ok := c.Get(model.CtxKeyAuthorizedUser)
+	if !ok {
+		c.Redirect(http.StatusFound, "/dashboard/login?redirect="+url.QueryEscape(c.Request.URL.RequestURI()))
+		return
+	}
+	if !n.HasPermission(c) {
+		waf.ShowBlockPage(c, singleton.Localizer.ErrorT("permission denied"))
+		return
+	}
+
+	query := redirect.Query()
+	query.Set(singleton.NATTicketQuery, singleton.IssueNATTicket(n.ID, auth.(*model.User).ID))
+	redirect.RawQuery = query.Encode()
+	c.Redirect(http.StatusFound, redirect.String())
+}
+
+func applyNATForm(n *model.NAT, nf *model.NATForm) error {
+	if len(nf.AllowedSources) > model.NATMaxAllowedSources {
+		return singleton.Localizer.ErrorT("too many allowed sources")
+	}
+	if _, err := model.ParseAllowedSources(nf.AllowedSources); err != nil {
+		return singleton.Localizer.ErrorT("invalid allowed sources: %v", err)
+	}
+
+	countries := make([]string, 0, len(nf.Countries))
+	for _, country := range nf.Countries {
+		country = strings.ToLower(strings.TrimSpace(country))
+		if !countryCodeRegex.MatchString(country) {
+			return singleton.Localizer.ErrorT("invalid country code %s", country)
+		}
+		countries = append(countries, country)
+	}
+	switch nf.CountryFilterMode {
+	case "", model.NATCountryFilterDeny:
+	case model.NATCountryFilterAllow:
+		if len(countries) == 0 {
+			return singleton.Localizer.ErrorT("country list is required")
+		}
+	default:
+		return singleton.Localizer.ErrorT("invalid country filter mode")
+	}
+
+	switch {
+	case nf.BasicAuthUsername == "":
+		n.BasicAuthPassword = ""
+	case nf.BasicAuthPassword != "":
+		hash, err := bcrypt.GenerateFromPassword([]byte(nf.BasicAuthPassword), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+		n.BasicAuthPassword = string(hash)
+	case nf.BasicAuthUsername != n.BasicAuthUsername || n.BasicAuthPassword == "":
+		return singleton.Localizer.ErrorT("basic auth password is required")
+	}
+
+	allowedSources, err := json.Marshal(nf.AllowedSources)
+	if err != nil {
+		return err
+	}
+	countriesRaw, err := json.Marshal(countries)
+	if err != nil {
+		return err
+	}
+
+	n.Enabled = nf.Enabled
+	n.Name = nf.Name
+	n.Domain = nf.Domain
+	n.Host = nf.Host
+	n.ServerID = nf.ServerID
+	n.RateLimit = nf.RateLimit
+	n.RequireLogin = nf.RequireLogin
+	n.BasicAuthUsername = nf.BasicAuthUsername
+	n.AllowedSources = nf.AllowedSources
+	n.AllowedSourcesRaw = string(allowedSources)
+	n.CountryFilterMode = nf.CountryFilterMode
+	n.Countries = countries
+	n.CountriesRaw = string(countriesRaw)
+	return nil
+}
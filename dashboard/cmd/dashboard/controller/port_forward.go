@@ -0,0 +1,202 @@
+package controller
+
+import (
+	"net"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/copier"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/service/rpc"
+	"github.com/nezhahq/nezha/service/singleton"
+)
+
+// List port forwards
+// @Summary List port forwards
+// @Schemes
+// @Description List TCP/UDP port forwards tunneled through agents
+// @Security BearerAuth
+// @Tags auth required
+// @Param id query uint false "Resource ID"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[[]model.PortForward]
+// @Router /port-forward [get]
+func listPortForward(c *gin.Context) ([]*model.PortForward, error) {
+	var pfs []*model.PortForward
+
+	slist := singleton.PortForwardShared.GetSortedList()
+
+	if err := copier.Copy(&pfs, &slist); err != nil {
+		return nil, err
+	}
+	for _, pf := range pfs {
+		pf.Listening, pf.ListenError = rpc.NezhaHandlerSingleton.PortForwardState(pf.ID)
+		pf.Traffic = singleton.TunnelStatsShared.Traffic(model.TunnelKindPortForward, pf.ID)
+	}
+
+	return pfs, nil
+}
+
+// Add port forward
+// @Summary Add port forward
+// @Security BearerAuth
+// @Schemes
+// @Description Listen on a dashboard port and forward each connection to a target reachable from the agent. Only admins may open listeners on the dashboard host
+// @Tags admin required
+// @Accept json
+// @param request body model.PortForwardForm true "Port Forward Request"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[uint64]
+// @Router /port-forward [post]
+func createPortForward(c *gin.Context) (uint64, error) {
+	var pff model.PortForwardForm
+	var pf model.PortForward
+
+	if err := c.ShouldBindJSON(&pff); err != nil {
+		return 0, err
+	}
+	if err := validatePortForwardForm(&pff); err != nil {
+		return 0, err
+	}
+
+	if _, ok := singleton.ServerShared.Get(pff.ServerID); ok {
+		if !checkServerPermission(c, pff.ServerID) {
+			return 0, singleton.Localizer.ErrorT("permission denied")
+		}
+	}
+
+	pf.UserID = getUid(c)
+	applyPortForwardForm(&pf, &pff)
+
+	if err := singleton.DB.Create(&pf).Error; err != nil {
+		return 0, newGormError("%v", err)
+	}
+
+	singleton.PortForwardShared.Update(&pf)
+	if err := rpc.NezhaHandlerSingleton.StartPortForward(&pf); err != nil {
+		return pf.ID, singleton.Localizer.ErrorT("port forward saved but failed to listen: %v", err)
+	}
+	return pf.ID, nil
+}
+
+// Edit port forward
+// @Summary Edit port forward
+// @Security BearerAuth
+// @Schemes
+// @Description Edit port forward
+// @Tags admin required
+// @Accept json
+// @param id path uint true "Port Forward ID"
+// @param request body model.PortForwardForm true "Port Forward Request"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[any]
+// @Router /port-forward/{id} [patch]
+func updatePortForward(c *gin.Context) (any, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	var pff model.PortForwardForm
+	if err := c.ShouldBindJSON(&pff); err != nil {
+		return nil, err
+	}
+	if err := validatePortForwardForm(&pff); err != nil {
+		return nil, err
+	}
+
+	if _, ok := singleton.ServerShared.Get(pff.ServerID); ok {
+		if !checkServerPermission(c, pff.ServerID) {
+			return nil, singleton.Localizer.ErrorT("permission denied")
+		}
+	}
+
+	var pf model.PortForward
+	if err = singleton.DB.First(&pf, id).Error; err != nil {
+		return nil, singleton.Localizer.ErrorT("port forward id %d does not exist", id)
+	}
+
+	if !pf.HasPermission(c) {
+		return nil, singleton.Localizer.ErrorT("permission denied")
+	}
+
+	applyPortForwardForm(&pf, &pff)
+
+	if err := singleton.DB.Save(&pf).Error; err != nil {
+		return nil, newGormError("%v", err)
+	}
+
+	singleton.PortForwardShared.Update(&pf)
+	if err := rpc.NezhaHandlerSingleton.StartPortForward(&pf); err != nil {
+		return nil, singleton.Localizer.ErrorT("port forward saved but failed to listen: %v", err)
+	}
+	return nil, nil
+}
+
+// Batch delete port forwards
+// @Summary Batch delete port forwards
+// @Security BearerAuth
+// @Schemes
+// @Description Batch delete port forwards
+// @Tags auth required
+// @Accept json
+// @param request body []uint64 true "id list"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[any]
+// @Router /batch-delete/port-forward [post]
+func batchDeletePortForward(c *gin.Context) (any, error) {
+	var ids []uint64
+	if err := c.ShouldBindJSON(&ids); err != nil {
+		return nil, err
+	}
+
+	if !singleton.PortForwardShared.CheckPermission(c, slices.Values(ids)) {
+		return nil, singleton.Localizer.ErrorT("permission denied")
+	}
+
+	if err := singleton.DB.Unscoped().Delete(&model.PortForward{}, "id in (?)", ids).Error; err != nil {
+		return nil, newGormError("%v", err)
+	}
+
+	for _, id := range ids {
+		rpc.NezhaHandlerSingleton.StopPortForward(id)
+	}
+	singleton.PortForwardShared.Delete(ids)
+	singleton.TunnelStatsShared.Delete(model.TunnelKindPortForward, ids)
+	return nil, nil
+}
+
+// validatePortForwardForm 监听端口不能与面板自身的端口冲突
+func validatePortForwardForm(pff *model.PortForwardForm) error {
+	if pff.Network != model.PortForwardNetworkTCP && pff.Network != model.PortForwardNetworkUDP {
+		return singleton.Localizer.ErrorT("invalid network")
+	}
+	if pff.ListenPort < 1 || pff.ListenPort > 65535 {
+		return singleton.Localizer.ErrorT("invalid port")
+	}
+	if pff.Network == model.PortForwardNetworkTCP &&
+		(pff.ListenPort == int(singleton.Conf.ListenPort) || pff.ListenPort == int(singleton.Conf.HTTPS.ListenPort)) {
+		return singleton.Localizer.ErrorT("port %d is used by the dashboard", pff.ListenPort)
+	}
+
+	host, port, err := net.SplitHostPort(pff.Target)
+	if err != nil || host == "" {
+		return singleton.Localizer.ErrorT("invalid target")
+	}
+	if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+		return singleton.Localizer.ErrorT("invalid target")
+	}
+	return nil
+}
+
+func applyPortForwardForm(pf *model.PortForward, pff *model.PortForwardForm) {
+	pf.Name = pff.Name
+	pf.Enabled = pff.Enabled
+	pf.ServerID = pff.ServerID
+	pf.Network = pff.Network
+	pf.ListenPort = pff.ListenPort
+	pf.Target = pff.Target
+	pf.RateLimit = pff.RateLimit
+}
@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/service/singleton"
+)
+
+// List top processes of server
+// @Summary List top processes of server
+// @Security BearerAuth
+// @Schemes
+// @Description Query the agent for the processes using the most CPU or memory
+// @Tags auth required
+// @param id path uint true "Server ID"
+// @param sort query string false "cpu (default) or memory"
+// @param limit query int false "Number of processes, 20 by default and at most 100"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[[]model.Process]
+// @Router /server/{id}/process [get]
+func listServerProcess(c *gin.Context) ([]*model.Process, error) {
+	sort := c.DefaultQuery("sort", model.ProcessSortCPU)
+	if sort != model.ProcessSortCPU && sort != model.ProcessSortMemory {
+		return nil, singleton.Localizer.ErrorT("invalid sort")
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > model.ProcessMaxLimit {
+		limit = 20
+	}
+
+	server, err := getAuthorizedServer(c)
+	if err != nil {
+		return nil, err
+	}
+	return singleton.ProcessShared.Top(server, sort, limit)
+}
+
+// Send signal to process
+// @Summary Send signal to process
+// @Security BearerAuth
+// @Schemes
+// @Description Send a signal to a process through the agent. Refused when command execution is disabled on the agent, every attempt is recorded in the terminal command audit
+// @Tags auth required
+// @Accept json
+// @param id path uint true "Server ID"
+// @param pid path int true "Process ID"
+// @param request body model.ProcessSignalForm true "Signal"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[any]
+// @Router /server/{id}/process/{pid}/signal [post]
+func signalServerProcess(c *gin.Context) (any, error) {
+	var form model.ProcessSignalForm
+	if err := c.ShouldBindJSON(&form); err != nil {
+		return nil, err
+	}
+	signal := model.NormalizeSignal(form.Signal)
+	if signal == "" {
+		return nil, singleton.Localizer.ErrorT("invalid signal")
+	}
+	pid, err := strconv.ParseInt(c.Param("pid"), 10, 32)
+	if err != nil || pid <= 1 {
+		return nil, singleton.Localizer.ErrorT("invalid pid")
+	}
+
+	server, err := getAuthorizedServer(c)
+	if err != nil {
+		return nil, err
+	}
+
+	user := c.MustGet(model.CtxKeyAuthorizedUser).(*model.User)
+	log.Printf("NEZHA>> User %s sends SIG%s to process %d on server %d", user.Username, signal, pid, server.ID)
+
+	err = singleton.ProcessShared.Signal(server, int32(pid), signal)
+	recordAuditedAction(user, server.ID, fmt.Sprintf("kill -%s %d", signal, pid), err)
+	if err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
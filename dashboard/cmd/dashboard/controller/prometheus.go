@@ -0,0 +1,330 @@
+package controller
+
+import (
+	"crypto/subtle"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/service/rpc"
+	"github.com/nezhahq/nezha/service/singleton"
+)
+
+// promWriter 以 Prometheus 文本格式输出指标，同一指标的样本需连续写入
+type promWriter struct {
+	strings.Builder
+}
+
+func (w *promWriter) family(name, typ, help string) {
+	w.WriteString("# HELP " + name + " " + help + "\n")
+	w.WriteString("# TYPE " + name + " " + typ + "\n")
+}
+
+// sample labels 为成对的标签名与标签值
+func (w *promWriter) sample(name string, value float64, labels ...string) {
+	w.WriteString(name)
+	if len(labels) > 0 {
+		w.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(labels[i] + `="` + promLabelReplacer.Replace(labels[i+1]) + `"`)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	w.WriteByte('\n')
+}
+
+var promLabelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func promBool(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+type promServerMetric struct {
+	name, typ, help string
+	value           func(*model.Server) float64
+}
+
+var promServerMetrics = []promServerMetric{
+	{"nezha_server_connected", "gauge", "Whether the agent task stream is connected.", func(s *model.Server) float64 { return promBool(s.TaskStream != nil) }},
+	{"nezha_server_last_active_timestamp_seconds", "gauge", "Time of the last state report.", func(s *model.Server) float64 {
+		if s.LastActive.IsZero() {
+			return 0
+		}
+		return float64(s.LastActive.Unix())
+	}},
+	{"nezha_server_cpu_usage_percent", "gauge", "CPU usage.", func(s *model.Server) float64 { return s.State.CPU }},
+	{"nezha_server_memory_used_bytes", "gauge", "Used memory.", func(s *model.Server) float64 { return float64(s.State.MemUsed) }},
+	{"nezha_server_memory_total_bytes", "gauge", "Total memory.", func(s *model.Server) float64 { return float64(s.Host.MemTotal) }},
+	{"nezha_server_swap_used_bytes", "gauge", "Used swap.", func(s *model.Server) float64 { return float64(s.State.SwapUsed) }},
+	{"nezha_server_swap_total_bytes", "gauge", "Total swap.", func(s *model.Server) float64 { return float64(s.Host.SwapTotal) }},
+	{"nezha_server_disk_used_bytes", "gauge", "Used disk space.", func(s *model.Server) float64 { return float64(s.State.DiskUsed) }},
+	{"nezha_server_disk_total_bytes", "gauge", "Total disk space.", func(s *model.Server) float64 { return float64(s.Host.DiskTotal) }},
+	{"nezha_server_network_receive_bytes_total", "counter", "Bytes received since the server booted.", func(s *model.Server) float64 { return float64(s.State.NetInTransfer) }},
+	{"nezha_server_network_transmit_bytes_total", "counter", "Bytes sent since the server booted.", func(s *model.Server) float64 { return float64(s.State.NetOutTransfer) }},
+	{"nezha_server_network_receive_bytes_per_second", "gauge", "Inbound network speed.", func(s *model.Server) float64 { return float64(s.State.NetInSpeed) }},
+	{"nezha_server_network_transmit_bytes_per_second", "gauge", "Outbound network speed.", func(s *model.Server) float64 { return float64(s.State.NetOutSpeed) }},
+	{"nezha_server_tunnel_receive_bytes_per_second", "gauge", "Inbound speed of AutoSSH mappings, NAT and port forwards on the server.", func(s *model.Server) float64 { return float64(s.TunnelInSpeed) }},
+	{"nezha_server_tunnel_transmit_bytes_per_second", "gauge", "Outbound speed of AutoSSH mappings, NAT and port forwards on the server.", func(s *model.Server) float64 { return float64(s.TunnelOutSpeed) }},
+	{"nezha_server_load1", "gauge", "1 minute load average.", func(s *model.Server) float64 { return s.State.Load1 }},
+	{"nezha_server_load5", "gauge", "5 minute load average.", func(s *model.Server) float64 { return s.State.Load5 }},
+	{"nezha_server_load15", "gauge", "15 minute load average.", func(s *model.Server) float64 { return s.State.Load15 }},
+	{"nezha_server_tcp_connections", "gauge", "TCP connections.", func(s *model.Server) float64 { return float64(s.State.TcpConnCount) }},
+	{"nezha_server_udp_connections", "gauge", "UDP connections.", func(s *model.Server) float64 { return float64(s.State.UdpConnCount) }},
+	{"nezha_server_processes", "gauge", "Running processes.", func(s *model.Server) float64 { return float64(s.State.ProcessCount) }},
+	{"nezha_server_uptime_seconds", "gauge", "Server uptime.", func(s *model.Server) float64 { return float64(s.State.Uptime) }},
+	{"nezha_server_boot_time_seconds", "gauge", "Server boot time.", func(s *model.Server) float64 { return float64(s.Host.BootTime) }},
+}
+
+// promBreakdownMetric 挂载点、块设备与网卡的指标，每个对象一个样本
+type promBreakdownMetric[T any] struct {
+	name, typ, help string
+	value           func(*T) float64
+}
+
+var promMountMetrics = []promBreakdownMetric[model.MountState]{
+	{"nezha_server_filesystem_size_bytes", "gauge", "Filesystem size of a mount.", func(m *model.MountState) float64 { return float64(m.Total) }},
+	{"nezha_server_filesystem_used_bytes", "gauge", "Used space of a mount.", func(m *model.MountState) float64 { return float64(m.Used) }},
+	{"nezha_server_filesystem_inodes", "gauge", "Total inodes of a mount.", func(m *model.MountState) float64 { return float64(m.InodesTotal) }},
+	{"nezha_server_filesystem_inodes_used", "gauge", "Used inodes of a mount.", func(m *model.MountState) float64 { return float64(m.InodesUsed) }},
+}
+
+var promDiskIOMetrics = []promBreakdownMetric[model.DiskIOState]{
+	{"nezha_server_disk_read_bytes_per_second", "gauge", "Read speed of a block device.", func(d *model.DiskIOState) float64 { return float64(d.ReadSpeed) }},
+	{"nezha_server_disk_written_bytes_per_second", "gauge", "Write speed of a block device.", func(d *model.DiskIOState) float64 { return float64(d.WriteSpeed) }},
+	{"nezha_server_disk_reads_per_second", "gauge", "Read operations per second of a block device.", func(d *model.DiskIOState) float64 { return float64(d.ReadIOPS) }},
+	{"nezha_server_disk_writes_per_second", "gauge", "Write operations per second of a block device.", func(d *model.DiskIOState) float64 { return float64(d.WriteIOPS) }},
+}
+
+var promNICMetrics = []promBreakdownMetric[model.NICState]{
+	{"nezha_server_nic_receive_bytes_total", "counter", "Bytes received by an interface.", func(n *model.NICState) float64 { return float64(n.InTransfer) }},
+	{"nezha_server_nic_transmit_bytes_total", "counter", "Bytes sent by an interface.", func(n *model.NICState) float64 { return float64(n.OutTransfer) }},
+	{"nezha_server_nic_receive_bytes_per_second", "gauge", "Inbound speed of an interface.", func(n *model.NICState) float64 { return float64(n.InSpeed) }},
+	{"nezha_server_nic_transmit_bytes_per_second", "gauge", "Outbound speed of an interface.", func(n *model.NICState) float64 { return float64(n.OutSpeed) }},
+	{"nezha_server_nic_receive_errors_total", "counter", "Receive errors of an interface.", func(n *model.NICState) float64 { return float64(n.InErrors) }},
+	{"nezha_server_nic_transmit_errors_total", "counter", "Transmit errors of an interface.", func(n *model.NICState) float64 { return float64(n.OutErrors) }},
+	{"nezha_server_nic_receive_drops_total", "counter", "Inbound packets dropped by an interface.", func(n *model.NICState) float64 { return float64(n.InDrops) }},
+	{"nezha_server_nic_transmit_drops_total", "counter", "Outbound packets dropped by an interface.", func(n *model.NICState) float64 { return float64(n.OutDrops) }},
+}
+
+// Prometheus metrics
+// @Summary Prometheus metrics
+// @Schemes
+// @Description Latest host state of every server, service monitor status and dashboard internals in the Prometheus text format. Requires prometheus_token in the config file, sent as a Bearer token
+// @Security BearerAuth
+// @Tags common
+// @Produce plain
+// @Success 200 {string} string
+// @Router /metrics [get]
+func prometheusMetrics(c *gin.Context) {
+	token := singleton.Conf.PrometheusToken
+	if token == "" {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+
+	realIP := c.GetString(model.CtxKeyRealIPStr)
+	if subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), []byte("Bearer "+token)) != 1 {
+		model.BlockIP(singleton.DB, realIP, model.WAFBlockReasonTypeBruteForceToken, model.BlockIDToken)
+		c.Header("WWW-Authenticate", `Bearer realm="nezha"`)
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	model.UnblockIP(singleton.DB, realIP, model.BlockIDToken)
+
+	var w promWriter
+	writeServerMetrics(&w)
+	writeServiceMetrics(&w)
+	writeDashboardMetrics(&w)
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(w.String()))
+}
+
+func serverGroupNames() map[uint64][]string {
+	var groups []model.ServerGroup
+	var links []model.ServerGroupServer
+	singleton.DB.Find(&groups)
+	singleton.DB.Find(&links)
+
+	names := make(map[uint64]string, len(groups))
+	for _, g := range groups {
+		names[g.ID] = g.Name
+	}
+	serverGroups := make(map[uint64][]string)
+	for _, l := range links {
+		if name, ok := names[l.ServerGroupId]; ok {
+			serverGroups[l.ServerId] = append(serverGroups[l.ServerId], name)
+		}
+	}
+	for _, v := range serverGroups {
+		slices.Sort(v)
+	}
+	return serverGroups
+}
+
+func writeServerMetrics(w *promWriter) {
+	servers := singleton.ServerShared.GetSortedList()
+	groups := serverGroupNames()
+
+	labels := make(map[uint64][]string, len(servers))
+	for _, s := range servers {
+		labels[s.ID] = []string{
+			"server_id", strconv.FormatUint(s.ID, 10),
+			"name", s.Name,
+			"group", strings.Join(groups[s.ID], ","),
+		}
+	}
+
+	w.family("nezha_server_info", "gauge", "Host information reported by the agent.")
+	for _, s := range servers {
+		w.sample("nezha_server_info", 1, append(slices.Clone(labels[s.ID]),
+			"platform", s.Host.Platform,
+			"platform_version", s.Host.PlatformVersion,
+			"arch", s.Host.Arch,
+			"virtualization", s.Host.Virtualization,
+			"version", s.Host.Version,
+		)...)
+	}
+
+	for _, m := range promServerMetrics {
+		w.family(m.name, m.typ, m.help)
+		for _, s := range servers {
+			w.sample(m.name, m.value(s), labels[s.ID]...)
+		}
+	}
+
+	w.family("nezha_server_temperature_celsius", "gauge", "Sensor temperature.")
+	for _, s := range servers {
+		for _, t := range s.State.Temperatures {
+			w.sample("nezha_server_temperature_celsius", t.Temperature, append(slices.Clone(labels[s.ID]), "sensor", t.Name)...)
+		}
+	}
+
+	w.family("nezha_server_gpu_usage_percent", "gauge", "GPU usage.")
+	for _, s := range servers {
+		for i, g := range s.State.GPU {
+			w.sample("nezha_server_gpu_usage_percent", g, append(slices.Clone(labels[s.ID]), "gpu", strconv.Itoa(i))...)
+		}
+	}
+
+	for _, m := range promMountMetrics {
+		w.family(m.name, m.typ, m.help)
+		for _, s := range servers {
+			for i := range s.State.Mounts {
+				mount := &s.State.Mounts[i]
+				w.sample(m.name, m.value(mount), append(slices.Clone(labels[s.ID]), "mountpoint", mount.Mountpoint, "device", mount.Device, "fstype", mount.Fstype)...)
+			}
+		}
+	}
+
+	for _, m := range promDiskIOMetrics {
+		w.family(m.name, m.typ, m.help)
+		for _, s := range servers {
+			for i := range s.State.DiskIO {
+				w.sample(m.name, m.value(&s.State.DiskIO[i]), append(slices.Clone(labels[s.ID]), "device", s.State.DiskIO[i].Device)...)
+			}
+		}
+	}
+
+	for _, m := range promNICMetrics {
+		w.family(m.name, m.typ, m.help)
+		for _, s := range servers {
+			for i := range s.State.NICs {
+				w.sample(m.name, m.value(&s.State.NICs[i]), append(slices.Clone(labels[s.ID]), "interface", s.State.NICs[i].Name)...)
+			}
+		}
+	}
+}
+
+func writeServiceMetrics(w *promWriter) {
+	snapshots := singleton.ServiceSentinelShared.Snapshot()
+	servers := singleton.ServerShared.GetList()
+
+	labels := func(s *singleton.ServiceSnapshot) []string {
+		return []string{"service_id", strconv.FormatUint(s.Service.ID, 10), "name", s.Service.Name}
+	}
+
+	w.family("nezha_service_up", "gauge", "Whether the service is up over the last 15 minutes, absent without checks.")
+	for i := range snapshots {
+		if up, ok := snapshots[i].Up(); ok {
+			w.sample("nezha_service_up", promBool(up), labels(&snapshots[i])...)
+		}
+	}
+
+	w.family("nezha_service_latency_milliseconds", "gauge", "Average latency of successful checks over the last 15 minutes.")
+	for i := range snapshots {
+		w.sample("nezha_service_latency_milliseconds", float64(snapshots[i].Delay), labels(&snapshots[i])...)
+	}
+
+	w.family("nezha_service_checks", "gauge", "Checks over the last 15 minutes by result.")
+	for i := range snapshots {
+		w.sample("nezha_service_checks", float64(snapshots[i].CurrentUp), append(labels(&snapshots[i]), "result", "up")...)
+		w.sample("nezha_service_checks", float64(snapshots[i].CurrentDown), append(labels(&snapshots[i]), "result", "down")...)
+	}
+
+	reporterLabels := func(s *singleton.ServiceSnapshot, reporter uint64) []string {
+		var name string
+		if server, ok := servers[reporter]; ok {
+			name = server.Name
+		}
+		return append(labels(s), "server_id", strconv.FormatUint(reporter, 10), "server_name", name)
+	}
+	reporters := func(s *singleton.ServiceSnapshot) []uint64 {
+		return slices.Sorted(maps.Keys(s.Reporters))
+	}
+
+	w.family("nezha_service_reporter_up", "gauge", "Result of the last check from each reporting server.")
+	for i := range snapshots {
+		for _, id := range reporters(&snapshots[i]) {
+			w.sample("nezha_service_reporter_up", promBool(snapshots[i].Reporters[id].Successful), reporterLabels(&snapshots[i], id)...)
+		}
+	}
+
+	w.family("nezha_service_reporter_latency_milliseconds", "gauge", "Latency of the last check from each reporting server.")
+	for i := range snapshots {
+		for _, id := range reporters(&snapshots[i]) {
+			w.sample("nezha_service_reporter_latency_milliseconds", float64(snapshots[i].Reporters[id].Delay), reporterLabels(&snapshots[i], id)...)
+		}
+	}
+
+	w.family("nezha_service_reporter_last_check_timestamp_seconds", "gauge", "Time of the last check from each reporting server.")
+	for i := range snapshots {
+		for _, id := range reporters(&snapshots[i]) {
+			w.sample("nezha_service_reporter_last_check_timestamp_seconds", float64(snapshots[i].Reporters[id].At.Unix()), reporterLabels(&snapshots[i], id)...)
+		}
+	}
+}
+
+func writeDashboardMetrics(w *promWriter) {
+	servers := singleton.ServerShared.GetSortedList()
+	var connected int
+	for _, s := range servers {
+		if s.TaskStream != nil {
+			connected++
+		}
+	}
+
+	w.family("nezha_dashboard_info", "gauge", "Dashboard version.")
+	w.sample("nezha_dashboard_info", 1, "version", singleton.Version)
+	w.family("nezha_dashboard_boot_time_seconds", "gauge", "Dashboard start time.")
+	w.sample("nezha_dashboard_boot_time_seconds", float64(singleton.DashboardBootTime))
+	w.family("nezha_dashboard_servers", "gauge", "Configured servers.")
+	w.sample("nezha_dashboard_servers", float64(len(servers)))
+	w.family("nezha_dashboard_connected_agents", "gauge", "Agents with a connected task stream.")
+	w.sample("nezha_dashboard_connected_agents", float64(connected))
+	w.family("nezha_dashboard_io_streams", "gauge", "Open IO streams used by terminals, file managers, NAT and port forwards.")
+	w.sample("nezha_dashboard_io_streams", float64(rpc.NezhaHandlerSingleton.StreamCount()))
+	w.family("nezha_dashboard_online_users", "gauge", "Users connected to the dashboard websocket.")
+	w.sample("nezha_dashboard_online_users", float64(singleton.GetOnlineUserCount()))
+}
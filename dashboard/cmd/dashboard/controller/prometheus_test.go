@@ -0,0 +1,18 @@
+package controller
+
+import "testing"
+
+func TestPromWriter(t *testing.T) {
+	var w promWriter
+	w.family("nezha_test", "gauge", "Test metric.")
+	w.sample("nezha_test", 1.5, "name", "a\"b\\c\nd", "group", "")
+	w.sample("nezha_test", 2)
+
+	want := "# HELP nezha_test Test metric.\n" +
+		"# TYPE nezha_test gauge\n" +
+		`nezha_test{name="a\"b\\c\nd",group=""} 1.5` + "\n" +
+		"nezha_test 2\n"
+	if w.String() != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", w.String(), want)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/url"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -111,17 +112,36 @@ func updateConfig(c *gin.Context) (any, error) {
 		return nil, errors.New("invalid user template")
 	}
 
+	if sf.AutoSSHHostKey != "" && !model.IsValidHostKeyFingerprint(sf.AutoSSHHostKey) {
+		return nil, singleton.Localizer.ErrorT("invalid host key fingerprint")
+	}
+	// 更换 AutoSSH 服务器后重新记录主机密钥
+	autosshHostKey := sf.AutoSSHHostKey
+	if sf.AutoSSHHost != singleton.Conf.AutoSSHHost && autosshHostKey == singleton.Conf.AutoSSHHostKey {
+		autosshHostKey = ""
+	}
+
+	if sf.SiteURL != "" {
+		u, err := url.Parse(sf.SiteURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, singleton.Localizer.ErrorT("invalid site url")
+		}
+	}
+
 	singleton.Conf.Language = strings.ReplaceAll(sf.Language, "-", "_")
 
 	singleton.Conf.EnableIPChangeNotification = sf.EnableIPChangeNotification
 	singleton.Conf.EnablePlainIPInNotification = sf.EnablePlainIPInNotification
 	singleton.Conf.Cover = sf.Cover
 	singleton.Conf.InstallHost = sf.InstallHost
+	singleton.Conf.SiteURL = strings.TrimSuffix(sf.SiteURL, "/")
 	singleton.Conf.IgnoredIPNotification = sf.IgnoredIPNotification
 	singleton.Conf.IPChangeNotificationGroupID = sf.IPChangeNotificationGroupID
 	singleton.Conf.SiteName = sf.SiteName
 	singleton.Conf.DNSServers = sf.DNSServers
 	singleton.Conf.AutoSSHHost = sf.AutoSSHHost
+	singleton.Conf.AutoSSHHostKey = autosshHostKey
+	singleton.Conf.AutoSSHNotificationGroupID = sf.AutoSSHNotificationGroupID
 	singleton.Conf.CustomCode = sf.CustomCode
 	singleton.Conf.CustomCodeDashboard = sf.CustomCodeDashboard
 	singleton.Conf.WebRealIPHeader = sf.WebRealIPHeader
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"log"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/service/singleton"
+)
+
+// List systemd units of server
+// @Summary List systemd units of server
+// @Security BearerAuth
+// @Schemes
+// @Description List systemd units last reported by the agent
+// @Tags auth required
+// @param id path uint true "Server ID"
+// @param state query string false "Active state, e.g. failed"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[model.SystemdState]
+// @Router /server/{id}/systemd [get]
+func listServerSystemdUnit(c *gin.Context) (*model.SystemdState, error) {
+	server, err := getAuthorizedServer(c)
+	if err != nil {
+		return nil, err
+	}
+	if server.Systemd == nil {
+		return &model.SystemdState{Units: []*model.SystemdUnit{}}, nil
+	}
+	state := c.Query("state")
+	if state == "" {
+		return server.Systemd, nil
+	}
+	filtered := *server.Systemd
+	filtered.Units = make([]*model.SystemdUnit, 0)
+	for _, u := range server.Systemd.Units {
+		if u.ActiveState == state {
+			filtered.Units = append(filtered.Units, u)
+		}
+	}
+	return &filtered, nil
+}
+
+// Start, stop, restart, enable or disable systemd unit
+// @Summary Start, stop, restart, enable or disable systemd unit
+// @Security BearerAuth
+// @Schemes
+// @Description Control a systemd unit through the agent. Refused when command execution is disabled on the agent, every attempt is recorded in the terminal command audit
+// @Tags auth required
+// @Accept json
+// @param id path uint true "Server ID"
+// @param unit path string true "Unit name"
+// @param request body model.SystemdActionForm true "Action"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[any]
+// @Router /server/{id}/systemd/{unit}/action [post]
+func serverSystemdAction(c *gin.Context) (any, error) {
+	var form model.SystemdActionForm
+	if err := c.ShouldBindJSON(&form); err != nil {
+		return nil, err
+	}
+	if !model.ValidSystemdAction(form.Action) {
+		return nil, singleton.Localizer.ErrorT("invalid action")
+	}
+
+	server, err := getAuthorizedServer(c)
+	if err != nil {
+		return nil, err
+	}
+	// 只允许操作 agent 上报过的单元
+	unit := c.Param("unit")
+	if server.Systemd == nil || server.Systemd.Unit(unit) == nil {
+		return nil, singleton.Localizer.ErrorT("unit not found")
+	}
+
+	user := c.MustGet(model.CtxKeyAuthorizedUser).(*model.User)
+	log.Printf("NEZHA>> User %s %s systemd unit %s on server %d", user.Username, form.Action, unit, server.ID)
+
+	err = singleton.SystemdShared.Action(server, unit, form.Action)
+	recordAuditedAction(user, server.ID, "systemctl "+form.Action+" "+unit, err)
+	if err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"encoding/json"
+	"io"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -68,52 +69,89 @@ func createTerminal(c *gin.Context) (*model.CreateTerminalResponse, error) {
 		return nil, singleton.Localizer.ErrorT("permission denied")
 	}
 
-	streamId, err := uuid.GenerateUUID()
+	// Get user info
+	auth, _ := c.Get(model.CtxKeyAuthorizedUser)
+	user := auth.(*model.User)
+
+	// 容器终端使用容器内的默认用户。容器内没有命令审计与黑名单，只允许管理员打开
+	var container *model.DockerContainer
+	var osUser string
+	var err error
+	if createTerminalReq.ContainerID != "" {
+		if !user.Role.IsAdmin() {
+			return nil, singleton.Localizer.ErrorT("permission denied")
+		}
+		if container, err = lookupContainer(server, createTerminalReq.ContainerID); err != nil {
+			return nil, err
+		}
+	} else if osUser, err = resolveTerminalOSUser(user, createTerminalReq.ServerID, createTerminalReq.OSUser); err != nil {
+		return nil, err
+	}
+
+	session, err := openTerminalSession(user, server, osUser, "", container)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get user info
-	uid := getUid(c)
-	auth, _ := c.Get(model.CtxKeyAuthorizedUser)
-	user := auth.(*model.User)
+	return &model.CreateTerminalResponse{
+		SessionID:   session.StreamID,
+		ServerID:    server.ID,
+		ServerName:  server.Name,
+		OSUser:      osUser,
+		ContainerID: session.ContainerID,
+	}, nil
+}
+
+// openTerminalSession 创建终端会话记录并通知 agent 建立终端流，container 不为空时在容器内打开
+func openTerminalSession(user *model.User, server *model.Server, osUser, broadcastId string, container *model.DockerContainer) (*model.TerminalSession, error) {
+	streamId, err := uuid.GenerateUUID()
+	if err != nil {
+		return nil, err
+	}
 
 	// Create terminal session record
 	session := &model.TerminalSession{
-		UserID:           uid,
+		UserID:           user.ID,
 		Username:         user.Username,
-		ServerID:         createTerminalReq.ServerID,
+		ServerID:         server.ID,
 		ServerName:       server.Name,
 		StreamID:         streamId,
 		StartedAt:        time.Now(),
-		RecordingEnabled: shouldEnableRecording(createTerminalReq.ServerID),
+		RecordingEnabled: shouldEnableRecording(server.ID),
+		OSUser:           osUser,
+		BroadcastID:      broadcastId,
+	}
+	if container != nil {
+		session.ContainerID = container.ID
+		session.ContainerName = container.Name
 	}
 	if err := singleton.DB.Create(session).Error; err != nil {
 		return nil, err
 	}
 
 	rpc.NezhaHandlerSingleton.CreateStream(streamId)
+	rpc.NezhaHandlerSingleton.SetStreamFilter(streamId, newTerminalTransferFilter(session))
 
 	terminalData, _ := json.Marshal(&model.TerminalTask{
-		StreamID: streamId,
+		StreamID:    streamId,
+		OSUser:      osUser,
+		ContainerID: session.ContainerID,
 	})
 	if err := server.TaskStream.Send(&proto.Task{
 		Type: model.TaskTypeTerminalGRPC,
 		Data: string(terminalData),
 	}); err != nil {
+		rpc.NezhaHandlerSingleton.CloseStream(streamId)
+		closeTerminalSession(streamId)
 		return nil, err
 	}
 
-	return &model.CreateTerminalResponse{
-		SessionID:  streamId,
-		ServerID:   server.ID,
-		ServerName: server.Name,
-	}, nil
+	return session, nil
 }
 
 // TerminalStream web ssh terminal stream
 // @Summary Terminal stream
-// @Description Terminal stream
+// @Description Terminal stream, reconnecting with the same id reattaches to a detached session
 // @Tags auth required
 // @Param id path string true "Stream UUID"
 // @Success 200 {object} model.CommonResponse[any]
@@ -123,11 +161,23 @@ func terminalStream(c *gin.Context) (any, error) {
 	if _, err := rpc.NezhaHandlerSingleton.GetStream(streamId); err != nil {
 		return nil, err
 	}
-	defer rpc.NezhaHandlerSingleton.CloseStream(streamId)
-	defer closeTerminalSession(streamId)
+
+	var session model.TerminalSession
+	if err := singleton.DB.Where("stream_id = ?", streamId).First(&session).Error; err != nil {
+		return nil, newGormError("%v", err)
+	}
+
+	auth, _ := c.Get(model.CtxKeyAuthorizedUser)
+	user := auth.(*model.User)
+	if session.UserID != user.ID && !user.Role.IsAdmin() {
+		return nil, singleton.Localizer.ErrorT("permission denied")
+	}
 
 	wsConn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
+		rpc.NezhaHandlerSingleton.ScheduleClose(streamId, terminalReattachGracePeriod(), func() {
+			closeTerminalSession(streamId)
+		})
 		return nil, newWsError("%v", err)
 	}
 	defer wsConn.Close()
@@ -136,22 +186,79 @@ func terminalStream(c *gin.Context) (any, error) {
 	go func() {
 		// PING 保活
 		for {
-			if err = conn.WriteMessage(websocket.PingMessage, []byte{}); err != nil {
+			if err := conn.WriteMessage(websocket.PingMessage, []byte{}); err != nil {
 				return
 			}
 			time.Sleep(time.Second * 10)
 		}
 	}()
 
-	if err = rpc.NezhaHandlerSingleton.UserConnected(streamId, conn); err != nil {
+	if err = attachTerminalSession(&session, conn); err != nil {
 		return nil, newWsError("%v", err)
 	}
+	return nil, newWsError("")
+}
 
-	if err = rpc.NezhaHandlerSingleton.StartStream(streamId, time.Second*10); err != nil {
-		return nil, newWsError("%v", err)
+// attachTerminalSession 将用户连接挂载到终端会话上，连接断开后按配置保留会话等待重连
+func attachTerminalSession(session *model.TerminalSession, userIo io.ReadWriteCloser) error {
+	streamId := session.StreamID
+
+	if session.DetachedAt != nil {
+		singleton.DB.Model(session).Updates(map[string]any{
+			"detached_at":    nil,
+			"reattach_count": session.ReattachCount + 1,
+		})
 	}
 
-	return nil, newWsError("")
+	// 重连时通知 agent 重放终端回滚缓冲区
+	agentClosed, err := rpc.NezhaHandlerSingleton.AttachUser(streamId, userIo, time.Second*10, []byte{2})
+
+	grace := terminalReattachGracePeriod()
+	if agentClosed || grace <= 0 {
+		rpc.NezhaHandlerSingleton.CloseStream(streamId)
+		closeTerminalSession(streamId)
+	} else if rpc.NezhaHandlerSingleton.IsDetached(streamId) {
+		now := time.Now()
+		singleton.DB.Model(session).Update("detached_at", &now)
+		rpc.NezhaHandlerSingleton.ScheduleClose(streamId, grace, func() {
+			closeTerminalSession(streamId)
+		})
+	}
+
+	return err
+}
+
+// List detached terminal sessions
+// @Summary List detached terminal sessions
+// @Description List terminal sessions of current user that are waiting to be reattached
+// @Security BearerAuth
+// @Tags auth required
+// @Produce json
+// @Success 200 {object} model.CommonResponse[[]model.TerminalSession]
+// @Router /terminal/detached [get]
+func listDetachedTerminalSessions(c *gin.Context) ([]model.TerminalSession, error) {
+	var sessions []model.TerminalSession
+	if err := singleton.DB.Where("user_id = ? AND detached_at IS NOT NULL AND ended_at IS NULL", getUid(c)).
+		Order("detached_at DESC").Find(&sessions).Error; err != nil {
+		return nil, newGormError("%v", err)
+	}
+
+	detached := sessions[:0]
+	for _, s := range sessions {
+		if rpc.NezhaHandlerSingleton.IsDetached(s.StreamID) {
+			detached = append(detached, s)
+		}
+	}
+	return detached, nil
+}
+
+// terminalReattachGracePeriod 返回浏览器断开后终端保留的时长
+func terminalReattachGracePeriod() time.Duration {
+	grace := singleton.Conf.TerminalReattachGracePeriod
+	if grace == 0 {
+		grace = 300
+	}
+	return time.Duration(grace) * time.Second
 }
 
 // closeTerminalSession closes a terminal session and updates the database
@@ -166,6 +273,7 @@ func closeTerminalSession(streamId string) {
 
 	session.EndedAt = &now
 	session.Duration = duration
+	session.DetachedAt = nil
 
 	singleton.DB.Save(&session)
 }
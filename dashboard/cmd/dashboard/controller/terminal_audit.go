@@ -2,6 +2,7 @@ package controller
 
 import (
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -166,6 +167,45 @@ func recordCommand(c *gin.Context) (any, error) {
 	return nil, nil
 }
 
+// Record terminal file transfer
+// @Summary Record terminal file transfer
+// @Description Record a trzsz/ZMODEM file transfer detected in terminal
+// @Tags auth required
+// @Accept json
+// @Param request body model.TransferRecordRequest true "Transfer Record Request"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[any]
+// @Router /terminal/record-transfer [post]
+func recordTransfer(c *gin.Context) (any, error) {
+	var req model.TransferRecordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+
+	// Get session info
+	var session model.TerminalSession
+	if err := singleton.DB.Where("stream_id = ?", req.StreamID).First(&session).Error; err != nil {
+		return nil, err
+	}
+
+	transfer := &model.TerminalTransfer{
+		SessionID: session.ID,
+		UserID:    session.UserID,
+		ServerID:  session.ServerID,
+		Protocol:  req.Protocol,
+		Direction: req.Direction,
+		Bytes:     req.Bytes,
+		StartedAt: req.StartedAt,
+		EndedAt:   req.EndedAt,
+		Aborted:   req.Aborted,
+	}
+	if err := singleton.DB.Create(transfer).Error; err != nil {
+		return nil, newGormError("%v", err)
+	}
+
+	return nil, nil
+}
+
 // List terminal sessions
 // @Summary List terminal sessions
 // @Description List terminal sessions with pagination
@@ -215,7 +255,8 @@ func listTerminalSessions(c *gin.Context) (any, error) {
 // @Description List terminal commands with pagination
 // @Security BearerAuth
 // @Tags auth required
-// @Param session_id query uint64 false "Filter by session ID"
+// @Param session_id query uint64 false "Filter by session ID, 0 for process signals sent outside of terminals"
+// @Param server_id query uint64 false "Filter by server ID"
 // @Param page query int false "Page number"
 // @Param page_size query int false "Page size"
 // @Produce json
@@ -225,12 +266,16 @@ func listTerminalCommands(c *gin.Context) (any, error) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "50"))
 	sessionID := c.Query("session_id")
+	serverID := c.Query("server_id")
 
 	query := singleton.DB.Model(&model.TerminalCommand{})
 
 	if sessionID != "" {
 		query = query.Where("session_id = ?", sessionID)
 	}
+	if serverID != "" {
+		query = query.Where("server_id = ?", serverID)
+	}
 
 	var total int64
 	query.Count(&total)
@@ -249,6 +294,45 @@ func listTerminalCommands(c *gin.Context) (any, error) {
 	}, nil
 }
 
+// List terminal file transfers
+// @Summary List terminal file transfers
+// @Description List trzsz/ZMODEM file transfers with pagination
+// @Security BearerAuth
+// @Tags admin required
+// @Param session_id query uint64 false "Filter by session ID"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[[]model.TerminalTransfer]
+// @Router /terminal/transfers [get]
+func listTerminalTransfers(c *gin.Context) (any, error) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "50"))
+	sessionID := c.Query("session_id")
+
+	query := singleton.DB.Model(&model.TerminalTransfer{})
+
+	if sessionID != "" {
+		query = query.Where("session_id = ?", sessionID)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var transfers []model.TerminalTransfer
+	offset := (page - 1) * pageSize
+	if err := query.Order("started_at DESC").Offset(offset).Limit(pageSize).Find(&transfers).Error; err != nil {
+		return nil, err
+	}
+
+	return gin.H{
+		"transfers": transfers,
+		"total":     total,
+		"page":      page,
+		"pageSize":  pageSize,
+	}, nil
+}
+
 // List terminal blacklist rules
 // @Summary List terminal blacklist rules
 // @Description List terminal command blacklist rules
@@ -476,3 +560,19 @@ func getRecordingURL(c *gin.Context) (string, error) {
 	url := fmt.Sprintf("/api/v1/terminal/recording/%d", sessionID)
 	return url, nil
 }
+
+// recordAuditedAction 终端之外对服务器的操作与终端命令一起审计，不属于任何终端会话，失败的操作同样记录
+func recordAuditedAction(user *model.User, serverID uint64, command string, err error) {
+	record := &model.TerminalCommand{
+		UserID:     user.ID,
+		ServerID:   serverID,
+		Command:    command,
+		ExecutedAt: time.Now(),
+	}
+	if err != nil {
+		record.ExitCode = 1
+	}
+	if dbErr := singleton.DB.Create(record).Error; dbErr != nil {
+		log.Printf("NEZHA>> Failed to record audited action %q: %v", command, dbErr)
+	}
+}
@@ -0,0 +1,270 @@
+package controller
+
+import (
+	"encoding/binary"
+	"io"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/hashicorp/go-uuid"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/pkg/websocketx"
+	"github.com/nezhahq/nezha/service/rpc"
+	"github.com/nezhahq/nezha/service/singleton"
+)
+
+// 广播终端中发往单台服务器的帧：0xfe + 8 字节服务器 ID + 原始终端帧
+const terminalBroadcastTargetFrame = 0xfe
+
+// 广播终端中每台服务器排队等待发送的输入帧数
+const terminalBroadcastQueueSize = 256
+
+// Create broadcast terminal
+// @Summary Create broadcast terminal
+// @Description Open one terminal session per server, input sent to the broadcast stream fans out to all of them
+// @Security BearerAuth
+// @Tags auth required
+// @Accept json
+// @Param request body model.TerminalBroadcastForm true "TerminalBroadcastForm"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[model.CreateTerminalBroadcastResponse]
+// @Router /terminal/broadcast [post]
+func createTerminalBroadcast(c *gin.Context) (*model.CreateTerminalBroadcastResponse, error) {
+	var bf model.TerminalBroadcastForm
+	if err := c.ShouldBindJSON(&bf); err != nil {
+		return nil, err
+	}
+
+	serverIDs := slices.Clone(bf.ServerIDs)
+	if bf.ServerGroupID != 0 {
+		var groupServers []model.ServerGroupServer
+		if err := singleton.DB.Where("server_group_id = ?", bf.ServerGroupID).Find(&groupServers).Error; err != nil {
+			return nil, newGormError("%v", err)
+		}
+		for _, gs := range groupServers {
+			serverIDs = append(serverIDs, gs.ServerId)
+		}
+	}
+	slices.Sort(serverIDs)
+	serverIDs = slices.Compact(serverIDs)
+	if len(serverIDs) == 0 {
+		return nil, singleton.Localizer.ErrorT("no server selected")
+	}
+
+	auth, _ := c.Get(model.CtxKeyAuthorizedUser)
+	user := auth.(*model.User)
+
+	broadcastId, err := uuid.GenerateUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &model.CreateTerminalBroadcastResponse{BroadcastID: broadcastId}
+	for _, sid := range serverIDs {
+		server, _ := singleton.ServerShared.Get(sid)
+		if server == nil || server.TaskStream == nil || !checkServerPermission(c, sid) {
+			resp.Failure = append(resp.Failure, sid)
+			continue
+		}
+
+		osUser, err := resolveTerminalOSUser(user, sid, bf.OSUser)
+		if err != nil {
+			resp.Failure = append(resp.Failure, sid)
+			continue
+		}
+
+		session, err := openTerminalSession(user, server, osUser, broadcastId, nil)
+		if err != nil {
+			resp.Failure = append(resp.Failure, sid)
+			continue
+		}
+
+		resp.Sessions = append(resp.Sessions, &model.CreateTerminalResponse{
+			SessionID:  session.StreamID,
+			ServerID:   server.ID,
+			ServerName: server.Name,
+			OSUser:     osUser,
+		})
+	}
+
+	if len(resp.Sessions) == 0 {
+		return nil, singleton.Localizer.ErrorT("server not found or not connected")
+	}
+	return resp, nil
+}
+
+// Broadcast terminal stream
+// @Summary Broadcast terminal stream
+// @Description Frames from the browser are sent to every server, a frame starting with 0xfe followed by an 8 byte big-endian server ID only goes to that server. Output frames are prefixed with the 8 byte big-endian server ID.
+// @Tags auth required
+// @Param id path string true "Broadcast UUID"
+// @Success 200 {object} model.CommonResponse[any]
+// @Router /ws/terminal/broadcast/{id} [get]
+func terminalBroadcastStream(c *gin.Context) (any, error) {
+	broadcastId := c.Param("id")
+
+	var sessions []model.TerminalSession
+	if err := singleton.DB.Where("broadcast_id = ? AND ended_at IS NULL", broadcastId).Find(&sessions).Error; err != nil {
+		return nil, newGormError("%v", err)
+	}
+
+	auth, _ := c.Get(model.CtxKeyAuthorizedUser)
+	user := auth.(*model.User)
+
+	sessions = slices.DeleteFunc(sessions, func(s model.TerminalSession) bool {
+		_, err := rpc.NezhaHandlerSingleton.GetStream(s.StreamID)
+		return err != nil
+	})
+	if len(sessions) == 0 {
+		return nil, singleton.Localizer.ErrorT("stream not found")
+	}
+	for _, s := range sessions {
+		if s.UserID != user.ID && !user.Role.IsAdmin() {
+			return nil, singleton.Localizer.ErrorT("permission denied")
+		}
+	}
+
+	wsConn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return nil, newWsError("%v", err)
+	}
+	defer wsConn.Close()
+	conn := websocketx.NewConn(wsConn)
+
+	go func() {
+		// PING 保活
+		for {
+			if err := conn.WriteMessage(websocket.PingMessage, []byte{}); err != nil {
+				return
+			}
+			time.Sleep(time.Second * 10)
+		}
+	}()
+
+	members := make(map[uint64]*terminalBroadcastMember, len(sessions))
+	var wg sync.WaitGroup
+	for i := range sessions {
+		m := newTerminalBroadcastMember(sessions[i].ServerID, conn)
+		members[m.serverId] = m
+		wg.Add(1)
+		go func(session *model.TerminalSession) {
+			defer wg.Done()
+			attachTerminalSession(session, m)
+			m.Close()
+		}(&sessions[i])
+	}
+
+	// 所有服务器的终端都结束后断开浏览器连接
+	go func() {
+		wg.Wait()
+		wsConn.Close()
+	}()
+
+	for {
+		mType, data, err := wsConn.ReadMessage()
+		if err != nil {
+			break
+		}
+		if mType == websocket.TextMessage {
+			data = append([]byte{0}, data...)
+		}
+		if len(data) == 0 {
+			continue
+		}
+
+		if data[0] == terminalBroadcastTargetFrame {
+			if len(data) < 9 {
+				continue
+			}
+			if m, ok := members[binary.BigEndian.Uint64(data[1:9])]; ok {
+				m.input(data[9:])
+			}
+			continue
+		}
+
+		for _, m := range members {
+			m.input(data)
+		}
+	}
+
+	for _, m := range members {
+		m.Close()
+	}
+	wg.Wait()
+
+	return nil, newWsError("")
+}
+
+// terminalBroadcastMember 广播终端中单台服务器的用户侧连接
+type terminalBroadcastMember struct {
+	serverId uint64
+	conn     io.Writer
+	prefix   []byte
+
+	inputCh   chan []byte
+	pending   []byte
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newTerminalBroadcastMember(serverId uint64, conn io.Writer) *terminalBroadcastMember {
+	return &terminalBroadcastMember{
+		serverId: serverId,
+		conn:     conn,
+		prefix:   binary.BigEndian.AppendUint64(nil, serverId),
+		inputCh:  make(chan []byte, terminalBroadcastQueueSize),
+		closed:   make(chan struct{}),
+	}
+}
+
+// input 将浏览器的输入交给该服务器，不会阻塞其他服务器。终端已结束时直接丢弃，
+// 队列已满说明该服务器的终端卡住，将其从广播中断开，会话保留等待单独重连
+func (m *terminalBroadcastMember) input(data []byte) {
+	select {
+	case <-m.closed:
+		return
+	default:
+	}
+
+	select {
+	case m.inputCh <- data:
+	default:
+		log.Printf("NEZHA>> Broadcast terminal of server %d stalled, detaching it", m.serverId)
+		m.Close()
+	}
+}
+
+func (m *terminalBroadcastMember) Read(p []byte) (int, error) {
+	if len(m.pending) == 0 {
+		select {
+		case m.pending = <-m.inputCh:
+		case <-m.closed:
+			return 0, io.EOF
+		}
+	}
+	n := copy(p, m.pending)
+	m.pending = m.pending[n:]
+	return n, nil
+}
+
+func (m *terminalBroadcastMember) Write(p []byte) (int, error) {
+	frame := make([]byte, 0, len(m.prefix)+len(p))
+	frame = append(frame, m.prefix...)
+	frame = append(frame, p...)
+	if _, err := m.conn.Write(frame); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (m *terminalBroadcastMember) Close() error {
+	m.closeOnce.Do(func() {
+		close(m.closed)
+	})
+	return nil
+}
@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"io"
+	"testing"
+)
+
+func TestTerminalBroadcastMemberStall(t *testing.T) {
+	m := newTerminalBroadcastMember(1, io.Discard)
+
+	m.input([]byte{0, 'l', 's'})
+	b := make([]byte, 2)
+	if n, err := m.Read(b); err != nil || string(b[:n]) != "\x00l" {
+		t.Fatalf("unexpected read %q, %v", b[:n], err)
+	}
+	if n, err := m.Read(b); err != nil || string(b[:n]) != "s" {
+		t.Fatalf("unexpected read %q, %v", b[:n], err)
+	}
+
+	// 没有读取的成员不能阻塞输入，队列满后被断开
+	for i := 0; i <= terminalBroadcastQueueSize; i++ {
+		m.input([]byte{0, 'x'})
+	}
+	select {
+	case <-m.closed:
+	default:
+		t.Fatal("stalled member was not detached")
+	}
+	m.input([]byte{0, 'y'})
+}
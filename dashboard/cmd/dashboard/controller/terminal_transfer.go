@@ -0,0 +1,243 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/pkg/zmodem"
+	"github.com/nezhahq/nezha/service/singleton"
+)
+
+// 面板接收的单个文件的大小上限
+const terminalTransferMaxSize = 1 << 30
+
+var terminalTransferDir = filepath.Join("data", "transfers")
+
+var errTerminalTransferTooLarge = errors.New("file too large")
+
+// terminalTransferFilter 在终端输出中识别 sz 发起的 ZMODEM 下载，由面板接收文件后
+// 在终端中输出下载链接。传输期间丢弃用户的键盘输入，Ctrl+C 取消传输
+type terminalTransferFilter struct {
+	session *model.TerminalSession
+
+	mu       sync.Mutex
+	tail     []byte // 上一段输出末尾可能是起始标记的一部分
+	receiver *zmodem.Receiver
+	notice   []byte
+
+	transfer *model.TerminalTransfer
+	file     *os.File
+	path     string
+}
+
+func newTerminalTransferFilter(session *model.TerminalSession) *terminalTransferFilter {
+	return &terminalTransferFilter{session: session}
+}
+
+func (f *terminalTransferFilter) FilterOutput(p []byte, toAgent func([]byte)) []byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// 回复给 sz 的数据作为终端输入发送
+	reply := func(b []byte) {
+		toAgent(append([]byte{0}, b...))
+	}
+
+	data := append(f.tail, p...)
+	f.tail = nil
+	var out []byte
+	for len(data) > 0 {
+		if f.receiver != nil {
+			n := f.receiver.Feed(data)
+			data = data[n:]
+			if !f.receiver.Done() {
+				break
+			}
+			f.receiver = nil
+			continue
+		}
+
+		start := bytes.Index(data, zmodem.StartMarker)
+		upload := bytes.Index(data, zmodem.UploadMarker)
+		if upload >= 0 && (start < 0 || upload < start) {
+			// 浏览器无法作为 ZMODEM 发送方，直接取消 rz
+			out = append(out, trimZPAD(data[:upload])...)
+			data = data[upload+len(zmodem.UploadMarker):]
+			reply(zmodem.CancelSequence)
+			f.notice = append(f.notice, "\r\nrz is not supported in the web terminal, upload files with the file manager instead\r\n"...)
+			continue
+		}
+		if start >= 0 {
+			out = append(out, trimZPAD(data[:start])...)
+			data = data[start:]
+			f.receiver = zmodem.NewReceiver(reply, f)
+			continue
+		}
+
+		keep := partialMarker(data)
+		out = append(out, data[:len(data)-keep]...)
+		f.tail = append([]byte(nil), data[len(data)-keep:]...)
+		break
+	}
+
+	out = append(out, f.notice...)
+	f.notice = nil
+	return out
+}
+
+func (f *terminalTransferFilter) FilterInput(p []byte, toAgent func([]byte)) []byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.receiver == nil || len(p) == 0 || p[0] != 0 {
+		return p
+	}
+	if bytes.IndexByte(p[1:], 0x03) >= 0 {
+		f.receiver.Abort()
+		f.receiver = nil
+	}
+	return nil
+}
+
+// Open 实现 zmodem.Handler，文件以传输记录的 ID 命名，不使用发送方提供的文件名
+func (f *terminalTransferFilter) Open(info zmodem.FileInfo) (io.Writer, error) {
+	if info.Size > terminalTransferMaxSize {
+		f.notice = fmt.Appendf(f.notice, "\r\n%s skipped: larger than %d bytes\r\n", info.Name, terminalTransferMaxSize)
+		return nil, errTerminalTransferTooLarge
+	}
+	if err := os.MkdirAll(terminalTransferDir, 0750); err != nil {
+		return nil, err
+	}
+
+	transfer := &model.TerminalTransfer{
+		SessionID: f.session.ID,
+		UserID:    f.session.UserID,
+		ServerID:  f.session.ServerID,
+		Protocol:  "zmodem",
+		Direction: "download",
+		FileName:  info.Name,
+		StartedAt: time.Now(),
+	}
+	if err := singleton.DB.Create(transfer).Error; err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(terminalTransferDir, strconv.FormatUint(transfer.ID, 10))
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		singleton.DB.Model(transfer).Updates(map[string]any{"ended_at": time.Now(), "aborted": true})
+		return nil, err
+	}
+
+	f.transfer, f.file, f.path = transfer, file, path
+	return &terminalTransferWriter{file: file}, nil
+}
+
+// Close 实现 zmodem.Handler，未完整接收的文件直接删除
+func (f *terminalTransferFilter) Close(info zmodem.FileInfo, received int64, complete bool) {
+	if f.file == nil {
+		return
+	}
+	f.file.Close()
+
+	updates := map[string]any{
+		"bytes":    received,
+		"ended_at": time.Now(),
+		"aborted":  !complete,
+	}
+	if complete {
+		updates["file_path"] = f.path
+		f.notice = fmt.Appendf(f.notice, "\r\n%s (%d bytes): /api/v1/terminal/transfers/%d/file\r\n", info.Name, received, f.transfer.ID)
+	} else {
+		os.Remove(f.path)
+		f.notice = fmt.Appendf(f.notice, "\r\n%s: transfer aborted\r\n", info.Name)
+	}
+	singleton.DB.Model(f.transfer).Updates(updates)
+
+	f.transfer, f.file, f.path = nil, nil, ""
+}
+
+type terminalTransferWriter struct {
+	file    *os.File
+	written int64
+}
+
+func (w *terminalTransferWriter) Write(p []byte) (int, error) {
+	if w.written+int64(len(p)) > terminalTransferMaxSize {
+		return 0, errTerminalTransferTooLarge
+	}
+	n, err := w.file.Write(p)
+	w.written += int64(n)
+	return n, err
+}
+
+// trimZPAD 去掉起始标记前的 ZPAD
+func trimZPAD(p []byte) []byte {
+	for i := 0; i < 2 && len(p) > 0 && p[len(p)-1] == '*'; i++ {
+		p = p[:len(p)-1]
+	}
+	return p
+}
+
+// partialMarker 返回 p 末尾与起始标记开头相同的字节数
+func partialMarker(p []byte) int {
+	for k := min(len(zmodem.StartMarker)-1, len(p)); k > 0; k-- {
+		if bytes.HasPrefix(zmodem.StartMarker, p[len(p)-k:]) {
+			return k
+		}
+	}
+	return 0
+}
+
+// Download terminal file transfer
+// @Summary Download terminal file transfer
+// @Description Download a file sent with sz in a web terminal, only the owner of the terminal session or an admin can download it
+// @Security BearerAuth
+// @Tags auth required
+// @Param id path uint true "Transfer ID"
+// @Produce application/octet-stream
+// @Success 200 {file} binary
+// @Router /terminal/transfers/{id}/file [get]
+func downloadTerminalTransfer(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid transfer id"})
+		return
+	}
+
+	var transfer model.TerminalTransfer
+	if err := singleton.DB.First(&transfer, id).Error; err != nil {
+		c.JSON(404, gin.H{"error": "transfer not found"})
+		return
+	}
+
+	user := c.MustGet(model.CtxKeyAuthorizedUser).(*model.User)
+	if transfer.UserID != user.ID && !user.Role.IsAdmin() {
+		c.JSON(403, gin.H{"error": "permission denied"})
+		return
+	}
+
+	if transfer.FilePath == "" {
+		c.JSON(404, gin.H{"error": "file not found"})
+		return
+	}
+	if _, err := os.Stat(transfer.FilePath); err != nil {
+		c.JSON(404, gin.H{"error": "file not found"})
+		return
+	}
+
+	c.Header("Content-Type", "application/octet-stream")
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": transfer.FileName}))
+	c.File(transfer.FilePath)
+}
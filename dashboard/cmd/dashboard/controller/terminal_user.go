@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/service/singleton"
+)
+
+// List terminal user mappings
+// @Summary List terminal user mappings
+// @Description List the OS accounts used when dashboard users open terminals
+// @Security BearerAuth
+// @Tags admin required
+// @Produce json
+// @Success 200 {object} model.CommonResponse[[]model.TerminalUserMapping]
+// @Router /terminal/user-mapping [get]
+func listTerminalUserMapping(c *gin.Context) ([]*model.TerminalUserMapping, error) {
+	var mappings []*model.TerminalUserMapping
+	if err := singleton.DB.Order("user_id, server_id").Find(&mappings).Error; err != nil {
+		return nil, newGormError("%v", err)
+	}
+	return mappings, nil
+}
+
+// Create or update terminal user mapping
+// @Summary Create or update terminal user mapping
+// @Description Map a dashboard user to an OS account on a server, server_id 0 applies to all servers
+// @Security BearerAuth
+// @Tags admin required
+// @Accept json
+// @Param request body model.TerminalUserMappingForm true "Terminal User Mapping"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[uint64]
+// @Router /terminal/user-mapping [post]
+func createTerminalUserMapping(c *gin.Context) (uint64, error) {
+	var mf model.TerminalUserMappingForm
+	if err := c.ShouldBindJSON(&mf); err != nil {
+		return 0, err
+	}
+
+	if !model.IsValidOSUser(mf.OSUser) {
+		return 0, singleton.Localizer.ErrorT("invalid os user")
+	}
+	if err := singleton.DB.First(&model.User{}, mf.UserID).Error; err != nil {
+		return 0, singleton.Localizer.ErrorT("user id %d does not exist", mf.UserID)
+	}
+	if mf.ServerID != 0 {
+		if _, ok := singleton.ServerShared.Get(mf.ServerID); !ok {
+			return 0, singleton.Localizer.ErrorT("server id %d does not exist", mf.ServerID)
+		}
+	}
+
+	var mapping model.TerminalUserMapping
+	singleton.DB.Where("user_id = ? AND server_id = ?", mf.UserID, mf.ServerID).First(&mapping)
+	mapping.UserID = mf.UserID
+	mapping.ServerID = mf.ServerID
+	mapping.OSUser = mf.OSUser
+
+	if err := singleton.DB.Save(&mapping).Error; err != nil {
+		return 0, newGormError("%v", err)
+	}
+
+	return mapping.ID, nil
+}
+
+// Delete terminal user mapping
+// @Summary Delete terminal user mapping
+// @Description Delete a terminal user mapping
+// @Security BearerAuth
+// @Tags admin required
+// @Param id path uint true "Mapping ID"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[any]
+// @Router /terminal/user-mapping/{id} [delete]
+func deleteTerminalUserMapping(c *gin.Context) (any, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := singleton.DB.Delete(&model.TerminalUserMapping{}, id).Error; err != nil {
+		return nil, newGormError("%v", err)
+	}
+
+	return nil, nil
+}
+
+// resolveTerminalOSUser 确定打开终端时使用的系统用户。
+// 管理员可以自由选择；普通用户只能使用映射的账号，服务器级映射优先于全局映射，
+// 没有映射时只能使用 Agent 运行用户。
+func resolveTerminalOSUser(user *model.User, serverID uint64, requested string) (string, error) {
+	if requested != "" && !model.IsValidOSUser(requested) {
+		return "", singleton.Localizer.ErrorT("invalid os user")
+	}
+
+	var mappings []model.TerminalUserMapping
+	if err := singleton.DB.Where("user_id = ? AND server_id IN ?", user.ID, []uint64{0, serverID}).
+		Order("server_id DESC").Find(&mappings).Error; err != nil {
+		return "", newGormError("%v", err)
+	}
+
+	if user.Role.IsAdmin() {
+		if requested == "" && len(mappings) > 0 {
+			return mappings[0].OSUser, nil
+		}
+		return requested, nil
+	}
+
+	var mapped string
+	if len(mappings) > 0 {
+		mapped = mappings[0].OSUser
+	}
+	if requested != "" && requested != mapped {
+		return "", singleton.Localizer.ErrorT("permission denied")
+	}
+	return mapped, nil
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/service/singleton"
+)
+
+// 隧道流量记录最多查询 30 天
+const maxTunnelTransferHours = 30 * 24
+
+// List AutoSSH mapping traffic
+// @Summary List AutoSSH mapping traffic
+// @Security BearerAuth
+// @Schemes
+// @Description List hourly traffic and connection counts of an AutoSSH mapping
+// @Tags auth required
+// @param id path uint true "Mapping ID"
+// @param hours query uint false "Hours to look back, 24 by default"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[[]model.TunnelTransfer]
+// @Router /autossh/{id}/transfer [get]
+func listAutoSSHTransfer(c *gin.Context) ([]model.TunnelTransfer, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if !checkAutoSSHPermission(c, id) {
+		return nil, singleton.Localizer.ErrorT("permission denied")
+	}
+	return queryTunnelTransfer(c, model.TunnelKindAutoSSH, id)
+}
+
+// List NAT profile traffic
+// @Summary List NAT profile traffic
+// @Security BearerAuth
+// @Schemes
+// @Description List hourly traffic and connection counts of a NAT profile
+// @Tags auth required
+// @param id path uint true "Profile ID"
+// @param hours query uint false "Hours to look back, 24 by default"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[[]model.TunnelTransfer]
+// @Router /nat/{id}/transfer [get]
+func listNATTransfer(c *gin.Context) ([]model.TunnelTransfer, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	var n model.NAT
+	if err := singleton.DB.First(&n, id).Error; err != nil {
+		return nil, singleton.Localizer.ErrorT("profile id %d does not exist", id)
+	}
+	if !n.HasPermission(c) {
+		return nil, singleton.Localizer.ErrorT("permission denied")
+	}
+	return queryTunnelTransfer(c, model.TunnelKindNAT, id)
+}
+
+// List port forward traffic
+// @Summary List port forward traffic
+// @Security BearerAuth
+// @Schemes
+// @Description List hourly traffic and connection counts of a port forward
+// @Tags auth required
+// @param id path uint true "Port Forward ID"
+// @param hours query uint false "Hours to look back, 24 by default"
+// @Produce json
+// @Success 200 {object} model.CommonResponse[[]model.TunnelTransfer]
+// @Router /port-forward/{id}/transfer [get]
+func listPortForwardTransfer(c *gin.Context) ([]model.TunnelTransfer, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	pf, ok := singleton.PortForwardShared.Get(id)
+	if !ok {
+		return nil, singleton.Localizer.ErrorT("port forward id %d does not exist", id)
+	}
+	if !pf.HasPermission(c) {
+		return nil, singleton.Localizer.ErrorT("permission denied")
+	}
+	return queryTunnelTransfer(c, model.TunnelKindPortForward, id)
+}
+
+func queryTunnelTransfer(c *gin.Context, kind string, id uint64) ([]model.TunnelTransfer, error) {
+	hours, _ := strconv.Atoi(c.DefaultQuery("hours", "24"))
+	if hours < 1 || hours > maxTunnelTransferHours {
+		hours = 24
+	}
+
+	var txs []model.TunnelTransfer
+	if err := singleton.DB.Where("kind = ? AND tunnel_id = ? AND created_at >= ?",
+		kind, id, time.Now().Add(-time.Duration(hours)*time.Hour)).
+		Order("created_at").Find(&txs).Error; err != nil {
+		return nil, newGormError("%v", err)
+	}
+	return txs, nil
+}
@@ -178,7 +178,7 @@ func getServerStat(withPublicNote, authorized bool) ([]byte, error) {
 				PublicNote:   utils.IfOr(withPublicNote, server.PublicNote, ""),
 				DisplayIndex: server.DisplayIndex,
 				Host:         utils.IfOr(authorized, server.Host, server.Host.Filter()),
-				State:        server.State,
+				State:        utils.IfOr(authorized, server.State, server.State.Filter()),
 				CountryCode:  countryCode,
 				LastActive:   server.LastActive,
 			})
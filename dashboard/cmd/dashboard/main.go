@@ -74,6 +74,32 @@ func initSystem(bus chan<- *model.Service) error {
 	if _, err := singleton.CronShared.AddFunc("0 0 * * * *", func() { singleton.RecordTransferHourlyUsage() }); err != nil {
 		return err
 	}
+
+	// 每分钟与 agent 核对 AutoSSH 映射的运行状态
+	if _, err := singleton.CronShared.AddFunc("0 * * * * *", singleton.AutoSSHShared.Reconcile); err != nil {
+		return err
+	}
+
+	// 每分钟计算隧道速率，错开 AutoSSH 状态核对
+	if _, err := singleton.CronShared.AddFunc("30 * * * * *", singleton.TunnelStatsShared.Sample); err != nil {
+		return err
+	}
+
+	// 每 10 秒写入主机指标，每分钟聚合已结束的时间段
+	if _, err := singleton.CronShared.AddFunc("*/10 * * * * *", singleton.MetricsShared.Flush); err != nil {
+		return err
+	}
+	if _, err := singleton.CronShared.AddFunc("15 * * * * *", singleton.MetricsShared.Rollup); err != nil {
+		return err
+	}
+
+	singleton.AutoSSHShared.SyncAuthorizedKeys()
+
+	if singleton.Conf.AutoSSHServerListen != "" {
+		if err := singleton.StartAutoSSHServer(singleton.Conf.AutoSSHServerListen, singleton.Conf.AutoSSHServerHostKeyFile); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -131,6 +157,7 @@ func main() {
 	go singleton.StartTerminalAuditCleanupTask()
 
 	grpcHandler := rpc.ServeRPC()
+	rpc.ServePortForwards()
 	httpHandler := controller.ServeWeb(frontendDist)
 	controller.InitUpgrader()
 
@@ -173,6 +200,7 @@ func main() {
 	}, func(c context.Context) error {
 		log.Println("NEZHA>> Graceful::START")
 		singleton.RecordTransferHourlyUsage()
+		singleton.MetricsShared.Flush()
 		log.Println("NEZHA>> Graceful::END")
 		var err error
 		if muxServerHTTPS != nil {
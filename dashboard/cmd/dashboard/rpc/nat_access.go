@@ -0,0 +1,161 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/pkg/utils"
+	"github.com/nezhahq/nezha/service/singleton"
+)
+
+// 请求日志中记录的路径长度上限
+const natLogMaxPath = 512
+
+func natRealIP(r *http.Request) (string, error) {
+	if singleton.Conf.WebRealIPHeader == "" || singleton.Conf.WebRealIPHeader == model.ConfigUsePeerIP {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			return "", err
+		}
+		return host, nil
+	}
+
+	vals := r.Header.Get(singleton.Conf.WebRealIPHeader)
+	if vals == "" {
+		return "", errors.New("real ip header not found")
+	}
+	return utils.GetIPFromHeader(vals)
+}
+
+func natRequestURL(r *http.Request) *url.URL {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return &url.URL{Scheme: scheme, Host: r.Host, Path: r.URL.Path, RawQuery: r.URL.RawQuery}
+}
+
+// checkNATAccess 按 NAT 的访问控制检查请求，未通过时已写入响应
+func checkNATAccess(w http.ResponseWriter, r *http.Request, n *model.NAT, reqLog *model.NATRequestLog) bool {
+	deny := func(status int, reason, message string) bool {
+		reqLog.Status = status
+		reqLog.Denied = reason
+		http.Error(w, message, status)
+		return false
+	}
+
+	ip, err := natRealIP(r)
+	if err != nil {
+		return deny(http.StatusForbidden, model.NATDeniedSource, err.Error())
+	}
+	reqLog.ClientIP = ip
+
+	if !singleton.CheckNATSource(n, ip) {
+		return deny(http.StatusForbidden, model.NATDeniedSource, "source address not allowed")
+	}
+
+	country, ok := singleton.CheckNATCountry(n, ip)
+	reqLog.Country = country
+	if !ok {
+		return deny(http.StatusForbidden, model.NATDeniedCountry, "country not allowed")
+	}
+
+	if n.BasicAuthUsername != "" {
+		username, password, ok := r.BasicAuth()
+		if !singleton.CheckNATBasicAuth(n, username, password, ok) {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf("Basic realm=%q", n.Name))
+			return deny(http.StatusUnauthorized, model.NATDeniedBasicAuth, "unauthorized")
+		}
+		// 凭据只用于访问 NAT，不转发给内网服务
+		r.Header.Del("Authorization")
+	}
+
+	if !n.RequireLogin {
+		return true
+	}
+
+	requestURL := natRequestURL(r)
+	if ticket := r.URL.Query().Get(singleton.NATTicketQuery); ticket != "" {
+		session, expire, ok := singleton.RedeemNATTicket(ticket, n.ID, ip)
+		if !ok {
+			return deny(http.StatusForbidden, model.NATDeniedLogin, "invalid or expired ticket")
+		}
+		http.SetCookie(w, &http.Cookie{
+			Name:     singleton.NATSessionCookie,
+			Value:    session,
+			Path:     "/",
+			Expires:  expire,
+			HttpOnly: true,
+			Secure:   requestURL.Scheme == "https",
+			SameSite: http.SameSiteLaxMode,
+		})
+		query := requestURL.Query()
+		query.Del(singleton.NATTicketQuery)
+		requestURL.RawQuery = query.Encode()
+		reqLog.Status = http.StatusFound
+		http.Redirect(w, r, requestURL.String(), http.StatusFound)
+		return false
+	}
+
+	if cookie, err := r.Cookie(singleton.NATSessionCookie); err == nil {
+		if userID, ok := singleton.VerifyNATSession(cookie.Value, n, ip); ok {
+			reqLog.UserID = userID
+			stripNATSessionCookie(r)
+			return true
+		}
+	}
+
+	if singleton.Conf.SiteURL == "" {
+		return deny(http.StatusForbidden, model.NATDeniedLogin, "dashboard login required")
+	}
+	reqLog.Status = http.StatusFound
+	reqLog.Denied = model.NATDeniedLogin
+	http.Redirect(w, r, singleton.Conf.SiteURL+"/api/v1/nat/"+strconv.FormatUint(n.ID, 10)+
+		"/authorize?redirect="+url.QueryEscape(requestURL.String()), http.StatusFound)
+	return false
+}
+
+// stripNATSessionCookie 会话 cookie 只用于访问 NAT，不转发给内网服务
+func stripNATSessionCookie(r *http.Request) {
+	cookies := r.Cookies()
+	r.Header.Del("Cookie")
+	for _, cookie := range cookies {
+		if cookie.Name != singleton.NATSessionCookie {
+			r.AddCookie(cookie)
+		}
+	}
+}
+
+// natStatusRecorder 从内网服务返回的响应行中取出状态码
+type natStatusRecorder struct {
+	*utils.RequestWrapper
+	status atomic.Int32
+	head   []byte
+}
+
+func (s *natStatusRecorder) Write(p []byte) (int, error) {
+	if len(s.head) < 12 {
+		s.head = append(s.head, p[:min(len(p), 12-len(s.head))]...)
+		// HTTP/1.1 200
+		if len(s.head) == 12 && strings.HasPrefix(string(s.head), "HTTP/") {
+			status, _ := strconv.Atoi(string(s.head[9:12]))
+			s.status.Store(int32(status))
+		}
+	}
+	return s.RequestWrapper.Write(p)
+}
+
+func natLogPath(r *http.Request) string {
+	path := r.URL.RequestURI()
+	if len(path) > natLogMaxPath {
+		path = path[:natLogMaxPath]
+	}
+	return path
+}
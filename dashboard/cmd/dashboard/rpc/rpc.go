@@ -124,17 +124,36 @@ func DispatchKeepalive() {
 }
 
 func ServeNAT(w http.ResponseWriter, r *http.Request, natConfig *model.NAT) {
+	reqLog := &model.NATRequestLog{
+		NATID:  natConfig.ID,
+		Method: r.Method,
+		Path:   natLogPath(r),
+	}
+	start := time.Now()
+	defer func() {
+		reqLog.Duration = time.Since(start).Milliseconds()
+		singleton.RecordNATRequest(reqLog)
+	}()
+
+	if !checkNATAccess(w, r, natConfig, reqLog) {
+		return
+	}
+
+	unavailable := func(message []byte) {
+		reqLog.Status = http.StatusServiceUnavailable
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write(message)
+	}
+
 	server, _ := singleton.ServerShared.Get(natConfig.ServerID)
 	if server == nil || server.TaskStream == nil {
-		w.WriteHeader(http.StatusServiceUnavailable)
-		w.Write([]byte("server not found or not connected"))
+		unavailable([]byte("server not found or not connected"))
 		return
 	}
 
 	streamId, err := uuid.GenerateUUID()
 	if err != nil {
-		w.WriteHeader(http.StatusServiceUnavailable)
-		w.Write(fmt.Appendf(nil, "stream id error: %v", err))
+		unavailable(fmt.Appendf(nil, "stream id error: %v", err))
 		return
 	}
 
@@ -146,8 +165,7 @@ func ServeNAT(w http.ResponseWriter, r *http.Request, natConfig *model.NAT) {
 		Host:     natConfig.Host,
 	})
 	if err != nil {
-		w.WriteHeader(http.StatusServiceUnavailable)
-		w.Write(fmt.Appendf(nil, "task data error: %v", err))
+		unavailable(fmt.Appendf(nil, "task data error: %v", err))
 		return
 	}
 
@@ -155,25 +173,39 @@ func ServeNAT(w http.ResponseWriter, r *http.Request, natConfig *model.NAT) {
 		Type: model.TaskTypeNAT,
 		Data: string(taskData),
 	}); err != nil {
-		w.WriteHeader(http.StatusServiceUnavailable)
-		w.Write(fmt.Appendf(nil, "send task error: %v", err))
+		unavailable(fmt.Appendf(nil, "send task error: %v", err))
 		return
 	}
 
+	// 每个连接只转发这一个请求，同一连接上的后续请求不会绕过访问控制与日志
 	wWrapped, err := utils.NewRequestWrapper(r, w)
 	if err != nil {
-		w.WriteHeader(http.StatusServiceUnavailable)
-		w.Write(fmt.Appendf(nil, "request wrapper error: %v", err))
+		unavailable(fmt.Appendf(nil, "request wrapper error: %v", err))
 		return
 	}
 
-	if err := rpcService.NezhaHandlerSingleton.UserConnected(streamId, wWrapped); err != nil {
-		w.WriteHeader(http.StatusServiceUnavailable)
-		w.Write(fmt.Appendf(nil, "user connected error: %v", err))
+	recorder := &natStatusRecorder{RequestWrapper: wWrapped}
+	userIo := singleton.TunnelStatsShared.Meter(model.TunnelKindNAT, natConfig.ID, natConfig.ServerID, recorder, natConfig.RateLimit)
+	if err := rpcService.NezhaHandlerSingleton.UserConnected(streamId, userIo); err != nil {
+		unavailable(fmt.Appendf(nil, "user connected error: %v", err))
 		return
 	}
 
 	rpcService.NezhaHandlerSingleton.StartStream(streamId, time.Second*10)
+	// 连接已被接管，内网服务没有响应时记为 502
+	reqLog.Status = int(recorder.status.Load())
+	if reqLog.Status == 0 {
+		reqLog.Status = http.StatusBadGateway
+	}
+}
+
+// ServePortForwards 打开所有已启用端口转发的监听
+func ServePortForwards() {
+	for _, pf := range singleton.PortForwardShared.GetSortedList() {
+		if err := rpcService.NezhaHandlerSingleton.StartPortForward(pf); err != nil {
+			log.Printf("NEZHA>> Port forward %d failed to listen: %v", pf.ID, err)
+		}
+	}
 }
 
 func canSendTaskToServer(task *model.Service, server *model.Server) bool {
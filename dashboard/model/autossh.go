@@ -1,56 +1,254 @@
 package model
 
-import "time"
+import (
+	"fmt"
+	"log"
+	"net/netip"
+	"regexp"
+	"time"
 
+	"github.com/goccy/go-json"
+	"gorm.io/gorm"
+)
+
+const (
+	AutoSSHMappingTypeLocal   = "local"   // 本地转发 -L
+	AutoSSHMappingTypeRemote  = "remote"  // 远程转发 -R
+	AutoSSHMappingTypeDynamic = "dynamic" // 动态转发 -D，在 agent 上提供 SOCKS5 代理
+)
+
+const (
+	AutoSSHStatusStopped  = "stopped"
+	AutoSSHStatusStarting = "starting" // 任务已下发，等待 agent 回报
+	AutoSSHStatusRunning  = "running"
+	AutoSSHStatusError    = "error"
+)
+
+// AutoSSHStatusReport.Event
 const (
-	AutoSSHMappingTypeLocal  = "local"  // 本地转发 -L
-	AutoSSHMappingTypeRemote = "remote" // 远程转发 -R
+	AutoSSHEventStart   = "start"
+	AutoSSHEventStop    = "stop"
+	AutoSSHEventStatus  = "status"
+	AutoSSHEventExit    = "exit"
+	AutoSSHEventRestart = "restart"
+	AutoSSHEventSync    = "sync"
 )
 
+// AutoSSHStatusReport.ErrorCode
 const (
-	AutoSSHStatusStopped = "stopped"
-	AutoSSHStatusRunning = "running"
-	AutoSSHStatusError   = "error"
+	AutoSSHErrorHostKey = "hostkey" // 主机密钥与固定的指纹不一致
+)
+
+var (
+	hostKeyFingerprintRegex = regexp.MustCompile(`^SHA256:[A-Za-z0-9+/]{43}$`)
+	targetHostRegex         = regexp.MustCompile(`^[A-Za-z0-9._:-]+$`)
 )
 
+// IsValidTargetHost 目标地址只允许域名或 IP，会被写入 authorized_keys 的 permitopen
+func IsValidTargetHost(host string) bool {
+	return len(host) <= 253 && targetHostRegex.MatchString(host)
+}
+
+// 来源白名单的最大条目数
+const AutoSSHMaxAllowedSources = 64
+
+// ParseAllowedSources 解析来源白名单，支持 CIDR 与单个 IP
+func ParseAllowedSources(sources []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(sources))
+	for _, s := range sources {
+		if p, err := netip.ParsePrefix(s); err == nil {
+			prefixes = append(prefixes, p.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid source %q", s)
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+	return prefixes, nil
+}
+
+// SourceAllowed 白名单为空时允许所有来源
+func SourceAllowed(prefixes []netip.Prefix, addr netip.Addr) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	addr = addr.Unmap()
+	for _, p := range prefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidBindAddress 绑定地址为空时使用 0.0.0.0
+func IsValidBindAddress(addr string) bool {
+	if addr == "" || addr == "localhost" {
+		return true
+	}
+	_, err := netip.ParseAddr(addr)
+	return err == nil
+}
+
+// IsValidHostKeyFingerprint 校验 ssh-keygen -lf 输出的 SHA256 指纹
+func IsValidHostKeyFingerprint(fp string) bool {
+	return hostKeyFingerprintRegex.MatchString(fp)
+}
+
 type AutoSSH struct {
 	Common
-	Name        string    `json:"name"`
-	ServerID    uint64    `json:"server_id" gorm:"index:idx_server_mapping_port"`
-	MappingType string    `json:"mapping_type" gorm:"index:idx_server_mapping_port"` // local or remote
-	SourcePort  int       `json:"source_port" gorm:"index:idx_server_mapping_port"`
-	TargetHost  string    `json:"target_host"`
-	TargetPort  int       `json:"target_port"`
-	Enabled     bool      `json:"enabled"`
+	Name        string `json:"name"`
+	ServerID    uint64 `json:"server_id" gorm:"index:idx_server_mapping_port"`
+	MappingType string `json:"mapping_type" gorm:"index:idx_server_mapping_port"` // local, remote or dynamic
+	SourcePort  int    `json:"source_port" gorm:"index:idx_server_mapping_port"`
+	TargetHost  string `json:"target_host"` // dynamic 映射无目标地址
+	TargetPort  int    `json:"target_port"`
+	Enabled     bool   `json:"enabled"`
+
+	// dynamic 映射的 SOCKS5 认证，为空时不需要认证。密码只通过 AutoSSHForm 写入，不返回给前端
+	SocksUsername string `json:"socks_username,omitempty"`
+	SocksPassword string `json:"-"`
+
+	RateLimit uint64 `json:"rate_limit,omitempty"` // 每个方向的限速（字节/秒），0 为不限速
+
+	// local 与 dynamic 映射在 agent 上的监听地址，为空时为 0.0.0.0；
+	// remote 映射在内置隧道服务器上的监听地址，为空时为 127.0.0.1
+	BindAddress       string   `json:"bind_address,omitempty"`
+	AllowedSourcesRaw string   `gorm:"default:'[]'" json:"-"`
+	AllowedSources    []string `gorm:"-" json:"allowed_sources,omitempty"` // 允许连接的来源 CIDR，为空时不限制
+
 	Status      string    `json:"status"`
 	LastError   string    `json:"last_error,omitempty"`
 	LastStartAt time.Time `json:"last_start_at,omitempty"`
+
+	PID          int       `json:"pid,omitempty"`
+	Restarts     int       `json:"restarts"`       // agent 自动重启次数
+	LastReportAt time.Time `json:"last_report_at"` // agent 最近一次回报状态的时间
+
+	LastErrorCode       string `json:"last_error_code,omitempty"` // 出错阶段，见 AutoSSHStatusReport.ErrorCode
+	Connections         uint64 `json:"connections"`               // 累计转发的连接数
+	ActiveConnections   int64  `json:"active_connections"`
+	FailedConnections   uint64 `json:"failed_connections"`
+	RejectedConnections uint64 `json:"rejected_connections"` // 被来源白名单拒绝的连接数
+
+	Listener *AutoSSHListenerState `json:"listener,omitempty" gorm:"-"` // 内置隧道服务器上 remote 映射的实时监听状态
+	Traffic  *TunnelTraffic        `json:"traffic,omitempty" gorm:"-"`
+
+	PublicExposure bool `json:"public_exposure" gorm:"-"` // 监听在公网地址且未限制来源
+}
+
+func (a *AutoSSH) AfterFind(tx *gorm.DB) error {
+	if a.AllowedSourcesRaw != "" {
+		if err := json.Unmarshal([]byte(a.AllowedSourcesRaw), &a.AllowedSources); err != nil {
+			log.Println("NEZHA>> AutoSSH.AfterFind:", err)
+			return nil
+		}
+	}
+	a.PublicExposure = a.IsPubliclyExposed()
+	return nil
+}
+
+// IsPubliclyExposed 映射的监听端口是否对任意公网来源开放
+// remote 映射在外部 sshd 上监听 0.0.0.0，绑定地址无法限制，按来源判断
+func (a *AutoSSH) IsPubliclyExposed() bool {
+	if a.MappingType != AutoSSHMappingTypeRemote && a.BindAddress != "" {
+		addr, err := netip.ParseAddr(a.BindAddress)
+		if a.BindAddress == "localhost" || (err == nil && (addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast())) {
+			return false
+		}
+	}
+
+	prefixes, err := ParseAllowedSources(a.AllowedSources)
+	if err != nil || len(prefixes) == 0 {
+		return true
+	}
+	for _, p := range prefixes {
+		if !p.Addr().IsLoopback() && !p.Addr().IsPrivate() && !p.Addr().IsLinkLocalUnicast() {
+			return true
+		}
+	}
+	return false
+}
+
+// AutoSSHListenerState 内置隧道服务器为 remote 映射打开的监听
+type AutoSSHListenerState struct {
+	MappingID         uint64    `json:"mapping_id"`
+	ServerID          uint64    `json:"server_id"`
+	Addr              string    `json:"addr"`
+	Since             time.Time `json:"since"`
+	Connections       uint64    `json:"connections"`
+	ActiveConnections int64     `json:"active_connections"`
+}
+
+// AutoSSHServerInfo 内置隧道服务器状态
+type AutoSSHServerInfo struct {
+	Enabled            bool                    `json:"enabled"`
+	Listen             string                  `json:"listen,omitempty"`
+	HostKeyFingerprint string                  `json:"host_key_fingerprint,omitempty"`
+	ConnectedServers   []uint64                `json:"connected_servers,omitempty"`
+	Listeners          []*AutoSSHListenerState `json:"listeners,omitempty"`
 }
 
 type AutoSSHForm struct {
 	Name        string `json:"name" validate:"required"`
 	ServerID    uint64 `json:"server_id" validate:"required"`
-	MappingType string `json:"mapping_type" validate:"required,oneof=local remote"`
+	MappingType string `json:"mapping_type" validate:"required,oneof=local remote dynamic"`
 	SourcePort  int    `json:"source_port" validate:"required,min=1,max=65535"`
-	TargetHost  string `json:"target_host" validate:"required"`
-	TargetPort  int    `json:"target_port" validate:"required,min=1,max=65535"`
+	TargetHost  string `json:"target_host" validate:"required_unless=MappingType dynamic"`
+	TargetPort  int    `json:"target_port" validate:"required_unless=MappingType dynamic,max=65535"`
 	Enabled     bool   `json:"enabled"`
+
+	SocksUsername string `json:"socks_username,omitempty" validate:"max=255"`
+	SocksPassword string `json:"socks_password,omitempty" validate:"max=255"` // 更新时留空则沿用原密码
+
+	RateLimit uint64 `json:"rate_limit,omitempty"`
+
+	BindAddress    string   `json:"bind_address,omitempty"`
+	AllowedSources []string `json:"allowed_sources,omitempty"`
 }
 
 type TaskAutoSSH struct {
-	Action      string            `json:"action"` // start, stop, status
+	Action      string            `json:"action"` // start, stop, status, sync
 	MappingID   uint64            `json:"mapping_id"`
 	MappingType string            `json:"mapping_type"`
 	SourcePort  int               `json:"source_port"`
 	TargetHost  string            `json:"target_host"`
 	TargetPort  int               `json:"target_port"`
-	SSHHost     string            `json:"ssh_host"`      // SSH 服务器地址，格式：user@host:port
+	SSHHost     string            `json:"ssh_host"` // SSH 服务器地址，格式：user@host:port
 	SSHOptions  map[string]string `json:"ssh_options,omitempty"`
+
+	// 动态转发（SOCKS5）的认证信息，为空时不需要认证
+	SocksUsername string `json:"socks_username,omitempty"`
+	SocksPassword string `json:"socks_password,omitempty"`
+
+	HostKeyFingerprint string `json:"host_key_fingerprint,omitempty"` // 固定的 SSH 服务器主机密钥指纹，为空时不校验
+	PrivateKey         string `json:"private_key,omitempty"`          // 面板生成的隧道私钥，agent 仅保存在内存中
+	RateLimit          uint64 `json:"rate_limit,omitempty"`           // 每个方向的限速（字节/秒），0 为不限速
+
+	BindAddress    string   `json:"bind_address,omitempty"`    // local 与 dynamic 映射的监听地址
+	AllowedSources []string `json:"allowed_sources,omitempty"` // agent 只接受来自这些 CIDR 的连接
+
+	Restore    bool     `json:"restore,omitempty"`     // 恢复任务，agent 上配置相同的隧道仍在运行时不重启
+	MappingIDs []uint64 `json:"mapping_ids,omitempty"` // sync 时应当运行的映射，agent 停止其余隧道
 }
 
 type AutoSSHStatusReport struct {
 	MappingID uint64 `json:"mapping_id"`
+	Event     string `json:"event,omitempty"`
 	Status    string `json:"status"`
 	Error     string `json:"error,omitempty"`
 	PID       int    `json:"pid,omitempty"`
+	Restarts  int    `json:"restarts,omitempty"`
+
+	ErrorCode           string `json:"error_code,omitempty"` // 出错阶段：config、dial、handshake、auth、listen、keepalive、closed
+	Connections         uint64 `json:"connections,omitempty"`
+	ActiveConnections   int64  `json:"active_connections,omitempty"`
+	FailedConnections   uint64 `json:"failed_connections,omitempty"`
+	RejectedConnections uint64 `json:"rejected_connections,omitempty"`
+	BytesIn             uint64 `json:"bytes_in,omitempty"`
+	BytesOut            uint64 `json:"bytes_out,omitempty"`
+
+	HostKeyFingerprint string `json:"host_key_fingerprint,omitempty"` // agent 实际看到的主机密钥指纹
 }
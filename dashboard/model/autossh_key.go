@@ -0,0 +1,72 @@
+package model
+
+import (
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AutoSSHKey 面板为服务器生成的隧道密钥对，公钥写入隧道服务器的 authorized_keys
+type AutoSSHKey struct {
+	Common
+	ServerID    uint64     `json:"server_id" gorm:"uniqueIndex"`
+	PublicKey   string     `json:"public_key"` // authorized_keys 格式
+	PrivateKey  string     `json:"-"`          // OpenSSH PEM 格式，仅通过任务下发给 agent
+	Fingerprint string     `json:"fingerprint"`
+	RotatedAt   time.Time  `json:"rotated_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"` // 吊销后不再自动生成，需手动轮换
+}
+
+func (k *AutoSSHKey) Active() bool {
+	return k.RevokedAt == nil && k.PrivateKey != ""
+}
+
+// authorizedKeysDenyAll 只能整体开启端口转发，用不可用的地址关闭另一方向的转发
+const authorizedKeysDenyAll = "127.0.0.1:1"
+
+// AuthorizedKeysLine 生成 authorized_keys 中的一行，只允许该服务器映射所需的端口转发
+func (k *AutoSSHKey) AuthorizedKeysLine(mappings []*AutoSSH) string {
+	return authorizedKeyOptions(mappings) + " " + k.PublicKey + " nezha-server-" + strconv.FormatUint(k.ServerID, 10)
+}
+
+// authorizedKeyOptions local 映射限制 permitopen，remote 映射限制 permitlisten
+func authorizedKeyOptions(mappings []*AutoSSH) string {
+	var permitOpen, permitListen []string
+	dynamic := false
+	for _, a := range mappings {
+		switch a.MappingType {
+		case AutoSSHMappingTypeLocal:
+			if !IsValidTargetHost(a.TargetHost) {
+				continue
+			}
+			permitOpen = append(permitOpen, `permitopen="`+net.JoinHostPort(a.TargetHost, strconv.Itoa(a.TargetPort))+`"`)
+		case AutoSSHMappingTypeRemote:
+			permitListen = append(permitListen, `permitlisten="0.0.0.0:`+strconv.Itoa(a.SourcePort)+`"`)
+		case AutoSSHMappingTypeDynamic:
+			dynamic = true
+		}
+	}
+
+	opts := []string{"restrict", `command="/bin/false"`}
+	if !dynamic && len(permitOpen) == 0 && len(permitListen) == 0 {
+		return strings.Join(opts, ",")
+	}
+	opts = append(opts, "port-forwarding")
+
+	// dynamic 映射的目标由 SOCKS5 客户端决定，无法限制 permitopen
+	if !dynamic {
+		if len(permitOpen) == 0 {
+			permitOpen = []string{`permitopen="` + authorizedKeysDenyAll + `"`}
+		}
+		slices.Sort(permitOpen)
+		opts = append(opts, slices.Compact(permitOpen)...)
+	}
+	if len(permitListen) == 0 {
+		permitListen = []string{`permitlisten="` + authorizedKeysDenyAll + `"`}
+	}
+	slices.Sort(permitListen)
+	opts = append(opts, slices.Compact(permitListen)...)
+	return strings.Join(opts, ",")
+}
@@ -0,0 +1,59 @@
+package model
+
+import "testing"
+
+func TestAutoSSHKeyAuthorizedKeysLine(t *testing.T) {
+	key := &AutoSSHKey{ServerID: 7, PublicKey: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIExample"}
+
+	cases := []struct {
+		name     string
+		mappings []*AutoSSH
+		exp      string
+	}{
+		{
+			name: "NoMapping",
+			exp:  `restrict,command="/bin/false"`,
+		},
+		{
+			name: "LocalAndRemote",
+			mappings: []*AutoSSH{
+				{MappingType: AutoSSHMappingTypeLocal, TargetHost: "10.0.0.2", TargetPort: 80},
+				{MappingType: AutoSSHMappingTypeLocal, TargetHost: "::1", TargetPort: 22},
+				{MappingType: AutoSSHMappingTypeRemote, SourcePort: 8080},
+			},
+			exp: `restrict,command="/bin/false",port-forwarding,permitopen="10.0.0.2:80",permitopen="[::1]:22",permitlisten="0.0.0.0:8080"`,
+		},
+		{
+			name: "RemoteOnly",
+			mappings: []*AutoSSH{
+				{MappingType: AutoSSHMappingTypeRemote, SourcePort: 9000},
+			},
+			exp: `restrict,command="/bin/false",port-forwarding,permitopen="127.0.0.1:1",permitlisten="0.0.0.0:9000"`,
+		},
+		{
+			name: "Dynamic",
+			mappings: []*AutoSSH{
+				{MappingType: AutoSSHMappingTypeLocal, TargetHost: "10.0.0.2", TargetPort: 80},
+				{MappingType: AutoSSHMappingTypeDynamic, SourcePort: 1080},
+			},
+			exp: `restrict,command="/bin/false",port-forwarding,permitlisten="127.0.0.1:1"`,
+		},
+		{
+			name: "InvalidTargetHost",
+			mappings: []*AutoSSH{
+				{MappingType: AutoSSHMappingTypeLocal, TargetHost: `x",command="sh`, TargetPort: 80},
+				{MappingType: AutoSSHMappingTypeRemote, SourcePort: 9000},
+			},
+			exp: `restrict,command="/bin/false",port-forwarding,permitopen="127.0.0.1:1",permitlisten="0.0.0.0:9000"`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			exp := c.exp + " " + key.PublicKey + " nezha-server-7"
+			if got := key.AuthorizedKeysLine(c.mappings); got != exp {
+				t.Fatalf("expected %s, but got %s", exp, got)
+			}
+		})
+	}
+}
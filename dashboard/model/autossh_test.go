@@ -0,0 +1,45 @@
+package model
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestAutoSSHPublicExposure(t *testing.T) {
+	cases := []struct {
+		name    string
+		mapping AutoSSH
+		exp     bool
+	}{
+		{"DefaultBind", AutoSSH{MappingType: AutoSSHMappingTypeLocal}, true},
+		{"Loopback", AutoSSH{MappingType: AutoSSHMappingTypeLocal, BindAddress: "127.0.0.1"}, false},
+		{"Private", AutoSSH{MappingType: AutoSSHMappingTypeDynamic, BindAddress: "192.168.1.2"}, false},
+		{"PrivateSources", AutoSSH{MappingType: AutoSSHMappingTypeLocal, AllowedSources: []string{"10.0.0.0/8", "::1"}}, false},
+		{"PublicSource", AutoSSH{MappingType: AutoSSHMappingTypeLocal, AllowedSources: []string{"10.0.0.0/8", "1.2.3.4"}}, true},
+		{"RemoteIgnoresBind", AutoSSH{MappingType: AutoSSHMappingTypeRemote, BindAddress: "127.0.0.1"}, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.mapping.IsPubliclyExposed(); got != c.exp {
+				t.Fatalf("expected %v, but got %v", c.exp, got)
+			}
+		})
+	}
+}
+
+func TestAutoSSHAllowedSources(t *testing.T) {
+	prefixes, err := ParseAllowedSources([]string{"10.1.2.3/8", "2001:db8::1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !SourceAllowed(prefixes, netip.MustParseAddr("::ffff:10.9.9.9")) {
+		t.Fatal("expected IPv4-mapped address inside 10.0.0.0/8 to be allowed")
+	}
+	if SourceAllowed(prefixes, netip.MustParseAddr("2001:db8::2")) {
+		t.Fatal("expected address outside the allowlist to be rejected")
+	}
+	if _, err := ParseAllowedSources([]string{"example.com"}); err == nil {
+		t.Fatal("expected invalid source to fail")
+	}
+}
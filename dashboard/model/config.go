@@ -30,6 +30,7 @@ type ConfigForGuests struct {
 
 type ConfigDashboard struct {
 	InstallHost string `koanf:"install_host" json:"install_host,omitempty"`
+	SiteURL     string `koanf:"site_url" json:"site_url,omitempty"` // 面板访问地址，如 https://nezha.example.com，NAT 要求登录时跳转到此处
 	AgentTLS    bool   `koanf:"tls" json:"tls,omitempty"` // 用于前端判断生成的安装命令是否启用 TLS
 
 	WebRealIPHeader   string `koanf:"web_real_ip_header" json:"web_real_ip_header,omitempty"`     // 前端真实IP
@@ -47,6 +48,9 @@ type ConfigDashboard struct {
 
 	DNSServers string `koanf:"dns_servers" json:"dns_servers,omitempty"`
 	AutoSSHHost string `koanf:"autossh_host" json:"autossh_host,omitempty"` // AutoSSH 服务器地址，格式：ip:port
+	// AutoSSH 服务器主机密钥指纹（SHA256:...），为空时记录首次连接时的指纹
+	AutoSSHHostKey             string `koanf:"autossh_host_key" json:"autossh_host_key,omitempty"`
+	AutoSSHNotificationGroupID uint64 `koanf:"autossh_notification_group_id" json:"autossh_notification_group_id,omitempty"` // 主机密钥不匹配等 AutoSSH 异常的通知组
 
 	// 终端审计配置
 	TerminalRecordingEnabled  bool   `koanf:"terminal_recording_enabled" json:"terminal_recording_enabled,omitempty"`   // 全局是否启用终端���制
@@ -54,6 +58,7 @@ type ConfigDashboard struct {
 	TerminalRetentionDays     int    `koanf:"terminal_retention_days" json:"terminal_retention_days,omitempty"`         // 审计数据保留天数，0表示永久保留，默认90天
 	TerminalMaxRecordingSize  int64  `koanf:"terminal_max_recording_size" json:"terminal_max_recording_size,omitempty"` // 单个录制文件最大大小（MB），默认100MB
 	TerminalCompressionEnabled bool   `koanf:"terminal_compression_enabled" json:"terminal_compression_enabled,omitempty"` // 是否启用压缩，默认启用
+	TerminalReattachGracePeriod int   `koanf:"terminal_reattach_grace_period" json:"terminal_reattach_grace_period,omitempty"` // 浏览器断开后终端保留时长（秒），0表示默认300秒，负数表示不保留
 }
 
 type Config struct {
@@ -78,6 +83,20 @@ type Config struct {
 	// HTTPS 配置
 	HTTPS HTTPSConf `koanf:"https" json:"https"`
 
+	// 面板维护的隧道服务器 authorized_keys 文件路径，为空时不写入文件
+	AutoSSHAuthorizedKeysFile string `koanf:"autossh_authorized_keys_file" json:"autossh_authorized_keys_file,omitempty"`
+	// 内置隧道服务器监听地址，如 ":2222"，为空时不启用
+	AutoSSHServerListen      string `koanf:"autossh_server_listen" json:"autossh_server_listen,omitempty"`
+	AutoSSHServerHostKeyFile string `koanf:"autossh_server_host_key_file" json:"autossh_server_host_key_file,omitempty"` // 默认 data/autossh_host_key
+
+	// 内置隧道服务器的 local 与 dynamic 映射默认不能连接面板主机本身及内网地址
+	AutoSSHServerAllowPrivateTargets bool `koanf:"autossh_server_allow_private_targets" json:"autossh_server_allow_private_targets,omitempty"`
+
+	// 主机指标历史
+	Metrics MetricsConf `koanf:"metrics" json:"metrics"`
+	// Prometheus 抓取 /metrics 时使用的 Bearer Token，为空时不提供 /metrics
+	PrometheusToken string `koanf:"prometheus_token" json:"prometheus_token,omitempty"`
+
 	k        *koanf.Koanf `json:"-"`
 	filePath string       `json:"-"`
 }
@@ -89,6 +108,15 @@ type HTTPSConf struct {
 	TLSKeyPath  string `koanf:"tls_key_path" json:"tls_key_path,omitempty"`
 }
 
+// MetricsConf 各精度指标数据的保留时长，为 0 时使用默认值
+type MetricsConf struct {
+	Disabled            bool `koanf:"disabled" json:"disabled,omitempty"`
+	RawRetentionHours   int  `koanf:"raw_retention_hours" json:"raw_retention_hours,omitempty"`     // 默认 6 小时
+	MinuteRetentionDays int  `koanf:"minute_retention_days" json:"minute_retention_days,omitempty"` // 默认 7 天
+	HourRetentionDays   int  `koanf:"hour_retention_days" json:"hour_retention_days,omitempty"`     // 默认 90 天
+	DayRetentionDays    int  `koanf:"day_retention_days" json:"day_retention_days,omitempty"`       // 默认 730 天
+}
+
 // Read 读取配置文件并应用
 func (c *Config) Read(path string, frontendTemplates []FrontendTemplate) error {
 	c.k = koanf.New(".")
@@ -143,6 +171,18 @@ func (c *Config) Read(path string, frontendTemplates []FrontendTemplate) error {
 	if c.AvgPingCount == 0 {
 		c.AvgPingCount = 2
 	}
+	if c.Metrics.RawRetentionHours == 0 {
+		c.Metrics.RawRetentionHours = 6
+	}
+	if c.Metrics.MinuteRetentionDays == 0 {
+		c.Metrics.MinuteRetentionDays = 7
+	}
+	if c.Metrics.HourRetentionDays == 0 {
+		c.Metrics.HourRetentionDays = 90
+	}
+	if c.Metrics.DayRetentionDays == 0 {
+		c.Metrics.DayRetentionDays = 730
+	}
 	if c.Cover == 0 {
 		c.Cover = 1
 	}
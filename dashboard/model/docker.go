@@ -0,0 +1,169 @@
+package model
+
+import (
+	"time"
+
+	pb "github.com/nezhahq/nezha/proto"
+)
+
+// TaskDocker.Action
+const (
+	DockerActionStart   = "start"
+	DockerActionStop    = "stop"
+	DockerActionRestart = "restart"
+	DockerActionLogs    = "logs"
+	DockerActionInspect = "inspect"
+)
+
+// container_restart 规则统计此时间内的重启次数
+const DockerRestartWindow = 10 * time.Minute
+
+const DockerHealthUnhealthy = "unhealthy"
+
+type TaskDocker struct {
+	Action      string `json:"action"`
+	ContainerID string `json:"container_id"`
+	Tail        int    `json:"tail,omitempty"` // logs 返回的最大行数
+}
+
+type DockerContainer struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	Image          string   `json:"image"`
+	State          string   `json:"state"` // created, running, paused, restarting, exited, dead
+	Status         string   `json:"status"`
+	Health         string   `json:"health,omitempty"` // starting, healthy, unhealthy，没有健康检查时为空
+	RestartCount   uint64   `json:"restart_count"`
+	Created        int64    `json:"created"`
+	StartedAt      int64    `json:"started_at,omitempty"`
+	CPU            float64  `json:"cpu"`
+	MemUsed        uint64   `json:"mem_used"`
+	MemLimit       uint64   `json:"mem_limit"`
+	NetInTransfer  uint64   `json:"net_in_transfer"`
+	NetOutTransfer uint64   `json:"net_out_transfer"`
+	Ports          []string `json:"ports,omitempty"`
+}
+
+type DockerImage struct {
+	ID         string   `json:"id"`
+	Tags       []string `json:"tags,omitempty"`
+	Size       uint64   `json:"size"`
+	Created    int64    `json:"created"`
+	Containers uint64   `json:"containers"`
+}
+
+type DockerRestartEvent struct {
+	At          time.Time `json:"at"`
+	ContainerID string    `json:"container_id"`
+	Count       uint64    `json:"count"`
+}
+
+// DockerState agent 最近一次上报的容器状态
+type DockerState struct {
+	UpdatedAt     time.Time             `json:"updated_at"`
+	EngineVersion string                `json:"engine_version,omitempty"`
+	Error         string                `json:"error,omitempty"`
+	Containers    []*DockerContainer    `json:"containers"`
+	Images        []*DockerImage        `json:"images"`
+	Restarts      []*DockerRestartEvent `json:"restarts,omitempty"` // DockerRestartWindow 内的重启
+}
+
+// DockerContainerDetail agent 返回的容器详情，不包含环境变量
+type DockerContainerDetail struct {
+	ID            string            `json:"id"`
+	Name          string            `json:"name"`
+	Image         string            `json:"image"`
+	Command       []string          `json:"command,omitempty"`
+	Created       int64             `json:"created"`
+	Status        string            `json:"status"`
+	ExitCode      int               `json:"exit_code"`
+	Error         string            `json:"error,omitempty"`
+	StartedAt     int64             `json:"started_at,omitempty"`
+	FinishedAt    int64             `json:"finished_at,omitempty"`
+	RestartCount  uint64            `json:"restart_count"`
+	RestartPolicy string            `json:"restart_policy,omitempty"`
+	Health        string            `json:"health,omitempty"`
+	FailingStreak int               `json:"failing_streak,omitempty"`
+	HealthLog     []string          `json:"health_log,omitempty"`
+	Mounts        []string          `json:"mounts,omitempty"`
+	Networks      map[string]string `json:"networks,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+}
+
+func (s *DockerState) Container(id string) *DockerContainer {
+	for _, c := range s.Containers {
+		if c.ID == id || c.Name == id {
+			return c
+		}
+	}
+	return nil
+}
+
+// RecentRestarts 统计 DockerRestartWindow 内的重启次数
+func (s *DockerState) RecentRestarts(now time.Time) uint64 {
+	var n uint64
+	for _, e := range s.Restarts {
+		if now.Sub(e.At) <= DockerRestartWindow {
+			n += e.Count
+		}
+	}
+	return n
+}
+
+func (s *DockerState) UnhealthyCount() int {
+	var n int
+	for _, c := range s.Containers {
+		if c.Health == DockerHealthUnhealthy {
+			n++
+		}
+	}
+	return n
+}
+
+func PB2DockerState(r *pb.DockerReport) DockerState {
+	state := DockerState{
+		EngineVersion: r.GetEngineVersion(),
+		Error:         r.GetError(),
+		Containers:    make([]*DockerContainer, 0, len(r.GetContainers())),
+		Images:        make([]*DockerImage, 0, len(r.GetImages())),
+	}
+	for _, c := range r.GetContainers() {
+		state.Containers = append(state.Containers, &DockerContainer{
+			ID:             c.GetId(),
+			Name:           c.GetName(),
+			Image:          c.GetImage(),
+			State:          c.GetState(),
+			Status:         c.GetStatus(),
+			Health:         c.GetHealth(),
+			RestartCount:   c.GetRestartCount(),
+			Created:        c.GetCreated(),
+			StartedAt:      c.GetStartedAt(),
+			CPU:            c.GetCpu(),
+			MemUsed:        c.GetMemUsed(),
+			MemLimit:       c.GetMemLimit(),
+			NetInTransfer:  c.GetNetInTransfer(),
+			NetOutTransfer: c.GetNetOutTransfer(),
+			Ports:          c.GetPorts(),
+		})
+	}
+	for _, i := range r.GetImages() {
+		state.Images = append(state.Images, &DockerImage{
+			ID:         i.GetId(),
+			Tags:       i.GetTags(),
+			Size:       i.GetSize(),
+			Created:    i.GetCreated(),
+			Containers: i.GetContainers(),
+		})
+	}
+	return state
+}
+
+type DockerActionForm struct {
+	Action string `json:"action"` // start, stop, restart
+}
+
+type DockerContainerInfo struct {
+	Container *DockerContainer       `json:"container"`
+	Detail    *DockerContainerDetail `json:"detail,omitempty"` // agent 不在线或查询失败时为空
+	Error     string                 `json:"error,omitempty"`
+}
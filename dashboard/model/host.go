@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"strings"
 
 	pb "github.com/nezhahq/nezha/proto"
 )
@@ -17,6 +18,40 @@ type SensorTemperature struct {
 	Temperature float64
 }
 
+// MountState 单个挂载点的空间与 inode 使用情况
+type MountState struct {
+	Mountpoint  string `json:"mountpoint"`
+	Device      string `json:"device,omitempty"`
+	Fstype      string `json:"fstype,omitempty"`
+	Total       uint64 `json:"total"`
+	Used        uint64 `json:"used"`
+	InodesTotal uint64 `json:"inodes_total,omitempty"`
+	InodesUsed  uint64 `json:"inodes_used,omitempty"`
+}
+
+// DiskIOState 块设备每秒的读写字节数与次数
+type DiskIOState struct {
+	Device     string `json:"device"`
+	ReadSpeed  uint64 `json:"read_speed"`
+	WriteSpeed uint64 `json:"write_speed"`
+	ReadIOPS   uint64 `json:"read_iops"`
+	WriteIOPS  uint64 `json:"write_iops"`
+}
+
+// NICState 单个网卡的速度与累计计数，ErrorRate 为每秒收发错误数
+type NICState struct {
+	Name        string  `json:"name"`
+	InSpeed     uint64  `json:"in_speed"`
+	OutSpeed    uint64  `json:"out_speed"`
+	InTransfer  uint64  `json:"in_transfer"`
+	OutTransfer uint64  `json:"out_transfer"`
+	InErrors    uint64  `json:"in_errors,omitempty"`
+	OutErrors   uint64  `json:"out_errors,omitempty"`
+	InDrops     uint64  `json:"in_drops,omitempty"`
+	OutDrops    uint64  `json:"out_drops,omitempty"`
+	ErrorRate   float64 `json:"error_rate,omitempty"`
+}
+
 type HostState struct {
 	CPU            float64             `json:"cpu,omitempty"`
 	MemUsed        uint64              `json:"mem_used,omitempty"`
@@ -35,6 +70,23 @@ type HostState struct {
 	ProcessCount   uint64              `json:"process_count,omitempty"`
 	Temperatures   []SensorTemperature `json:"temperatures,omitempty"`
 	GPU            []float64           `json:"gpu,omitempty"`
+	Mounts         []MountState        `json:"mounts,omitempty"`
+	DiskIO         []DiskIOState       `json:"disk_io,omitempty"`
+	NICs           []NICState          `json:"nics,omitempty"`
+	CustomMetrics  []CustomMetric      `json:"custom_metrics,omitempty"`
+}
+
+// CustomMetric 自定义采集器上报的指标，Name 包含标签，如 queue_depth{queue="mail"}
+type CustomMetric struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+	Type  string  `json:"type"` // gauge 或 counter
+}
+
+// BaseName 去掉标签后的指标名称
+func (m *CustomMetric) BaseName() string {
+	name, _, _ := strings.Cut(m.Name, "{")
+	return name
 }
 
 func (s *HostState) PB() *pb.State {
@@ -46,6 +98,55 @@ func (s *HostState) PB() *pb.State {
 		})
 	}
 
+	var mounts []*pb.State_Mount
+	for _, m := range s.Mounts {
+		mounts = append(mounts, &pb.State_Mount{
+			Mountpoint:  m.Mountpoint,
+			Device:      m.Device,
+			Fstype:      m.Fstype,
+			Total:       m.Total,
+			Used:        m.Used,
+			InodesTotal: m.InodesTotal,
+			InodesUsed:  m.InodesUsed,
+		})
+	}
+
+	var diskIO []*pb.State_DiskIO
+	for _, d := range s.DiskIO {
+		diskIO = append(diskIO, &pb.State_DiskIO{
+			Device:     d.Device,
+			ReadSpeed:  d.ReadSpeed,
+			WriteSpeed: d.WriteSpeed,
+			ReadIops:   d.ReadIOPS,
+			WriteIops:  d.WriteIOPS,
+		})
+	}
+
+	var nics []*pb.State_NIC
+	for _, n := range s.NICs {
+		nics = append(nics, &pb.State_NIC{
+			Name:        n.Name,
+			InSpeed:     n.InSpeed,
+			OutSpeed:    n.OutSpeed,
+			InTransfer:  n.InTransfer,
+			OutTransfer: n.OutTransfer,
+			InErrors:    n.InErrors,
+			OutErrors:   n.OutErrors,
+			InDrops:     n.InDrops,
+			OutDrops:    n.OutDrops,
+			ErrorRate:   n.ErrorRate,
+		})
+	}
+
+	customMetrics := make([]*pb.State_CustomMetric, 0, len(s.CustomMetrics))
+	for _, m := range s.CustomMetrics {
+		customMetrics = append(customMetrics, &pb.State_CustomMetric{
+			Name:  m.Name,
+			Value: m.Value,
+			Type:  m.Type,
+		})
+	}
+
 	return &pb.State{
 		Cpu:            s.CPU,
 		MemUsed:        s.MemUsed,
@@ -64,6 +165,10 @@ func (s *HostState) PB() *pb.State {
 		ProcessCount:   s.ProcessCount,
 		Temperatures:   ts,
 		Gpu:            s.GPU,
+		Mounts:         mounts,
+		DiskIo:         diskIO,
+		Nics:           nics,
+		CustomMetrics:  customMetrics,
 	}
 }
 
@@ -76,6 +181,55 @@ func PB2State(s *pb.State) HostState {
 		})
 	}
 
+	var mounts []MountState
+	for _, m := range s.GetMounts() {
+		mounts = append(mounts, MountState{
+			Mountpoint:  m.GetMountpoint(),
+			Device:      m.GetDevice(),
+			Fstype:      m.GetFstype(),
+			Total:       m.GetTotal(),
+			Used:        m.GetUsed(),
+			InodesTotal: m.GetInodesTotal(),
+			InodesUsed:  m.GetInodesUsed(),
+		})
+	}
+
+	var diskIO []DiskIOState
+	for _, d := range s.GetDiskIo() {
+		diskIO = append(diskIO, DiskIOState{
+			Device:     d.GetDevice(),
+			ReadSpeed:  d.GetReadSpeed(),
+			WriteSpeed: d.GetWriteSpeed(),
+			ReadIOPS:   d.GetReadIops(),
+			WriteIOPS:  d.GetWriteIops(),
+		})
+	}
+
+	var nics []NICState
+	for _, n := range s.GetNics() {
+		nics = append(nics, NICState{
+			Name:        n.GetName(),
+			InSpeed:     n.GetInSpeed(),
+			OutSpeed:    n.GetOutSpeed(),
+			InTransfer:  n.GetInTransfer(),
+			OutTransfer: n.GetOutTransfer(),
+			InErrors:    n.GetInErrors(),
+			OutErrors:   n.GetOutErrors(),
+			InDrops:     n.GetInDrops(),
+			OutDrops:    n.GetOutDrops(),
+			ErrorRate:   n.GetErrorRate(),
+		})
+	}
+
+	var customMetrics []CustomMetric
+	for _, m := range s.GetCustomMetrics() {
+		customMetrics = append(customMetrics, CustomMetric{
+			Name:  m.GetName(),
+			Value: m.GetValue(),
+			Type:  m.GetType(),
+		})
+	}
+
 	return HostState{
 		CPU:            s.GetCpu(),
 		MemUsed:        s.GetMemUsed(),
@@ -94,7 +248,21 @@ func PB2State(s *pb.State) HostState {
 		ProcessCount:   s.GetProcessCount(),
 		Temperatures:   ts,
 		GPU:            s.GetGpu(),
+		Mounts:         mounts,
+		DiskIO:         diskIO,
+		NICs:           nics,
+		CustomMetrics:  customMetrics,
+	}
+}
+
+// Filter 游客不展示挂载点、块设备、网卡名称与自定义指标
+func (s *HostState) Filter() *HostState {
+	if s == nil {
+		return nil
 	}
+	ret := *s
+	ret.Mounts, ret.DiskIO, ret.NICs, ret.CustomMetrics = nil, nil, nil, nil
+	return &ret
 }
 
 type Host struct {
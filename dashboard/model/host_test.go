@@ -0,0 +1,77 @@
+package model
+
+import "testing"
+
+func TestBreakdownRules(t *testing.T) {
+	state := HostState{
+		DiskUsed: 10,
+		Mounts: []MountState{
+			{Mountpoint: "/", Total: 100, Used: 10, InodesTotal: 100, InodesUsed: 95},
+			{Mountpoint: "/var", Total: 100, Used: 97, InodesTotal: 100, InodesUsed: 1},
+		},
+		DiskIO: []DiskIOState{{Device: "sda", ReadSpeed: 10 << 20, WriteSpeed: 1 << 20}},
+		NICs: []NICState{
+			{Name: "eth0", InSpeed: 1000, ErrorRate: 0},
+			{Name: "eth1", InSpeed: 10, ErrorRate: 5},
+		},
+	}
+	// 经过 proto 转换后不丢失明细
+	state = PB2State(state.PB())
+	server := &Server{Host: &Host{DiskTotal: 200}, State: &state}
+
+	cases := []struct {
+		rule Rule
+		pass bool
+	}{
+		{Rule{Type: "disk", Max: 50}, true},
+		{Rule{Type: "mount", Max: 90}, false},
+		{Rule{Type: "mount", Target: "/", Max: 90}, true},
+		{Rule{Type: "mount_inode", Target: "/", Max: 90}, false},
+		{Rule{Type: "mount", Target: "/data", Max: 90}, true},
+		{Rule{Type: "disk_read_speed", Target: "sda", Max: 1 << 20}, false},
+		{Rule{Type: "disk_write_speed", Max: 2 << 20}, true},
+		{Rule{Type: "nic_in_speed", Target: "eth1", Max: 100}, true},
+		{Rule{Type: "nic_in_speed", Max: 100}, false},
+		{Rule{Type: "nic_errors", Target: "eth0", Max: 1}, true},
+		{Rule{Type: "nic_errors", Max: 1}, false},
+	}
+	for _, c := range cases {
+		if got := c.rule.Snapshot(nil, server, nil); got != c.pass {
+			t.Errorf("%s(%s) = %v, want %v", c.rule.Type, c.rule.Target, got, c.pass)
+		}
+	}
+
+	filtered := state.Filter()
+	if filtered.Mounts != nil || filtered.NICs != nil || filtered.DiskIO != nil || filtered.DiskUsed != 10 {
+		t.Fatalf("unexpected filtered state: %+v", filtered)
+	}
+	if len(state.Mounts) != 2 {
+		t.Fatal("Filter should not modify the original state")
+	}
+}
+
+func TestCustomMetricRule(t *testing.T) {
+	state := HostState{CustomMetrics: []CustomMetric{
+		{Name: `queue_depth{queue="mail"}`, Value: 120, Type: "gauge"},
+		{Name: `queue_depth{queue="sms"}`, Value: 3, Type: "gauge"},
+		{Name: "backup_age_seconds", Value: 7200, Type: "gauge"},
+	}}
+	state = PB2State(state.PB())
+	server := &Server{Host: &Host{}, State: &state}
+
+	cases := []struct {
+		rule Rule
+		pass bool
+	}{
+		{Rule{Type: "custom", Target: "queue_depth", Max: 100}, false},
+		{Rule{Type: "custom", Target: `queue_depth{queue="sms"}`, Max: 100}, true},
+		{Rule{Type: "custom", Target: "backup_age_seconds", Max: 86400}, true},
+		{Rule{Type: "custom", Target: "backup_age_seconds", Min: 10000}, false},
+		{Rule{Type: "custom", Target: "missing", Max: 1}, true},
+	}
+	for _, c := range cases {
+		if got := c.rule.Snapshot(nil, server, nil); got != c.pass {
+			t.Errorf("%s(%s) = %v, want %v", c.rule.Type, c.rule.Target, got, c.pass)
+		}
+	}
+}
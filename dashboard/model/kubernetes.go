@@ -0,0 +1,254 @@
+package model
+
+import (
+	"time"
+
+	pb "github.com/nezhahq/nezha/proto"
+)
+
+const (
+	KubePodPending             = "Pending"
+	KubePodRunning             = "Running"
+	KubeReasonCrashLoopBackOff = "CrashLoopBackOff"
+)
+
+// CPU 单位为毫核，内存单位为字节
+type KubeNode struct {
+	Name              string   `json:"name"`
+	Ready             bool     `json:"ready"`
+	Unschedulable     bool     `json:"unschedulable,omitempty"`
+	Roles             []string `json:"roles,omitempty"`
+	KubeletVersion    string   `json:"kubelet_version"`
+	InternalIP        string   `json:"internal_ip,omitempty"`
+	CPUCapacity       uint64   `json:"cpu_capacity"`
+	MemoryCapacity    uint64   `json:"memory_capacity"`
+	CPUAllocatable    uint64   `json:"cpu_allocatable"`
+	MemoryAllocatable uint64   `json:"memory_allocatable"`
+	CPUUsage          uint64   `json:"cpu_usage"` // 未安装 metrics-server 时为 0
+	MemoryUsage       uint64   `json:"memory_usage"`
+	Created           int64    `json:"created"`
+}
+
+type KubeNamespace struct {
+	Name    string `json:"name"`
+	Phase   string `json:"phase"`
+	Created int64  `json:"created"`
+}
+
+type KubeWorkload struct {
+	Kind      string `json:"kind"` // Deployment, StatefulSet, DaemonSet
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Desired   uint32 `json:"desired"`
+	Ready     uint32 `json:"ready"`
+	Available uint32 `json:"available"`
+	Updated   uint32 `json:"updated"`
+	Created   int64  `json:"created"`
+}
+
+type KubePod struct {
+	Namespace       string `json:"namespace"`
+	Name            string `json:"name"`
+	Node            string `json:"node,omitempty"`
+	Phase           string `json:"phase"`
+	Reason          string `json:"reason,omitempty"` // 如 CrashLoopBackOff、ImagePullBackOff、Unschedulable
+	Restarts        uint32 `json:"restarts"`
+	ReadyContainers uint32 `json:"ready_containers"`
+	TotalContainers uint32 `json:"total_containers"`
+	OwnerKind       string `json:"owner_kind,omitempty"`
+	OwnerName       string `json:"owner_name,omitempty"`
+	CPURequest      uint64 `json:"cpu_request"`
+	MemoryRequest   uint64 `json:"memory_request"`
+	CPUUsage        uint64 `json:"cpu_usage"`
+	MemoryUsage     uint64 `json:"memory_usage"`
+	Created         int64  `json:"created"`
+	IP              string `json:"ip,omitempty"`
+}
+
+type KubeEvent struct {
+	Namespace  string `json:"namespace"`
+	Type       string `json:"type"` // Normal, Warning
+	Reason     string `json:"reason"`
+	Message    string `json:"message"`
+	ObjectKind string `json:"object_kind"`
+	ObjectName string `json:"object_name"`
+	Count      uint32 `json:"count"`
+	LastSeen   int64  `json:"last_seen"`
+}
+
+// KubeCluster agent 最近一次上报的集群状态
+type KubeCluster struct {
+	UpdatedAt  time.Time        `json:"updated_at"`
+	Name       string           `json:"name"`
+	Version    string           `json:"version,omitempty"`
+	Error      string           `json:"error,omitempty"`
+	Nodes      []*KubeNode      `json:"nodes"`
+	Namespaces []*KubeNamespace `json:"namespaces"`
+	Workloads  []*KubeWorkload  `json:"workloads"`
+	Pods       []*KubePod       `json:"pods"`
+	Events     []*KubeEvent     `json:"events"`
+}
+
+// KubeClusterSummary 集群列表中展示的统计信息
+type KubeClusterSummary struct {
+	ServerID          uint64    `json:"server_id"`
+	ServerName        string    `json:"server_name"`
+	Name              string    `json:"name"`
+	Version           string    `json:"version,omitempty"`
+	Error             string    `json:"error,omitempty"`
+	UpdatedAt         time.Time `json:"updated_at"`
+	Nodes             int       `json:"nodes"`
+	ReadyNodes        int       `json:"ready_nodes"`
+	Namespaces        int       `json:"namespaces"`
+	Workloads         int       `json:"workloads"`
+	Pods              int       `json:"pods"`
+	RunningPods       int       `json:"running_pods"`
+	PendingPods       int       `json:"pending_pods"`
+	CrashLoopPods     int       `json:"crash_loop_pods"`
+	CPUAllocatable    uint64    `json:"cpu_allocatable"`
+	CPUUsage          uint64    `json:"cpu_usage"`
+	MemoryAllocatable uint64    `json:"memory_allocatable"`
+	MemoryUsage       uint64    `json:"memory_usage"`
+}
+
+func (c *KubeCluster) CrashLoopCount() int {
+	var n int
+	for _, p := range c.Pods {
+		if p.Reason == KubeReasonCrashLoopBackOff {
+			n++
+		}
+	}
+	return n
+}
+
+func (c *KubeCluster) NotReadyNodeCount() int {
+	var n int
+	for _, node := range c.Nodes {
+		if !node.Ready {
+			n++
+		}
+	}
+	return n
+}
+
+func (c *KubeCluster) PendingPodCount() int {
+	var n int
+	for _, p := range c.Pods {
+		if p.Phase == KubePodPending {
+			n++
+		}
+	}
+	return n
+}
+
+func (c *KubeCluster) Summary(server *Server) *KubeClusterSummary {
+	s := &KubeClusterSummary{
+		ServerID:      server.ID,
+		ServerName:    server.Name,
+		Name:          c.Name,
+		Version:       c.Version,
+		Error:         c.Error,
+		UpdatedAt:     c.UpdatedAt,
+		Nodes:         len(c.Nodes),
+		ReadyNodes:    len(c.Nodes) - c.NotReadyNodeCount(),
+		Namespaces:    len(c.Namespaces),
+		Workloads:     len(c.Workloads),
+		Pods:          len(c.Pods),
+		PendingPods:   c.PendingPodCount(),
+		CrashLoopPods: c.CrashLoopCount(),
+	}
+	for _, p := range c.Pods {
+		if p.Phase == KubePodRunning {
+			s.RunningPods++
+		}
+	}
+	for _, n := range c.Nodes {
+		s.CPUAllocatable += n.CPUAllocatable
+		s.CPUUsage += n.CPUUsage
+		s.MemoryAllocatable += n.MemoryAllocatable
+		s.MemoryUsage += n.MemoryUsage
+	}
+	return s
+}
+
+func PB2KubeCluster(r *pb.KubernetesReport) KubeCluster {
+	c := KubeCluster{
+		Name:       r.GetCluster(),
+		Version:    r.GetVersion(),
+		Error:      r.GetError(),
+		Nodes:      make([]*KubeNode, 0, len(r.GetNodes())),
+		Namespaces: make([]*KubeNamespace, 0, len(r.GetNamespaces())),
+		Workloads:  make([]*KubeWorkload, 0, len(r.GetWorkloads())),
+		Pods:       make([]*KubePod, 0, len(r.GetPods())),
+		Events:     make([]*KubeEvent, 0, len(r.GetEvents())),
+	}
+	for _, n := range r.GetNodes() {
+		c.Nodes = append(c.Nodes, &KubeNode{
+			Name:              n.GetName(),
+			Ready:             n.GetReady(),
+			Unschedulable:     n.GetUnschedulable(),
+			Roles:             n.GetRoles(),
+			KubeletVersion:    n.GetKubeletVersion(),
+			InternalIP:        n.GetInternalIp(),
+			CPUCapacity:       n.GetCpuCapacity(),
+			MemoryCapacity:    n.GetMemoryCapacity(),
+			CPUAllocatable:    n.GetCpuAllocatable(),
+			MemoryAllocatable: n.GetMemoryAllocatable(),
+			CPUUsage:          n.GetCpuUsage(),
+			MemoryUsage:       n.GetMemoryUsage(),
+			Created:           n.GetCreated(),
+		})
+	}
+	for _, ns := range r.GetNamespaces() {
+		c.Namespaces = append(c.Namespaces, &KubeNamespace{
+			Name:    ns.GetName(),
+			Phase:   ns.GetPhase(),
+			Created: ns.GetCreated(),
+		})
+	}
+	for _, w := range r.GetWorkloads() {
+		c.Workloads = append(c.Workloads, &KubeWorkload{
+			Kind:      w.GetKind(),
+			Namespace: w.GetNamespace(),
+			Name:      w.GetName(),
+			Desired:   w.GetDesired(),
+			Ready:     w.GetReady(),
+			Available: w.GetAvailable(),
+			Updated:   w.GetUpdated(),
+			Created:   w.GetCreated(),
+		})
+	}
+	for _, p := range r.GetPods() {
+		c.Pods = append(c.Pods, &KubePod{
+			Namespace:       p.GetNamespace(),
+			Name:            p.GetName(),
+			Node:            p.GetNode(),
+			Phase:           p.GetPhase(),
+			Reason:          p.GetReason(),
+			Restarts:        p.GetRestarts(),
+			ReadyContainers: p.GetReadyContainers(),
+			TotalContainers: p.GetTotalContainers(),
+			OwnerKind:       p.GetOwnerKind(),
+			OwnerName:       p.GetOwnerName(),
+			CPURequest:      p.GetCpuRequest(),
+			MemoryRequest:   p.GetMemoryRequest(),
+			CPUUsage:        p.GetCpuUsage(),
+			MemoryUsage:     p.GetMemoryUsage(),
+			Created:         p.GetCreated(),
+			IP:              p.GetIp(),
+		})
+	}
+	for _, e := range r.GetEvents() {
+		c.Events = append(c.Events, &KubeEvent{
+			Namespace:  e.GetNamespace(),
+			Type:       e.GetType(),
+			Reason:     e.GetReason(),
+			Message:    e.GetMessage(),
+			ObjectKind: e.GetObjectKind(),
+			ObjectName: e.GetObjectName(),
+			Count:      e.GetCount(),
+			LastSeen:   e.GetLastSeen(),
+		})
+	}
+	return c
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"gorm.io/gorm"
+)
+
+// TaskLog.Mode
+const (
+	LogModeFollow  = "follow"  // 跟随文件
+	LogModeJournal = "journal" // 跟随 systemd 单元的日志
+	LogModeSearch  = "search"  // 在文件及其轮转文件中搜索
+)
+
+var (
+	logUnitRe      = regexp.MustCompile(`^[A-Za-z0-9:_.@\\-]+$`)
+	windowsDriveRe = regexp.MustCompile(`^[A-Za-z]:[\\/]`)
+)
+
+type TaskLog struct {
+	StreamID string `json:"stream_id"`
+	Mode     string `json:"mode"`
+	Path     string `json:"path,omitempty"`
+	Unit     string `json:"unit,omitempty"`
+	Filter   string `json:"filter,omitempty"`
+	Regex    bool   `json:"regex,omitempty"`
+	Lines    int    `json:"lines,omitempty"` // 跟随前输出的历史行数
+	Limit    int    `json:"limit,omitempty"` // search 返回的最大行数
+}
+
+// LogAccessRule 普通用户可以读取的日志，没有规则的普通用户无法读取任何日志，管理员不受限制
+type LogAccessRule struct {
+	Common
+	UserID   uint64   `json:"user_id" gorm:"uniqueIndex:idx_log_access_user_server"`
+	ServerID uint64   `json:"server_id" gorm:"uniqueIndex:idx_log_access_user_server"` // 0 表示所有服务器
+	Paths    []string `gorm:"-" json:"paths"`                                          // 允许读取的文件，支持通配符，如 /var/log/nginx/*.log，以 /** 结尾时包含整个目录
+	Units    []string `gorm:"-" json:"units"`                                          // 允许读取的 systemd 单元，支持通配符
+
+	PathsRaw string `gorm:"default:'[]'" json:"-"`
+	UnitsRaw string `gorm:"default:'[]'" json:"-"`
+}
+
+func (r *LogAccessRule) BeforeSave(tx *gorm.DB) error {
+	paths, err := json.Marshal(r.Paths)
+	if err != nil {
+		return err
+	}
+	units, err := json.Marshal(r.Units)
+	if err != nil {
+		return err
+	}
+	r.PathsRaw, r.UnitsRaw = string(paths), string(units)
+	return nil
+}
+
+func (r *LogAccessRule) AfterFind(tx *gorm.DB) error {
+	if err := json.Unmarshal([]byte(r.PathsRaw), &r.Paths); err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(r.UnitsRaw), &r.Units)
+}
+
+// AllowPath path 需先经过 CleanLogPath，允许读取某个文件时也允许搜索它的轮转文件，
+// 这里只检查路径字符串，经过符号链接的路径由 agent 拒绝
+func (r *LogAccessRule) AllowPath(p string) bool {
+	for _, pattern := range r.Paths {
+		if dir, ok := strings.CutSuffix(pattern, "/**"); ok {
+			if strings.HasPrefix(p, dir+"/") {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(pattern, p); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *LogAccessRule) AllowUnit(unit string) bool {
+	for _, pattern := range r.Units {
+		if ok, _ := path.Match(pattern, unit); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// CleanLogPath 只接受绝对路径，Windows 路径统一使用 / 分隔，清理后 .. 无法越过授权的目录
+func CleanLogPath(p string) (string, bool) {
+	var prefix string
+	if windowsDriveRe.MatchString(p) {
+		prefix, p = p[:2], strings.ReplaceAll(p[2:], `\`, "/")
+	}
+	if !strings.HasPrefix(p, "/") || strings.ContainsRune(p, 0) {
+		return "", false
+	}
+	return prefix + path.Clean(p), true
+}
+
+// ValidLogUnit 单元名称不能以 - 开头，避免被当作 journalctl 的参数
+func ValidLogUnit(unit string) bool {
+	return unit != "" && len(unit) <= 256 && !strings.HasPrefix(unit, "-") && logUnitRe.MatchString(unit)
+}
+
+type LogForm struct {
+	Mode   string `json:"mode"`                                 // follow, journal, search
+	Path   string `json:"path,omitempty" validate:"optional"`   // follow、search 时必填
+	Unit   string `json:"unit,omitempty" validate:"optional"`   // journal 时必填
+	Filter string `json:"filter,omitempty" validate:"optional"` // 只返回包含该字符串或匹配该正则的行
+	Regex  bool   `json:"regex,omitempty" validate:"optional"`  // filter 为正则表达式
+	Lines  int    `json:"lines,omitempty" validate:"optional"`  // 跟随前输出的历史行数，默认 100
+	Limit  int    `json:"limit,omitempty" validate:"optional"`  // search 返回的最大行数，默认 1000
+}
+
+type LogAccessRuleForm struct {
+	UserID   uint64   `json:"user_id,omitempty"`
+	ServerID uint64   `json:"server_id,omitempty" validate:"optional"` // 0 表示所有服务器
+	Paths    []string `json:"paths,omitempty" validate:"optional"`
+	Units    []string `json:"units,omitempty" validate:"optional"`
+}
+
+type CreateLogResponse struct {
+	SessionID string `json:"session_id,omitempty"`
+}
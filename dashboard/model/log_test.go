@@ -0,0 +1,62 @@
+package model
+
+import "testing"
+
+func TestCleanLogPath(t *testing.T) {
+	cases := []struct {
+		path string
+		want string
+		ok   bool
+	}{
+		{"/var/log/syslog", "/var/log/syslog", true},
+		{"/var/log/nginx/../../../etc/shadow", "/etc/shadow", true},
+		{"/var/log//nginx/./error.log", "/var/log/nginx/error.log", true},
+		{`C:\logs\app\..\app.log`, "C:/logs/app.log", true},
+		{"var/log/syslog", "", false},
+		{"", "", false},
+	}
+	for _, c := range cases {
+		got, ok := CleanLogPath(c.path)
+		if got != c.want || ok != c.ok {
+			t.Errorf("CleanLogPath(%q) = %q, %v, want %q, %v", c.path, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestLogAccessRule(t *testing.T) {
+	rule := LogAccessRule{
+		Paths: []string{"/var/log/nginx/*.log", "/srv/app/logs/**", "/var/log/syslog"},
+		Units: []string{"nginx.service", "app-*.service"},
+	}
+
+	paths := map[string]bool{
+		"/var/log/nginx/error.log":      true,
+		"/var/log/nginx/sub/error.log":  false,
+		"/var/log/syslog":               true,
+		"/var/log/auth.log":             false,
+		"/srv/app/logs/2024/01/app.log": true,
+		"/srv/app/logs":                 false,
+		"/srv/app/logs-old/app.log":     false,
+	}
+	for p, want := range paths {
+		cleaned, _ := CleanLogPath(p)
+		if got := rule.AllowPath(cleaned); got != want {
+			t.Errorf("AllowPath(%q) = %v, want %v", p, got, want)
+		}
+	}
+
+	units := map[string]bool{
+		"nginx.service":      true,
+		"app-worker.service": true,
+		"sshd.service":       false,
+	}
+	for u, want := range units {
+		if got := rule.AllowUnit(u); got != want {
+			t.Errorf("AllowUnit(%q) = %v, want %v", u, got, want)
+		}
+	}
+
+	if ValidLogUnit("--since=yesterday") || !ValidLogUnit("getty@tty1.service") {
+		t.Error("ValidLogUnit mismatch")
+	}
+}
@@ -0,0 +1,134 @@
+package model
+
+import (
+	"time"
+)
+
+// MetricPoint.Resolution，单位为秒，0 为原始数据
+const (
+	MetricResolutionRaw    = 0
+	MetricResolutionMinute = 60
+	MetricResolutionHour   = 3600
+	MetricResolutionDay    = 86400
+)
+
+// MetricPoint 主机状态的时间序列数据点，聚合数据点为时间段内的平均值，*Max 为最大值
+type MetricPoint struct {
+	ID         uint64 `gorm:"primaryKey" json:"-"`
+	ServerID   uint64 `gorm:"index:idx_metric_server_time,priority:1" json:"-"`
+	Resolution int    `gorm:"index:idx_metric_server_time,priority:2;index:idx_metric_time,priority:1" json:"-"`
+	Timestamp  int64  `gorm:"index:idx_metric_server_time,priority:3;index:idx_metric_time,priority:2" json:"timestamp"` // 时间段起点，Unix 秒
+	Samples    uint64 `json:"samples"`                                                                                   // 聚合的原始数据点数量
+
+	CPU            float64 `json:"cpu"`
+	CPUMax         float64 `json:"cpu_max"`
+	MemUsed        float64 `json:"mem_used"`
+	MemUsedMax     float64 `json:"mem_used_max"`
+	SwapUsed       float64 `json:"swap_used"`
+	DiskUsed       float64 `json:"disk_used"`
+	Load1          float64 `json:"load_1"`
+	Load5          float64 `json:"load_5"`
+	Load15         float64 `json:"load_15"`
+	NetInSpeed     float64 `json:"net_in_speed"`
+	NetInSpeedMax  float64 `json:"net_in_speed_max"`
+	NetOutSpeed    float64 `json:"net_out_speed"`
+	NetOutSpeedMax float64 `json:"net_out_speed_max"`
+	TcpConnCount   float64 `json:"tcp_conn_count"`
+	UdpConnCount   float64 `json:"udp_conn_count"`
+	ProcessCount   float64 `json:"process_count"`
+	Temperature    float64 `json:"temperature"` // 各传感器中的最高温度
+	TemperatureMax float64 `json:"temperature_max"`
+	GPU            float64 `json:"gpu"` // 各 GPU 的平均使用率
+	GPUMax         float64 `json:"gpu_max"`
+}
+
+func NewMetricPoint(serverID uint64, s *HostState, at time.Time) *MetricPoint {
+	p := &MetricPoint{
+		ServerID:     serverID,
+		Resolution:   MetricResolutionRaw,
+		Timestamp:    at.Unix(),
+		Samples:      1,
+		CPU:          s.CPU,
+		MemUsed:      float64(s.MemUsed),
+		SwapUsed:     float64(s.SwapUsed),
+		DiskUsed:     float64(s.DiskUsed),
+		Load1:        s.Load1,
+		Load5:        s.Load5,
+		Load15:       s.Load15,
+		NetInSpeed:   float64(s.NetInSpeed),
+		NetOutSpeed:  float64(s.NetOutSpeed),
+		TcpConnCount: float64(s.TcpConnCount),
+		UdpConnCount: float64(s.UdpConnCount),
+		ProcessCount: float64(s.ProcessCount),
+	}
+	for _, t := range s.Temperatures {
+		p.Temperature = max(p.Temperature, t.Temperature)
+	}
+	if len(s.GPU) > 0 {
+		for _, g := range s.GPU {
+			p.GPU += g
+		}
+		p.GPU /= float64(len(s.GPU))
+	}
+
+	p.CPUMax = p.CPU
+	p.MemUsedMax = p.MemUsed
+	p.NetInSpeedMax = p.NetInSpeed
+	p.NetOutSpeedMax = p.NetOutSpeed
+	p.TemperatureMax = p.Temperature
+	p.GPUMax = p.GPU
+	return p
+}
+
+// StateSample agent 与面板断开期间缓存、重连后补传的状态
+type StateSample struct {
+	State HostState
+	At    time.Time
+}
+
+// MetricSeries 指标范围查询的结果
+type MetricSeries struct {
+	ServerID   uint64         `json:"server_id"`
+	Resolution string         `json:"resolution"` // raw、1m、1h 或 1d
+	Start      int64          `json:"start"`
+	End        int64          `json:"end"`
+	Points     []*MetricPoint `json:"points"`
+}
+
+// CustomMetricPoint 自定义指标的时间序列数据点，counter 保存的是累计值
+type CustomMetricPoint struct {
+	ID         uint64  `gorm:"primaryKey" json:"-"`
+	ServerID   uint64  `gorm:"index:idx_custom_metric_server_name_time,priority:1" json:"-"`
+	Name       string  `gorm:"index:idx_custom_metric_server_name_time,priority:2" json:"-"`
+	Resolution int     `gorm:"index:idx_custom_metric_server_name_time,priority:3;index:idx_custom_metric_time,priority:1" json:"-"`
+	Timestamp  int64   `gorm:"index:idx_custom_metric_server_name_time,priority:4;index:idx_custom_metric_time,priority:2" json:"timestamp"`
+	Samples    uint64  `json:"samples"`
+	Value      float64 `json:"value"`
+	ValueMax   float64 `json:"value_max"`
+}
+
+func NewCustomMetricPoints(serverID uint64, s *HostState, at time.Time) []*CustomMetricPoint {
+	points := make([]*CustomMetricPoint, 0, len(s.CustomMetrics))
+	for _, m := range s.CustomMetrics {
+		points = append(points, &CustomMetricPoint{
+			ServerID:   serverID,
+			Name:       m.Name,
+			Resolution: MetricResolutionRaw,
+			Timestamp:  at.Unix(),
+			Samples:    1,
+			Value:      m.Value,
+			ValueMax:   m.Value,
+		})
+	}
+	return points
+}
+
+// CustomMetricSeries 自定义指标范围查询的结果
+type CustomMetricSeries struct {
+	ServerID   uint64               `json:"server_id"`
+	Name       string               `json:"name"`
+	Resolution string               `json:"resolution"`
+	Start      int64                `json:"start"`
+	End        int64                `json:"end"`
+	Points     []*CustomMetricPoint `json:"points"`
+}
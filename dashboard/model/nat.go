@@ -1,5 +1,29 @@
 package model
 
+import (
+	"log"
+	"time"
+
+	"github.com/goccy/go-json"
+	"gorm.io/gorm"
+)
+
+const (
+	NATCountryFilterAllow = "allow" // 仅允许列表中的国家/地区
+	NATCountryFilterDeny  = "deny"  // 拒绝列表中的国家/地区
+)
+
+// NATRequestLog.Denied
+const (
+	NATDeniedSource    = "source"
+	NATDeniedCountry   = "country"
+	NATDeniedBasicAuth = "basic_auth"
+	NATDeniedLogin     = "login"
+)
+
+// 来源白名单的最大条目数
+const NATMaxAllowedSources = 64
+
 type NAT struct {
 	Common
 	Enabled  bool   `json:"enabled"`
@@ -7,4 +31,49 @@ type NAT struct {
 	ServerID uint64 `json:"server_id"`
 	Host     string `json:"host"`
 	Domain   string `json:"domain" gorm:"unique"`
+
+	RateLimit uint64 `json:"rate_limit,omitempty"` // 每个方向的限速（字节/秒），0 为不限速
+
+	// 访问控制，按来源、国家、Basic Auth、面板登录的顺序检查
+	RequireLogin      bool     `json:"require_login,omitempty"` // 要求登录面板且有权限访问此 NAT
+	BasicAuthUsername string   `json:"basic_auth_username,omitempty"`
+	BasicAuthPassword string   `json:"-"` // bcrypt 哈希
+	AllowedSourcesRaw string   `gorm:"default:'[]'" json:"-"`
+	AllowedSources    []string `gorm:"-" json:"allowed_sources,omitempty"` // 允许访问的来源 CIDR，为空时不限制
+	CountryFilterMode string   `json:"country_filter_mode,omitempty"`      // allow 或 deny，为空时不过滤
+	CountriesRaw      string   `gorm:"default:'[]'" json:"-"`
+	Countries         []string `gorm:"-" json:"countries,omitempty"` // 小写的国家/地区代码
+
+	Traffic *TunnelTraffic `json:"traffic,omitempty" gorm:"-"`
+}
+
+func (n *NAT) AfterFind(tx *gorm.DB) error {
+	if n.AllowedSourcesRaw != "" {
+		if err := json.Unmarshal([]byte(n.AllowedSourcesRaw), &n.AllowedSources); err != nil {
+			log.Println("NEZHA>> NAT.AfterFind:", err)
+			return nil
+		}
+	}
+	if n.CountriesRaw != "" {
+		if err := json.Unmarshal([]byte(n.CountriesRaw), &n.Countries); err != nil {
+			log.Println("NEZHA>> NAT.AfterFind:", err)
+			return nil
+		}
+	}
+	return nil
+}
+
+// NATRequestLog 经 NAT 转发或被拒绝的请求
+type NATRequestLog struct {
+	ID        uint64    `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+	NATID     uint64    `gorm:"index" json:"nat_id"`
+	UserID    uint64    `json:"user_id,omitempty"` // 通过面板登录访问时的用户
+	ClientIP  string    `json:"client_ip"`
+	Country   string    `json:"country,omitempty"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Status    int       `json:"status"`                // 被拒绝时为面板返回的状态码，转发时为目标返回的状态码
+	Denied    string    `json:"denied,omitempty"`      // 拒绝原因：source、country、basic_auth、login
+	Duration  int64     `json:"duration_ms,omitempty"` // 毫秒
 }
@@ -6,4 +6,13 @@ type NATForm struct {
 	ServerID uint64 `json:"server_id,omitempty"`
 	Host     string `json:"host,omitempty"`
 	Domain   string `json:"domain,omitempty"`
+
+	RateLimit uint64 `json:"rate_limit,omitempty"`
+
+	RequireLogin      bool     `json:"require_login,omitempty"`
+	BasicAuthUsername string   `json:"basic_auth_username,omitempty"`
+	BasicAuthPassword string   `json:"basic_auth_password,omitempty"` // 用户名不变时留空表示不修改密码
+	AllowedSources    []string `json:"allowed_sources,omitempty"`
+	CountryFilterMode string   `json:"country_filter_mode,omitempty" enums:"allow,deny"`
+	Countries         []string `json:"countries,omitempty"`
 }
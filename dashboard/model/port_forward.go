@@ -0,0 +1,34 @@
+package model
+
+const (
+	PortForwardNetworkTCP = "tcp"
+	PortForwardNetworkUDP = "udp"
+)
+
+// PortForward 面板监听 TCP/UDP 端口，每个连接通过 IOStream 转发到 agent 可访问的目标
+type PortForward struct {
+	Common
+	Name       string `json:"name"`
+	Enabled    bool   `json:"enabled"`
+	ServerID   uint64 `json:"server_id"`
+	Network    string `json:"network" gorm:"uniqueIndex:idx_port_forward_listen"` // tcp 或 udp
+	ListenPort int    `json:"listen_port" gorm:"uniqueIndex:idx_port_forward_listen"`
+	Target     string `json:"target"` // agent 可访问的 host:port
+
+	RateLimit uint64 `json:"rate_limit,omitempty"` // 每个方向的限速（字节/秒），0 为不限速
+
+	Listening   bool           `json:"listening" gorm:"-"`
+	ListenError string         `json:"listen_error,omitempty" gorm:"-"`
+	Traffic     *TunnelTraffic `json:"traffic,omitempty" gorm:"-"`
+}
+
+type PortForwardForm struct {
+	Name       string `json:"name" minLength:"1"`
+	Enabled    bool   `json:"enabled,omitempty"`
+	ServerID   uint64 `json:"server_id"`
+	Network    string `json:"network" enums:"tcp,udp"`
+	ListenPort int    `json:"listen_port"`
+	Target     string `json:"target"`
+
+	RateLimit uint64 `json:"rate_limit,omitempty"`
+}
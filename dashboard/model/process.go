@@ -0,0 +1,54 @@
+package model
+
+import "strings"
+
+// TaskProcess.Action
+const (
+	ProcessActionList   = "list"
+	ProcessActionSignal = "signal"
+)
+
+// TaskProcess.Sort
+const (
+	ProcessSortCPU    = "cpu"
+	ProcessSortMemory = "memory"
+)
+
+const ProcessMaxLimit = 100
+
+type TaskProcess struct {
+	Action string `json:"action"`
+	Sort   string `json:"sort,omitempty"`  // cpu 或 memory
+	Limit  int    `json:"limit,omitempty"` // list 返回的最大进程数
+	PID    int32  `json:"pid,omitempty"`
+	Signal string `json:"signal,omitempty"` // 如 TERM、KILL
+}
+
+// Process CPU 为 agent 采样间隔内的占用率，单核满载为 100
+type Process struct {
+	PID       int32   `json:"pid"`
+	PPID      int32   `json:"ppid"`
+	Name      string  `json:"name"`
+	User      string  `json:"user"`
+	Cmdline   string  `json:"cmdline"`
+	RSS       uint64  `json:"rss"`
+	CPU       float64 `json:"cpu"`
+	StartTime int64   `json:"start_time"`
+}
+
+type ProcessSignalForm struct {
+	Signal string `json:"signal"` // HUP, INT, QUIT, KILL, TERM, USR1, USR2, STOP, CONT
+}
+
+var processSignals = []string{"HUP", "INT", "QUIT", "KILL", "TERM", "USR1", "USR2", "STOP", "CONT"}
+
+// NormalizeSignal 去掉 SIG 前缀并转为大写，不支持的信号返回空字符串
+func NormalizeSignal(name string) string {
+	name = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(name)), "SIG")
+	for _, s := range processSignals {
+		if s == name {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,12 @@
+package model
+
+import "testing"
+
+func TestNormalizeSignal(t *testing.T) {
+	cases := map[string]string{"TERM": "TERM", "sigkill": "KILL", " hup ": "HUP", "WINCH": "", "": ""}
+	for in, want := range cases {
+		if got := NormalizeSignal(in); got != want {
+			t.Errorf("NormalizeSignal(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -23,7 +23,14 @@ type Rule struct {
 	// 指标类型，cpu、memory、swap、disk、net_in_speed、net_out_speed
 	// net_all_speed、transfer_in、transfer_out、transfer_all、offline
 	// transfer_in_cycle、transfer_out_cycle、transfer_all_cycle
+	// tunnel_in_speed、tunnel_out_speed、tunnel_all_speed
+	// container_restart（10 分钟内的容器重启次数）、container_unhealthy（不健康的容器数）
+	// kube_crash_loop（CrashLoopBackOff 的 Pod 数）、kube_node_not_ready（未就绪的节点数）、kube_pod_pending（Pending 的 Pod 数）
+	// mount、mount_inode（挂载点空间与 inode 使用率）、disk_read_speed、disk_write_speed（块设备读写速度）
+	// nic_in_speed、nic_out_speed、nic_errors（网卡速度与每秒错误数）
+	// custom（agent 自定义采集器上报的指标）、systemd_failed（失败的 systemd 单元数）
 	Type          string          `json:"type"`
+	Target        string          `json:"target,omitempty" validate:"optional"`                                                     // mount、disk_*、nic_* 规则指定的挂载点、设备或网卡，为空时取最大值；custom 规则指定的指标名称；systemd_failed 规则指定的单元
 	Min           float64         `json:"min,omitempty" validate:"optional"`                                                        // 最小阈值 (百分比、字节 kb ÷ 1024)
 	Max           float64         `json:"max,omitempty" validate:"optional"`                                                        // 最大阈值 (百分比、字节 kb ÷ 1024)
 	CycleStart    *time.Time      `json:"cycle_start,omitempty" validate:"optional"`                                                // 流量统计的开始时间
@@ -80,6 +87,12 @@ func (u *Rule) Snapshot(cycleTransferStats *CycleTransferStats, server *Server,
 		src = float64(server.State.NetOutSpeed)
 	case "net_all_speed":
 		src = float64(server.State.NetOutSpeed + server.State.NetOutSpeed)
+	case "tunnel_in_speed":
+		src = float64(server.TunnelInSpeed)
+	case "tunnel_out_speed":
+		src = float64(server.TunnelOutSpeed)
+	case "tunnel_all_speed":
+		src = float64(server.TunnelInSpeed + server.TunnelOutSpeed)
 	case "transfer_in":
 		src = float64(server.State.NetInTransfer)
 	case "transfer_out":
@@ -125,6 +138,38 @@ func (u *Rule) Snapshot(cycleTransferStats *CycleTransferStats, server *Server,
 		src = float64(server.State.UdpConnCount)
 	case "process_count":
 		src = float64(server.State.ProcessCount)
+	case "container_restart":
+		if server.Docker != nil {
+			src = float64(server.Docker.RecentRestarts(time.Now()))
+		}
+	case "container_unhealthy":
+		if server.Docker != nil {
+			src = float64(server.Docker.UnhealthyCount())
+		}
+	case "kube_crash_loop":
+		if server.Kubernetes != nil {
+			src = float64(server.Kubernetes.CrashLoopCount())
+		}
+	case "kube_node_not_ready":
+		if server.Kubernetes != nil {
+			src = float64(server.Kubernetes.NotReadyNodeCount())
+		}
+	case "kube_pod_pending":
+		if server.Kubernetes != nil {
+			src = float64(server.Kubernetes.PendingPodCount())
+		}
+	case "mount", "mount_inode", "disk_read_speed", "disk_write_speed", "nic_in_speed", "nic_out_speed", "nic_errors":
+		if server.State != nil {
+			src = u.breakdownValue(server.State)
+		}
+	case "systemd_failed":
+		if server.Systemd != nil {
+			src = float64(server.Systemd.FailedCount(u.Target))
+		}
+	case "custom":
+		if server.State != nil {
+			src = u.customValue(server.State)
+		}
 	case "temperature_max":
 		var temp []float64
 		if server.State.Temperatures != nil {
@@ -171,6 +216,67 @@ func (u *Rule) Snapshot(cycleTransferStats *CycleTransferStats, server *Server,
 	return true
 }
 
+// breakdownValue 取 Target 指定的挂载点、设备或网卡的值，未指定时取所有对象中的最大值
+func (u *Rule) breakdownValue(state *HostState) float64 {
+	var src float64
+	switch u.Type {
+	case "mount", "mount_inode":
+		for _, m := range state.Mounts {
+			if u.Target != "" && m.Mountpoint != u.Target {
+				continue
+			}
+			if u.Type == "mount" {
+				src = max(src, percentage(m.Used, m.Total))
+			} else {
+				src = max(src, percentage(m.InodesUsed, m.InodesTotal))
+			}
+		}
+	case "disk_read_speed", "disk_write_speed":
+		for _, d := range state.DiskIO {
+			if u.Target != "" && d.Device != u.Target {
+				continue
+			}
+			if u.Type == "disk_read_speed" {
+				src = max(src, float64(d.ReadSpeed))
+			} else {
+				src = max(src, float64(d.WriteSpeed))
+			}
+		}
+	case "nic_in_speed", "nic_out_speed", "nic_errors":
+		for _, n := range state.NICs {
+			if u.Target != "" && n.Name != u.Target {
+				continue
+			}
+			switch u.Type {
+			case "nic_in_speed":
+				src = max(src, float64(n.InSpeed))
+			case "nic_out_speed":
+				src = max(src, float64(n.OutSpeed))
+			default:
+				src = max(src, n.ErrorRate)
+			}
+		}
+	}
+	return src
+}
+
+// customValue Target 带标签时精确匹配，不带标签时取同名指标中的最大值
+func (u *Rule) customValue(state *HostState) float64 {
+	var src float64
+	var found bool
+	exact := strings.Contains(u.Target, "{")
+	for _, m := range state.CustomMetrics {
+		if (exact && m.Name != u.Target) || (!exact && m.BaseName() != u.Target) {
+			continue
+		}
+		if !found || m.Value > src {
+			src = m.Value
+		}
+		found = true
+	}
+	return src
+}
+
 // IsTransferDurationRule 判断该规则是否属于周期流量规则 属于则返回true
 func (u *Rule) IsTransferDurationRule() bool {
 	return strings.HasSuffix(u.Type, "_cycle")
@@ -37,6 +37,13 @@ type Server struct {
 
 	PrevTransferInSnapshot  uint64 `gorm:"-" json:"-"` // 上次数据点时的入站使用量
 	PrevTransferOutSnapshot uint64 `gorm:"-" json:"-"` // 上次数据点时的出站使用量
+
+	TunnelInSpeed  uint64 `gorm:"-" json:"-"` // 服务器上 AutoSSH 映射与 NAT 的总入站速率
+	TunnelOutSpeed uint64 `gorm:"-" json:"-"`
+
+	Docker     *DockerState  `gorm:"-" json:"-"` // 最近一次上报的容器状态，未运行 Docker 时为空
+	Kubernetes *KubeCluster  `gorm:"-" json:"-"` // 最近一次上报的集群状态，未开启采集时为空
+	Systemd    *SystemdState `gorm:"-" json:"-"` // 最近一次上报的 systemd 单元状态，非 systemd 主机为空
 }
 
 func InitServer(s *Server) {
@@ -55,6 +62,11 @@ func (s *Server) CopyFromRunningServer(old *Server) {
 	s.ConfigCache = old.ConfigCache
 	s.PrevTransferInSnapshot = old.PrevTransferInSnapshot
 	s.PrevTransferOutSnapshot = old.PrevTransferOutSnapshot
+	s.TunnelInSpeed = old.TunnelInSpeed
+	s.TunnelOutSpeed = old.TunnelOutSpeed
+	s.Docker = old.Docker
+	s.Kubernetes = old.Kubernetes
+	s.Systemd = old.Systemd
 }
 
 func (s *Server) AfterFind(tx *gorm.DB) error {
@@ -29,15 +29,22 @@ const (
 	TaskTypeAutoSSH
 	TaskTypeTerminalCommand
 	TaskTypeCommandCheck
+	TaskTypeDocker
+	TaskTypeProcess
+	TaskTypeSystemd
+	TaskTypeLog
 )
 
 type TerminalTask struct {
-	StreamID string
+	StreamID    string
+	OSUser      string // 以该系统用户身份启动终端，为空时使用 Agent 运行用户
+	ContainerID string `json:",omitempty"` // 不为空时在该容器内打开终端
 }
 
 type TaskNAT struct {
 	StreamID string
 	Host     string
+	Network  string `json:",omitempty"` // 为空时转发 HTTP 请求，tcp/udp 时为端口转发
 }
 
 type TaskFM struct {
@@ -136,7 +143,8 @@ func IsServiceSentinelNeeded(t uint64) bool {
 	case TaskTypeCommand, TaskTypeTerminalGRPC, TaskTypeUpgrade,
 		TaskTypeKeepalive, TaskTypeNAT, TaskTypeFM,
 		TaskTypeReportConfig, TaskTypeApplyConfig, TaskTypeAutoSSH,
-		TaskTypeTerminalCommand, TaskTypeCommandCheck:
+		TaskTypeTerminalCommand, TaskTypeCommandCheck, TaskTypeDocker, TaskTypeProcess,
+		TaskTypeSystemd, TaskTypeLog:
 		return false
 	default:
 		return true
@@ -3,12 +3,15 @@ package model
 type SettingForm struct {
 	DNSServers                  string `json:"dns_servers,omitempty" validate:"optional"`
 	AutoSSHHost                 string `json:"autossh_host,omitempty" validate:"optional"` // AutoSSH服务器地址
+	AutoSSHHostKey              string `json:"autossh_host_key,omitempty" validate:"optional"` // AutoSSH服务器主机密钥指纹，为空时自动记录
+	AutoSSHNotificationGroupID  uint64 `json:"autossh_notification_group_id,omitempty"`        // AutoSSH异常的通知组
 	IgnoredIPNotification       string `json:"ignored_ip_notification,omitempty" validate:"optional"`
 	IPChangeNotificationGroupID uint64 `json:"ip_change_notification_group_id,omitempty"` // IP变更提醒的通知组
 	Cover                       uint8  `json:"cover,omitempty"`
 	SiteName                    string `json:"site_name,omitempty" minLength:"1"`
 	Language                    string `json:"language,omitempty" minLength:"2"`
 	InstallHost                 string `json:"install_host,omitempty" validate:"optional"`
+	SiteURL                     string `json:"site_url,omitempty" validate:"optional"` // 面板访问地址
 	CustomCode                  string `json:"custom_code,omitempty" validate:"optional"`
 	CustomCodeDashboard         string `json:"custom_code_dashboard,omitempty" validate:"optional"`
 	WebRealIPHeader                string `json:"web_real_ip_header,omitempty" validate:"optional"` // 前端真实IP
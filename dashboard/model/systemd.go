@@ -0,0 +1,89 @@
+package model
+
+import (
+	"time"
+
+	pb "github.com/nezhahq/nezha/proto"
+)
+
+// TaskSystemd.Action
+const (
+	SystemdActionStart   = "start"
+	SystemdActionStop    = "stop"
+	SystemdActionRestart = "restart"
+	SystemdActionEnable  = "enable"
+	SystemdActionDisable = "disable"
+)
+
+const SystemdActiveFailed = "failed"
+
+type TaskSystemd struct {
+	Action string `json:"action"`
+	Unit   string `json:"unit"`
+}
+
+type SystemdUnit struct {
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	LoadState     string `json:"load_state"`   // loaded, not-found, masked
+	ActiveState   string `json:"active_state"` // active, inactive, failed, activating, deactivating
+	SubState      string `json:"sub_state"`
+	UnitFileState string `json:"unit_file_state,omitempty"` // enabled, disabled, static, masked 等
+}
+
+// SystemdState agent 最近一次上报的 systemd 单元状态
+type SystemdState struct {
+	UpdatedAt time.Time      `json:"updated_at"`
+	Error     string         `json:"error,omitempty"`
+	Units     []*SystemdUnit `json:"units"`
+}
+
+type SystemdActionForm struct {
+	Action string `json:"action"` // start, stop, restart, enable, disable
+}
+
+func ValidSystemdAction(action string) bool {
+	switch action {
+	case SystemdActionStart, SystemdActionStop, SystemdActionRestart, SystemdActionEnable, SystemdActionDisable:
+		return true
+	}
+	return false
+}
+
+func (s *SystemdState) Unit(name string) *SystemdUnit {
+	for _, u := range s.Units {
+		if u.Name == name {
+			return u
+		}
+	}
+	return nil
+}
+
+// FailedCount 统计失败的单元数，target 不为空时只统计该单元
+func (s *SystemdState) FailedCount(target string) int {
+	var n int
+	for _, u := range s.Units {
+		if (target == "" || u.Name == target) && u.ActiveState == SystemdActiveFailed {
+			n++
+		}
+	}
+	return n
+}
+
+func PB2SystemdState(r *pb.SystemdReport) SystemdState {
+	state := SystemdState{
+		Error: r.GetError(),
+		Units: make([]*SystemdUnit, 0, len(r.GetUnits())),
+	}
+	for _, u := range r.GetUnits() {
+		state.Units = append(state.Units, &SystemdUnit{
+			Name:          u.GetName(),
+			Description:   u.GetDescription(),
+			LoadState:     u.GetLoadState(),
+			ActiveState:   u.GetActiveState(),
+			SubState:      u.GetSubState(),
+			UnitFileState: u.GetUnitFileState(),
+		})
+	}
+	return state
+}
@@ -1,12 +1,45 @@
 package model
 
+import "regexp"
+
+var osUserRegexp = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,31}$`)
+
+// IsValidOSUser 判断系统用户名是否合法
+func IsValidOSUser(name string) bool {
+	return osUserRegexp.MatchString(name)
+}
+
 type TerminalForm struct {
 	Protocol string `json:"protocol,omitempty"`
 	ServerID uint64 `json:"server_id,omitempty"`
+	OSUser   string `json:"os_user,omitempty" validate:"optional"` // 以指定系统用户身份打开终端
+
+	ContainerID string `json:"container_id,omitempty" validate:"optional"` // 在该容器内打开终端，仅管理员可用
+}
+
+type TerminalBroadcastForm struct {
+	ServerIDs     []uint64 `json:"server_ids,omitempty" validate:"optional"`
+	ServerGroupID uint64   `json:"server_group_id,omitempty" validate:"optional"` // 加入该分组内的所有服务器
+	OSUser        string   `json:"os_user,omitempty" validate:"optional"`
+}
+
+type TerminalUserMappingForm struct {
+	UserID   uint64 `json:"user_id,omitempty"`
+	ServerID uint64 `json:"server_id,omitempty" validate:"optional"` // 0 表示所有服务器
+	OSUser   string `json:"os_user,omitempty"`
 }
 
 type CreateTerminalResponse struct {
 	SessionID  string `json:"session_id,omitempty"`
 	ServerID   uint64 `json:"server_id,omitempty"`
 	ServerName string `json:"server_name,omitempty"`
+	OSUser     string `json:"os_user,omitempty"`
+
+	ContainerID string `json:"container_id,omitempty"`
+}
+
+type CreateTerminalBroadcastResponse struct {
+	BroadcastID string                    `json:"broadcast_id,omitempty"`
+	Sessions    []*CreateTerminalResponse `json:"sessions,omitempty"`
+	Failure     []uint64                  `json:"failure,omitempty" validate:"optional"` // 未能打开终端的服务器
 }
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 // CommandCheckRequest represents a request to check if a command should be blocked
 type CommandCheckRequest struct {
 	StreamID   string `json:"stream_id"`
@@ -13,3 +15,14 @@ type CommandCheckResponse struct {
 	Reason  string `json:"reason,omitempty"`
 	Action  string `json:"action,omitempty"` // block/warn/log
 }
+
+// TransferRecordRequest represents a file transfer reported by the agent
+type TransferRecordRequest struct {
+	StreamID  string    `json:"stream_id"`
+	Protocol  string    `json:"protocol"`
+	Direction string    `json:"direction"`
+	Bytes     int64     `json:"bytes"`
+	StartedAt time.Time `json:"started_at"`
+	EndedAt   time.Time `json:"ended_at"`
+	Aborted   bool      `json:"aborted"`
+}
@@ -16,6 +16,13 @@ type TerminalSession struct {
 	CommandCount     int        `json:"command_count"`
 	RecordingPath    string     `json:"recording_path,omitempty"`
 	RecordingEnabled bool       `json:"recording_enabled"`
+	OSUser           string     `json:"os_user,omitempty"`                   // 终端登录的系统用户，为空表示 Agent 运行用户
+	BroadcastID      string     `json:"broadcast_id,omitempty" gorm:"index"` // 所属的多服务器广播终端
+	DetachedAt       *time.Time `json:"detached_at,omitempty"`               // 浏览器断开、等待重连的时间
+	ReattachCount    int        `json:"reattach_count"`
+
+	ContainerID   string `json:"container_id,omitempty"` // 容器终端的容器 ID
+	ContainerName string `json:"container_name,omitempty"`
 }
 
 // TerminalCommand 终端命令执行记录
@@ -32,6 +39,22 @@ type TerminalCommand struct {
 	BlockReason string    `json:"block_reason,omitempty"`
 }
 
+// TerminalTransfer 终端内 trzsz / ZMODEM 文件传输记录
+type TerminalTransfer struct {
+	Common
+	SessionID uint64    `json:"session_id" gorm:"index"`
+	UserID    uint64    `json:"user_id" gorm:"index"`
+	ServerID  uint64    `json:"server_id" gorm:"index"`
+	Protocol  string    `json:"protocol"`  // zmodem/trzsz
+	Direction string    `json:"direction"` // upload/download
+	Bytes     int64     `json:"bytes"`
+	StartedAt time.Time `json:"started_at" gorm:"index"`
+	EndedAt   time.Time `json:"ended_at"`
+	Aborted   bool      `json:"aborted"`
+	FileName  string    `json:"file_name,omitempty"`
+	FilePath  string    `json:"-"` // 面板接收的 ZMODEM 下载保存的位置
+}
+
 // TerminalBlacklist 终端命令黑名单
 type TerminalBlacklist struct {
 	Common
@@ -43,6 +66,14 @@ type TerminalBlacklist struct {
 	CreatedAt   time.Time `json:"created_at"`
 }
 
+// TerminalUserMapping 面板用户在服务器上打开终端时使用的系统用户
+type TerminalUserMapping struct {
+	Common
+	UserID   uint64 `json:"user_id" gorm:"uniqueIndex:idx_terminal_user_server"`
+	ServerID uint64 `json:"server_id" gorm:"uniqueIndex:idx_terminal_user_server"` // 0 表示所有服务器
+	OSUser   string `json:"os_user"`
+}
+
 // TerminalAuditConfig 终端审计配置
 type TerminalAuditConfig struct {
 	RecordingEnabled   bool   `json:"recording_enabled"`
@@ -0,0 +1,27 @@
+package model
+
+const (
+	TunnelKindAutoSSH     = "autossh"
+	TunnelKindNAT         = "nat"
+	TunnelKindPortForward = "port_forward"
+)
+
+// TunnelTransfer 隧道每小时的流量与连接数
+type TunnelTransfer struct {
+	Common
+	Kind        string `json:"kind" gorm:"index:idx_tunnel_transfer"`
+	TunnelID    uint64 `json:"tunnel_id" gorm:"index:idx_tunnel_transfer"`
+	ServerID    uint64 `json:"server_id"`
+	In          uint64 `json:"in"`
+	Out         uint64 `json:"out"`
+	Connections uint64 `json:"connections"`
+}
+
+// TunnelTraffic 隧道自面板启动以来的实时流量
+type TunnelTraffic struct {
+	In          uint64 `json:"in"`
+	Out         uint64 `json:"out"`
+	Connections uint64 `json:"connections"`
+	InSpeed     uint64 `json:"in_speed"`
+	OutSpeed    uint64 `json:"out_speed"`
+}
@@ -6,14 +6,25 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"strings"
+	"sync"
 )
 
 var _ io.ReadWriteCloser = (*RequestWrapper)(nil)
 
+// RequestWrapper 接管客户端连接，只转发这一个请求。
+// 请求以 Connection: close 发出，之后客户端在同一连接上发送的数据被丢弃，
+// 只有协议升级成功（101）之后才继续转发客户端的数据
 type RequestWrapper struct {
 	req    *http.Request
 	reader *bytes.Buffer
 	writer net.Conn
+
+	upgrade  bool
+	head     []byte
+	headOnce sync.Once
+	headCh   chan struct{}
+	upgraded bool
 }
 
 func NewRequestWrapper(req *http.Request, writer http.ResponseWriter) (*RequestWrapper, error) {
@@ -25,14 +36,22 @@ func NewRequestWrapper(req *http.Request, writer http.ResponseWriter) (*RequestW
 	if err != nil {
 		return nil, err
 	}
+
+	upgrade := req.Header.Get("Upgrade") != "" && headerHasToken(req.Header, "Connection", "upgrade")
+	if !upgrade {
+		req.Close = true
+	}
 	buf := bytes.NewBuffer(nil)
 	if err = req.Write(buf); err != nil {
+		conn.Close()
 		return nil, err
 	}
 	return &RequestWrapper{
-		req:    req,
-		reader: buf,
-		writer: conn,
+		req:     req,
+		reader:  buf,
+		writer:  conn,
+		upgrade: upgrade,
+		headCh:  make(chan struct{}),
 	}, nil
 }
 
@@ -44,16 +63,47 @@ func (rw *RequestWrapper) Read(p []byte) (int, error) {
 	if err != io.EOF {
 		return count, err
 	}
-	// request 数据读完之后等待客户端断开连接或 grpc 超时
-	return rw.writer.Read(p)
+
+	if rw.upgrade {
+		<-rw.headCh
+		if rw.upgraded {
+			return rw.writer.Read(p)
+		}
+	}
+	// request 数据读完之后丢弃客户端的后续数据，等待客户端断开连接或 grpc 超时
+	for {
+		if _, err := rw.writer.Read(p); err != nil {
+			return 0, err
+		}
+	}
 }
 
 func (rw *RequestWrapper) Write(p []byte) (int, error) {
+	if rw.upgrade && len(rw.head) < 12 {
+		rw.head = append(rw.head, p[:min(len(p), 12-len(rw.head))]...)
+		// HTTP/1.1 101
+		if len(rw.head) == 12 {
+			rw.upgraded = strings.HasPrefix(string(rw.head), "HTTP/") && string(rw.head[9:12]) == "101"
+			rw.headOnce.Do(func() { close(rw.headCh) })
+		}
+	}
 	return rw.writer.Write(p)
 }
 
 func (rw *RequestWrapper) Close() error {
+	rw.headOnce.Do(func() { close(rw.headCh) })
 	rw.req.Body.Close()
 	rw.writer.Close()
 	return nil
 }
+
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
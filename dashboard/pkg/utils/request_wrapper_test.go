@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestWrapperSingleRequest(t *testing.T) {
+	type result struct {
+		req  *http.Request
+		rest []byte
+		err  error
+	}
+	resultCh := make(chan result, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw, err := NewRequestWrapper(r, w)
+		if err != nil {
+			resultCh <- result{err: err}
+			return
+		}
+		defer rw.Close()
+
+		// 模拟内网服务读取转发的数据
+		br := bufio.NewReader(rw)
+		req, err := http.ReadRequest(br)
+		if err != nil {
+			resultCh <- result{err: err}
+			return
+		}
+		rw.Write([]byte("HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nok"))
+		rest, _ := io.ReadAll(br)
+		resultCh <- result{req: req, rest: rest}
+	}))
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// 同一连接上连续发送两个请求，第二个请求不能被转发
+	if _, err := conn.Write([]byte("GET /first HTTP/1.1\r\nHost: a\r\n\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if _, err := conn.Write([]byte("GET /second HTTP/1.1\r\nHost: a\r\nAuthorization: Basic YTpi\r\n\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	conn.(*net.TCPConn).CloseWrite()
+
+	res := <-resultCh
+	if res.err != nil {
+		t.Fatal(res.err)
+	}
+	if res.req.URL.Path != "/first" || !res.req.Close {
+		t.Errorf("unexpected forwarded request: %s, close %v", res.req.URL.Path, res.req.Close)
+	}
+	if len(res.rest) != 0 {
+		t.Errorf("data after the first request was forwarded: %q", res.rest)
+	}
+}
@@ -0,0 +1,456 @@
+// Package zmodem 以接收方身份处理终端中 sz 发起的 ZMODEM 下载
+package zmodem
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"hash/crc32"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	zpad = '*'
+	zdle = 0x18
+	xon  = 0x11
+	xoff = 0x13
+
+	zcrce = 'h' // 子包结束，之后是头部
+	zcrcg = 'i' // 子包结束，后面还有子包，不需要应答
+	zcrcq = 'j' // 子包结束，后面还有子包，需要 ZACK
+	zcrcw = 'k' // 子包结束，之后是头部，需要 ZACK
+	zrub0 = 'l'
+	zrub1 = 'm'
+)
+
+// 帧类型
+const (
+	ZRQINIT = iota
+	ZRINIT
+	ZSINIT
+	ZACK
+	ZFILE
+	ZSKIP
+	ZNAK
+	ZABORT
+	ZFIN
+	ZRPOS
+	ZDATA
+	ZEOF
+	ZFERR
+	ZCRC
+	ZCHALLENGE
+	ZCOMPL
+	ZCAN
+	ZFREECNT
+	ZCOMMAND
+	ZSTDERR
+)
+
+// ZRINIT 的能力标志：全双工、可同时收发、支持 32 位 CRC
+const receiverFlags = 0x01 | 0x02 | 0x20
+
+const (
+	// 数据子包的最大长度
+	maxSubpacket = 8 << 10
+	// 查找头部时允许跳过的字节数，超出时认为发送方已退出
+	maxGarbage = 32 << 10
+)
+
+// CancelSequence 取消传输：8 个 CAN 加 8 个退格
+var CancelSequence = append(bytes.Repeat([]byte{zdle}, 8), bytes.Repeat([]byte{0x08}, 8)...)
+
+// StartMarker sz 发出的 ZRQINIT 头部，不含前面的 ZPAD
+var StartMarker = []byte{zdle, 'B', '0', '0'}
+
+// UploadMarker rz 发出的 ZRINIT 头部，不含前面的 ZPAD
+var UploadMarker = []byte{zdle, 'B', '0', '1'}
+
+type FileInfo struct {
+	Name string
+	Size int64 // 发送方声明的大小，未知时为 0
+}
+
+type Handler interface {
+	// Open 开始接收文件，返回错误时跳过该文件
+	Open(info FileInfo) (io.Writer, error)
+	// Close 文件结束，complete 为 false 表示没有完整接收
+	Close(info FileInfo, received int64, complete bool)
+}
+
+const (
+	stSeek = iota
+	stSeekType
+	stHex
+	stBinary
+	stData
+	stCRC
+	stOver
+)
+
+// Receiver 按字节流推进的 ZMODEM 接收方，不启动 goroutine，也不做超时处理
+type Receiver struct {
+	reply   func([]byte)
+	handler Handler
+
+	state    int
+	esc      bool
+	cans     int
+	garbage  int
+	buf      []byte
+	need     int  // 头部或 CRC 还需要的字节数
+	crc32    bool // 当前帧使用 32 位 CRC
+	frame    byte // 数据子包所属的帧类型
+	frameEnd byte
+	crcBuf   []byte
+	overs    int
+
+	file     io.Writer
+	info     FileInfo
+	pos      int64
+	lastSent []byte
+
+	done    bool
+	aborted bool
+}
+
+// NewReceiver reply 用于向发送方回复数据
+func NewReceiver(reply func([]byte), handler Handler) *Receiver {
+	return &Receiver{reply: reply, handler: handler}
+}
+
+// Done 传输是否已经结束
+func (r *Receiver) Done() bool {
+	return r.done
+}
+
+// Aborted 传输是否被中断
+func (r *Receiver) Aborted() bool {
+	return r.aborted
+}
+
+// Feed 处理发送方的数据，传输结束时返回已消费的字节数，其余部分是普通的终端输出
+func (r *Receiver) Feed(p []byte) int {
+	for i, c := range p {
+		if r.done {
+			return i
+		}
+		if r.state == stOver {
+			// 跳过 ZFIN 头部末尾的 CR LF
+			if r.overs == 0 && (c == '\r' || c == '\n' || c == '\n'|0x80) {
+				continue
+			}
+			if c == 'O' && r.overs < 2 {
+				r.overs++
+				if r.overs == 2 {
+					r.done = true
+					return i + 1
+				}
+				continue
+			}
+			r.done = true
+			return i
+		}
+
+		// 连续 5 个 CAN 表示发送方取消传输
+		if c == zdle {
+			if r.cans++; r.cans >= 5 {
+				r.finish(true)
+				return i + 1
+			}
+		} else {
+			r.cans = 0
+		}
+
+		r.step(c)
+		if r.done {
+			return i + 1
+		}
+	}
+	return len(p)
+}
+
+// Abort 通知发送方取消传输
+func (r *Receiver) Abort() {
+	if r.done {
+		return
+	}
+	r.reply(CancelSequence)
+	r.finish(true)
+}
+
+func (r *Receiver) finish(aborted bool) {
+	if r.file != nil {
+		r.handler.Close(r.info, r.pos, false)
+		r.file = nil
+	}
+	r.done = true
+	r.aborted = aborted
+}
+
+func (r *Receiver) step(c byte) {
+	switch r.state {
+	case stSeek:
+		if c == zdle {
+			r.state = stSeekType
+			return
+		}
+		if r.garbage++; r.garbage > maxGarbage {
+			r.Abort()
+		}
+	case stSeekType:
+		r.buf = r.buf[:0]
+		r.esc = false
+		switch c {
+		case 'A':
+			r.state, r.crc32, r.need = stBinary, false, 7
+		case 'C':
+			r.state, r.crc32, r.need = stBinary, true, 9
+		case 'B':
+			r.state, r.crc32, r.need = stHex, false, 14
+		case zdle:
+		default:
+			r.state = stSeek
+		}
+	case stHex:
+		r.buf = append(r.buf, c)
+		if len(r.buf) < r.need {
+			return
+		}
+		r.state = stSeek
+		data, err := hex.DecodeString(strings.ToLower(string(r.buf)))
+		if err != nil || crc16(data[:5]) != binary.BigEndian.Uint16(data[5:]) {
+			r.sendHeader(ZNAK, 0)
+			return
+		}
+		r.handleHeader(data[0], data[1:5])
+	case stBinary:
+		b, ok, _ := r.unescape(c)
+		if !ok {
+			return
+		}
+		r.buf = append(r.buf, b)
+		if len(r.buf) < r.need {
+			return
+		}
+		r.state = stSeek
+		if !r.checkCRC(r.buf[:5], r.buf[5:]) {
+			r.sendHeader(ZNAK, 0)
+			return
+		}
+		r.handleHeader(r.buf[0], r.buf[1:5])
+	case stData:
+		b, ok, end := r.unescape(c)
+		if end {
+			r.frameEnd = b
+			r.crcBuf = r.crcBuf[:0]
+			r.state = stCRC
+			return
+		}
+		if !ok {
+			return
+		}
+		if len(r.buf) >= maxSubpacket {
+			r.handleData(false)
+			return
+		}
+		r.buf = append(r.buf, b)
+	case stCRC:
+		b, ok, _ := r.unescape(c)
+		if !ok {
+			return
+		}
+		r.crcBuf = append(r.crcBuf, b)
+		size := 2
+		if r.crc32 {
+			size = 4
+		}
+		if len(r.crcBuf) < size {
+			return
+		}
+		r.handleData(r.checkCRC(append(r.buf, r.frameEnd), r.crcBuf))
+	}
+}
+
+// unescape 处理 ZDLE 转义，ok 表示得到一个数据字节，end 表示数据子包结束
+func (r *Receiver) unescape(c byte) (b byte, ok, end bool) {
+	if r.esc {
+		r.esc = false
+		switch c {
+		case zcrce, zcrcg, zcrcq, zcrcw:
+			return c, false, true
+		case zrub0:
+			return 0x7f, true, false
+		case zrub1:
+			return 0xff, true, false
+		}
+		if c&0x60 == 0x40 {
+			return c ^ 0x40, true, false
+		}
+		return 0, false, false
+	}
+	switch c {
+	case zdle:
+		r.esc = true
+		return 0, false, false
+	case xon, xoff, xon | 0x80, xoff | 0x80:
+		return 0, false, false
+	}
+	return c, true, false
+}
+
+func (r *Receiver) checkCRC(data, sum []byte) bool {
+	if r.crc32 {
+		return crc32.ChecksumIEEE(data) == binary.LittleEndian.Uint32(sum)
+	}
+	return crc16(data) == binary.BigEndian.Uint16(sum)
+}
+
+func (r *Receiver) handleHeader(typ byte, hdr []byte) {
+	r.garbage = 0
+	pos := int64(binary.LittleEndian.Uint32(hdr))
+
+	switch typ {
+	case ZRQINIT:
+		r.sendHeader(ZRINIT, receiverFlags<<24)
+	case ZSINIT, ZFILE:
+		r.frame = typ
+		r.enterData()
+	case ZDATA:
+		if r.file == nil {
+			r.sendHeader(ZSKIP, 0)
+			return
+		}
+		if pos != r.pos {
+			r.sendHeader(ZRPOS, uint32(r.pos))
+			return
+		}
+		r.frame = typ
+		r.enterData()
+	case ZEOF:
+		if r.file == nil || pos != r.pos {
+			return
+		}
+		r.handler.Close(r.info, r.pos, true)
+		r.file = nil
+		r.sendHeader(ZRINIT, receiverFlags<<24)
+	case ZFIN:
+		if r.file != nil {
+			r.handler.Close(r.info, r.pos, false)
+			r.file = nil
+		}
+		r.sendHeader(ZFIN, 0)
+		r.state = stOver
+	case ZNAK:
+		if r.lastSent != nil {
+			r.reply(r.lastSent)
+		}
+	case ZCOMMAND:
+		// 不允许发送方在面板上执行命令
+		r.Abort()
+	case ZABORT, ZFERR, ZCAN:
+		r.finish(true)
+	}
+}
+
+func (r *Receiver) enterData() {
+	r.state = stData
+	r.buf = r.buf[:0]
+	r.esc = false
+}
+
+func (r *Receiver) handleData(crcOK bool) {
+	r.state = stSeek
+	if !crcOK {
+		if r.frame == ZDATA {
+			r.sendHeader(ZRPOS, uint32(r.pos))
+		} else {
+			r.sendHeader(ZNAK, 0)
+		}
+		return
+	}
+
+	switch r.frame {
+	case ZSINIT:
+		r.sendHeader(ZACK, 0)
+	case ZFILE:
+		if r.file != nil {
+			r.handler.Close(r.info, r.pos, false)
+			r.file = nil
+		}
+		r.info = parseFileInfo(r.buf)
+		w, err := r.handler.Open(r.info)
+		if err != nil {
+			r.sendHeader(ZSKIP, 0)
+			return
+		}
+		r.file, r.pos = w, 0
+		r.sendHeader(ZRPOS, 0)
+	case ZDATA:
+		if _, err := r.file.Write(r.buf); err != nil {
+			r.Abort()
+			return
+		}
+		r.pos += int64(len(r.buf))
+		switch r.frameEnd {
+		case zcrcg:
+			r.enterData()
+		case zcrcq:
+			r.sendHeader(ZACK, uint32(r.pos))
+			r.enterData()
+		case zcrcw:
+			r.sendHeader(ZACK, uint32(r.pos))
+		}
+	}
+}
+
+// sendHeader 接收方总是发送十六进制头部，flags 按小端序写入 ZP0..ZP3
+func (r *Receiver) sendHeader(typ byte, flags uint32) {
+	data := make([]byte, 5, 7)
+	data[0] = typ
+	binary.LittleEndian.PutUint32(data[1:], flags)
+	data = binary.BigEndian.AppendUint16(data, crc16(data))
+
+	out := []byte{zpad, zpad, zdle, 'B'}
+	out = append(out, hex.EncodeToString(data)...)
+	out = append(out, '\r', '\n'|0x80)
+	if typ != ZFIN && typ != ZACK {
+		out = append(out, xon)
+	}
+	r.lastSent = out
+	r.reply(out)
+}
+
+// parseFileInfo ZFILE 数据子包：文件名\0大小 修改时间 权限 ...\0
+func parseFileInfo(data []byte) FileInfo {
+	name, rest, _ := bytes.Cut(data, []byte{0})
+	var info FileInfo
+	// 只保留文件名，丢弃发送方的目录
+	info.Name = path.Base(strings.ReplaceAll(string(name), `\`, "/"))
+	if info.Name == "." || info.Name == "/" || info.Name == ".." {
+		info.Name = "file"
+	}
+	if fields := strings.Fields(string(bytes.TrimRight(rest, "\x00"))); len(fields) > 0 {
+		info.Size, _ = strconv.ParseInt(fields[0], 10, 64)
+	}
+	return info
+}
+
+// crc16 CRC-16/XMODEM
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,166 @@
+package zmodem
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"testing"
+)
+
+type memHandler struct {
+	files    map[string]*bytes.Buffer
+	complete map[string]bool
+	limit    int
+}
+
+func (h *memHandler) Open(info FileInfo) (io.Writer, error) {
+	if info.Name == "skip.bin" {
+		return nil, errors.New("skipped")
+	}
+	buf := new(bytes.Buffer)
+	h.files[info.Name] = buf
+	return limitWriter{buf, h.limit}, nil
+}
+
+func (h *memHandler) Close(info FileInfo, received int64, complete bool) {
+	h.complete[info.Name] = complete
+}
+
+type limitWriter struct {
+	buf   *bytes.Buffer
+	limit int
+}
+
+func (w limitWriter) Write(p []byte) (int, error) {
+	if w.limit > 0 && w.buf.Len()+len(p) > w.limit {
+		return 0, errors.New("too large")
+	}
+	return w.buf.Write(p)
+}
+
+// 以下模拟 lrzsz 的 sz 生成发送方数据流
+
+func escape(data []byte) []byte {
+	var out []byte
+	for _, c := range data {
+		switch c {
+		case zdle, xon, xoff, xon | 0x80, xoff | 0x80, 0x10, 0x90, 0x0d, 0x8d:
+			out = append(out, zdle, c^0x40)
+		case 0x7f:
+			out = append(out, zdle, zrub0)
+		case 0xff:
+			out = append(out, zdle, zrub1)
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func hexHeader(typ byte, flags uint32) []byte {
+	r := NewReceiver(func([]byte) {}, nil)
+	r.sendHeader(typ, flags)
+	return r.lastSent
+}
+
+func binHeader32(typ byte, flags uint32) []byte {
+	data := make([]byte, 5)
+	data[0] = typ
+	binary.LittleEndian.PutUint32(data[1:], flags)
+	data = binary.LittleEndian.AppendUint32(data, crc32.ChecksumIEEE(data))
+	return append([]byte{zpad, zdle, 'C'}, escape(data)...)
+}
+
+func subpacket32(data []byte, end byte) []byte {
+	sum := crc32.ChecksumIEEE(append(append([]byte(nil), data...), end))
+	out := append(escape(data), zdle, end)
+	return append(out, escape(binary.LittleEndian.AppendUint32(nil, sum))...)
+}
+
+func sendFile(name string, content []byte) []byte {
+	var s []byte
+	s = append(s, binHeader32(ZFILE, 0)...)
+	s = append(s, subpacket32([]byte(name+"\x00"+"11 14737356 100644 0 1 11\x00"), zcrcw)...)
+	s = append(s, binHeader32(ZDATA, 0)...)
+	half := len(content) / 2
+	s = append(s, subpacket32(content[:half], zcrcg)...)
+	s = append(s, subpacket32(content[half:], zcrce)...)
+	s = append(s, hexHeader(ZEOF, uint32(len(content)))...)
+	return s
+}
+
+func TestCRC16(t *testing.T) {
+	// lrzsz 的 rz 启动时发出 **\x18B0100000023be50
+	if got := crc16([]byte{ZRINIT, 0, 0, 0, 0x23}); got != 0xbe50 {
+		t.Fatalf("crc16 = %04x", got)
+	}
+	if got := string(hexHeader(ZRINIT, receiverFlags<<24)); got != "**\x18B0100000023be50\r\x8a\x11" {
+		t.Fatalf("unexpected ZRINIT: %q", got)
+	}
+}
+
+func TestReceiver(t *testing.T) {
+	content := []byte("hello\x18\x11\x7f\xff\r\n world")
+	var stream []byte
+	stream = append(stream, "rz\r"...)
+	start := len(stream)
+	stream = append(stream, hexHeader(ZRQINIT, 0)...)
+	stream = append(stream, sendFile("../../etc/a.txt", content)...)
+	stream = append(stream, binHeader32(ZFILE, 0)...)
+	stream = append(stream, subpacket32([]byte("skip.bin\x0010\x00"), zcrcw)...)
+	stream = append(stream, hexHeader(ZFIN, 0)...)
+	stream = append(stream, "OO$ prompt"...)
+
+	// 逐字节输入，覆盖任意的分块边界
+	var replies bytes.Buffer
+	h := &memHandler{files: map[string]*bytes.Buffer{}, complete: map[string]bool{}}
+	r := NewReceiver(func(p []byte) { replies.Write(p) }, h)
+	rest := -1
+	for i := start; i < len(stream); i++ {
+		if n := r.Feed(stream[i : i+1]); r.Done() {
+			rest = i + n
+			break
+		}
+	}
+	if !r.Done() || r.Aborted() {
+		t.Fatalf("done=%v aborted=%v", r.Done(), r.Aborted())
+	}
+	if got := string(stream[rest:]); got != "$ prompt" {
+		t.Errorf("unexpected terminal output after transfer: %q", got)
+	}
+	if got := h.files["a.txt"]; got == nil || !bytes.Equal(got.Bytes(), content) || !h.complete["a.txt"] {
+		t.Errorf("unexpected file: %v, complete %v", got, h.complete["a.txt"])
+	}
+	for _, typ := range []byte{ZRINIT, ZRPOS, ZSKIP, ZFIN} {
+		if !bytes.Contains(replies.Bytes(), hexHeader(typ, 0)[:6]) {
+			t.Errorf("missing reply type %d", typ)
+		}
+	}
+}
+
+func TestReceiverAbort(t *testing.T) {
+	var replies bytes.Buffer
+	h := &memHandler{files: map[string]*bytes.Buffer{}, complete: map[string]bool{}, limit: 4}
+	r := NewReceiver(func(p []byte) { replies.Write(p) }, h)
+
+	stream := append(hexHeader(ZRQINIT, 0), sendFile("big.bin", []byte("0123456789"))...)
+	r.Feed(stream)
+	if !r.Done() || !r.Aborted() {
+		t.Fatal("oversized file should abort the transfer")
+	}
+	if h.complete["big.bin"] {
+		t.Error("aborted file marked complete")
+	}
+	if !bytes.HasSuffix(replies.Bytes(), CancelSequence) {
+		t.Error("sender was not cancelled")
+	}
+
+	// 发送方取消
+	r = NewReceiver(func([]byte) {}, h)
+	r.Feed(hexHeader(ZRQINIT, 0))
+	if n := r.Feed([]byte("\x18\x18\x18\x18\x18\x08\x08")); !r.Aborted() || n != 5 {
+		t.Errorf("sender cancel not detected, consumed %d", n)
+	}
+}
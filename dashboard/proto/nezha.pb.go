@@ -164,6 +164,10 @@ type State struct {
 	ProcessCount   uint64                     `protobuf:"varint,15,opt,name=process_count,json=processCount,proto3" json:"process_count,omitempty"`
 	Temperatures   []*State_SensorTemperature `protobuf:"bytes,16,rep,name=temperatures,proto3" json:"temperatures,omitempty"`
 	Gpu            []float64                  `protobuf:"fixed64,17,rep,packed,name=gpu,proto3" json:"gpu,omitempty"`
+	Mounts         []*State_Mount             `protobuf:"bytes,18,rep,name=mounts,proto3" json:"mounts,omitempty"`
+	DiskIo         []*State_DiskIO            `protobuf:"bytes,19,rep,name=disk_io,json=diskIo,proto3" json:"disk_io,omitempty"`
+	Nics           []*State_NIC               `protobuf:"bytes,20,rep,name=nics,proto3" json:"nics,omitempty"`
+	CustomMetrics  []*State_CustomMetric      `protobuf:"bytes,21,rep,name=custom_metrics,json=customMetrics,proto3" json:"custom_metrics,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -317,6 +321,34 @@ func (x *State) GetGpu() []float64 {
 	return nil
 }
 
+func (x *State) GetMounts() []*State_Mount {
+	if x != nil {
+		return x.Mounts
+	}
+	return nil
+}
+
+func (x *State) GetDiskIo() []*State_DiskIO {
+	if x != nil {
+		return x.DiskIo
+	}
+	return nil
+}
+
+func (x *State) GetNics() []*State_NIC {
+	if x != nil {
+		return x.Nics
+	}
+	return nil
+}
+
+func (x *State) GetCustomMetrics() []*State_CustomMetric {
+	if x != nil {
+		return x.CustomMetrics
+	}
+	return nil
+}
+
 type State_SensorTemperature struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -369,29 +401,33 @@ func (x *State_SensorTemperature) GetTemperature() float64 {
 	return 0
 }
 
-type Task struct {
+type State_Mount struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Type          uint64                 `protobuf:"varint,2,opt,name=type,proto3" json:"type,omitempty"`
-	Data          string                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Mountpoint    string                 `protobuf:"bytes,1,opt,name=mountpoint,proto3" json:"mountpoint,omitempty"`
+	Device        string                 `protobuf:"bytes,2,opt,name=device,proto3" json:"device,omitempty"`
+	Fstype        string                 `protobuf:"bytes,3,opt,name=fstype,proto3" json:"fstype,omitempty"`
+	Total         uint64                 `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	Used          uint64                 `protobuf:"varint,5,opt,name=used,proto3" json:"used,omitempty"`
+	InodesTotal   uint64                 `protobuf:"varint,6,opt,name=inodes_total,json=inodesTotal,proto3" json:"inodes_total,omitempty"`
+	InodesUsed    uint64                 `protobuf:"varint,7,opt,name=inodes_used,json=inodesUsed,proto3" json:"inodes_used,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Task) Reset() {
-	*x = Task{}
+func (x *State_Mount) Reset() {
+	*x = State_Mount{}
 	mi := &file_nezha_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Task) String() string {
+func (x *State_Mount) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Task) ProtoMessage() {}
+func (*State_Mount) ProtoMessage() {}
 
-func (x *Task) ProtoReflect() protoreflect.Message {
+func (x *State_Mount) ProtoReflect() protoreflect.Message {
 	mi := &file_nezha_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -403,57 +439,85 @@ func (x *Task) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Task.ProtoReflect.Descriptor instead.
-func (*Task) Descriptor() ([]byte, []int) {
+// Deprecated: Use State_Mount.ProtoReflect.Descriptor instead.
+func (*State_Mount) Descriptor() ([]byte, []int) {
 	return file_nezha_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *Task) GetId() uint64 {
+func (x *State_Mount) GetMountpoint() string {
 	if x != nil {
-		return x.Id
+		return x.Mountpoint
+	}
+	return ""
+}
+
+func (x *State_Mount) GetDevice() string {
+	if x != nil {
+		return x.Device
+	}
+	return ""
+}
+
+func (x *State_Mount) GetFstype() string {
+	if x != nil {
+		return x.Fstype
+	}
+	return ""
+}
+
+func (x *State_Mount) GetTotal() uint64 {
+	if x != nil {
+		return x.Total
 	}
 	return 0
 }
 
-func (x *Task) GetType() uint64 {
+func (x *State_Mount) GetUsed() uint64 {
 	if x != nil {
-		return x.Type
+		return x.Used
 	}
 	return 0
 }
 
-func (x *Task) GetData() string {
+func (x *State_Mount) GetInodesTotal() uint64 {
 	if x != nil {
-		return x.Data
+		return x.InodesTotal
 	}
-	return ""
+	return 0
 }
 
-type TaskResult struct {
+func (x *State_Mount) GetInodesUsed() uint64 {
+	if x != nil {
+		return x.InodesUsed
+	}
+	return 0
+}
+
+type State_DiskIO struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Type          uint64                 `protobuf:"varint,2,opt,name=type,proto3" json:"type,omitempty"`
-	Delay         float32                `protobuf:"fixed32,3,opt,name=delay,proto3" json:"delay,omitempty"`
-	Data          string                 `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
-	Successful    bool                   `protobuf:"varint,5,opt,name=successful,proto3" json:"successful,omitempty"`
+	Device        string                 `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	ReadSpeed     uint64                 `protobuf:"varint,2,opt,name=read_speed,json=readSpeed,proto3" json:"read_speed,omitempty"`
+	WriteSpeed    uint64                 `protobuf:"varint,3,opt,name=write_speed,json=writeSpeed,proto3" json:"write_speed,omitempty"`
+	ReadIops      uint64                 `protobuf:"varint,4,opt,name=read_iops,json=readIops,proto3" json:"read_iops,omitempty"`
+	WriteIops     uint64                 `protobuf:"varint,5,opt,name=write_iops,json=writeIops,proto3" json:"write_iops,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TaskResult) Reset() {
-	*x = TaskResult{}
+func (x *State_DiskIO) Reset() {
+	*x = State_DiskIO{}
 	mi := &file_nezha_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TaskResult) String() string {
+func (x *State_DiskIO) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TaskResult) ProtoMessage() {}
+func (*State_DiskIO) ProtoMessage() {}
 
-func (x *TaskResult) ProtoReflect() protoreflect.Message {
+func (x *State_DiskIO) ProtoReflect() protoreflect.Message {
 	mi := &file_nezha_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -465,67 +529,76 @@ func (x *TaskResult) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TaskResult.ProtoReflect.Descriptor instead.
-func (*TaskResult) Descriptor() ([]byte, []int) {
+// Deprecated: Use State_DiskIO.ProtoReflect.Descriptor instead.
+func (*State_DiskIO) Descriptor() ([]byte, []int) {
 	return file_nezha_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *TaskResult) GetId() uint64 {
+func (x *State_DiskIO) GetDevice() string {
 	if x != nil {
-		return x.Id
+		return x.Device
 	}
-	return 0
+	return ""
 }
 
-func (x *TaskResult) GetType() uint64 {
+func (x *State_DiskIO) GetReadSpeed() uint64 {
 	if x != nil {
-		return x.Type
+		return x.ReadSpeed
 	}
 	return 0
 }
 
-func (x *TaskResult) GetDelay() float32 {
+func (x *State_DiskIO) GetWriteSpeed() uint64 {
 	if x != nil {
-		return x.Delay
+		return x.WriteSpeed
 	}
 	return 0
 }
 
-func (x *TaskResult) GetData() string {
+func (x *State_DiskIO) GetReadIops() uint64 {
 	if x != nil {
-		return x.Data
+		return x.ReadIops
 	}
-	return ""
+	return 0
 }
 
-func (x *TaskResult) GetSuccessful() bool {
+func (x *State_DiskIO) GetWriteIops() uint64 {
 	if x != nil {
-		return x.Successful
+		return x.WriteIops
 	}
-	return false
+	return 0
 }
 
-type Receipt struct {
+type State_NIC struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Proced        bool                   `protobuf:"varint,1,opt,name=proced,proto3" json:"proced,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	InSpeed       uint64                 `protobuf:"varint,2,opt,name=in_speed,json=inSpeed,proto3" json:"in_speed,omitempty"`
+	OutSpeed      uint64                 `protobuf:"varint,3,opt,name=out_speed,json=outSpeed,proto3" json:"out_speed,omitempty"`
+	InTransfer    uint64                 `protobuf:"varint,4,opt,name=in_transfer,json=inTransfer,proto3" json:"in_transfer,omitempty"`
+	OutTransfer   uint64                 `protobuf:"varint,5,opt,name=out_transfer,json=outTransfer,proto3" json:"out_transfer,omitempty"`
+	InErrors      uint64                 `protobuf:"varint,6,opt,name=in_errors,json=inErrors,proto3" json:"in_errors,omitempty"`
+	OutErrors     uint64                 `protobuf:"varint,7,opt,name=out_errors,json=outErrors,proto3" json:"out_errors,omitempty"`
+	InDrops       uint64                 `protobuf:"varint,8,opt,name=in_drops,json=inDrops,proto3" json:"in_drops,omitempty"`
+	OutDrops      uint64                 `protobuf:"varint,9,opt,name=out_drops,json=outDrops,proto3" json:"out_drops,omitempty"`
+	ErrorRate     float64                `protobuf:"fixed64,10,opt,name=error_rate,json=errorRate,proto3" json:"error_rate,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Receipt) Reset() {
-	*x = Receipt{}
+func (x *State_NIC) Reset() {
+	*x = State_NIC{}
 	mi := &file_nezha_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Receipt) String() string {
+func (x *State_NIC) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Receipt) ProtoMessage() {}
+func (*State_NIC) ProtoMessage() {}
 
-func (x *Receipt) ProtoReflect() protoreflect.Message {
+func (x *State_NIC) ProtoReflect() protoreflect.Message {
 	mi := &file_nezha_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -537,39 +610,104 @@ func (x *Receipt) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Receipt.ProtoReflect.Descriptor instead.
-func (*Receipt) Descriptor() ([]byte, []int) {
+// Deprecated: Use State_NIC.ProtoReflect.Descriptor instead.
+func (*State_NIC) Descriptor() ([]byte, []int) {
 	return file_nezha_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *Receipt) GetProced() bool {
+func (x *State_NIC) GetName() string {
 	if x != nil {
-		return x.Proced
+		return x.Name
 	}
-	return false
+	return ""
 }
 
-type Uint64Receipt struct {
+func (x *State_NIC) GetInSpeed() uint64 {
+	if x != nil {
+		return x.InSpeed
+	}
+	return 0
+}
+
+func (x *State_NIC) GetOutSpeed() uint64 {
+	if x != nil {
+		return x.OutSpeed
+	}
+	return 0
+}
+
+func (x *State_NIC) GetInTransfer() uint64 {
+	if x != nil {
+		return x.InTransfer
+	}
+	return 0
+}
+
+func (x *State_NIC) GetOutTransfer() uint64 {
+	if x != nil {
+		return x.OutTransfer
+	}
+	return 0
+}
+
+func (x *State_NIC) GetInErrors() uint64 {
+	if x != nil {
+		return x.InErrors
+	}
+	return 0
+}
+
+func (x *State_NIC) GetOutErrors() uint64 {
+	if x != nil {
+		return x.OutErrors
+	}
+	return 0
+}
+
+func (x *State_NIC) GetInDrops() uint64 {
+	if x != nil {
+		return x.InDrops
+	}
+	return 0
+}
+
+func (x *State_NIC) GetOutDrops() uint64 {
+	if x != nil {
+		return x.OutDrops
+	}
+	return 0
+}
+
+func (x *State_NIC) GetErrorRate() float64 {
+	if x != nil {
+		return x.ErrorRate
+	}
+	return 0
+}
+
+type State_CustomMetric struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Data          uint64                 `protobuf:"varint,1,opt,name=data,proto3" json:"data,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value         float64                `protobuf:"fixed64,2,opt,name=value,proto3" json:"value,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Uint64Receipt) Reset() {
-	*x = Uint64Receipt{}
+func (x *State_CustomMetric) Reset() {
+	*x = State_CustomMetric{}
 	mi := &file_nezha_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Uint64Receipt) String() string {
+func (x *State_CustomMetric) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Uint64Receipt) ProtoMessage() {}
+func (*State_CustomMetric) ProtoMessage() {}
 
-func (x *Uint64Receipt) ProtoReflect() protoreflect.Message {
+func (x *State_CustomMetric) ProtoReflect() protoreflect.Message {
 	mi := &file_nezha_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -581,39 +719,55 @@ func (x *Uint64Receipt) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Uint64Receipt.ProtoReflect.Descriptor instead.
-func (*Uint64Receipt) Descriptor() ([]byte, []int) {
+// Deprecated: Use State_CustomMetric.ProtoReflect.Descriptor instead.
+func (*State_CustomMetric) Descriptor() ([]byte, []int) {
 	return file_nezha_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *Uint64Receipt) GetData() uint64 {
+func (x *State_CustomMetric) GetName() string {
 	if x != nil {
-		return x.Data
+		return x.Name
+	}
+	return ""
+}
+
+func (x *State_CustomMetric) GetValue() float64 {
+	if x != nil {
+		return x.Value
 	}
 	return 0
 }
 
-type IOStreamData struct {
+func (x *State_CustomMetric) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type Task struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          uint64                 `protobuf:"varint,2,opt,name=type,proto3" json:"type,omitempty"`
+	Data          string                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *IOStreamData) Reset() {
-	*x = IOStreamData{}
+func (x *Task) Reset() {
+	*x = Task{}
 	mi := &file_nezha_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *IOStreamData) String() string {
+func (x *Task) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*IOStreamData) ProtoMessage() {}
+func (*Task) ProtoMessage() {}
 
-func (x *IOStreamData) ProtoReflect() protoreflect.Message {
+func (x *Task) ProtoReflect() protoreflect.Message {
 	mi := &file_nezha_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -625,42 +779,57 @@ func (x *IOStreamData) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use IOStreamData.ProtoReflect.Descriptor instead.
-func (*IOStreamData) Descriptor() ([]byte, []int) {
+// Deprecated: Use Task.ProtoReflect.Descriptor instead.
+func (*Task) Descriptor() ([]byte, []int) {
 	return file_nezha_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *IOStreamData) GetData() []byte {
+func (x *Task) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Task) GetType() uint64 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *Task) GetData() string {
 	if x != nil {
 		return x.Data
 	}
-	return nil
+	return ""
 }
 
-type GeoIP struct {
-	state             protoimpl.MessageState `protogen:"open.v1"`
-	Use6              bool                   `protobuf:"varint,1,opt,name=use6,proto3" json:"use6,omitempty"`
-	Ip                *IP                    `protobuf:"bytes,2,opt,name=ip,proto3" json:"ip,omitempty"`
-	CountryCode       string                 `protobuf:"bytes,3,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
-	DashboardBootTime uint64                 `protobuf:"varint,4,opt,name=dashboard_boot_time,json=dashboardBootTime,proto3" json:"dashboard_boot_time,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+type TaskResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          uint64                 `protobuf:"varint,2,opt,name=type,proto3" json:"type,omitempty"`
+	Delay         float32                `protobuf:"fixed32,3,opt,name=delay,proto3" json:"delay,omitempty"`
+	Data          string                 `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	Successful    bool                   `protobuf:"varint,5,opt,name=successful,proto3" json:"successful,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GeoIP) Reset() {
-	*x = GeoIP{}
+func (x *TaskResult) Reset() {
+	*x = TaskResult{}
 	mi := &file_nezha_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GeoIP) String() string {
+func (x *TaskResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GeoIP) ProtoMessage() {}
+func (*TaskResult) ProtoMessage() {}
 
-func (x *GeoIP) ProtoReflect() protoreflect.Message {
+func (x *TaskResult) ProtoReflect() protoreflect.Message {
 	mi := &file_nezha_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -669,65 +838,1194 @@ func (x *GeoIP) ProtoReflect() protoreflect.Message {
 		}
 		return ms
 	}
-	return mi.MessageOf(x)
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskResult.ProtoReflect.Descriptor instead.
+func (*TaskResult) Descriptor() ([]byte, []int) {
+	return file_nezha_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TaskResult) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TaskResult) GetType() uint64 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *TaskResult) GetDelay() float32 {
+	if x != nil {
+		return x.Delay
+	}
+	return 0
+}
+
+func (x *TaskResult) GetData() string {
+	if x != nil {
+		return x.Data
+	}
+	return ""
+}
+
+func (x *TaskResult) GetSuccessful() bool {
+	if x != nil {
+		return x.Successful
+	}
+	return false
+}
+
+type Receipt struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Proced        bool                   `protobuf:"varint,1,opt,name=proced,proto3" json:"proced,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Receipt) Reset() {
+	*x = Receipt{}
+	mi := &file_nezha_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Receipt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Receipt) ProtoMessage() {}
+
+func (x *Receipt) ProtoReflect() protoreflect.Message {
+	mi := &file_nezha_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Receipt.ProtoReflect.Descriptor instead.
+func (*Receipt) Descriptor() ([]byte, []int) {
+	return file_nezha_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Receipt) GetProced() bool {
+	if x != nil {
+		return x.Proced
+	}
+	return false
+}
+
+type Uint64Receipt struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          uint64                 `protobuf:"varint,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Uint64Receipt) Reset() {
+	*x = Uint64Receipt{}
+	mi := &file_nezha_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Uint64Receipt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Uint64Receipt) ProtoMessage() {}
+
+func (x *Uint64Receipt) ProtoReflect() protoreflect.Message {
+	mi := &file_nezha_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Uint64Receipt.ProtoReflect.Descriptor instead.
+func (*Uint64Receipt) Descriptor() ([]byte, []int) {
+	return file_nezha_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Uint64Receipt) GetData() uint64 {
+	if x != nil {
+		return x.Data
+	}
+	return 0
+}
+
+type IOStreamData struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IOStreamData) Reset() {
+	*x = IOStreamData{}
+	mi := &file_nezha_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IOStreamData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IOStreamData) ProtoMessage() {}
+
+func (x *IOStreamData) ProtoReflect() protoreflect.Message {
+	mi := &file_nezha_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IOStreamData.ProtoReflect.Descriptor instead.
+func (*IOStreamData) Descriptor() ([]byte, []int) {
+	return file_nezha_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *IOStreamData) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type GeoIP struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Use6              bool                   `protobuf:"varint,1,opt,name=use6,proto3" json:"use6,omitempty"`
+	Ip                *IP                    `protobuf:"bytes,2,opt,name=ip,proto3" json:"ip,omitempty"`
+	CountryCode       string                 `protobuf:"bytes,3,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	DashboardBootTime uint64                 `protobuf:"varint,4,opt,name=dashboard_boot_time,json=dashboardBootTime,proto3" json:"dashboard_boot_time,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *GeoIP) Reset() {
+	*x = GeoIP{}
+	mi := &file_nezha_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeoIP) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeoIP) ProtoMessage() {}
+
+func (x *GeoIP) ProtoReflect() protoreflect.Message {
+	mi := &file_nezha_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeoIP.ProtoReflect.Descriptor instead.
+func (*GeoIP) Descriptor() ([]byte, []int) {
+	return file_nezha_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GeoIP) GetUse6() bool {
+	if x != nil {
+		return x.Use6
+	}
+	return false
+}
+
+func (x *GeoIP) GetIp() *IP {
+	if x != nil {
+		return x.Ip
+	}
+	return nil
+}
+
+func (x *GeoIP) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+func (x *GeoIP) GetDashboardBootTime() uint64 {
+	if x != nil {
+		return x.DashboardBootTime
+	}
+	return 0
+}
+
+type IP struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ipv4          string                 `protobuf:"bytes,1,opt,name=ipv4,proto3" json:"ipv4,omitempty"`
+	Ipv6          string                 `protobuf:"bytes,2,opt,name=ipv6,proto3" json:"ipv6,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IP) Reset() {
+	*x = IP{}
+	mi := &file_nezha_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IP) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IP) ProtoMessage() {}
+
+func (x *IP) ProtoReflect() protoreflect.Message {
+	mi := &file_nezha_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IP.ProtoReflect.Descriptor instead.
+func (*IP) Descriptor() ([]byte, []int) {
+	return file_nezha_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *IP) GetIpv4() string {
+	if x != nil {
+		return x.Ipv4
+	}
+	return ""
+}
+
+func (x *IP) GetIpv6() string {
+	if x != nil {
+		return x.Ipv6
+	}
+	return ""
+}
+
+type DockerReport struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EngineVersion string                 `protobuf:"bytes,1,opt,name=engine_version,json=engineVersion,proto3" json:"engine_version,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Containers    []*DockerContainer     `protobuf:"bytes,3,rep,name=containers,proto3" json:"containers,omitempty"`
+	Images        []*DockerImage         `protobuf:"bytes,4,rep,name=images,proto3" json:"images,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DockerReport) Reset() {
+	*x = DockerReport{}
+	mi := &file_nezha_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DockerReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DockerReport) ProtoMessage() {}
+
+func (x *DockerReport) ProtoReflect() protoreflect.Message {
+	mi := &file_nezha_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DockerReport.ProtoReflect.Descriptor instead.
+func (*DockerReport) Descriptor() ([]byte, []int) {
+	return file_nezha_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DockerReport) GetEngineVersion() string {
+	if x != nil {
+		return x.EngineVersion
+	}
+	return ""
+}
+
+func (x *DockerReport) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *DockerReport) GetContainers() []*DockerContainer {
+	if x != nil {
+		return x.Containers
+	}
+	return nil
+}
+
+func (x *DockerReport) GetImages() []*DockerImage {
+	if x != nil {
+		return x.Images
+	}
+	return nil
+}
+
+type DockerContainer struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Image          string                 `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
+	State          string                 `protobuf:"bytes,4,opt,name=state,proto3" json:"state,omitempty"`
+	Status         string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Health         string                 `protobuf:"bytes,6,opt,name=health,proto3" json:"health,omitempty"`
+	RestartCount   uint64                 `protobuf:"varint,7,opt,name=restart_count,json=restartCount,proto3" json:"restart_count,omitempty"`
+	Created        int64                  `protobuf:"varint,8,opt,name=created,proto3" json:"created,omitempty"`
+	StartedAt      int64                  `protobuf:"varint,9,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	Cpu            float64                `protobuf:"fixed64,10,opt,name=cpu,proto3" json:"cpu,omitempty"`
+	MemUsed        uint64                 `protobuf:"varint,11,opt,name=mem_used,json=memUsed,proto3" json:"mem_used,omitempty"`
+	MemLimit       uint64                 `protobuf:"varint,12,opt,name=mem_limit,json=memLimit,proto3" json:"mem_limit,omitempty"`
+	NetInTransfer  uint64                 `protobuf:"varint,13,opt,name=net_in_transfer,json=netInTransfer,proto3" json:"net_in_transfer,omitempty"`
+	NetOutTransfer uint64                 `protobuf:"varint,14,opt,name=net_out_transfer,json=netOutTransfer,proto3" json:"net_out_transfer,omitempty"`
+	Ports          []string               `protobuf:"bytes,15,rep,name=ports,proto3" json:"ports,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DockerContainer) Reset() {
+	*x = DockerContainer{}
+	mi := &file_nezha_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DockerContainer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DockerContainer) ProtoMessage() {}
+
+func (x *DockerContainer) ProtoReflect() protoreflect.Message {
+	mi := &file_nezha_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DockerContainer.ProtoReflect.Descriptor instead.
+func (*DockerContainer) Descriptor() ([]byte, []int) {
+	return file_nezha_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DockerContainer) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DockerContainer) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DockerContainer) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *DockerContainer) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *DockerContainer) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *DockerContainer) GetHealth() string {
+	if x != nil {
+		return x.Health
+	}
+	return ""
+}
+
+func (x *DockerContainer) GetRestartCount() uint64 {
+	if x != nil {
+		return x.RestartCount
+	}
+	return 0
+}
+
+func (x *DockerContainer) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *DockerContainer) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *DockerContainer) GetCpu() float64 {
+	if x != nil {
+		return x.Cpu
+	}
+	return 0
+}
+
+func (x *DockerContainer) GetMemUsed() uint64 {
+	if x != nil {
+		return x.MemUsed
+	}
+	return 0
+}
+
+func (x *DockerContainer) GetMemLimit() uint64 {
+	if x != nil {
+		return x.MemLimit
+	}
+	return 0
+}
+
+func (x *DockerContainer) GetNetInTransfer() uint64 {
+	if x != nil {
+		return x.NetInTransfer
+	}
+	return 0
+}
+
+func (x *DockerContainer) GetNetOutTransfer() uint64 {
+	if x != nil {
+		return x.NetOutTransfer
+	}
+	return 0
+}
+
+func (x *DockerContainer) GetPorts() []string {
+	if x != nil {
+		return x.Ports
+	}
+	return nil
+}
+
+type DockerImage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Tags          []string               `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+	Size          uint64                 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Created       int64                  `protobuf:"varint,4,opt,name=created,proto3" json:"created,omitempty"`
+	Containers    uint64                 `protobuf:"varint,5,opt,name=containers,proto3" json:"containers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DockerImage) Reset() {
+	*x = DockerImage{}
+	mi := &file_nezha_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DockerImage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DockerImage) ProtoMessage() {}
+
+func (x *DockerImage) ProtoReflect() protoreflect.Message {
+	mi := &file_nezha_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DockerImage.ProtoReflect.Descriptor instead.
+func (*DockerImage) Descriptor() ([]byte, []int) {
+	return file_nezha_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DockerImage) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DockerImage) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *DockerImage) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *DockerImage) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *DockerImage) GetContainers() uint64 {
+	if x != nil {
+		return x.Containers
+	}
+	return 0
+}
+
+type KubernetesReport struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cluster       string                 `protobuf:"bytes,1,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	Nodes         []*KubeNode            `protobuf:"bytes,4,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Namespaces    []*KubeNamespace       `protobuf:"bytes,5,rep,name=namespaces,proto3" json:"namespaces,omitempty"`
+	Workloads     []*KubeWorkload        `protobuf:"bytes,6,rep,name=workloads,proto3" json:"workloads,omitempty"`
+	Pods          []*KubePod             `protobuf:"bytes,7,rep,name=pods,proto3" json:"pods,omitempty"`
+	Events        []*KubeEvent           `protobuf:"bytes,8,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KubernetesReport) Reset() {
+	*x = KubernetesReport{}
+	mi := &file_nezha_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KubernetesReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KubernetesReport) ProtoMessage() {}
+
+func (x *KubernetesReport) ProtoReflect() protoreflect.Message {
+	mi := &file_nezha_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KubernetesReport.ProtoReflect.Descriptor instead.
+func (*KubernetesReport) Descriptor() ([]byte, []int) {
+	return file_nezha_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *KubernetesReport) GetCluster() string {
+	if x != nil {
+		return x.Cluster
+	}
+	return ""
+}
+
+func (x *KubernetesReport) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *KubernetesReport) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *KubernetesReport) GetNodes() []*KubeNode {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+func (x *KubernetesReport) GetNamespaces() []*KubeNamespace {
+	if x != nil {
+		return x.Namespaces
+	}
+	return nil
+}
+
+func (x *KubernetesReport) GetWorkloads() []*KubeWorkload {
+	if x != nil {
+		return x.Workloads
+	}
+	return nil
+}
+
+func (x *KubernetesReport) GetPods() []*KubePod {
+	if x != nil {
+		return x.Pods
+	}
+	return nil
+}
+
+func (x *KubernetesReport) GetEvents() []*KubeEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type KubeNode struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Name              string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Ready             bool                   `protobuf:"varint,2,opt,name=ready,proto3" json:"ready,omitempty"`
+	Unschedulable     bool                   `protobuf:"varint,3,opt,name=unschedulable,proto3" json:"unschedulable,omitempty"`
+	Roles             []string               `protobuf:"bytes,4,rep,name=roles,proto3" json:"roles,omitempty"`
+	KubeletVersion    string                 `protobuf:"bytes,5,opt,name=kubelet_version,json=kubeletVersion,proto3" json:"kubelet_version,omitempty"`
+	InternalIp        string                 `protobuf:"bytes,6,opt,name=internal_ip,json=internalIp,proto3" json:"internal_ip,omitempty"`
+	CpuCapacity       uint64                 `protobuf:"varint,7,opt,name=cpu_capacity,json=cpuCapacity,proto3" json:"cpu_capacity,omitempty"`
+	MemoryCapacity    uint64                 `protobuf:"varint,8,opt,name=memory_capacity,json=memoryCapacity,proto3" json:"memory_capacity,omitempty"`
+	CpuAllocatable    uint64                 `protobuf:"varint,9,opt,name=cpu_allocatable,json=cpuAllocatable,proto3" json:"cpu_allocatable,omitempty"`
+	MemoryAllocatable uint64                 `protobuf:"varint,10,opt,name=memory_allocatable,json=memoryAllocatable,proto3" json:"memory_allocatable,omitempty"`
+	CpuUsage          uint64                 `protobuf:"varint,11,opt,name=cpu_usage,json=cpuUsage,proto3" json:"cpu_usage,omitempty"`
+	MemoryUsage       uint64                 `protobuf:"varint,12,opt,name=memory_usage,json=memoryUsage,proto3" json:"memory_usage,omitempty"`
+	Created           int64                  `protobuf:"varint,13,opt,name=created,proto3" json:"created,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *KubeNode) Reset() {
+	*x = KubeNode{}
+	mi := &file_nezha_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KubeNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KubeNode) ProtoMessage() {}
+
+func (x *KubeNode) ProtoReflect() protoreflect.Message {
+	mi := &file_nezha_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KubeNode.ProtoReflect.Descriptor instead.
+func (*KubeNode) Descriptor() ([]byte, []int) {
+	return file_nezha_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *KubeNode) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *KubeNode) GetReady() bool {
+	if x != nil {
+		return x.Ready
+	}
+	return false
+}
+
+func (x *KubeNode) GetUnschedulable() bool {
+	if x != nil {
+		return x.Unschedulable
+	}
+	return false
+}
+
+func (x *KubeNode) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *KubeNode) GetKubeletVersion() string {
+	if x != nil {
+		return x.KubeletVersion
+	}
+	return ""
+}
+
+func (x *KubeNode) GetInternalIp() string {
+	if x != nil {
+		return x.InternalIp
+	}
+	return ""
+}
+
+func (x *KubeNode) GetCpuCapacity() uint64 {
+	if x != nil {
+		return x.CpuCapacity
+	}
+	return 0
+}
+
+func (x *KubeNode) GetMemoryCapacity() uint64 {
+	if x != nil {
+		return x.MemoryCapacity
+	}
+	return 0
+}
+
+func (x *KubeNode) GetCpuAllocatable() uint64 {
+	if x != nil {
+		return x.CpuAllocatable
+	}
+	return 0
+}
+
+func (x *KubeNode) GetMemoryAllocatable() uint64 {
+	if x != nil {
+		return x.MemoryAllocatable
+	}
+	return 0
+}
+
+func (x *KubeNode) GetCpuUsage() uint64 {
+	if x != nil {
+		return x.CpuUsage
+	}
+	return 0
+}
+
+func (x *KubeNode) GetMemoryUsage() uint64 {
+	if x != nil {
+		return x.MemoryUsage
+	}
+	return 0
+}
+
+func (x *KubeNode) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+type KubeNamespace struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Phase         string                 `protobuf:"bytes,2,opt,name=phase,proto3" json:"phase,omitempty"`
+	Created       int64                  `protobuf:"varint,3,opt,name=created,proto3" json:"created,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KubeNamespace) Reset() {
+	*x = KubeNamespace{}
+	mi := &file_nezha_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KubeNamespace) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KubeNamespace) ProtoMessage() {}
+
+func (x *KubeNamespace) ProtoReflect() protoreflect.Message {
+	mi := &file_nezha_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KubeNamespace.ProtoReflect.Descriptor instead.
+func (*KubeNamespace) Descriptor() ([]byte, []int) {
+	return file_nezha_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *KubeNamespace) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *KubeNamespace) GetPhase() string {
+	if x != nil {
+		return x.Phase
+	}
+	return ""
+}
+
+func (x *KubeNamespace) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+type KubeWorkload struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Namespace     string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Desired       uint32                 `protobuf:"varint,4,opt,name=desired,proto3" json:"desired,omitempty"`
+	Ready         uint32                 `protobuf:"varint,5,opt,name=ready,proto3" json:"ready,omitempty"`
+	Available     uint32                 `protobuf:"varint,6,opt,name=available,proto3" json:"available,omitempty"`
+	Updated       uint32                 `protobuf:"varint,7,opt,name=updated,proto3" json:"updated,omitempty"`
+	Created       int64                  `protobuf:"varint,8,opt,name=created,proto3" json:"created,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KubeWorkload) Reset() {
+	*x = KubeWorkload{}
+	mi := &file_nezha_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KubeWorkload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KubeWorkload) ProtoMessage() {}
+
+func (x *KubeWorkload) ProtoReflect() protoreflect.Message {
+	mi := &file_nezha_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KubeWorkload.ProtoReflect.Descriptor instead.
+func (*KubeWorkload) Descriptor() ([]byte, []int) {
+	return file_nezha_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *KubeWorkload) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *KubeWorkload) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *KubeWorkload) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *KubeWorkload) GetDesired() uint32 {
+	if x != nil {
+		return x.Desired
+	}
+	return 0
+}
+
+func (x *KubeWorkload) GetReady() uint32 {
+	if x != nil {
+		return x.Ready
+	}
+	return 0
+}
+
+func (x *KubeWorkload) GetAvailable() uint32 {
+	if x != nil {
+		return x.Available
+	}
+	return 0
+}
+
+func (x *KubeWorkload) GetUpdated() uint32 {
+	if x != nil {
+		return x.Updated
+	}
+	return 0
+}
+
+func (x *KubeWorkload) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+type KubePod struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Namespace       string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Node            string                 `protobuf:"bytes,3,opt,name=node,proto3" json:"node,omitempty"`
+	Phase           string                 `protobuf:"bytes,4,opt,name=phase,proto3" json:"phase,omitempty"`
+	Reason          string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	Restarts        uint32                 `protobuf:"varint,6,opt,name=restarts,proto3" json:"restarts,omitempty"`
+	ReadyContainers uint32                 `protobuf:"varint,7,opt,name=ready_containers,json=readyContainers,proto3" json:"ready_containers,omitempty"`
+	TotalContainers uint32                 `protobuf:"varint,8,opt,name=total_containers,json=totalContainers,proto3" json:"total_containers,omitempty"`
+	OwnerKind       string                 `protobuf:"bytes,9,opt,name=owner_kind,json=ownerKind,proto3" json:"owner_kind,omitempty"`
+	OwnerName       string                 `protobuf:"bytes,10,opt,name=owner_name,json=ownerName,proto3" json:"owner_name,omitempty"`
+	CpuRequest      uint64                 `protobuf:"varint,11,opt,name=cpu_request,json=cpuRequest,proto3" json:"cpu_request,omitempty"`
+	MemoryRequest   uint64                 `protobuf:"varint,12,opt,name=memory_request,json=memoryRequest,proto3" json:"memory_request,omitempty"`
+	CpuUsage        uint64                 `protobuf:"varint,13,opt,name=cpu_usage,json=cpuUsage,proto3" json:"cpu_usage,omitempty"`
+	MemoryUsage     uint64                 `protobuf:"varint,14,opt,name=memory_usage,json=memoryUsage,proto3" json:"memory_usage,omitempty"`
+	Created         int64                  `protobuf:"varint,15,opt,name=created,proto3" json:"created,omitempty"`
+	Ip              string                 `protobuf:"bytes,16,opt,name=ip,proto3" json:"ip,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *KubePod) Reset() {
+	*x = KubePod{}
+	mi := &file_nezha_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KubePod) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KubePod) ProtoMessage() {}
+
+func (x *KubePod) ProtoReflect() protoreflect.Message {
+	mi := &file_nezha_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KubePod.ProtoReflect.Descriptor instead.
+func (*KubePod) Descriptor() ([]byte, []int) {
+	return file_nezha_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *KubePod) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *KubePod) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *KubePod) GetNode() string {
+	if x != nil {
+		return x.Node
+	}
+	return ""
+}
+
+func (x *KubePod) GetPhase() string {
+	if x != nil {
+		return x.Phase
+	}
+	return ""
+}
+
+func (x *KubePod) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *KubePod) GetRestarts() uint32 {
+	if x != nil {
+		return x.Restarts
+	}
+	return 0
+}
+
+func (x *KubePod) GetReadyContainers() uint32 {
+	if x != nil {
+		return x.ReadyContainers
+	}
+	return 0
+}
+
+func (x *KubePod) GetTotalContainers() uint32 {
+	if x != nil {
+		return x.TotalContainers
+	}
+	return 0
+}
+
+func (x *KubePod) GetOwnerKind() string {
+	if x != nil {
+		return x.OwnerKind
+	}
+	return ""
+}
+
+func (x *KubePod) GetOwnerName() string {
+	if x != nil {
+		return x.OwnerName
+	}
+	return ""
 }
 
-// Deprecated: Use GeoIP.ProtoReflect.Descriptor instead.
-func (*GeoIP) Descriptor() ([]byte, []int) {
-	return file_nezha_proto_rawDescGZIP(), []int{8}
+func (x *KubePod) GetCpuRequest() uint64 {
+	if x != nil {
+		return x.CpuRequest
+	}
+	return 0
 }
 
-func (x *GeoIP) GetUse6() bool {
+func (x *KubePod) GetMemoryRequest() uint64 {
 	if x != nil {
-		return x.Use6
+		return x.MemoryRequest
 	}
-	return false
+	return 0
 }
 
-func (x *GeoIP) GetIp() *IP {
+func (x *KubePod) GetCpuUsage() uint64 {
 	if x != nil {
-		return x.Ip
+		return x.CpuUsage
 	}
-	return nil
+	return 0
 }
 
-func (x *GeoIP) GetCountryCode() string {
+func (x *KubePod) GetMemoryUsage() uint64 {
 	if x != nil {
-		return x.CountryCode
+		return x.MemoryUsage
 	}
-	return ""
+	return 0
 }
 
-func (x *GeoIP) GetDashboardBootTime() uint64 {
+func (x *KubePod) GetCreated() int64 {
 	if x != nil {
-		return x.DashboardBootTime
+		return x.Created
 	}
 	return 0
 }
 
-type IP struct {
+func (x *KubePod) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+type KubeEvent struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Ipv4          string                 `protobuf:"bytes,1,opt,name=ipv4,proto3" json:"ipv4,omitempty"`
-	Ipv6          string                 `protobuf:"bytes,2,opt,name=ipv6,proto3" json:"ipv6,omitempty"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	ObjectKind    string                 `protobuf:"bytes,5,opt,name=object_kind,json=objectKind,proto3" json:"object_kind,omitempty"`
+	ObjectName    string                 `protobuf:"bytes,6,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	Count         uint32                 `protobuf:"varint,7,opt,name=count,proto3" json:"count,omitempty"`
+	LastSeen      int64                  `protobuf:"varint,8,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *IP) Reset() {
-	*x = IP{}
-	mi := &file_nezha_proto_msgTypes[9]
+func (x *KubeEvent) Reset() {
+	*x = KubeEvent{}
+	mi := &file_nezha_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *IP) String() string {
+func (x *KubeEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*IP) ProtoMessage() {}
+func (*KubeEvent) ProtoMessage() {}
 
-func (x *IP) ProtoReflect() protoreflect.Message {
-	mi := &file_nezha_proto_msgTypes[9]
+func (x *KubeEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_nezha_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -738,25 +2036,67 @@ func (x *IP) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use IP.ProtoReflect.Descriptor instead.
-func (*IP) Descriptor() ([]byte, []int) {
-	return file_nezha_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use KubeEvent.ProtoReflect.Descriptor instead.
+func (*KubeEvent) Descriptor() ([]byte, []int) {
+	return file_nezha_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *IP) GetIpv4() string {
+func (x *KubeEvent) GetNamespace() string {
 	if x != nil {
-		return x.Ipv4
+		return x.Namespace
 	}
 	return ""
 }
 
-func (x *IP) GetIpv6() string {
+func (x *KubeEvent) GetType() string {
 	if x != nil {
-		return x.Ipv6
+		return x.Type
+	}
+	return ""
+}
+
+func (x *KubeEvent) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *KubeEvent) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *KubeEvent) GetObjectKind() string {
+	if x != nil {
+		return x.ObjectKind
+	}
+	return ""
+}
+
+func (x *KubeEvent) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
 	}
 	return ""
 }
 
+func (x *KubeEvent) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *KubeEvent) GetLastSeen() int64 {
+	if x != nil {
+		return x.LastSeen
+	}
+	return 0
+}
+
 // Terminal audit messages
 type TerminalCommand struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -770,7 +2110,7 @@ type TerminalCommand struct {
 
 func (x *TerminalCommand) Reset() {
 	*x = TerminalCommand{}
-	mi := &file_nezha_proto_msgTypes[10]
+	mi := &file_nezha_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -782,7 +2122,7 @@ func (x *TerminalCommand) String() string {
 func (*TerminalCommand) ProtoMessage() {}
 
 func (x *TerminalCommand) ProtoReflect() protoreflect.Message {
-	mi := &file_nezha_proto_msgTypes[10]
+	mi := &file_nezha_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -795,7 +2135,7 @@ func (x *TerminalCommand) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TerminalCommand.ProtoReflect.Descriptor instead.
 func (*TerminalCommand) Descriptor() ([]byte, []int) {
-	return file_nezha_proto_rawDescGZIP(), []int{10}
+	return file_nezha_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *TerminalCommand) GetStreamId() string {
@@ -837,7 +2177,7 @@ type CommandCheckRequest struct {
 
 func (x *CommandCheckRequest) Reset() {
 	*x = CommandCheckRequest{}
-	mi := &file_nezha_proto_msgTypes[11]
+	mi := &file_nezha_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -849,7 +2189,7 @@ func (x *CommandCheckRequest) String() string {
 func (*CommandCheckRequest) ProtoMessage() {}
 
 func (x *CommandCheckRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_nezha_proto_msgTypes[11]
+	mi := &file_nezha_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -862,7 +2202,7 @@ func (x *CommandCheckRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CommandCheckRequest.ProtoReflect.Descriptor instead.
 func (*CommandCheckRequest) Descriptor() ([]byte, []int) {
-	return file_nezha_proto_rawDescGZIP(), []int{11}
+	return file_nezha_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *CommandCheckRequest) GetStreamId() string {
@@ -896,7 +2236,7 @@ type CommandCheckResponse struct {
 
 func (x *CommandCheckResponse) Reset() {
 	*x = CommandCheckResponse{}
-	mi := &file_nezha_proto_msgTypes[12]
+	mi := &file_nezha_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -908,7 +2248,7 @@ func (x *CommandCheckResponse) String() string {
 func (*CommandCheckResponse) ProtoMessage() {}
 
 func (x *CommandCheckResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_nezha_proto_msgTypes[12]
+	mi := &file_nezha_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -921,7 +2261,7 @@ func (x *CommandCheckResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CommandCheckResponse.ProtoReflect.Descriptor instead.
 func (*CommandCheckResponse) Descriptor() ([]byte, []int) {
-	return file_nezha_proto_rawDescGZIP(), []int{12}
+	return file_nezha_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *CommandCheckResponse) GetBlocked() bool {
@@ -938,6 +2278,238 @@ func (x *CommandCheckResponse) GetReason() string {
 	return ""
 }
 
+type StateSample struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         *State                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StateSample) Reset() {
+	*x = StateSample{}
+	mi := &file_nezha_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StateSample) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StateSample) ProtoMessage() {}
+
+func (x *StateSample) ProtoReflect() protoreflect.Message {
+	mi := &file_nezha_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StateSample.ProtoReflect.Descriptor instead.
+func (*StateSample) Descriptor() ([]byte, []int) {
+	return file_nezha_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *StateSample) GetState() *State {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+func (x *StateSample) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type StateBackfill struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Samples       []*StateSample         `protobuf:"bytes,1,rep,name=samples,proto3" json:"samples,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StateBackfill) Reset() {
+	*x = StateBackfill{}
+	mi := &file_nezha_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StateBackfill) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StateBackfill) ProtoMessage() {}
+
+func (x *StateBackfill) ProtoReflect() protoreflect.Message {
+	mi := &file_nezha_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StateBackfill.ProtoReflect.Descriptor instead.
+func (*StateBackfill) Descriptor() ([]byte, []int) {
+	return file_nezha_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *StateBackfill) GetSamples() []*StateSample {
+	if x != nil {
+		return x.Samples
+	}
+	return nil
+}
+
+type SystemdReport struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Units         []*SystemdUnit         `protobuf:"bytes,2,rep,name=units,proto3" json:"units,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SystemdReport) Reset() {
+	*x = SystemdReport{}
+	mi := &file_nezha_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SystemdReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemdReport) ProtoMessage() {}
+
+func (x *SystemdReport) ProtoReflect() protoreflect.Message {
+	mi := &file_nezha_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemdReport.ProtoReflect.Descriptor instead.
+func (*SystemdReport) Descriptor() ([]byte, []int) {
+	return file_nezha_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *SystemdReport) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *SystemdReport) GetUnits() []*SystemdUnit {
+	if x != nil {
+		return x.Units
+	}
+	return nil
+}
+
+type SystemdUnit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	LoadState     string                 `protobuf:"bytes,3,opt,name=load_state,json=loadState,proto3" json:"load_state,omitempty"`
+	ActiveState   string                 `protobuf:"bytes,4,opt,name=active_state,json=activeState,proto3" json:"active_state,omitempty"`
+	SubState      string                 `protobuf:"bytes,5,opt,name=sub_state,json=subState,proto3" json:"sub_state,omitempty"`
+	UnitFileState string                 `protobuf:"bytes,6,opt,name=unit_file_state,json=unitFileState,proto3" json:"unit_file_state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SystemdUnit) Reset() {
+	*x = SystemdUnit{}
+	mi := &file_nezha_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SystemdUnit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemdUnit) ProtoMessage() {}
+
+func (x *SystemdUnit) ProtoReflect() protoreflect.Message {
+	mi := &file_nezha_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemdUnit.ProtoReflect.Descriptor instead.
+func (*SystemdUnit) Descriptor() ([]byte, []int) {
+	return file_nezha_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SystemdUnit) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SystemdUnit) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *SystemdUnit) GetLoadState() string {
+	if x != nil {
+		return x.LoadState
+	}
+	return ""
+}
+
+func (x *SystemdUnit) GetActiveState() string {
+	if x != nil {
+		return x.ActiveState
+	}
+	return ""
+}
+
+func (x *SystemdUnit) GetSubState() string {
+	if x != nil {
+		return x.SubState
+	}
+	return ""
+}
+
+func (x *SystemdUnit) GetUnitFileState() string {
+	if x != nil {
+		return x.UnitFileState
+	}
+	return ""
+}
+
 var File_nezha_proto protoreflect.FileDescriptor
 
 const file_nezha_proto_rawDesc = "" +
@@ -957,7 +2529,7 @@ const file_nezha_proto_rawDesc = "" +
 	"\tboot_time\x18\t \x01(\x04R\bbootTime\x12\x18\n" +
 	"\aversion\x18\n" +
 	" \x01(\tR\aversion\x12\x10\n" +
-	"\x03gpu\x18\v \x03(\tR\x03gpu\"\xa9\x04\n" +
+	"\x03gpu\x18\v \x03(\tR\x03gpu\"\xeb\x05\n" +
 	"\x05State\x12\x10\n" +
 	"\x03cpu\x18\x01 \x01(\x01R\x03cpu\x12\x19\n" +
 	"\bmem_used\x18\x02 \x01(\x04R\amemUsed\x12\x1b\n" +
@@ -977,10 +2549,53 @@ const file_nezha_proto_rawDesc = "" +
 	"\x0eudp_conn_count\x18\x0e \x01(\x04R\fudpConnCount\x12#\n" +
 	"\rprocess_count\x18\x0f \x01(\x04R\fprocessCount\x12B\n" +
 	"\ftemperatures\x18\x10 \x03(\v2\x1e.proto.State_SensorTemperatureR\ftemperatures\x12\x10\n" +
-	"\x03gpu\x18\x11 \x03(\x01R\x03gpu\"O\n" +
+	"\x03gpu\x18\x11 \x03(\x01R\x03gpu\x12*\n" +
+	"\x06mounts\x18\x12 \x03(\v2\x12.proto.State_MountR\x06mounts\x12,\n" +
+	"\adisk_io\x18\x13 \x03(\v2\x13.proto.State_DiskIOR\x06diskIo\x12$\n" +
+	"\x04nics\x18\x14 \x03(\v2\x10.proto.State_NICR\x04nics\x12@\n" +
+	"\x0ecustom_metrics\x18\x15 \x03(\v2\x19.proto.State_CustomMetricR\rcustomMetrics\"O\n" +
 	"\x17State_SensorTemperature\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
-	"\vtemperature\x18\x02 \x01(\x01R\vtemperature\">\n" +
+	"\vtemperature\x18\x02 \x01(\x01R\vtemperature\"\xcb\x01\n" +
+	"\vState_Mount\x12\x1e\n" +
+	"\n" +
+	"mountpoint\x18\x01 \x01(\tR\n" +
+	"mountpoint\x12\x16\n" +
+	"\x06device\x18\x02 \x01(\tR\x06device\x12\x16\n" +
+	"\x06fstype\x18\x03 \x01(\tR\x06fstype\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x04R\x05total\x12\x12\n" +
+	"\x04used\x18\x05 \x01(\x04R\x04used\x12!\n" +
+	"\finodes_total\x18\x06 \x01(\x04R\vinodesTotal\x12\x1f\n" +
+	"\vinodes_used\x18\a \x01(\x04R\n" +
+	"inodesUsed\"\xa2\x01\n" +
+	"\fState_DiskIO\x12\x16\n" +
+	"\x06device\x18\x01 \x01(\tR\x06device\x12\x1d\n" +
+	"\n" +
+	"read_speed\x18\x02 \x01(\x04R\treadSpeed\x12\x1f\n" +
+	"\vwrite_speed\x18\x03 \x01(\x04R\n" +
+	"writeSpeed\x12\x1b\n" +
+	"\tread_iops\x18\x04 \x01(\x04R\breadIops\x12\x1d\n" +
+	"\n" +
+	"write_iops\x18\x05 \x01(\x04R\twriteIops\"\xae\x02\n" +
+	"\tState_NIC\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x19\n" +
+	"\bin_speed\x18\x02 \x01(\x04R\ainSpeed\x12\x1b\n" +
+	"\tout_speed\x18\x03 \x01(\x04R\boutSpeed\x12\x1f\n" +
+	"\vin_transfer\x18\x04 \x01(\x04R\n" +
+	"inTransfer\x12!\n" +
+	"\fout_transfer\x18\x05 \x01(\x04R\voutTransfer\x12\x1b\n" +
+	"\tin_errors\x18\x06 \x01(\x04R\binErrors\x12\x1d\n" +
+	"\n" +
+	"out_errors\x18\a \x01(\x04R\toutErrors\x12\x19\n" +
+	"\bin_drops\x18\b \x01(\x04R\ainDrops\x12\x1b\n" +
+	"\tout_drops\x18\t \x01(\x04R\boutDrops\x12\x1d\n" +
+	"\n" +
+	"error_rate\x18\n" +
+	" \x01(\x01R\terrorRate\"R\n" +
+	"\x12State_CustomMetric\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\">\n" +
 	"\x04Task\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
 	"\x04type\x18\x02 \x01(\x04R\x04type\x12\x12\n" +
@@ -1007,7 +2622,112 @@ const file_nezha_proto_rawDesc = "" +
 	"\x13dashboard_boot_time\x18\x04 \x01(\x04R\x11dashboardBootTime\",\n" +
 	"\x02IP\x12\x12\n" +
 	"\x04ipv4\x18\x01 \x01(\tR\x04ipv4\x12\x12\n" +
-	"\x04ipv6\x18\x02 \x01(\tR\x04ipv6\"\x8a\x01\n" +
+	"\x04ipv6\x18\x02 \x01(\tR\x04ipv6\"\xaf\x01\n" +
+	"\fDockerReport\x12%\n" +
+	"\x0eengine_version\x18\x01 \x01(\tR\rengineVersion\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x126\n" +
+	"\n" +
+	"containers\x18\x03 \x03(\v2\x16.proto.DockerContainerR\n" +
+	"containers\x12*\n" +
+	"\x06images\x18\x04 \x03(\v2\x12.proto.DockerImageR\x06images\"\xa1\x03\n" +
+	"\x0fDockerContainer\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05image\x18\x03 \x01(\tR\x05image\x12\x14\n" +
+	"\x05state\x18\x04 \x01(\tR\x05state\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x16\n" +
+	"\x06health\x18\x06 \x01(\tR\x06health\x12#\n" +
+	"\rrestart_count\x18\a \x01(\x04R\frestartCount\x12\x18\n" +
+	"\acreated\x18\b \x01(\x03R\acreated\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\t \x01(\x03R\tstartedAt\x12\x10\n" +
+	"\x03cpu\x18\n" +
+	" \x01(\x01R\x03cpu\x12\x19\n" +
+	"\bmem_used\x18\v \x01(\x04R\amemUsed\x12\x1b\n" +
+	"\tmem_limit\x18\f \x01(\x04R\bmemLimit\x12&\n" +
+	"\x0fnet_in_transfer\x18\r \x01(\x04R\rnetInTransfer\x12(\n" +
+	"\x10net_out_transfer\x18\x0e \x01(\x04R\x0enetOutTransfer\x12\x14\n" +
+	"\x05ports\x18\x0f \x03(\tR\x05ports\"\x7f\n" +
+	"\vDockerImage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04tags\x18\x02 \x03(\tR\x04tags\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x04R\x04size\x12\x18\n" +
+	"\acreated\x18\x04 \x01(\x03R\acreated\x12\x1e\n" +
+	"\n" +
+	"containers\x18\x05 \x01(\x04R\n" +
+	"containers\"\xba\x02\n" +
+	"\x10KubernetesReport\x12\x18\n" +
+	"\acluster\x18\x01 \x01(\tR\acluster\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12%\n" +
+	"\x05nodes\x18\x04 \x03(\v2\x0f.proto.KubeNodeR\x05nodes\x124\n" +
+	"\n" +
+	"namespaces\x18\x05 \x03(\v2\x14.proto.KubeNamespaceR\n" +
+	"namespaces\x121\n" +
+	"\tworkloads\x18\x06 \x03(\v2\x13.proto.KubeWorkloadR\tworkloads\x12\"\n" +
+	"\x04pods\x18\a \x03(\v2\x0e.proto.KubePodR\x04pods\x12(\n" +
+	"\x06events\x18\b \x03(\v2\x10.proto.KubeEventR\x06events\"\xb8\x03\n" +
+	"\bKubeNode\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05ready\x18\x02 \x01(\bR\x05ready\x12$\n" +
+	"\runschedulable\x18\x03 \x01(\bR\runschedulable\x12\x14\n" +
+	"\x05roles\x18\x04 \x03(\tR\x05roles\x12'\n" +
+	"\x0fkubelet_version\x18\x05 \x01(\tR\x0ekubeletVersion\x12\x1f\n" +
+	"\vinternal_ip\x18\x06 \x01(\tR\n" +
+	"internalIp\x12!\n" +
+	"\fcpu_capacity\x18\a \x01(\x04R\vcpuCapacity\x12'\n" +
+	"\x0fmemory_capacity\x18\b \x01(\x04R\x0ememoryCapacity\x12'\n" +
+	"\x0fcpu_allocatable\x18\t \x01(\x04R\x0ecpuAllocatable\x12-\n" +
+	"\x12memory_allocatable\x18\n" +
+	" \x01(\x04R\x11memoryAllocatable\x12\x1b\n" +
+	"\tcpu_usage\x18\v \x01(\x04R\bcpuUsage\x12!\n" +
+	"\fmemory_usage\x18\f \x01(\x04R\vmemoryUsage\x12\x18\n" +
+	"\acreated\x18\r \x01(\x03R\acreated\"S\n" +
+	"\rKubeNamespace\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05phase\x18\x02 \x01(\tR\x05phase\x12\x18\n" +
+	"\acreated\x18\x03 \x01(\x03R\acreated\"\xd6\x01\n" +
+	"\fKubeWorkload\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x18\n" +
+	"\adesired\x18\x04 \x01(\rR\adesired\x12\x14\n" +
+	"\x05ready\x18\x05 \x01(\rR\x05ready\x12\x1c\n" +
+	"\tavailable\x18\x06 \x01(\rR\tavailable\x12\x18\n" +
+	"\aupdated\x18\a \x01(\rR\aupdated\x12\x18\n" +
+	"\acreated\x18\b \x01(\x03R\acreated\"\xdf\x03\n" +
+	"\aKubePod\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04node\x18\x03 \x01(\tR\x04node\x12\x14\n" +
+	"\x05phase\x18\x04 \x01(\tR\x05phase\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12\x1a\n" +
+	"\brestarts\x18\x06 \x01(\rR\brestarts\x12)\n" +
+	"\x10ready_containers\x18\a \x01(\rR\x0freadyContainers\x12)\n" +
+	"\x10total_containers\x18\b \x01(\rR\x0ftotalContainers\x12\x1d\n" +
+	"\n" +
+	"owner_kind\x18\t \x01(\tR\townerKind\x12\x1d\n" +
+	"\n" +
+	"owner_name\x18\n" +
+	" \x01(\tR\townerName\x12\x1f\n" +
+	"\vcpu_request\x18\v \x01(\x04R\n" +
+	"cpuRequest\x12%\n" +
+	"\x0ememory_request\x18\f \x01(\x04R\rmemoryRequest\x12\x1b\n" +
+	"\tcpu_usage\x18\r \x01(\x04R\bcpuUsage\x12!\n" +
+	"\fmemory_usage\x18\x0e \x01(\x04R\vmemoryUsage\x12\x18\n" +
+	"\acreated\x18\x0f \x01(\x03R\acreated\x12\x0e\n" +
+	"\x02ip\x18\x10 \x01(\tR\x02ip\"\xe4\x01\n" +
+	"\tKubeEvent\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\x12\x1f\n" +
+	"\vobject_kind\x18\x05 \x01(\tR\n" +
+	"objectKind\x12\x1f\n" +
+	"\vobject_name\x18\x06 \x01(\tR\n" +
+	"objectName\x12\x14\n" +
+	"\x05count\x18\a \x01(\rR\x05count\x12\x1b\n" +
+	"\tlast_seen\x18\b \x01(\x03R\blastSeen\"\x8a\x01\n" +
 	"\x0fTerminalCommand\x12\x1b\n" +
 	"\tstream_id\x18\x01 \x01(\tR\bstreamId\x12\x18\n" +
 	"\acommand\x18\x02 \x01(\tR\acommand\x12\x1f\n" +
@@ -1022,14 +2742,34 @@ const file_nezha_proto_rawDesc = "" +
 	"workingDir\"H\n" +
 	"\x14CommandCheckResponse\x12\x18\n" +
 	"\ablocked\x18\x01 \x01(\bR\ablocked\x12\x16\n" +
-	"\x06reason\x18\x02 \x01(\tR\x06reason2\xd2\x02\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"O\n" +
+	"\vStateSample\x12\"\n" +
+	"\x05state\x18\x01 \x01(\v2\f.proto.StateR\x05state\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\"=\n" +
+	"\rStateBackfill\x12,\n" +
+	"\asamples\x18\x01 \x03(\v2\x12.proto.StateSampleR\asamples\"O\n" +
+	"\rSystemdReport\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\x12(\n" +
+	"\x05units\x18\x02 \x03(\v2\x12.proto.SystemdUnitR\x05units\"\xca\x01\n" +
+	"\vSystemdUnit\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1d\n" +
+	"\n" +
+	"load_state\x18\x03 \x01(\tR\tloadState\x12!\n" +
+	"\factive_state\x18\x04 \x01(\tR\vactiveState\x12\x1b\n" +
+	"\tsub_state\x18\x05 \x01(\tR\bsubState\x12&\n" +
+	"\x0funit_file_state\x18\x06 \x01(\tR\runitFileState2\xc0\x04\n" +
 	"\fNezhaService\x127\n" +
 	"\x11ReportSystemState\x12\f.proto.State\x1a\x0e.proto.Receipt\"\x00(\x010\x01\x121\n" +
 	"\x10ReportSystemInfo\x12\v.proto.Host\x1a\x0e.proto.Receipt\"\x00\x123\n" +
 	"\vRequestTask\x12\x11.proto.TaskResult\x1a\v.proto.Task\"\x00(\x010\x01\x12:\n" +
 	"\bIOStream\x12\x13.proto.IOStreamData\x1a\x13.proto.IOStreamData\"\x00(\x010\x01\x12+\n" +
 	"\vReportGeoIP\x12\f.proto.GeoIP\x1a\f.proto.GeoIP\"\x00\x128\n" +
-	"\x11ReportSystemInfo2\x12\v.proto.Host\x1a\x14.proto.Uint64Receipt\"\x00B\tZ\a./protob\x06proto3"
+	"\x11ReportSystemInfo2\x12\v.proto.Host\x1a\x14.proto.Uint64Receipt\"\x00\x125\n" +
+	"\fReportDocker\x12\x13.proto.DockerReport\x1a\x0e.proto.Receipt\"\x00\x12=\n" +
+	"\x10ReportKubernetes\x12\x17.proto.KubernetesReport\x1a\x0e.proto.Receipt\"\x00\x12=\n" +
+	"\x13ReportStateBackfill\x12\x14.proto.StateBackfill\x1a\x0e.proto.Receipt\"\x00\x127\n" +
+	"\rReportSystemd\x12\x14.proto.SystemdReport\x1a\x0e.proto.Receipt\"\x00B\tZ\a./protob\x06proto3"
 
 var (
 	file_nezha_proto_rawDescOnce sync.Once
@@ -1043,42 +2783,81 @@ func file_nezha_proto_rawDescGZIP() []byte {
 	return file_nezha_proto_rawDescData
 }
 
-var file_nezha_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_nezha_proto_msgTypes = make([]protoimpl.MessageInfo, 30)
 var file_nezha_proto_goTypes = []any{
 	(*Host)(nil),                    // 0: proto.Host
 	(*State)(nil),                   // 1: proto.State
 	(*State_SensorTemperature)(nil), // 2: proto.State_SensorTemperature
-	(*Task)(nil),                    // 3: proto.Task
-	(*TaskResult)(nil),              // 4: proto.TaskResult
-	(*Receipt)(nil),                 // 5: proto.Receipt
-	(*Uint64Receipt)(nil),           // 6: proto.Uint64Receipt
-	(*IOStreamData)(nil),            // 7: proto.IOStreamData
-	(*GeoIP)(nil),                   // 8: proto.GeoIP
-	(*IP)(nil),                      // 9: proto.IP
-	(*TerminalCommand)(nil),         // 10: proto.TerminalCommand
-	(*CommandCheckRequest)(nil),     // 11: proto.CommandCheckRequest
-	(*CommandCheckResponse)(nil),    // 12: proto.CommandCheckResponse
+	(*State_Mount)(nil),             // 3: proto.State_Mount
+	(*State_DiskIO)(nil),            // 4: proto.State_DiskIO
+	(*State_NIC)(nil),               // 5: proto.State_NIC
+	(*State_CustomMetric)(nil),      // 6: proto.State_CustomMetric
+	(*Task)(nil),                    // 7: proto.Task
+	(*TaskResult)(nil),              // 8: proto.TaskResult
+	(*Receipt)(nil),                 // 9: proto.Receipt
+	(*Uint64Receipt)(nil),           // 10: proto.Uint64Receipt
+	(*IOStreamData)(nil),            // 11: proto.IOStreamData
+	(*GeoIP)(nil),                   // 12: proto.GeoIP
+	(*IP)(nil),                      // 13: proto.IP
+	(*DockerReport)(nil),            // 14: proto.DockerReport
+	(*DockerContainer)(nil),         // 15: proto.DockerContainer
+	(*DockerImage)(nil),             // 16: proto.DockerImage
+	(*KubernetesReport)(nil),        // 17: proto.KubernetesReport
+	(*KubeNode)(nil),                // 18: proto.KubeNode
+	(*KubeNamespace)(nil),           // 19: proto.KubeNamespace
+	(*KubeWorkload)(nil),            // 20: proto.KubeWorkload
+	(*KubePod)(nil),                 // 21: proto.KubePod
+	(*KubeEvent)(nil),               // 22: proto.KubeEvent
+	(*TerminalCommand)(nil),         // 23: proto.TerminalCommand
+	(*CommandCheckRequest)(nil),     // 24: proto.CommandCheckRequest
+	(*CommandCheckResponse)(nil),    // 25: proto.CommandCheckResponse
+	(*StateSample)(nil),             // 26: proto.StateSample
+	(*StateBackfill)(nil),           // 27: proto.StateBackfill
+	(*SystemdReport)(nil),           // 28: proto.SystemdReport
+	(*SystemdUnit)(nil),             // 29: proto.SystemdUnit
 }
 var file_nezha_proto_depIdxs = []int32{
-	2, // 0: proto.State.temperatures:type_name -> proto.State_SensorTemperature
-	9, // 1: proto.GeoIP.ip:type_name -> proto.IP
-	1, // 2: proto.NezhaService.ReportSystemState:input_type -> proto.State
-	0, // 3: proto.NezhaService.ReportSystemInfo:input_type -> proto.Host
-	4, // 4: proto.NezhaService.RequestTask:input_type -> proto.TaskResult
-	7, // 5: proto.NezhaService.IOStream:input_type -> proto.IOStreamData
-	8, // 6: proto.NezhaService.ReportGeoIP:input_type -> proto.GeoIP
-	0, // 7: proto.NezhaService.ReportSystemInfo2:input_type -> proto.Host
-	5, // 8: proto.NezhaService.ReportSystemState:output_type -> proto.Receipt
-	5, // 9: proto.NezhaService.ReportSystemInfo:output_type -> proto.Receipt
-	3, // 10: proto.NezhaService.RequestTask:output_type -> proto.Task
-	7, // 11: proto.NezhaService.IOStream:output_type -> proto.IOStreamData
-	8, // 12: proto.NezhaService.ReportGeoIP:output_type -> proto.GeoIP
-	6, // 13: proto.NezhaService.ReportSystemInfo2:output_type -> proto.Uint64Receipt
-	8, // [8:14] is the sub-list for method output_type
-	2, // [2:8] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	2,  // 0: proto.State.temperatures:type_name -> proto.State_SensorTemperature
+	3,  // 1: proto.State.mounts:type_name -> proto.State_Mount
+	4,  // 2: proto.State.disk_io:type_name -> proto.State_DiskIO
+	5,  // 3: proto.State.nics:type_name -> proto.State_NIC
+	6,  // 4: proto.State.custom_metrics:type_name -> proto.State_CustomMetric
+	13, // 5: proto.GeoIP.ip:type_name -> proto.IP
+	15, // 6: proto.DockerReport.containers:type_name -> proto.DockerContainer
+	16, // 7: proto.DockerReport.images:type_name -> proto.DockerImage
+	18, // 8: proto.KubernetesReport.nodes:type_name -> proto.KubeNode
+	19, // 9: proto.KubernetesReport.namespaces:type_name -> proto.KubeNamespace
+	20, // 10: proto.KubernetesReport.workloads:type_name -> proto.KubeWorkload
+	21, // 11: proto.KubernetesReport.pods:type_name -> proto.KubePod
+	22, // 12: proto.KubernetesReport.events:type_name -> proto.KubeEvent
+	1,  // 13: proto.StateSample.state:type_name -> proto.State
+	26, // 14: proto.StateBackfill.samples:type_name -> proto.StateSample
+	29, // 15: proto.SystemdReport.units:type_name -> proto.SystemdUnit
+	1,  // 16: proto.NezhaService.ReportSystemState:input_type -> proto.State
+	0,  // 17: proto.NezhaService.ReportSystemInfo:input_type -> proto.Host
+	8,  // 18: proto.NezhaService.RequestTask:input_type -> proto.TaskResult
+	11, // 19: proto.NezhaService.IOStream:input_type -> proto.IOStreamData
+	12, // 20: proto.NezhaService.ReportGeoIP:input_type -> proto.GeoIP
+	0,  // 21: proto.NezhaService.ReportSystemInfo2:input_type -> proto.Host
+	14, // 22: proto.NezhaService.ReportDocker:input_type -> proto.DockerReport
+	17, // 23: proto.NezhaService.ReportKubernetes:input_type -> proto.KubernetesReport
+	27, // 24: proto.NezhaService.ReportStateBackfill:input_type -> proto.StateBackfill
+	28, // 25: proto.NezhaService.ReportSystemd:input_type -> proto.SystemdReport
+	9,  // 26: proto.NezhaService.ReportSystemState:output_type -> proto.Receipt
+	9,  // 27: proto.NezhaService.ReportSystemInfo:output_type -> proto.Receipt
+	7,  // 28: proto.NezhaService.RequestTask:output_type -> proto.Task
+	11, // 29: proto.NezhaService.IOStream:output_type -> proto.IOStreamData
+	12, // 30: proto.NezhaService.ReportGeoIP:output_type -> proto.GeoIP
+	10, // 31: proto.NezhaService.ReportSystemInfo2:output_type -> proto.Uint64Receipt
+	9,  // 32: proto.NezhaService.ReportDocker:output_type -> proto.Receipt
+	9,  // 33: proto.NezhaService.ReportKubernetes:output_type -> proto.Receipt
+	9,  // 34: proto.NezhaService.ReportStateBackfill:output_type -> proto.Receipt
+	9,  // 35: proto.NezhaService.ReportSystemd:output_type -> proto.Receipt
+	26, // [26:36] is the sub-list for method output_type
+	16, // [16:26] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_nezha_proto_init() }
@@ -1092,7 +2871,7 @@ func file_nezha_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_nezha_proto_rawDesc), len(file_nezha_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   13,
+			NumMessages:   30,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
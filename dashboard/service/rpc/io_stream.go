@@ -17,6 +17,24 @@ type ioStreamContext struct {
 	agentIoConnectCh chan struct{}
 	userIoChOnce     sync.Once
 	agentIoChOnce    sync.Once
+
+	// 以下字段仅在可重连的流（终端）中使用
+	attachMu    sync.Mutex
+	pumpOnce    sync.Once
+	pumping     bool
+	closing     bool
+	agentDoneCh chan struct{}
+	detachTimer *time.Timer
+	filter      StreamFilter
+	agentMu     sync.Mutex
+}
+
+// StreamFilter 截获可重连流两端的数据，toAgent 用于直接向 agent 回复
+type StreamFilter interface {
+	// FilterOutput 处理 agent 的输出，返回转发给用户的数据，返回后 p 会被复用
+	FilterOutput(p []byte, toAgent func([]byte)) []byte
+	// FilterInput 处理用户的输入，返回转发给 agent 的数据
+	FilterInput(p []byte, toAgent func([]byte)) []byte
 }
 
 type bp struct {
@@ -38,9 +56,17 @@ func (s *NezhaHandler) CreateStream(streamId string) {
 	s.ioStreams[streamId] = &ioStreamContext{
 		userIoConnectCh:  make(chan struct{}),
 		agentIoConnectCh: make(chan struct{}),
+		agentDoneCh:      make(chan struct{}),
 	}
 }
 
+// StreamCount 当前打开的 IOStream 数量
+func (s *NezhaHandler) StreamCount() int {
+	s.ioStreamMutex.RLock()
+	defer s.ioStreamMutex.RUnlock()
+	return len(s.ioStreams)
+}
+
 func (s *NezhaHandler) GetStream(streamId string) (*ioStreamContext, error) {
 	s.ioStreamMutex.RLock()
 	defer s.ioStreamMutex.RUnlock()
@@ -57,6 +83,12 @@ func (s *NezhaHandler) CloseStream(streamId string) error {
 	defer s.ioStreamMutex.Unlock()
 
 	if ctx, ok := s.ioStreams[streamId]; ok {
+		ctx.attachMu.Lock()
+		ctx.closing = true
+		if ctx.detachTimer != nil {
+			ctx.detachTimer.Stop()
+		}
+		ctx.attachMu.Unlock()
 		if ctx.userIo != nil {
 			ctx.userIo.Close()
 		}
@@ -69,6 +101,19 @@ func (s *NezhaHandler) CloseStream(streamId string) error {
 	return nil
 }
 
+// SetStreamFilter 为可重连的流设置过滤器，需要在挂载用户之前调用
+func (s *NezhaHandler) SetStreamFilter(streamId string, filter StreamFilter) error {
+	stream, err := s.GetStream(streamId)
+	if err != nil {
+		return err
+	}
+
+	stream.attachMu.Lock()
+	stream.filter = filter
+	stream.attachMu.Unlock()
+	return nil
+}
+
 func (s *NezhaHandler) UserConnected(streamId string, userIo io.ReadWriteCloser) error {
 	stream, err := s.GetStream(streamId)
 	if err != nil {
@@ -103,6 +148,42 @@ func (s *NezhaHandler) StartStream(streamId string, timeout time.Duration) error
 		return err
 	}
 
+	if err = stream.waitConnected(timeout); err != nil {
+		return err
+	}
+
+	isDone := new(atomic.Bool)
+	endCh := make(chan struct{})
+
+	go func() {
+		bp := bufPool.Get().(*bp)
+		defer bufPool.Put(bp)
+		_, innerErr := io.CopyBuffer(stream.userIo, stream.agentIo, bp.buf)
+		if innerErr != nil {
+			err = innerErr
+		}
+		if isDone.CompareAndSwap(false, true) {
+			close(endCh)
+		}
+	}()
+	go func() {
+		bp := bufPool.Get().(*bp)
+		defer bufPool.Put(bp)
+		_, innerErr := io.CopyBuffer(stream.agentIo, stream.userIo, bp.buf)
+		if innerErr != nil {
+			err = innerErr
+		}
+		if isDone.CompareAndSwap(false, true) {
+			close(endCh)
+		}
+	}()
+
+	<-endCh
+	return err
+}
+
+// waitConnected 等待用户与 agent 两端均已连接
+func (stream *ioStreamContext) waitConnected(timeout time.Duration) error {
 	timeoutTimer := time.NewTimer(timeout)
 
 LOOP:
@@ -134,32 +215,180 @@ LOOP:
 		return singleton.Localizer.ErrorT("timeout: agent connection not established")
 	}
 
-	isDone := new(atomic.Bool)
-	endCh := make(chan struct{})
+	return nil
+}
 
-	go func() {
-		bp := bufPool.Get().(*bp)
-		defer bufPool.Put(bp)
-		_, innerErr := io.CopyBuffer(stream.userIo, stream.agentIo, bp.buf)
-		if innerErr != nil {
-			err = innerErr
+// AttachUser 将用户连接挂载到可重连的流上，阻塞直到该用户连接断开或 agent 侧结束。
+// 与 StartStream 不同，用户断开后 agent 侧的输出会被持续读取并丢弃，流保持打开，
+// 之后可以再次调用 AttachUser 重新挂载；重新挂载时会先向 agent 发送 onReattach。
+// 返回值 agentClosed 表示 agent 侧已经结束，该流不能再被重连。
+func (s *NezhaHandler) AttachUser(streamId string, userIo io.ReadWriteCloser, timeout time.Duration, onReattach []byte) (agentClosed bool, err error) {
+	stream, err := s.GetStream(streamId)
+	if err != nil {
+		return false, err
+	}
+
+	stream.attachMu.Lock()
+	if stream.closing {
+		stream.attachMu.Unlock()
+		return true, errors.New("stream not found")
+	}
+	if stream.detachTimer != nil {
+		stream.detachTimer.Stop()
+		stream.detachTimer = nil
+	}
+	// 同一个流只允许一个用户连接，新的连接会顶替旧的连接
+	if stream.userIo != nil {
+		stream.userIo.Close()
+	}
+	stream.userIo = userIo
+	reattach := stream.pumping
+	stream.attachMu.Unlock()
+
+	stream.userIoChOnce.Do(func() {
+		close(stream.userIoConnectCh)
+	})
+
+	if !reattach {
+		if err = stream.waitConnected(timeout); err != nil {
+			return true, err
 		}
-		if isDone.CompareAndSwap(false, true) {
-			close(endCh)
+		stream.pumpOnce.Do(func() {
+			stream.attachMu.Lock()
+			stream.pumping = true
+			stream.attachMu.Unlock()
+			go stream.pumpAgentOutput()
+		})
+	} else if len(onReattach) > 0 {
+		if err = stream.writeAgent(onReattach); err != nil {
+			return true, err
 		}
-	}()
-	go func() {
-		bp := bufPool.Get().(*bp)
-		defer bufPool.Put(bp)
-		_, innerErr := io.CopyBuffer(stream.agentIo, stream.userIo, bp.buf)
-		if innerErr != nil {
-			err = innerErr
+	}
+
+	bp := bufPool.Get().(*bp)
+	_, err = io.CopyBuffer(streamInput{stream}, userIo, bp.buf)
+	bufPool.Put(bp)
+
+	stream.attachMu.Lock()
+	if stream.userIo == userIo {
+		stream.userIo = nil
+	}
+	stream.attachMu.Unlock()
+
+	select {
+	case <-stream.agentDoneCh:
+		return true, err
+	default:
+		return false, err
+	}
+}
+
+// writeAgent 过滤器的回复与用户输入可能同时写入 agent 侧，需要串行
+func (stream *ioStreamContext) writeAgent(p []byte) error {
+	stream.agentMu.Lock()
+	defer stream.agentMu.Unlock()
+	_, err := stream.agentIo.Write(p)
+	return err
+}
+
+func (stream *ioStreamContext) replyAgent(p []byte) {
+	stream.writeAgent(p)
+}
+
+// streamInput 用户输入经过过滤器后写入 agent 侧
+type streamInput struct {
+	stream *ioStreamContext
+}
+
+func (w streamInput) Write(p []byte) (int, error) {
+	data := p
+	if w.stream.filter != nil {
+		if data = w.stream.filter.FilterInput(p, w.stream.replyAgent); len(data) == 0 {
+			return len(p), nil
 		}
-		if isDone.CompareAndSwap(false, true) {
-			close(endCh)
+	}
+	if err := w.stream.writeAgent(data); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// pumpAgentOutput 持续读取 agent 侧输出并转发给当前挂载的用户连接，没有用户时直接丢弃
+func (stream *ioStreamContext) pumpAgentOutput() {
+	bp := bufPool.Get().(*bp)
+	defer bufPool.Put(bp)
+
+	for {
+		n, err := stream.agentIo.Read(bp.buf)
+		if n > 0 {
+			data := bp.buf[:n]
+			if stream.filter != nil {
+				data = stream.filter.FilterOutput(data, stream.replyAgent)
+			}
+			stream.attachMu.Lock()
+			userIo := stream.userIo
+			stream.attachMu.Unlock()
+			if userIo != nil && len(data) > 0 {
+				userIo.Write(data)
+			}
 		}
-	}()
+		if err != nil {
+			close(stream.agentDoneCh)
+			stream.attachMu.Lock()
+			if stream.userIo != nil {
+				stream.userIo.Close()
+			}
+			stream.attachMu.Unlock()
+			return
+		}
+	}
+}
 
-	<-endCh
-	return err
+// ScheduleClose 在用户断开后保留流 grace 时长，期间没有重新挂载用户则关闭流并调用 onClose
+func (s *NezhaHandler) ScheduleClose(streamId string, grace time.Duration, onClose func()) {
+	stream, err := s.GetStream(streamId)
+	if err != nil {
+		return
+	}
+
+	stream.attachMu.Lock()
+	defer stream.attachMu.Unlock()
+
+	if stream.userIo != nil || stream.closing {
+		return
+	}
+	if stream.detachTimer != nil {
+		stream.detachTimer.Stop()
+	}
+	stream.detachTimer = time.AfterFunc(grace, func() {
+		stream.attachMu.Lock()
+		if stream.userIo != nil || stream.closing {
+			stream.attachMu.Unlock()
+			return
+		}
+		stream.attachMu.Unlock()
+
+		s.CloseStream(streamId)
+		if onClose != nil {
+			onClose()
+		}
+	})
+}
+
+// IsDetached 判断可重连的流当前是否处于没有用户连接的保留状态
+func (s *NezhaHandler) IsDetached(streamId string) bool {
+	stream, err := s.GetStream(streamId)
+	if err != nil {
+		return false
+	}
+
+	stream.attachMu.Lock()
+	defer stream.attachMu.Unlock()
+
+	select {
+	case <-stream.agentDoneCh:
+		return false
+	default:
+	}
+	return stream.pumping && stream.userIo == nil && !stream.closing
 }
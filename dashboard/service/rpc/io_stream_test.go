@@ -1,7 +1,9 @@
 package rpc
 
 import (
+	"bytes"
 	"io"
+	"net"
 	"reflect"
 	"testing"
 	"time"
@@ -98,6 +100,174 @@ func TestIOStream(t *testing.T) {
 	})
 }
 
+func TestIOStreamReattach(t *testing.T) {
+	handler := NewNezhaHandler()
+
+	const testStreamID = "eeeeeeee-eeee-eeee-eeee-eeeeeeeeeeee"
+
+	handler.CreateStream(testStreamID)
+	agentSide, agentRemote := net.Pipe()
+	defer agentRemote.Close()
+	handler.AgentConnected(testStreamID, agentSide)
+
+	replay := []byte{2}
+
+	attach := func() (net.Conn, chan bool) {
+		userSide, userRemote := net.Pipe()
+		done := make(chan bool, 1)
+		go func() {
+			agentClosed, _ := handler.AttachUser(testStreamID, userSide, time.Second*10, replay)
+			done <- agentClosed
+		}()
+		return userRemote, done
+	}
+
+	expectRead := func(r io.Reader, want []byte) {
+		t.Helper()
+		b := make([]byte, len(want))
+		if _, err := io.ReadFull(r, b); err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		if !reflect.DeepEqual(want, b) {
+			t.Fatalf("expected %v, but got %v", want, b)
+		}
+	}
+
+	user, done := attach()
+	if _, err := user.Write([]byte{0, 'l', 's'}); err != nil {
+		t.Fatalf("write to user failed: %v", err)
+	}
+	expectRead(agentRemote, []byte{0, 'l', 's'})
+	if _, err := agentRemote.Write([]byte("hello")); err != nil {
+		t.Fatalf("write to agent failed: %v", err)
+	}
+	expectRead(user, []byte("hello"))
+
+	user.Close()
+	if agentClosed := <-done; agentClosed {
+		t.Fatal("agent should still be alive after user detached")
+	}
+	if !handler.IsDetached(testStreamID) {
+		t.Fatal("stream should be detached")
+	}
+
+	// 用户断开期间的输出不应阻塞 agent
+	if _, err := agentRemote.Write([]byte("lost")); err != nil {
+		t.Fatalf("write to agent failed: %v", err)
+	}
+
+	user, done = attach()
+	expectRead(agentRemote, replay)
+	go agentRemote.Write([]byte("again"))
+
+	// 断开期间的输出可能恰好投递给新连接，只校验最新输出
+	var received []byte
+	b := make([]byte, 16)
+	for !bytes.HasSuffix(received, []byte("again")) {
+		n, err := user.Read(b)
+		if err != nil {
+			t.Fatalf("read user failed: %v", err)
+		}
+		received = append(received, b[:n]...)
+	}
+
+	agentRemote.Close()
+	if agentClosed := <-done; !agentClosed {
+		t.Fatal("expected agentClosed after agent side ended")
+	}
+	user.Close()
+	handler.CloseStream(testStreamID)
+}
+
+func TestIOStreamScheduleClose(t *testing.T) {
+	handler := NewNezhaHandler()
+
+	const testStreamID = "dddddddd-dddd-dddd-dddd-dddddddddddd"
+
+	handler.CreateStream(testStreamID)
+	agentSide, agentRemote := net.Pipe()
+	defer agentRemote.Close()
+	handler.AgentConnected(testStreamID, agentSide)
+
+	userSide, userRemote := net.Pipe()
+	done := make(chan struct{})
+	go func() {
+		handler.AttachUser(testStreamID, userSide, time.Second*10, nil)
+		close(done)
+	}()
+	userRemote.Write([]byte{0})
+	expectBuf := make([]byte, 1)
+	agentRemote.Read(expectBuf)
+	userRemote.Close()
+	<-done
+
+	closed := make(chan struct{})
+	handler.ScheduleClose(testStreamID, time.Millisecond*50, func() {
+		close(closed)
+	})
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second * 5):
+		t.Fatal("stream was not closed after grace period")
+	}
+	if _, err := handler.GetStream(testStreamID); err == nil {
+		t.Fatal("stream should be removed after grace period")
+	}
+}
+
+// echoFilter 将 agent 输出中的 ping 直接回复给 agent，并丢弃用户输入中的 x
+type echoFilter struct{}
+
+func (echoFilter) FilterOutput(p []byte, toAgent func([]byte)) []byte {
+	if bytes.Equal(p, []byte("ping")) {
+		toAgent([]byte("pong"))
+		return nil
+	}
+	return p
+}
+
+func (echoFilter) FilterInput(p []byte, toAgent func([]byte)) []byte {
+	return bytes.ReplaceAll(p, []byte("x"), nil)
+}
+
+func TestIOStreamFilter(t *testing.T) {
+	handler := NewNezhaHandler()
+
+	const testStreamID = "cccccccc-cccc-cccc-cccc-cccccccccccc"
+
+	handler.CreateStream(testStreamID)
+	handler.SetStreamFilter(testStreamID, echoFilter{})
+	agentSide, agentRemote := net.Pipe()
+	defer agentRemote.Close()
+	handler.AgentConnected(testStreamID, agentSide)
+
+	userSide, userRemote := net.Pipe()
+	defer userRemote.Close()
+	go handler.AttachUser(testStreamID, userSide, time.Second*10, nil)
+
+	b := make([]byte, 16)
+	if _, err := agentRemote.Write([]byte("ping")); err != nil {
+		t.Fatalf("write to agent failed: %v", err)
+	}
+	if n, err := agentRemote.Read(b); err != nil || string(b[:n]) != "pong" {
+		t.Fatalf("expected pong from filter, got %q, %v", b[:n], err)
+	}
+
+	if _, err := userRemote.Write([]byte("axb")); err != nil {
+		t.Fatalf("write to user failed: %v", err)
+	}
+	if n, err := agentRemote.Read(b); err != nil || string(b[:n]) != "ab" {
+		t.Fatalf("expected filtered input, got %q, %v", b[:n], err)
+	}
+
+	go agentRemote.Write([]byte("hello"))
+	if n, err := userRemote.Read(b); err != nil || string(b[:n]) != "hello" {
+		t.Fatalf("expected output, got %q, %v", b[:n], err)
+	}
+	handler.CloseStream(testStreamID)
+}
+
 func newPipeReadWriter() io.ReadWriteCloser {
 	r, w := io.Pipe()
 	return struct {
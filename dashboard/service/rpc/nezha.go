@@ -27,6 +27,10 @@ type NezhaHandler struct {
 	Auth          *authHandler
 	ioStreams     map[string]*ioStreamContext
 	ioStreamMutex *sync.RWMutex
+
+	portForwardMu     sync.Mutex
+	portForwards      map[uint64]*portForwardListener
+	portForwardErrors map[uint64]string
 }
 
 func NewNezhaHandler() *NezhaHandler {
@@ -34,6 +38,9 @@ func NewNezhaHandler() *NezhaHandler {
 		Auth:          &authHandler{},
 		ioStreamMutex: new(sync.RWMutex),
 		ioStreams:     make(map[string]*ioStreamContext),
+
+		portForwards:      make(map[uint64]*portForwardListener),
+		portForwardErrors: make(map[uint64]string),
 	}
 }
 
@@ -46,11 +53,17 @@ func (s *NezhaHandler) RequestTask(stream pb.NezhaService_RequestTaskServer) err
 
 	server, _ := singleton.ServerShared.Get(clientID)
 	server.TaskStream = stream
+	go singleton.AutoSSHShared.Restore(clientID)
+
 	var result *pb.TaskResult
 	for {
 		result, err = stream.Recv()
 		if err != nil {
 			log.Printf("NEZHA>> RequestTask error: %v, clientID: %d\n", err, clientID)
+			// 已有新的任务流连接时由其恢复映射
+			if server.TaskStream == stream {
+				singleton.AutoSSHShared.Detach(clientID)
+			}
 			return err
 		}
 		switch result.GetType() {
@@ -82,6 +95,16 @@ func (s *NezhaHandler) RequestTask(stream pb.NezhaService_RequestTaskServer) err
 				}
 				server.ConfigCache <- result.Data
 			}
+		case model.TaskTypeAutoSSH:
+			singleton.AutoSSHShared.ApplyStatusReport(clientID, result.GetData())
+		case model.TaskTypeDocker:
+			singleton.DockerShared.ApplyResult(clientID, result)
+		case model.TaskTypeProcess:
+			singleton.ProcessShared.ApplyResult(clientID, result)
+		case model.TaskTypeSystemd:
+			singleton.SystemdShared.ApplyResult(clientID, result)
+		case model.TaskTypeLog:
+			singleton.LogShared.ApplyResult(clientID, result)
 		default:
 			if model.IsServiceSentinelNeeded(result.GetType()) {
 				singleton.ServiceSentinelShared.Dispatch(singleton.ReportData{
@@ -114,6 +137,7 @@ func (s *NezhaHandler) ReportSystemState(stream pb.NezhaService_ReportSystemStat
 
 		server.LastActive = time.Now()
 		server.State = &innerState
+		singleton.MetricsShared.Add(clientID, &innerState, server.LastActive)
 
 		// 应对 dashboard / agent 重启的情况，如果从未记录过，先打点，等到小时时间点时入库
 		if server.PrevTransferInSnapshot == 0 || server.PrevTransferOutSnapshot == 0 {
@@ -169,6 +193,66 @@ func (s *NezhaHandler) ReportSystemInfo2(c context.Context, r *pb.Host) (*pb.Uin
 	return &pb.Uint64Receipt{Data: singleton.DashboardBootTime}, nil
 }
 
+func (s *NezhaHandler) ReportDocker(c context.Context, r *pb.DockerReport) (*pb.Receipt, error) {
+	clientID, err := s.Auth.Check(c)
+	if err != nil {
+		return nil, err
+	}
+
+	server, ok := singleton.ServerShared.Get(clientID)
+	if !ok || server == nil {
+		return nil, errors.New("server not found")
+	}
+
+	singleton.DockerShared.Report(server, r, time.Now())
+	return &pb.Receipt{Proced: true}, nil
+}
+
+func (s *NezhaHandler) ReportKubernetes(c context.Context, r *pb.KubernetesReport) (*pb.Receipt, error) {
+	clientID, err := s.Auth.Check(c)
+	if err != nil {
+		return nil, err
+	}
+
+	server, ok := singleton.ServerShared.Get(clientID)
+	if !ok || server == nil {
+		return nil, errors.New("server not found")
+	}
+
+	singleton.ReportKubernetes(server, r, time.Now())
+	return &pb.Receipt{Proced: true}, nil
+}
+
+func (s *NezhaHandler) ReportSystemd(c context.Context, r *pb.SystemdReport) (*pb.Receipt, error) {
+	clientID, err := s.Auth.Check(c)
+	if err != nil {
+		return nil, err
+	}
+
+	server, ok := singleton.ServerShared.Get(clientID)
+	if !ok || server == nil {
+		return nil, errors.New("server not found")
+	}
+
+	singleton.SystemdShared.Report(server, r, time.Now())
+	return &pb.Receipt{Proced: true}, nil
+}
+
+func (s *NezhaHandler) ReportStateBackfill(c context.Context, r *pb.StateBackfill) (*pb.Receipt, error) {
+	clientID, err := s.Auth.Check(c)
+	if err != nil {
+		return nil, err
+	}
+
+	server, ok := singleton.ServerShared.Get(clientID)
+	if !ok || server == nil {
+		return nil, errors.New("server not found")
+	}
+
+	singleton.BackfillState(server, r, time.Now())
+	return &pb.Receipt{Proced: true}, nil
+}
+
 func (s *NezhaHandler) IOStream(stream pb.NezhaService_IOStreamServer) error {
 	if _, err := s.Auth.Check(stream.Context()); err != nil {
 		return err
@@ -0,0 +1,278 @@
+package rpc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/hashicorp/go-uuid"
+
+	"github.com/nezhahq/nezha/model"
+	pb "github.com/nezhahq/nezha/proto"
+	"github.com/nezhahq/nezha/service/singleton"
+)
+
+const (
+	portForwardConnectTimeout = 10 * time.Second
+	portForwardUDPIdleTimeout = 2 * time.Minute
+	// UDP 数据报在流中以 2 字节长度前缀分帧
+	portForwardMaxDatagram = 65535
+)
+
+type portForwardListener struct {
+	pf  *model.PortForward
+	tcp net.Listener
+	udp net.PacketConn
+
+	mu       sync.Mutex
+	sessions map[string]*udpSession
+}
+
+// StartPortForward 按配置重新打开端口转发的监听
+func (s *NezhaHandler) StartPortForward(pf *model.PortForward) error {
+	s.StopPortForward(pf.ID)
+	if !pf.Enabled {
+		return nil
+	}
+
+	addr := net.JoinHostPort(singleton.Conf.ListenHost, strconv.Itoa(pf.ListenPort))
+	l := &portForwardListener{pf: pf, sessions: make(map[string]*udpSession)}
+	var err error
+	switch pf.Network {
+	case model.PortForwardNetworkTCP:
+		l.tcp, err = net.Listen("tcp", addr)
+	case model.PortForwardNetworkUDP:
+		l.udp, err = net.ListenPacket("udp", addr)
+	default:
+		err = fmt.Errorf("unsupported network: %s", pf.Network)
+	}
+
+	s.portForwardMu.Lock()
+	defer s.portForwardMu.Unlock()
+	if err != nil {
+		s.portForwardErrors[pf.ID] = err.Error()
+		return err
+	}
+	delete(s.portForwardErrors, pf.ID)
+	s.portForwards[pf.ID] = l
+
+	if l.tcp != nil {
+		go s.acceptPortForward(l)
+	} else {
+		go s.readPortForwardUDP(l)
+	}
+	log.Printf("NEZHA>> Port forward %d listening on %s/%s -> %s", pf.ID, addr, pf.Network, pf.Target)
+	return nil
+}
+
+// StopPortForward 关闭端口转发的监听，已建立的连接随之断开
+func (s *NezhaHandler) StopPortForward(id uint64) {
+	s.portForwardMu.Lock()
+	l, ok := s.portForwards[id]
+	delete(s.portForwards, id)
+	delete(s.portForwardErrors, id)
+	s.portForwardMu.Unlock()
+	if !ok {
+		return
+	}
+
+	if l.tcp != nil {
+		l.tcp.Close()
+	}
+	if l.udp != nil {
+		l.udp.Close()
+		l.mu.Lock()
+		for _, session := range l.sessions {
+			session.Close()
+		}
+		l.mu.Unlock()
+	}
+}
+
+// PortForwardState 返回监听状态与最近一次监听失败的原因
+func (s *NezhaHandler) PortForwardState(id uint64) (bool, string) {
+	s.portForwardMu.Lock()
+	defer s.portForwardMu.Unlock()
+	_, ok := s.portForwards[id]
+	return ok, s.portForwardErrors[id]
+}
+
+func (s *NezhaHandler) acceptPortForward(l *portForwardListener) {
+	for {
+		conn, err := l.tcp.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			if err := s.servePortForward(l.pf, conn); err != nil {
+				log.Printf("NEZHA>> Port forward %d error: %v", l.pf.ID, err)
+			}
+		}()
+	}
+}
+
+// servePortForward 通过 IOStream 将用户连接转发到 agent，阻塞直到任意一端结束
+func (s *NezhaHandler) servePortForward(pf *model.PortForward, userIo io.ReadWriteCloser) error {
+	server, _ := singleton.ServerShared.Get(pf.ServerID)
+	if server == nil || server.TaskStream == nil {
+		return errors.New("server not found or not connected")
+	}
+
+	streamId, err := uuid.GenerateUUID()
+	if err != nil {
+		return err
+	}
+	s.CreateStream(streamId)
+	defer s.CloseStream(streamId)
+
+	taskData, err := json.Marshal(model.TaskNAT{
+		StreamID: streamId,
+		Host:     pf.Target,
+		Network:  pf.Network,
+	})
+	if err != nil {
+		return err
+	}
+	if err := server.TaskStream.Send(&pb.Task{
+		Type: model.TaskTypeNAT,
+		Data: string(taskData),
+	}); err != nil {
+		return err
+	}
+
+	userIo = singleton.TunnelStatsShared.Meter(model.TunnelKindPortForward, pf.ID, pf.ServerID, userIo, pf.RateLimit)
+	if err := s.UserConnected(streamId, userIo); err != nil {
+		return err
+	}
+	return s.StartStream(streamId, portForwardConnectTimeout)
+}
+
+func (s *NezhaHandler) readPortForwardUDP(l *portForwardListener) {
+	buf := make([]byte, portForwardMaxDatagram)
+	for {
+		n, addr, err := l.udp.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+
+		l.mu.Lock()
+		session, ok := l.sessions[addr.String()]
+		if !ok {
+			session = newUDPSession(l.udp, addr)
+			l.sessions[addr.String()] = session
+			go func() {
+				if err := s.servePortForward(l.pf, session); err != nil {
+					log.Printf("NEZHA>> Port forward %d error: %v", l.pf.ID, err)
+				}
+				session.Close()
+				l.mu.Lock()
+				delete(l.sessions, addr.String())
+				l.mu.Unlock()
+			}()
+		}
+		l.mu.Unlock()
+
+		session.push(buf[:n])
+	}
+}
+
+// udpSession 一个客户端地址的 UDP 会话，读写均为分帧后的数据报
+type udpSession struct {
+	conn net.PacketConn
+	addr net.Addr
+
+	in      chan []byte
+	pending []byte // 未读完的帧
+	partial []byte // agent 返回的不完整帧
+
+	idle       time.Duration
+	lastActive atomic.Int64 // 最近一次收到或发出数据报的时间，两个方向都没有数据时才关闭会话
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func newUDPSession(conn net.PacketConn, addr net.Addr) *udpSession {
+	u := &udpSession{
+		conn:   conn,
+		addr:   addr,
+		in:     make(chan []byte, 64),
+		closed: make(chan struct{}),
+		idle:   portForwardUDPIdleTimeout,
+	}
+	u.touch()
+	return u
+}
+
+func (u *udpSession) touch() {
+	u.lastActive.Store(time.Now().UnixNano())
+}
+
+// push 会话繁忙时丢弃数据报
+func (u *udpSession) push(datagram []byte) {
+	frame := make([]byte, 2+len(datagram))
+	binary.BigEndian.PutUint16(frame, uint16(len(datagram)))
+	copy(frame[2:], datagram)
+	u.touch()
+
+	select {
+	case u.in <- frame:
+	case <-u.closed:
+	default:
+	}
+}
+
+func (u *udpSession) Read(p []byte) (int, error) {
+	for len(u.pending) == 0 {
+		wait := time.Until(time.Unix(0, u.lastActive.Load()).Add(u.idle))
+		if wait <= 0 {
+			u.Close()
+			return 0, io.EOF
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case frame := <-u.in:
+			u.pending = frame
+		case <-u.closed:
+			timer.Stop()
+			return 0, io.EOF
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+	n := copy(p, u.pending)
+	u.pending = u.pending[n:]
+	return n, nil
+}
+
+func (u *udpSession) Write(p []byte) (int, error) {
+	u.partial = append(u.partial, p...)
+	for len(u.partial) >= 2 {
+		size := int(binary.BigEndian.Uint16(u.partial))
+		if len(u.partial) < 2+size {
+			break
+		}
+		if _, err := u.conn.WriteTo(u.partial[2:2+size], u.addr); err != nil {
+			return 0, err
+		}
+		u.touch()
+		u.partial = u.partial[2+size:]
+	}
+	return len(p), nil
+}
+
+func (u *udpSession) Close() error {
+	u.closeOnce.Do(func() {
+		close(u.closed)
+	})
+	return nil
+}
@@ -0,0 +1,103 @@
+package rpc
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestUDPSession(t *testing.T) {
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	client, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	session := newUDPSession(server, client.LocalAddr())
+	session.push([]byte("ping"))
+
+	frame := make([]byte, 16)
+	n, err := session.Read(frame)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(frame[:n], []byte{0, 4, 'p', 'i', 'n', 'g'}) {
+		t.Fatalf("unexpected frame: %v", frame[:n])
+	}
+
+	// agent 返回的帧可能被拆分写入
+	for _, chunk := range [][]byte{{0}, {4, 'p', 'o'}, {'n', 'g', 0, 0}} {
+		if _, err := session.Write(chunk); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	buf := make([]byte, 16)
+	n, _, err = client.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf[:n]) != "pong" {
+		t.Fatalf("expected pong, got %q", buf[:n])
+	}
+	n, _, err = client.ReadFrom(buf)
+	if err != nil || n != 0 {
+		t.Fatalf("expected empty datagram, got %d bytes, err %v", n, err)
+	}
+
+	session.Close()
+	if _, err := session.Read(frame); err != io.EOF {
+		t.Fatalf("expected EOF after close, got %v", err)
+	}
+}
+
+func TestUDPSessionIdle(t *testing.T) {
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	client, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	session := newUDPSession(server, client.LocalAddr())
+	session.idle = 200 * time.Millisecond
+
+	// 只有 agent 发往客户端的数据时会话保持打开
+	done := make(chan error, 1)
+	go func() {
+		_, err := session.Read(make([]byte, 16))
+		done <- err
+	}()
+	for i := 0; i < 5; i++ {
+		time.Sleep(100 * time.Millisecond)
+		if _, err := session.Write([]byte{0, 1, 'x'}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	select {
+	case err := <-done:
+		t.Fatalf("session closed while receiving from agent: %v", err)
+	default:
+	}
+
+	select {
+	case err := <-done:
+		if err != io.EOF {
+			t.Fatalf("expected EOF after idle, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("idle session was not closed")
+	}
+}
@@ -2,14 +2,24 @@ package singleton
 
 import (
 	"cmp"
+	"fmt"
+	"log"
 	"slices"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
 
 	"github.com/nezhahq/nezha/model"
 	"github.com/nezhahq/nezha/pkg/utils"
+	pb "github.com/nezhahq/nezha/proto"
 )
 
 type AutoSSHClass struct {
 	class[uint64, *model.AutoSSH]
+
+	hostKeyMu sync.Mutex
+	keyMu     sync.Mutex
 }
 
 func NewAutoSSHClass() *AutoSSHClass {
@@ -18,6 +28,17 @@ func NewAutoSSHClass() *AutoSSHClass {
 	DB.Find(&sortedList)
 	list := make(map[uint64]*model.AutoSSH, len(sortedList))
 	for _, mapping := range sortedList {
+		// 面板重启后数据库中的运行状态已失效，等待 agent 连接后恢复
+		if mapping.Status != model.AutoSSHStatusStopped {
+			if mapping.Enabled {
+				mapping.Status = model.AutoSSHStatusStarting
+			} else {
+				mapping.Status = model.AutoSSHStatusStopped
+			}
+			mapping.PID = 0
+			mapping.ActiveConnections = 0
+			DB.Save(mapping)
+		}
 		list[mapping.ID] = mapping
 	}
 
@@ -58,3 +79,252 @@ func (c *AutoSSHClass) sortList() {
 	defer c.sortedListMu.Unlock()
 	c.sortedList = sortedList
 }
+
+// UpdateStatus 更新映射状态并持久化
+func (c *AutoSSHClass) UpdateStatus(id uint64, status string, errorMsg string) {
+	var a model.AutoSSH
+	if err := DB.First(&a, id).Error; err != nil {
+		return
+	}
+
+	a.Status = status
+	a.LastError = errorMsg
+	a.LastErrorCode = ""
+	if status == model.AutoSSHStatusRunning {
+		a.LastStartAt = time.Now()
+	}
+	if status != model.AutoSSHStatusRunning {
+		a.PID = 0
+	}
+
+	DB.Save(&a)
+	c.Update(&a)
+}
+
+// ApplyStatusReport 根据 agent 回报的状态更新映射
+func (c *AutoSSHClass) ApplyStatusReport(serverID uint64, data string) {
+	var report model.AutoSSHStatusReport
+	if err := json.Unmarshal([]byte(data), &report); err != nil {
+		log.Printf("NEZHA>> AutoSSH status report error: %v, serverID: %d", err, serverID)
+		return
+	}
+
+	if report.Event == model.AutoSSHEventSync {
+		return
+	}
+
+	var a model.AutoSSH
+	if err := DB.First(&a, report.MappingID).Error; err != nil || a.ServerID != serverID {
+		return
+	}
+
+	TunnelStatsShared.ApplyAutoSSHReport(serverID, &report)
+
+	if report.ErrorCode == model.AutoSSHErrorHostKey {
+		if a.LastErrorCode != model.AutoSSHErrorHostKey {
+			c.notifyHostKeyMismatch(&a, serverID, report.HostKeyFingerprint)
+		}
+	} else if report.HostKeyFingerprint != "" {
+		c.pinHostKey(report.HostKeyFingerprint)
+	}
+
+	switch report.Status {
+	case model.AutoSSHStatusRunning:
+		if a.Status != model.AutoSSHStatusRunning || a.PID != report.PID || a.Restarts != report.Restarts {
+			a.LastStartAt = time.Now()
+		}
+		a.PID = report.PID
+		a.LastError = ""
+	case model.AutoSSHStatusStopped:
+		a.PID = 0
+		// 面板认为应当运行的映射被 agent 报告为停止，视为异常
+		expected := a.Status == model.AutoSSHStatusRunning || a.Status == model.AutoSSHStatusStarting
+		if expected && report.Event != model.AutoSSHEventStop && report.Error == "" {
+			report.Error = "tunnel is not running on agent"
+		}
+		if report.Error != "" {
+			report.Status = model.AutoSSHStatusError
+		}
+		a.LastError = report.Error
+	default:
+		a.PID = 0
+		a.LastError = report.Error
+	}
+
+	a.Status = report.Status
+	a.Restarts = report.Restarts
+	a.LastErrorCode = report.ErrorCode
+	a.Connections = report.Connections
+	a.ActiveConnections = report.ActiveConnections
+	a.FailedConnections = report.FailedConnections
+	a.RejectedConnections = report.RejectedConnections
+	a.LastReportAt = time.Now()
+
+	DB.Save(&a)
+	c.Update(&a)
+}
+
+// pinHostKey 首次连接时记录 AutoSSH 服务器的主机密钥指纹
+func (c *AutoSSHClass) pinHostKey(fingerprint string) {
+	c.hostKeyMu.Lock()
+	defer c.hostKeyMu.Unlock()
+
+	if Conf.AutoSSHHostKey != "" || !model.IsValidHostKeyFingerprint(fingerprint) {
+		return
+	}
+	Conf.AutoSSHHostKey = fingerprint
+	if err := Conf.Save(); err != nil {
+		log.Printf("NEZHA>> AutoSSH host key pin error: %v", err)
+		return
+	}
+	log.Printf("NEZHA>> AutoSSH host key pinned: %s", fingerprint)
+}
+
+func (c *AutoSSHClass) notifyHostKeyMismatch(a *model.AutoSSH, serverID uint64, fingerprint string) {
+	serverName := fmt.Sprintf("%d", serverID)
+	server, ok := ServerShared.Get(serverID)
+	if ok {
+		serverName = server.Name
+	}
+
+	log.Printf("NEZHA>> AutoSSH host key mismatch, mapping: %d, server: %s, expected: %s, got: %s",
+		a.ID, serverName, Conf.AutoSSHHostKey, fingerprint)
+	NotificationShared.SendNotification(Conf.AutoSSHNotificationGroupID,
+		fmt.Sprintf(
+			"[%s] %s, %s, %s => %s",
+			Localizer.T("AutoSSH Host Key Mismatch"),
+			a.Name, serverName, Conf.AutoSSHHostKey, fingerprint,
+		),
+		NotificationMuteLabel.AutoSSHHostKeyMismatch(a.ID), server)
+}
+
+// Reconcile 向在线的 agent 查询已启用映射的实际运行状态
+func (c *AutoSSHClass) Reconcile() {
+	for _, a := range c.GetSortedList() {
+		if a.Status == model.AutoSSHStatusStopped {
+			continue
+		}
+
+		server, ok := ServerShared.Get(a.ServerID)
+		if !ok || server.TaskStream == nil {
+			if a.Status != model.AutoSSHStatusError {
+				c.UpdateStatus(a.ID, model.AutoSSHStatusError, "server not found or not connected")
+			}
+			continue
+		}
+
+		taskData, _ := json.Marshal(model.TaskAutoSSH{
+			Action:    "status",
+			MappingID: a.ID,
+		})
+		server.TaskStream.Send(&pb.Task{
+			Type: model.TaskTypeAutoSSH,
+			Data: string(taskData),
+		})
+	}
+}
+
+// StartTask 生成映射的启动任务，restore 为 true 时 agent 保留配置相同的运行中隧道
+func (c *AutoSSHClass) StartTask(a *model.AutoSSH, restore bool) (*pb.Task, error) {
+	// 使用面板生成的密钥，已吊销时由 agent 使用本机密钥
+	var privateKey string
+	if key := c.ActiveKey(a.ServerID); key != nil {
+		privateKey = key.PrivateKey
+	}
+
+	// 未配置主机密钥且连接的是内置隧道服务器时固定为其密钥，外部 sshd 在首次连接时记录
+	hostKey := Conf.AutoSSHHostKey
+	if hostKey == "" && AutoSSHServerShared != nil && AutoSSHServerShared.IsEndpoint(Conf.AutoSSHHost) {
+		hostKey = AutoSSHServerShared.HostKeyFingerprint()
+	}
+
+	taskData, err := json.Marshal(model.TaskAutoSSH{
+		Action:      "start",
+		MappingID:   a.ID,
+		MappingType: a.MappingType,
+		SourcePort:  a.SourcePort,
+		TargetHost:  a.TargetHost,
+		TargetPort:  a.TargetPort,
+		SSHHost:     Conf.AutoSSHHost,
+		SSHOptions: map[string]string{
+			"ServerAliveInterval": "30",
+			"ServerAliveCountMax": "3",
+		},
+		SocksUsername:      a.SocksUsername,
+		SocksPassword:      a.SocksPassword,
+		HostKeyFingerprint: hostKey,
+		PrivateKey:         privateKey,
+		RateLimit:          a.RateLimit,
+		BindAddress:        a.BindAddress,
+		AllowedSources:     a.AllowedSources,
+		Restore:            restore,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Task{
+		Type: model.TaskTypeAutoSSH,
+		Data: string(taskData),
+	}, nil
+}
+
+// Restore agent 任务流连接后重新下发已启用且未被手动停止的映射，并让 agent 停止其余隧道
+func (c *AutoSSHClass) Restore(serverID uint64) {
+	server, ok := ServerShared.Get(serverID)
+	if !ok || server.TaskStream == nil {
+		return
+	}
+	stream := server.TaskStream
+
+	var restoring []*model.AutoSSH
+	mappingIDs := []uint64{}
+	for _, a := range c.GetSortedList() {
+		if a.ServerID != serverID || a.Status == model.AutoSSHStatusStopped {
+			continue
+		}
+		if !a.Enabled {
+			c.UpdateStatus(a.ID, model.AutoSSHStatusStopped, "")
+			continue
+		}
+		restoring = append(restoring, a)
+		mappingIDs = append(mappingIDs, a.ID)
+	}
+
+	taskData, _ := json.Marshal(model.TaskAutoSSH{
+		Action:     "sync",
+		MappingIDs: mappingIDs,
+	})
+	if err := stream.Send(&pb.Task{
+		Type: model.TaskTypeAutoSSH,
+		Data: string(taskData),
+	}); err != nil {
+		log.Printf("NEZHA>> AutoSSH restore error: %v, serverID: %d", err, serverID)
+		return
+	}
+
+	for _, a := range restoring {
+		task, err := c.StartTask(a, true)
+		if err != nil {
+			c.UpdateStatus(a.ID, model.AutoSSHStatusError, fmt.Sprintf("marshal task error: %v", err))
+			continue
+		}
+		if err := stream.Send(task); err != nil {
+			c.UpdateStatus(a.ID, model.AutoSSHStatusError, fmt.Sprintf("send task error: %v", err))
+			continue
+		}
+		c.UpdateStatus(a.ID, model.AutoSSHStatusStarting, "")
+	}
+	if len(restoring) > 0 {
+		log.Printf("NEZHA>> AutoSSH restored %d mappings, serverID: %d", len(restoring), serverID)
+	}
+}
+
+// Detach agent 任务流断开后将服务器上的映射标记为异常，重新连接时恢复
+func (c *AutoSSHClass) Detach(serverID uint64) {
+	for _, a := range c.GetSortedList() {
+		if a.ServerID != serverID || a.Status == model.AutoSSHStatusStopped || a.Status == model.AutoSSHStatusError {
+			continue
+		}
+		c.UpdateStatus(a.ID, model.AutoSSHStatusError, "agent disconnected")
+	}
+}
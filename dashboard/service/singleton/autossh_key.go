@@ -0,0 +1,139 @@
+package singleton
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"gorm.io/gorm"
+
+	"github.com/nezhahq/nezha/model"
+)
+
+// ActiveKey 返回服务器当前可用的隧道密钥，从未生成过时自动生成，已吊销时返回 nil
+func (c *AutoSSHClass) ActiveKey(serverID uint64) *model.AutoSSHKey {
+	var key model.AutoSSHKey
+	err := DB.Where("server_id = ?", serverID).First(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		k, err := c.RotateKey(serverID)
+		if err != nil {
+			log.Printf("NEZHA>> AutoSSH key generate error: %v, serverID: %d", err, serverID)
+			return nil
+		}
+		return k
+	}
+	if err != nil || !key.Active() {
+		return nil
+	}
+	return &key
+}
+
+// RotateKey 为服务器生成新的密钥对并替换旧密钥，同时解除吊销
+func (c *AutoSSHClass) RotateKey(serverID uint64) (*model.AutoSSHKey, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	block, err := ssh.MarshalPrivateKey(priv, fmt.Sprintf("nezha-server-%d", serverID))
+	if err != nil {
+		return nil, err
+	}
+
+	c.keyMu.Lock()
+	defer c.keyMu.Unlock()
+
+	var key model.AutoSSHKey
+	DB.Where("server_id = ?", serverID).First(&key)
+	key.ServerID = serverID
+	key.PublicKey = strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub)))
+	key.PrivateKey = string(pem.EncodeToMemory(block))
+	key.Fingerprint = ssh.FingerprintSHA256(sshPub)
+	key.RotatedAt = time.Now()
+	key.RevokedAt = nil
+	if err := DB.Save(&key).Error; err != nil {
+		return nil, err
+	}
+
+	c.syncAuthorizedKeysLocked()
+	return &key, nil
+}
+
+// RevokeKey 吊销服务器的隧道密钥，从 authorized_keys 中移除
+func (c *AutoSSHClass) RevokeKey(serverID uint64) error {
+	c.keyMu.Lock()
+	defer c.keyMu.Unlock()
+
+	now := time.Now()
+	result := DB.Model(&model.AutoSSHKey{}).Where("server_id = ?", serverID).
+		Updates(map[string]any{"revoked_at": &now, "private_key": ""})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		// 记录吊销状态，避免之后自动生成新密钥
+		if err := DB.Create(&model.AutoSSHKey{ServerID: serverID, RevokedAt: &now}).Error; err != nil {
+			return err
+		}
+	}
+
+	c.syncAuthorizedKeysLocked()
+	return nil
+}
+
+// AuthorizedKeys 生成隧道服务器的 authorized_keys 内容，按映射限制每个密钥可转发的目标
+func (c *AutoSSHClass) AuthorizedKeys() string {
+	var keys []model.AutoSSHKey
+	DB.Where("revoked_at IS NULL AND private_key != ''").Order("server_id").Find(&keys)
+
+	mappings := make(map[uint64][]*model.AutoSSH)
+	for _, a := range c.GetSortedList() {
+		mappings[a.ServerID] = append(mappings[a.ServerID], a)
+	}
+
+	var b strings.Builder
+	b.WriteString("# Managed by Nezha dashboard, do not edit.\n")
+	for _, key := range keys {
+		b.WriteString(key.AuthorizedKeysLine(mappings[key.ServerID]))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// SyncAuthorizedKeys 映射或密钥变更后重写 authorized_keys 文件
+func (c *AutoSSHClass) SyncAuthorizedKeys() {
+	c.keyMu.Lock()
+	defer c.keyMu.Unlock()
+	c.syncAuthorizedKeysLocked()
+}
+
+func (c *AutoSSHClass) syncAuthorizedKeysLocked() {
+	path := Conf.AutoSSHAuthorizedKeysFile
+	if path == "" {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		log.Printf("NEZHA>> AutoSSH authorized_keys write error: %v", err)
+		return
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(c.AuthorizedKeys()), 0600); err != nil {
+		log.Printf("NEZHA>> AutoSSH authorized_keys write error: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		log.Printf("NEZHA>> AutoSSH authorized_keys write error: %v", err)
+	}
+}
@@ -0,0 +1,577 @@
+package singleton
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/netip"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/nezhahq/nezha/model"
+)
+
+const (
+	autosshServerHandshakeTimeout = 30 * time.Second
+	autosshServerDialTimeout      = 10 * time.Second
+	autosshServerExtServerID      = "server_id"
+)
+
+// AutoSSHServerShared 内置隧道服务器，未启用时为 nil
+var AutoSSHServerShared *AutoSSHServer
+
+// AutoSSHServer 面板内置的 SSH 隧道服务器，只接受面板生成的 agent 密钥，只允许 AutoSSH 映射声明的转发
+type AutoSSHServer struct {
+	config      *ssh.ServerConfig
+	listen      string
+	fingerprint string
+
+	mu       sync.Mutex
+	conns    map[uint64]map[*ssh.ServerConn]struct{} // serverID -> 连接
+	forwards map[uint64]*autosshForward              // mappingID -> remote 映射的监听
+}
+
+type autosshForward struct {
+	mappingID uint64
+	serverID  uint64
+	conn      *ssh.ServerConn
+	listener  net.Listener
+	since     time.Time
+	allowed   []netip.Prefix
+
+	connections atomic.Uint64
+	active      atomic.Int64
+}
+
+// 与 RFC 4254 中的消息格式对应
+type autosshForwardRequest struct {
+	BindAddr string
+	BindPort uint32
+}
+
+type autosshChannelAddr struct {
+	Host       string
+	Port       uint32
+	OriginHost string
+	OriginPort uint32
+}
+
+// StartAutoSSHServer 启动内置隧道服务器
+func StartAutoSSHServer(listen, hostKeyFile string) error {
+	if hostKeyFile == "" {
+		hostKeyFile = filepath.Join("data", "autossh_host_key")
+	}
+	signer, err := loadOrCreateAutoSSHHostKey(hostKeyFile)
+	if err != nil {
+		return err
+	}
+
+	s := &AutoSSHServer{
+		listen:      listen,
+		fingerprint: ssh.FingerprintSHA256(signer.PublicKey()),
+		conns:       make(map[uint64]map[*ssh.ServerConn]struct{}),
+		forwards:    make(map[uint64]*autosshForward),
+	}
+	s.config = &ssh.ServerConfig{
+		PublicKeyCallback: s.authenticate,
+		ServerVersion:     "SSH-2.0-NezhaTunnel",
+	}
+	s.config.AddHostKey(signer)
+
+	l, err := net.Listen("tcp", listen)
+	if err != nil {
+		return err
+	}
+	AutoSSHServerShared = s
+	go s.serve(l)
+
+	log.Printf("NEZHA>> AutoSSH server listening on %s, host key: %s", listen, s.fingerprint)
+	return nil
+}
+
+func loadOrCreateAutoSSHHostKey(path string) (ssh.Signer, error) {
+	if data, err := os.ReadFile(path); err == nil {
+		return ssh.ParsePrivateKey(data)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	block, err := ssh.MarshalPrivateKey(priv, "nezha-autossh-server")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		return nil, err
+	}
+	return ssh.NewSignerFromKey(priv)
+}
+
+func (s *AutoSSHServer) HostKeyFingerprint() string {
+	return s.fingerprint
+}
+
+// IsEndpoint 判断 agent 使用的 SSH 地址（user@host:port）是否指向内置隧道服务器，
+// 端口需要与监听端口一致，主机需要是监听地址、面板地址或本机回环地址，无法确定时返回 false
+func (s *AutoSSHServer) IsEndpoint(sshHost string) bool {
+	if idx := strings.LastIndex(sshHost, "@"); idx != -1 {
+		sshHost = sshHost[idx+1:]
+	}
+	host, port, err := net.SplitHostPort(sshHost)
+	if err != nil {
+		host, port = strings.Trim(sshHost, "[]"), "22"
+	}
+	listenHost, listenPort, err := net.SplitHostPort(s.listen)
+	if err != nil || port != listenPort || host == "" {
+		return false
+	}
+
+	if ip, err := netip.ParseAddr(host); (err == nil && ip.IsLoopback()) || strings.EqualFold(host, "localhost") {
+		return true
+	}
+	candidates := []string{listenHost}
+	if h, _, err := net.SplitHostPort(Conf.InstallHost); err == nil {
+		candidates = append(candidates, h)
+	}
+	if u, err := url.Parse(Conf.SiteURL); err == nil && u.Hostname() != "" {
+		candidates = append(candidates, u.Hostname())
+	}
+	return slices.ContainsFunc(candidates, func(c string) bool {
+		return c != "" && strings.EqualFold(c, host)
+	})
+}
+
+func (s *AutoSSHServer) serve(l net.Listener) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			log.Printf("NEZHA>> AutoSSH server stopped: %v", err)
+			return
+		}
+		go s.handleConn(conn)
+	}
+}
+
+// authenticate 只接受未吊销的面板生成密钥
+func (s *AutoSSHServer) authenticate(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+	var k model.AutoSSHKey
+	if err := DB.Where("fingerprint = ?", ssh.FingerprintSHA256(key)).First(&k).Error; err != nil || !k.Active() {
+		return nil, errors.New("unknown key")
+	}
+	if k.PublicKey != strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key))) {
+		return nil, errors.New("unknown key")
+	}
+	return &ssh.Permissions{
+		Extensions: map[string]string{autosshServerExtServerID: strconv.FormatUint(k.ServerID, 10)},
+	}, nil
+}
+
+func (s *AutoSSHServer) handleConn(nc net.Conn) {
+	nc.SetDeadline(time.Now().Add(autosshServerHandshakeTimeout))
+	conn, chans, reqs, err := ssh.NewServerConn(nc, s.config)
+	if err != nil {
+		nc.Close()
+		return
+	}
+	nc.SetDeadline(time.Time{})
+
+	serverID, _ := strconv.ParseUint(conn.Permissions.Extensions[autosshServerExtServerID], 10, 64)
+	s.addConn(serverID, conn)
+	defer s.removeConn(serverID, conn)
+
+	go s.handleGlobalRequests(serverID, conn, reqs)
+
+	for newChan := range chans {
+		if newChan.ChannelType() != "direct-tcpip" {
+			newChan.Reject(ssh.UnknownChannelType, "only port forwarding is allowed")
+			continue
+		}
+		go s.handleDirect(serverID, newChan)
+	}
+}
+
+func (s *AutoSSHServer) addConn(serverID uint64, conn *ssh.ServerConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conns[serverID] == nil {
+		s.conns[serverID] = make(map[*ssh.ServerConn]struct{})
+	}
+	s.conns[serverID][conn] = struct{}{}
+}
+
+// removeConn 连接断开时关闭其打开的监听
+func (s *AutoSSHServer) removeConn(serverID uint64, conn *ssh.ServerConn) {
+	s.mu.Lock()
+	delete(s.conns[serverID], conn)
+	if len(s.conns[serverID]) == 0 {
+		delete(s.conns, serverID)
+	}
+	var closing []*autosshForward
+	for id, f := range s.forwards {
+		if f.conn == conn {
+			closing = append(closing, f)
+			delete(s.forwards, id)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, f := range closing {
+		f.listener.Close()
+	}
+	conn.Close()
+}
+
+// findAutoSSHMapping 查找服务器上允许该转发的已启用映射
+func findAutoSSHMapping(serverID uint64, match func(*model.AutoSSH) bool) *model.AutoSSH {
+	for _, a := range AutoSSHShared.GetSortedList() {
+		if a.ServerID == serverID && a.Enabled && match(a) {
+			return a
+		}
+	}
+	return nil
+}
+
+func (s *AutoSSHServer) handleGlobalRequests(serverID uint64, conn *ssh.ServerConn, reqs <-chan *ssh.Request) {
+	for req := range reqs {
+		switch req.Type {
+		case "tcpip-forward":
+			req.Reply(s.openForward(serverID, conn, req.Payload) == nil, nil)
+		case "cancel-tcpip-forward":
+			var fr autosshForwardRequest
+			if ssh.Unmarshal(req.Payload, &fr) == nil {
+				s.closeForwards(func(f *autosshForward) bool {
+					return f.conn == conn && f.listener.Addr().(*net.TCPAddr).Port == int(fr.BindPort)
+				})
+			}
+			req.Reply(true, nil)
+		default:
+			// keepalive@openssh.com 等请求回复失败即可
+			if req.WantReply {
+				req.Reply(false, nil)
+			}
+		}
+	}
+}
+
+// openForward 只为声明了该端口的 remote 映射打开监听
+func (s *AutoSSHServer) openForward(serverID uint64, conn *ssh.ServerConn, payload []byte) error {
+	var fr autosshForwardRequest
+	if err := ssh.Unmarshal(payload, &fr); err != nil {
+		return err
+	}
+	mapping := findAutoSSHMapping(serverID, func(a *model.AutoSSH) bool {
+		return a.MappingType == model.AutoSSHMappingTypeRemote && a.SourcePort == int(fr.BindPort)
+	})
+	if mapping == nil {
+		log.Printf("NEZHA>> AutoSSH server rejected forward, server: %d, port: %d", serverID, fr.BindPort)
+		return errors.New("forward not allowed")
+	}
+
+	// 同一映射的旧连接尚未断开时先释放端口
+	s.closeForwards(func(f *autosshForward) bool { return f.mappingID == mapping.ID })
+
+	// 忽略客户端请求的地址，只监听映射配置的地址
+	bindAddr, err := autosshBindAddress(mapping)
+	if err != nil {
+		return err
+	}
+	l, err := net.Listen("tcp", net.JoinHostPort(bindAddr, strconv.Itoa(int(fr.BindPort))))
+	if err != nil {
+		log.Printf("NEZHA>> AutoSSH server listen error: %v, mapping: %d", err, mapping.ID)
+		return err
+	}
+
+	allowed, _ := model.ParseAllowedSources(mapping.AllowedSources)
+	f := &autosshForward{
+		mappingID: mapping.ID,
+		serverID:  serverID,
+		conn:      conn,
+		listener:  l,
+		since:     time.Now(),
+		allowed:   allowed,
+	}
+	s.mu.Lock()
+	s.forwards[mapping.ID] = f
+	s.mu.Unlock()
+
+	go s.acceptForward(f, fr)
+	return nil
+}
+
+func (s *AutoSSHServer) acceptForward(f *autosshForward, fr autosshForwardRequest) {
+	for {
+		c, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		origin := c.RemoteAddr().(*net.TCPAddr)
+		if ip, ok := netip.AddrFromSlice(origin.IP); !ok || !model.SourceAllowed(f.allowed, ip) {
+			c.Close()
+			continue
+		}
+		go func() {
+			defer c.Close()
+
+			ch, reqs, err := f.conn.OpenChannel("forwarded-tcpip", ssh.Marshal(&autosshChannelAddr{
+				Host:       fr.BindAddr,
+				Port:       fr.BindPort,
+				OriginHost: origin.IP.String(),
+				OriginPort: uint32(origin.Port),
+			}))
+			if err != nil {
+				return
+			}
+			go ssh.DiscardRequests(reqs)
+
+			f.connections.Add(1)
+			f.active.Add(1)
+			defer f.active.Add(-1)
+			autosshPipe(c, ch)
+		}()
+	}
+}
+
+// handleDirect local 映射只允许声明的目标，dynamic 映射允许任意目标。
+// 连接从面板主机发起，dynamic 映射只允许管理员创建，目标不能是内网地址，除非配置允许
+func (s *AutoSSHServer) handleDirect(serverID uint64, newChan ssh.NewChannel) {
+	var addr autosshChannelAddr
+	if err := ssh.Unmarshal(newChan.ExtraData(), &addr); err != nil {
+		newChan.Reject(ssh.ConnectionFailed, "invalid request")
+		return
+	}
+
+	mapping := findAutoSSHMapping(serverID, func(a *model.AutoSSH) bool {
+		switch a.MappingType {
+		case model.AutoSSHMappingTypeDynamic:
+			return autosshCreatedByAdmin(a)
+		case model.AutoSSHMappingTypeLocal:
+			return a.TargetHost == addr.Host && a.TargetPort == int(addr.Port)
+		}
+		return false
+	})
+	if mapping == nil {
+		newChan.Reject(ssh.Prohibited, "forward not allowed")
+		return
+	}
+
+	target, err := dialAutoSSHTarget(addr.Host, addr.Port, Conf.AutoSSHServerAllowPrivateTargets)
+	if err != nil {
+		newChan.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	defer target.Close()
+
+	ch, reqs, err := newChan.Accept()
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	autosshPipe(target, ch)
+}
+
+// autosshBindAddress remote 映射在内置隧道服务器上的监听地址，默认仅本机
+func autosshBindAddress(mapping *model.AutoSSH) (string, error) {
+	bindAddr := mapping.BindAddress
+	if bindAddr == "" || bindAddr == "localhost" {
+		bindAddr = "127.0.0.1"
+	}
+	if !model.IsValidBindAddress(bindAddr) {
+		return "", errors.New("invalid bind address")
+	}
+	return bindAddr, nil
+}
+
+// autosshCreatedByAdmin 映射的创建者是否为管理员
+func autosshCreatedByAdmin(a *model.AutoSSH) bool {
+	UserLock.RLock()
+	defer UserLock.RUnlock()
+	u, ok := UserInfoMap[a.UserID]
+	return ok && u.Role.IsAdmin()
+}
+
+var errAutoSSHPrivateTarget = errors.New("target address not allowed")
+
+// isAutoSSHPrivateAddr 面板主机本身、内网与链路本地地址
+func isAutoSSHPrivateAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified() || autosshSharedAddrSpace.Contains(ip)
+}
+
+// RFC 6598 运营商级 NAT 地址
+var autosshSharedAddrSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// dialAutoSSHTarget 先解析再连接解析得到的地址，避免检查之后域名解析结果发生变化
+func dialAutoSSHTarget(host string, port uint32, allowPrivate bool) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), autosshServerDialTimeout)
+	defer cancel()
+
+	ips, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil, err
+	}
+	if !allowPrivate {
+		for _, ip := range ips {
+			if isAutoSSHPrivateAddr(ip) {
+				return nil, errAutoSSHPrivateTarget
+			}
+		}
+	}
+
+	if len(ips) == 0 {
+		return nil, errors.New("no address for " + host)
+	}
+
+	var dialer net.Dialer
+	for _, ip := range ips {
+		var conn net.Conn
+		if conn, err = dialer.DialContext(ctx, "tcp", netip.AddrPortFrom(ip.Unmap(), uint16(port)).String()); err == nil {
+			return conn, nil
+		}
+	}
+	return nil, err
+}
+
+// autosshPipe 双向转发并传递半关闭
+func autosshPipe(conn net.Conn, ch ssh.Channel) {
+	defer ch.Close()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		io.Copy(conn, ch)
+		if tc, ok := conn.(*net.TCPConn); ok {
+			tc.CloseWrite()
+		} else {
+			conn.Close()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		io.Copy(ch, conn)
+		ch.CloseWrite()
+	}()
+	wg.Wait()
+}
+
+func (s *AutoSSHServer) closeForwards(match func(*autosshForward) bool) {
+	s.mu.Lock()
+	var closing []*autosshForward
+	for id, f := range s.forwards {
+		if match(f) {
+			closing = append(closing, f)
+			delete(s.forwards, id)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, f := range closing {
+		f.listener.Close()
+	}
+}
+
+// CloseForward 映射停止、修改或删除时关闭其监听
+func (s *AutoSSHServer) CloseForward(mappingID uint64) {
+	s.closeForwards(func(f *autosshForward) bool { return f.mappingID == mappingID })
+}
+
+// DisconnectServer 断开服务器的所有隧道连接，用于密钥轮换与吊销
+func (s *AutoSSHServer) DisconnectServer(serverID uint64) {
+	s.mu.Lock()
+	var conns []*ssh.ServerConn
+	for conn := range s.conns[serverID] {
+		conns = append(conns, conn)
+	}
+	s.mu.Unlock()
+
+	for _, conn := range conns {
+		conn.Close()
+	}
+}
+
+// ListenerState 返回映射的实时监听状态，未监听时返回 nil
+func (s *AutoSSHServer) ListenerState(mappingID uint64) *model.AutoSSHListenerState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if f, ok := s.forwards[mappingID]; ok {
+		return f.state()
+	}
+	return nil
+}
+
+func (f *autosshForward) state() *model.AutoSSHListenerState {
+	return &model.AutoSSHListenerState{
+		MappingID:         f.mappingID,
+		ServerID:          f.serverID,
+		Addr:              f.listener.Addr().String(),
+		Since:             f.since,
+		Connections:       f.connections.Load(),
+		ActiveConnections: f.active.Load(),
+	}
+}
+
+func (s *AutoSSHServer) Info() *model.AutoSSHServerInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info := &model.AutoSSHServerInfo{
+		Enabled:            true,
+		Listen:             s.listen,
+		HostKeyFingerprint: s.fingerprint,
+	}
+	for serverID := range s.conns {
+		info.ConnectedServers = append(info.ConnectedServers, serverID)
+	}
+	slices.Sort(info.ConnectedServers)
+	for _, f := range s.forwards {
+		info.Listeners = append(info.Listeners, f.state())
+	}
+	slices.SortFunc(info.Listeners, func(a, b *model.AutoSSHListenerState) int {
+		return int(a.MappingID) - int(b.MappingID)
+	})
+	return info
+}
+
+// CheckPort 检查端口能否被映射使用，映射自身已打开的监听不算占用
+func (s *AutoSSHServer) CheckPort(port int, mappingID uint64) error {
+	s.mu.Lock()
+	for _, f := range s.forwards {
+		if f.listener.Addr().(*net.TCPAddr).Port != port {
+			continue
+		}
+		s.mu.Unlock()
+		if f.mappingID == mappingID {
+			return nil
+		}
+		return fmt.Errorf("port %d is listened by mapping %d", port, f.mappingID)
+	}
+	s.mu.Unlock()
+
+	l, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(port)))
+	if err != nil {
+		return err
+	}
+	return l.Close()
+}
@@ -0,0 +1,108 @@
+package singleton
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/nezhahq/nezha/model"
+)
+
+func TestAutoSSHServerIsEndpoint(t *testing.T) {
+	Conf = &ConfigClass{Config: &model.Config{}}
+	Conf.InstallHost = "nezha.example.com:8008"
+
+	s := &AutoSSHServer{listen: ":2222"}
+	cases := map[string]bool{
+		"nezha@nezha.example.com:2222": true,
+		"nezha@127.0.0.1:2222":         true,
+		"nezha@[::1]:2222":             true,
+		"nezha@nezha.example.com:22":   false,
+		"nezha@ssh.example.com:2222":   false,
+		"nezha@nezha.example.com":      false,
+		"":                             false,
+	}
+	for host, want := range cases {
+		if got := s.IsEndpoint(host); got != want {
+			t.Errorf("IsEndpoint(%q) = %v, want %v", host, got, want)
+		}
+	}
+
+	s.listen = "10.0.0.1:22"
+	if !s.IsEndpoint("tunnel@10.0.0.1") || s.IsEndpoint("tunnel@10.0.0.2") {
+		t.Error("listen address mismatch")
+	}
+}
+
+func TestAutoSSHServerBindAddress(t *testing.T) {
+	cases := map[string]string{
+		"":          "127.0.0.1",
+		"localhost": "127.0.0.1",
+		"10.0.0.1":  "10.0.0.1",
+		"0.0.0.0":   "0.0.0.0",
+	}
+	for bind, want := range cases {
+		if got, err := autosshBindAddress(&model.AutoSSH{BindAddress: bind}); err != nil || got != want {
+			t.Errorf("autosshBindAddress(%q) = %q, %v, want %q", bind, got, err, want)
+		}
+	}
+	if _, err := autosshBindAddress(&model.AutoSSH{BindAddress: "example.com"}); err == nil {
+		t.Error("expected invalid bind address to fail")
+	}
+}
+
+func TestAutoSSHServerDynamicRequiresAdmin(t *testing.T) {
+	UserInfoMap = map[uint64]model.UserInfo{
+		1: {Role: model.RoleAdmin},
+		2: {Role: model.RoleMember},
+	}
+	if !autosshCreatedByAdmin(&model.AutoSSH{Common: model.Common{UserID: 1}}) {
+		t.Error("mapping created by an admin should be allowed")
+	}
+	if autosshCreatedByAdmin(&model.AutoSSH{Common: model.Common{UserID: 2}}) {
+		t.Error("mapping created by a member should be refused")
+	}
+	if autosshCreatedByAdmin(&model.AutoSSH{Common: model.Common{UserID: 3}}) {
+		t.Error("mapping of an unknown user should be refused")
+	}
+}
+
+func TestAutoSSHServerPrivateTargets(t *testing.T) {
+	cases := map[string]bool{
+		"127.0.0.1":       true,
+		"::1":             true,
+		"::ffff:10.0.0.1": true,
+		"192.168.1.1":     true,
+		"169.254.169.254": true,
+		"fe80::1":         true,
+		"100.64.0.1":      true,
+		"0.0.0.0":         true,
+		"1.1.1.1":         false,
+		"2001:4860::8888": false,
+	}
+	for addr, want := range cases {
+		if got := isAutoSSHPrivateAddr(netip.MustParseAddr(addr)); got != want {
+			t.Errorf("isAutoSSHPrivateAddr(%s) = %v, want %v", addr, got, want)
+		}
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	port := uint32(l.Addr().(*net.TCPAddr).Port)
+
+	if _, err := dialAutoSSHTarget("127.0.0.1", port, false); !errors.Is(err, errAutoSSHPrivateTarget) {
+		t.Fatalf("expected loopback target to be refused, got %v", err)
+	}
+	if _, err := dialAutoSSHTarget("localhost", port, false); !errors.Is(err, errAutoSSHPrivateTarget) {
+		t.Fatalf("expected localhost to be refused, got %v", err)
+	}
+	conn, err := dialAutoSSHTarget("127.0.0.1", port, true)
+	if err != nil {
+		t.Fatalf("expected loopback target to be allowed by config, got %v", err)
+	}
+	conn.Close()
+}
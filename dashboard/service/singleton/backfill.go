@@ -0,0 +1,84 @@
+package singleton
+
+import (
+	"log"
+	"slices"
+	"time"
+
+	"github.com/nezhahq/nezha/model"
+	pb "github.com/nezhahq/nezha/proto"
+)
+
+// BackfillState 记录 agent 补传的断线期间的状态，不影响服务器的当前状态与在线时间
+func BackfillState(server *model.Server, r *pb.StateBackfill, now time.Time) {
+	samples := make([]model.StateSample, 0, len(r.GetSamples()))
+	for _, s := range r.GetSamples() {
+		at := time.Unix(s.GetTimestamp(), 0)
+		if s.GetState() == nil || s.GetTimestamp() <= 0 || at.After(now) {
+			continue
+		}
+		samples = append(samples, model.StateSample{State: model.PB2State(s.GetState()), At: at})
+	}
+	if len(samples) == 0 {
+		return
+	}
+	slices.SortStableFunc(samples, func(a, b model.StateSample) int {
+		return a.At.Compare(b.At)
+	})
+
+	if err := MetricsShared.Backfill(server.ID, samples); err != nil {
+		log.Printf("NEZHA>> Metrics backfill error: %v, server: %d", err, server.ID)
+	}
+	if txs := backfillTransfer(server, samples); len(txs) > 0 {
+		log.Printf("NEZHA>> Saved backfilled traffic metrics to database. Affected %d row(s), Error: %v", len(txs), DB.Create(txs).Error)
+	}
+}
+
+// backfillTransfer 按小时计算断线期间的流量，并将流量快照推进到最后一个状态，
+// 计数器变小说明 agent 所在主机重启过，此时计数器的值即为重启后的流量
+func backfillTransfer(server *model.Server, samples []model.StateSample) []model.Transfer {
+	prevIn, prevOut := server.PrevTransferInSnapshot, server.PrevTransferOutSnapshot
+	if prevIn == 0 || prevOut == 0 {
+		prevIn, prevOut = samples[0].State.NetInTransfer, samples[0].State.NetOutTransfer
+	}
+
+	var txs []model.Transfer
+	var in, out uint64
+	flush := func(at time.Time) {
+		if in == 0 && out == 0 {
+			return
+		}
+		txs = append(txs, model.Transfer{
+			Common:   model.Common{CreatedAt: transferHour(at).Add(time.Hour)},
+			ServerID: server.ID,
+			In:       in,
+			Out:      out,
+		})
+		in, out = 0, 0
+	}
+	delta := func(cur, prev uint64) uint64 {
+		if cur < prev {
+			return cur
+		}
+		return cur - prev
+	}
+
+	for i, s := range samples {
+		if i > 0 && transferHour(s.At).After(transferHour(samples[i-1].At)) {
+			flush(samples[i-1].At)
+		}
+		in += delta(s.State.NetInTransfer, prevIn)
+		out += delta(s.State.NetOutTransfer, prevOut)
+		prevIn, prevOut = s.State.NetInTransfer, s.State.NetOutTransfer
+	}
+	flush(samples[len(samples)-1].At)
+
+	server.PrevTransferInSnapshot, server.PrevTransferOutSnapshot = prevIn, prevOut
+	return txs
+}
+
+// transferHour 与 RecordTransferHourlyUsage 一致，按面板时区取整点
+func transferHour(t time.Time) time.Time {
+	t = t.In(Loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, Loc)
+}
@@ -0,0 +1,70 @@
+package singleton
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nezhahq/nezha/model"
+	pb "github.com/nezhahq/nezha/proto"
+)
+
+func TestBackfillState(t *testing.T) {
+	setupMetricsTest(t)
+	if err := DB.AutoMigrate(model.Transfer{}); err != nil {
+		t.Fatal(err)
+	}
+	MetricsShared = NewMetricsClass()
+
+	// 其他服务器的数据已经聚合到当前时间
+	base := time.Now().Truncate(time.Hour).Add(-2 * time.Hour)
+	now := base.Add(2*time.Hour + 5*time.Minute)
+	for ts := base; ts.Before(now); ts = ts.Add(time.Minute) {
+		MetricsShared.Add(2, &model.HostState{CPU: 1}, ts)
+	}
+	MetricsShared.Flush()
+	for _, step := range metricRollupChain[:2] {
+		if err := MetricsShared.rollup(step[0], step[1], now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	server := &model.Server{Common: model.Common{ID: 1}, PrevTransferInSnapshot: 100, PrevTransferOutSnapshot: 10}
+	sample := func(at time.Time, cpu float64, in, out uint64) *pb.StateSample {
+		return &pb.StateSample{Timestamp: at.Unix(), State: &pb.State{Cpu: cpu, NetInTransfer: in, NetOutTransfer: out}}
+	}
+	BackfillState(server, &pb.StateBackfill{Samples: []*pb.StateSample{
+		sample(base.Add(40*time.Minute), 30, 200, 20),
+		sample(base.Add(10*time.Minute), 10, 150, 15),
+		// 主机重启后计数器归零
+		sample(base.Add(70*time.Minute), 50, 60, 6),
+		sample(now.Add(time.Hour), 99, 999, 999),
+	}}, now)
+
+	if server.State != nil || !server.LastActive.IsZero() {
+		t.Fatal("backfill should not change the current state")
+	}
+
+	points, err := MetricsShared.Query(1, model.MetricResolutionMinute, base, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 3 || points[0].CPU != 10 || points[2].CPU != 50 {
+		t.Fatalf("unexpected minute points: %+v", points)
+	}
+	points, _ = MetricsShared.Query(1, model.MetricResolutionHour, base, now)
+	if len(points) != 2 || points[0].Samples != 2 || points[0].CPU != 20 {
+		t.Fatalf("unexpected hour points: %+v", points)
+	}
+
+	var txs []model.Transfer
+	DB.Where("server_id = ?", 1).Order("created_at").Find(&txs)
+	if len(txs) != 2 || txs[0].In != 100 || txs[0].Out != 10 || txs[1].In != 60 || txs[1].Out != 6 {
+		t.Fatalf("unexpected transfers: %+v", txs)
+	}
+	if !txs[0].CreatedAt.Equal(base.Add(time.Hour)) || !txs[1].CreatedAt.Equal(base.Add(2*time.Hour)) {
+		t.Fatalf("unexpected transfer hours: %v %v", txs[0].CreatedAt, txs[1].CreatedAt)
+	}
+	if server.PrevTransferInSnapshot != 60 || server.PrevTransferOutSnapshot != 6 {
+		t.Fatalf("unexpected snapshot: %d %d", server.PrevTransferInSnapshot, server.PrevTransferOutSnapshot)
+	}
+}
@@ -0,0 +1,55 @@
+package singleton
+
+import (
+	"time"
+
+	"github.com/nezhahq/nezha/model"
+	pb "github.com/nezhahq/nezha/proto"
+)
+
+// DockerClass 保存 agent 上报的容器状态，并将容器操作的结果交给等待的请求
+type DockerClass struct {
+	*taskCaller
+}
+
+func NewDockerClass() *DockerClass {
+	return &DockerClass{
+		taskCaller: newTaskCaller(),
+	}
+}
+
+// Report 更新服务器的容器状态，并记录与上次上报相比新增的重启
+func (d *DockerClass) Report(server *model.Server, r *pb.DockerReport, now time.Time) {
+	state := model.PB2DockerState(r)
+	state.UpdatedAt = now
+
+	prev := server.Docker
+	if prev != nil {
+		for _, e := range prev.Restarts {
+			if now.Sub(e.At) <= model.DockerRestartWindow {
+				state.Restarts = append(state.Restarts, e)
+			}
+		}
+		// 采集失败时保留上次的容器列表
+		if state.Error != "" && len(state.Containers) == 0 {
+			state.Containers, state.Images = prev.Containers, prev.Images
+		}
+		for _, c := range state.Containers {
+			old := prev.Container(c.ID)
+			if old != nil && c.RestartCount > old.RestartCount {
+				state.Restarts = append(state.Restarts, &model.DockerRestartEvent{
+					At:          now,
+					ContainerID: c.ID,
+					Count:       c.RestartCount - old.RestartCount,
+				})
+			}
+		}
+	}
+
+	server.Docker = &state
+}
+
+// Call 向 agent 下发容器操作并等待结果
+func (d *DockerClass) Call(server *model.Server, task *model.TaskDocker) (string, error) {
+	return d.call(server, model.TaskTypeDocker, task)
+}
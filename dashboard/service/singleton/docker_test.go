@@ -0,0 +1,105 @@
+package singleton
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/nezhahq/nezha/model"
+	pb "github.com/nezhahq/nezha/proto"
+)
+
+type fakeTaskStream struct {
+	pb.NezhaService_RequestTaskServer
+	sent chan *pb.Task
+}
+
+func (f *fakeTaskStream) Send(t *pb.Task) error {
+	f.sent <- t
+	return nil
+}
+
+func TestDockerReport(t *testing.T) {
+	d := NewDockerClass()
+	server := &model.Server{}
+	now := time.Now()
+
+	report := func(restarts uint64, health string, at time.Time) {
+		d.Report(server, &pb.DockerReport{Containers: []*pb.DockerContainer{
+			{Id: "aaa", Name: "web", RestartCount: restarts, Health: health},
+			{Id: "bbb", Name: "job"},
+		}}, at)
+	}
+
+	report(5, "healthy", now)
+	if n := server.Docker.RecentRestarts(now); n != 0 {
+		t.Fatalf("first report should not count restarts, got %d", n)
+	}
+
+	report(7, "unhealthy", now.Add(time.Minute))
+	if n := server.Docker.RecentRestarts(now.Add(time.Minute)); n != 2 {
+		t.Fatalf("restarts = %d, want 2", n)
+	}
+	if n := server.Docker.UnhealthyCount(); n != 1 {
+		t.Fatalf("unhealthy = %d, want 1", n)
+	}
+
+	rule := model.Rule{Type: "container_restart", Max: 1}
+	if rule.Snapshot(nil, server, nil) {
+		t.Fatal("container_restart rule should fail")
+	}
+	rule = model.Rule{Type: "container_unhealthy", Max: 0.5}
+	if rule.Snapshot(nil, server, nil) {
+		t.Fatal("container_unhealthy rule should fail")
+	}
+
+	// 超出统计窗口的重启不再计入
+	later := now.Add(time.Minute + model.DockerRestartWindow + time.Second)
+	report(7, "healthy", later)
+	if n := server.Docker.RecentRestarts(later); n != 0 {
+		t.Fatalf("restarts = %d, want 0", n)
+	}
+	if len(server.Docker.Restarts) != 0 {
+		t.Fatalf("expired restarts should be dropped: %v", server.Docker.Restarts)
+	}
+
+	// 采集失败时保留上次的容器
+	d.Report(server, &pb.DockerReport{Error: "docker: connection refused"}, later)
+	if server.Docker.Error == "" || server.Docker.Container("web") == nil {
+		t.Fatalf("unexpected state: %+v", server.Docker)
+	}
+}
+
+func TestDockerCall(t *testing.T) {
+	d := NewDockerClass()
+	stream := &fakeTaskStream{sent: make(chan *pb.Task, 1)}
+	server := &model.Server{Common: model.Common{ID: 1}, TaskStream: stream}
+
+	go func() {
+		task := <-stream.sent
+		var td model.TaskDocker
+		if err := json.Unmarshal([]byte(task.GetData()), &td); err != nil || td.Action != model.DockerActionLogs {
+			t.Errorf("unexpected task: %v", task)
+		}
+		// 其他服务器的结果被忽略
+		d.ApplyResult(2, &pb.TaskResult{Id: task.GetId(), Data: "spoofed", Successful: true})
+		d.ApplyResult(1, &pb.TaskResult{Id: task.GetId(), Data: "hello", Successful: true})
+	}()
+
+	data, err := d.Call(server, &model.TaskDocker{Action: model.DockerActionLogs, ContainerID: "aaa"})
+	if err != nil || data != "hello" {
+		t.Fatalf("Call() = %q, %v", data, err)
+	}
+	if len(d.pending) != 0 {
+		t.Fatal("pending call should be removed")
+	}
+
+	go func() {
+		task := <-stream.sent
+		d.ApplyResult(1, &pb.TaskResult{Id: task.GetId(), Data: "no such container"})
+	}()
+	if _, err := d.Call(server, &model.TaskDocker{Action: model.DockerActionStart, ContainerID: "aaa"}); err == nil || err.Error() != "no such container" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
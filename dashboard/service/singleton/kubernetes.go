@@ -0,0 +1,37 @@
+package singleton
+
+import (
+	"time"
+
+	"github.com/nezhahq/nezha/model"
+	pb "github.com/nezhahq/nezha/proto"
+)
+
+// ReportKubernetes 更新服务器上报的集群状态，采集失败时保留上次的对象
+func ReportKubernetes(server *model.Server, r *pb.KubernetesReport, now time.Time) {
+	cluster := model.PB2KubeCluster(r)
+	cluster.UpdatedAt = now
+
+	if prev := server.Kubernetes; prev != nil && cluster.Error != "" {
+		if len(cluster.Nodes) == 0 {
+			cluster.Nodes = prev.Nodes
+		}
+		if len(cluster.Namespaces) == 0 {
+			cluster.Namespaces = prev.Namespaces
+		}
+		if len(cluster.Workloads) == 0 {
+			cluster.Workloads = prev.Workloads
+		}
+		if len(cluster.Pods) == 0 {
+			cluster.Pods = prev.Pods
+		}
+		if len(cluster.Events) == 0 {
+			cluster.Events = prev.Events
+		}
+		if cluster.Name == "" {
+			cluster.Name, cluster.Version = prev.Name, prev.Version
+		}
+	}
+
+	server.Kubernetes = &cluster
+}
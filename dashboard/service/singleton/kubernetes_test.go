@@ -0,0 +1,52 @@
+package singleton
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nezhahq/nezha/model"
+	pb "github.com/nezhahq/nezha/proto"
+)
+
+func TestReportKubernetes(t *testing.T) {
+	server := &model.Server{Common: model.Common{ID: 1}, Name: "k8s"}
+	now := time.Now()
+
+	ReportKubernetes(server, &pb.KubernetesReport{
+		Cluster: "prod",
+		Version: "v1.30.2",
+		Nodes: []*pb.KubeNode{
+			{Name: "node-1", Ready: true, CpuAllocatable: 4000, CpuUsage: 1000},
+			{Name: "node-2", CpuAllocatable: 2000},
+		},
+		Pods: []*pb.KubePod{
+			{Namespace: "default", Name: "web-1", Phase: model.KubePodRunning},
+			{Namespace: "default", Name: "web-2", Phase: model.KubePodRunning, Reason: model.KubeReasonCrashLoopBackOff},
+			{Namespace: "default", Name: "web-3", Phase: model.KubePodPending},
+		},
+	}, now)
+
+	summary := server.Kubernetes.Summary(server)
+	if summary.Name != "prod" || summary.Nodes != 2 || summary.ReadyNodes != 1 || summary.Pods != 3 ||
+		summary.RunningPods != 2 || summary.PendingPods != 1 || summary.CrashLoopPods != 1 ||
+		summary.CPUAllocatable != 6000 || summary.CPUUsage != 1000 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+
+	for _, typ := range []string{"kube_crash_loop", "kube_node_not_ready", "kube_pod_pending"} {
+		rule := model.Rule{Type: typ, Max: 0.5}
+		if rule.Snapshot(nil, server, nil) {
+			t.Fatalf("%s rule should fail", typ)
+		}
+	}
+
+	// 采集失败时保留上次的对象
+	ReportKubernetes(server, &pb.KubernetesReport{Error: "401 Unauthorized"}, now.Add(time.Minute))
+	cluster := server.Kubernetes
+	if cluster.Error == "" || cluster.Name != "prod" || len(cluster.Nodes) != 2 || len(cluster.Pods) != 3 {
+		t.Fatalf("unexpected cluster: %+v", cluster)
+	}
+	if !cluster.UpdatedAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("updated_at = %v", cluster.UpdatedAt)
+	}
+}
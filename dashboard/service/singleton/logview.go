@@ -0,0 +1,22 @@
+package singleton
+
+import (
+	"github.com/nezhahq/nezha/model"
+)
+
+// LogClass 下发日志任务，agent 确认可以读取之后才会打开日志流
+type LogClass struct {
+	*taskCaller
+}
+
+func NewLogClass() *LogClass {
+	return &LogClass{
+		taskCaller: newTaskCaller(),
+	}
+}
+
+// Start 由 agent 检查 DisableCommandExecute，被拒绝时返回 agent 给出的原因
+func (l *LogClass) Start(server *model.Server, task *model.TaskLog) error {
+	_, err := l.call(server, model.TaskTypeLog, task)
+	return err
+}
@@ -0,0 +1,37 @@
+package singleton
+
+import (
+	"testing"
+
+	"github.com/goccy/go-json"
+
+	"github.com/nezhahq/nezha/model"
+	pb "github.com/nezhahq/nezha/proto"
+)
+
+func TestLogStart(t *testing.T) {
+	l := NewLogClass()
+	stream := &fakeTaskStream{sent: make(chan *pb.Task, 1)}
+	server := &model.Server{Common: model.Common{ID: 1}, TaskStream: stream}
+
+	go func() {
+		task := <-stream.sent
+		var tl model.TaskLog
+		if err := json.Unmarshal([]byte(task.GetData()), &tl); err != nil || task.GetType() != model.TaskTypeLog ||
+			tl.StreamID != "stream" || tl.Unit != "nginx.service" {
+			t.Errorf("unexpected task: %v", task)
+		}
+		l.ApplyResult(1, &pb.TaskResult{Id: task.GetId(), Data: "此 Agent 已禁止命令执行"})
+	}()
+	if err := l.Start(server, &model.TaskLog{StreamID: "stream", Mode: model.LogModeJournal, Unit: "nginx.service"}); err == nil {
+		t.Fatal("expected error from agent")
+	}
+
+	go func() {
+		task := <-stream.sent
+		l.ApplyResult(1, &pb.TaskResult{Id: task.GetId(), Successful: true})
+	}()
+	if err := l.Start(server, &model.TaskLog{StreamID: "stream", Mode: model.LogModeJournal, Unit: "nginx.service"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
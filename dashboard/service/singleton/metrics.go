@@ -0,0 +1,325 @@
+package singleton
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nezhahq/nezha/model"
+)
+
+// 一次查询最多返回的数据点
+const metricsMaxPoints = 5000
+
+// 按 Samples 加权平均的列
+var metricAvgColumns = []string{
+	"cpu", "mem_used", "swap_used", "disk_used", "load1", "load5", "load15",
+	"net_in_speed", "net_out_speed", "tcp_conn_count", "udp_conn_count", "process_count",
+	"temperature", "gpu",
+}
+
+// 取最大值的列
+var metricMaxColumns = []string{
+	"cpu_max", "mem_used_max", "net_in_speed_max", "net_out_speed_max", "temperature_max", "gpu_max",
+}
+
+var metricRollupSelect = func() string {
+	columns := []string{"server_id", "SUM(samples) AS samples"}
+	for _, c := range metricAvgColumns {
+		columns = append(columns, "SUM("+c+" * samples) / SUM(samples) AS "+c)
+	}
+	for _, c := range metricMaxColumns {
+		columns = append(columns, "MAX("+c+") AS "+c)
+	}
+	return strings.Join(columns, ", ")
+}()
+
+const customMetricRollupSelect = "server_id, name, SUM(samples) AS samples, SUM(value * samples) / SUM(samples) AS value, MAX(value_max) AS value_max"
+
+// 依次由上一级精度聚合
+var metricRollupChain = [][2]int{
+	{model.MetricResolutionRaw, model.MetricResolutionMinute},
+	{model.MetricResolutionMinute, model.MetricResolutionHour},
+	{model.MetricResolutionHour, model.MetricResolutionDay},
+}
+
+// MetricsClass 主机状态的时间序列存储，原始数据先缓存在内存中批量写入
+type MetricsClass struct {
+	mu            sync.Mutex
+	pending       []*model.MetricPoint
+	pendingCustom []*model.CustomMetricPoint
+
+	rollupMu sync.Mutex
+}
+
+func NewMetricsClass() *MetricsClass {
+	return &MetricsClass{}
+}
+
+// Add 记录 agent 上报的一次状态
+func (m *MetricsClass) Add(serverID uint64, state *model.HostState, at time.Time) {
+	if Conf.Metrics.Disabled {
+		return
+	}
+	m.mu.Lock()
+	m.pending = append(m.pending, model.NewMetricPoint(serverID, state, at))
+	m.pendingCustom = append(m.pendingCustom, model.NewCustomMetricPoints(serverID, state, at)...)
+	m.mu.Unlock()
+}
+
+// Flush 写入缓存的原始数据
+func (m *MetricsClass) Flush() {
+	m.mu.Lock()
+	pending, pendingCustom := m.pending, m.pendingCustom
+	m.pending, m.pendingCustom = nil, nil
+	m.mu.Unlock()
+
+	if len(pending) > 0 {
+		if err := DB.CreateInBatches(pending, 500).Error; err != nil {
+			log.Printf("NEZHA>> Metrics flush error: %v", err)
+		}
+	}
+	if len(pendingCustom) > 0 {
+		if err := DB.CreateInBatches(pendingCustom, 500).Error; err != nil {
+			log.Printf("NEZHA>> Custom metrics flush error: %v", err)
+		}
+	}
+}
+
+// Rollup 聚合已结束的时间段并清理过期数据，面板停机期间遗漏的时间段会被补上
+func (m *MetricsClass) Rollup() {
+	m.Flush()
+
+	m.rollupMu.Lock()
+	defer m.rollupMu.Unlock()
+
+	now := time.Now()
+	for _, step := range metricRollupChain {
+		if err := m.rollup(step[0], step[1], now); err != nil {
+			log.Printf("NEZHA>> Metrics rollup %d -> %d error: %v", step[0], step[1], err)
+		}
+	}
+
+	for _, res := range []int{model.MetricResolutionRaw, model.MetricResolutionMinute, model.MetricResolutionHour, model.MetricResolutionDay} {
+		before := now.Add(-MetricsRetention(res)).Unix()
+		DB.Unscoped().Delete(&model.MetricPoint{}, "resolution = ? AND timestamp < ?", res, before)
+		DB.Unscoped().Delete(&model.CustomMetricPoint{}, "resolution = ? AND timestamp < ?", res, before)
+	}
+}
+
+func (m *MetricsClass) rollup(src, dst int, now time.Time) error {
+	if err := rollupPoints[model.MetricPoint](metricRollupSelect, "server_id", src, dst, now); err != nil {
+		return err
+	}
+	return rollupPoints[model.CustomMetricPoint](customMetricRollupSelect, "server_id, name", src, dst, now)
+}
+
+func rollupPoints[T any](columns, group string, src, dst int, now time.Time) error {
+	end := metricBucketStart(dst, now.Unix())
+
+	var start int64
+	var last *int64
+	if err := DB.Model(new(T)).Where("resolution = ?", dst).Select("MAX(timestamp)").Scan(&last).Error; err != nil {
+		return err
+	}
+	if last != nil {
+		start = metricBucketEnd(dst, *last)
+	} else {
+		var first *int64
+		if err := DB.Model(new(T)).Where("resolution = ?", src).Select("MIN(timestamp)").Scan(&first).Error; err != nil {
+			return err
+		}
+		if first == nil {
+			return nil
+		}
+		start = metricBucketStart(dst, *first)
+	}
+	// 早于源数据保留时长的时间段已无数据
+	if oldest := metricBucketStart(dst, now.Add(-MetricsRetention(src)).Unix()); start < oldest {
+		start = oldest
+	}
+
+	for bucket := start; bucket < end; bucket = metricBucketEnd(dst, bucket) {
+		var points []*T
+		if err := DB.Model(new(T)).Select("? AS resolution, ? AS timestamp, "+columns, dst, bucket).
+			Where("resolution = ? AND timestamp >= ? AND timestamp < ?", src, bucket, metricBucketEnd(dst, bucket)).
+			Group(group).Scan(&points).Error; err != nil {
+			return err
+		}
+		if len(points) == 0 {
+			continue
+		}
+		if err := DB.CreateInBatches(points, 500).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Backfill 写入 agent 补传的原始数据，并重新聚合该服务器已被聚合过的时间段，samples 按时间升序
+func (m *MetricsClass) Backfill(serverID uint64, samples []model.StateSample) error {
+	if Conf.Metrics.Disabled || len(samples) == 0 {
+		return nil
+	}
+
+	points := make([]*model.MetricPoint, 0, len(samples))
+	var custom []*model.CustomMetricPoint
+	for i := range samples {
+		points = append(points, model.NewMetricPoint(serverID, &samples[i].State, samples[i].At))
+		custom = append(custom, model.NewCustomMetricPoints(serverID, &samples[i].State, samples[i].At)...)
+	}
+	if err := DB.CreateInBatches(points, 500).Error; err != nil {
+		return err
+	}
+	if len(custom) > 0 {
+		if err := DB.CreateInBatches(custom, 500).Error; err != nil {
+			return err
+		}
+	}
+
+	m.rollupMu.Lock()
+	defer m.rollupMu.Unlock()
+
+	from, to := samples[0].At.Unix(), samples[len(samples)-1].At.Unix()
+	for _, step := range metricRollupChain {
+		if err := rerollupPoints[model.MetricPoint](metricRollupSelect, "server_id", serverID, step[0], step[1], from, to); err != nil {
+			return err
+		}
+		if err := rerollupPoints[model.CustomMetricPoint](customMetricRollupSelect, "server_id, name", serverID, step[0], step[1], from, to); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rerollupPoints 只处理 Rollup 已经处理过的时间段，之后的时间段由 Rollup 正常聚合
+func rerollupPoints[T any](columns, group string, serverID uint64, src, dst int, from, to int64) error {
+	var last *int64
+	if err := DB.Model(new(T)).Where("resolution = ?", dst).Select("MAX(timestamp)").Scan(&last).Error; err != nil {
+		return err
+	}
+	if last == nil {
+		return nil
+	}
+	end := min(metricBucketEnd(dst, *last), metricBucketEnd(dst, metricBucketStart(dst, to)))
+
+	for bucket := metricBucketStart(dst, from); bucket < end; bucket = metricBucketEnd(dst, bucket) {
+		var points []*T
+		if err := DB.Model(new(T)).Select("? AS resolution, ? AS timestamp, "+columns, dst, bucket).
+			Where("server_id = ? AND resolution = ? AND timestamp >= ? AND timestamp < ?", serverID, src, bucket, metricBucketEnd(dst, bucket)).
+			Group(group).Scan(&points).Error; err != nil {
+			return err
+		}
+		if len(points) == 0 {
+			continue
+		}
+		if err := DB.Unscoped().Where("server_id = ? AND resolution = ? AND timestamp = ?", serverID, dst, bucket).Delete(new(T)).Error; err != nil {
+			return err
+		}
+		if err := DB.CreateInBatches(points, 500).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// metricBucketStart 按天聚合时以面板时区的零点为界
+func metricBucketStart(res int, ts int64) int64 {
+	switch res {
+	case model.MetricResolutionRaw:
+		return ts
+	case model.MetricResolutionDay:
+		t := time.Unix(ts, 0).In(Loc)
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, Loc).Unix()
+	}
+	return ts - ts%int64(res)
+}
+
+func metricBucketEnd(res int, start int64) int64 {
+	switch res {
+	case model.MetricResolutionRaw:
+		return start + 1
+	case model.MetricResolutionDay:
+		return time.Unix(start, 0).In(Loc).AddDate(0, 0, 1).Unix()
+	}
+	return start + int64(res)
+}
+
+// MetricsRetention 各精度数据的保留时长
+func MetricsRetention(res int) time.Duration {
+	switch res {
+	case model.MetricResolutionRaw:
+		return time.Duration(Conf.Metrics.RawRetentionHours) * time.Hour
+	case model.MetricResolutionMinute:
+		return time.Duration(Conf.Metrics.MinuteRetentionDays) * 24 * time.Hour
+	case model.MetricResolutionHour:
+		return time.Duration(Conf.Metrics.HourRetentionDays) * 24 * time.Hour
+	}
+	return time.Duration(Conf.Metrics.DayRetentionDays) * 24 * time.Hour
+}
+
+// ParseMetricResolution 解析 raw、1m、1h、1d，auto 时根据时间范围选择
+func ParseMetricResolution(s string, start, end time.Time) (int, error) {
+	switch s {
+	case "raw":
+		return model.MetricResolutionRaw, nil
+	case "1m":
+		return model.MetricResolutionMinute, nil
+	case "1h":
+		return model.MetricResolutionHour, nil
+	case "1d":
+		return model.MetricResolutionDay, nil
+	case "", "auto":
+		return autoMetricResolution(start, end, time.Now()), nil
+	}
+	return 0, errors.New("invalid resolution")
+}
+
+// autoMetricResolution 选择数据仍在保留期内且点数不过多的最细精度
+func autoMetricResolution(start, end, now time.Time) int {
+	span := end.Sub(start)
+	covers := func(res int) bool {
+		return !start.Before(now.Add(-MetricsRetention(res)))
+	}
+	switch {
+	case span <= time.Hour && covers(model.MetricResolutionRaw):
+		return model.MetricResolutionRaw
+	case span <= 25*time.Hour && covers(model.MetricResolutionMinute):
+		return model.MetricResolutionMinute
+	case span <= 62*24*time.Hour && covers(model.MetricResolutionHour):
+		return model.MetricResolutionHour
+	}
+	return model.MetricResolutionDay
+}
+
+func MetricResolutionName(res int) string {
+	switch res {
+	case model.MetricResolutionRaw:
+		return "raw"
+	case model.MetricResolutionMinute:
+		return "1m"
+	case model.MetricResolutionHour:
+		return "1h"
+	}
+	return "1d"
+}
+
+// Query 查询时间范围内的数据点，按时间升序
+func (m *MetricsClass) Query(serverID uint64, res int, start, end time.Time) ([]*model.MetricPoint, error) {
+	var points []*model.MetricPoint
+	err := DB.Where("server_id = ? AND resolution = ? AND timestamp >= ? AND timestamp <= ?",
+		serverID, res, metricBucketStart(res, start.Unix()), end.Unix()).
+		Order("timestamp").Limit(metricsMaxPoints).Find(&points).Error
+	return points, err
+}
+
+// QueryCustom 查询自定义指标在时间范围内的数据点，按时间升序
+func (m *MetricsClass) QueryCustom(serverID uint64, name string, res int, start, end time.Time) ([]*model.CustomMetricPoint, error) {
+	var points []*model.CustomMetricPoint
+	err := DB.Where("server_id = ? AND name = ? AND resolution = ? AND timestamp >= ? AND timestamp <= ?",
+		serverID, name, res, metricBucketStart(res, start.Unix()), end.Unix()).
+		Order("timestamp").Limit(metricsMaxPoints).Find(&points).Error
+	return points, err
+}
@@ -0,0 +1,138 @@
+package singleton
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/nezhahq/nezha/model"
+)
+
+func setupMetricsTest(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(model.MetricPoint{}, model.CustomMetricPoint{}); err != nil {
+		t.Fatal(err)
+	}
+	DB = db
+	Loc = time.UTC
+	Conf = &ConfigClass{Config: &model.Config{}}
+	Conf.Metrics = model.MetricsConf{RawRetentionHours: 6, MinuteRetentionDays: 7, HourRetentionDays: 90, DayRetentionDays: 730}
+}
+
+func TestMetricsRollup(t *testing.T) {
+	setupMetricsTest(t)
+
+	m := NewMetricsClass()
+	base := time.Now().Truncate(time.Hour).Add(-time.Hour)
+	m.Add(1, &model.HostState{CPU: 10, MemUsed: 100, GPU: []float64{20, 40}}, base)
+	m.Add(1, &model.HostState{CPU: 30, MemUsed: 300}, base.Add(30*time.Second))
+	m.Add(1, &model.HostState{CPU: 50, MemUsed: 500}, base.Add(time.Minute))
+	m.Add(2, &model.HostState{CPU: 90, Temperatures: []model.SensorTemperature{{Name: "a", Temperature: 40}, {Name: "b", Temperature: 60}}}, base)
+	m.Flush()
+
+	now := base.Add(time.Hour + time.Minute)
+	if err := m.rollup(model.MetricResolutionRaw, model.MetricResolutionMinute, now); err != nil {
+		t.Fatal(err)
+	}
+	points, err := m.Query(1, model.MetricResolutionMinute, base, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 2 {
+		t.Fatalf("expected 2 minute points, got %d", len(points))
+	}
+	if p := points[0]; p.Timestamp != base.Unix() || p.Samples != 2 || p.CPU != 20 || p.CPUMax != 30 || p.MemUsed != 200 || p.GPU != 15 || p.GPUMax != 30 {
+		t.Fatalf("unexpected minute point: %+v", p)
+	}
+	points, _ = m.Query(2, model.MetricResolutionMinute, base, now)
+	if len(points) != 1 || points[0].Temperature != 60 {
+		t.Fatalf("unexpected minute points for server 2: %+v", points)
+	}
+
+	// 已聚合的时间段不会重复聚合
+	if err := m.rollup(model.MetricResolutionRaw, model.MetricResolutionMinute, now); err != nil {
+		t.Fatal(err)
+	}
+	var count int64
+	DB.Model(&model.MetricPoint{}).Where("resolution = ?", model.MetricResolutionMinute).Count(&count)
+	if count != 3 {
+		t.Fatalf("expected 3 minute points, got %d", count)
+	}
+
+	if err := m.rollup(model.MetricResolutionMinute, model.MetricResolutionHour, now); err != nil {
+		t.Fatal(err)
+	}
+	points, _ = m.Query(1, model.MetricResolutionHour, base, now)
+	if len(points) != 1 || points[0].Samples != 3 || points[0].CPU != 30 || points[0].CPUMax != 50 {
+		t.Fatalf("unexpected hour points: %+v", points)
+	}
+}
+
+func TestCustomMetricsRollup(t *testing.T) {
+	setupMetricsTest(t)
+
+	m := NewMetricsClass()
+	base := time.Now().Truncate(time.Hour).Add(-time.Hour)
+	queue := func(mail, sms float64) *model.HostState {
+		return &model.HostState{CustomMetrics: []model.CustomMetric{
+			{Name: `queue_depth{queue="mail"}`, Value: mail, Type: "gauge"},
+			{Name: `queue_depth{queue="sms"}`, Value: sms, Type: "gauge"},
+		}}
+	}
+	m.Add(1, queue(10, 1), base)
+	m.Add(1, queue(30, 2), base.Add(30*time.Second))
+	m.Add(1, queue(50, 3), base.Add(time.Minute))
+	m.Flush()
+
+	now := base.Add(time.Hour + time.Minute)
+	if err := m.rollup(model.MetricResolutionRaw, model.MetricResolutionMinute, now); err != nil {
+		t.Fatal(err)
+	}
+	points, err := m.QueryCustom(1, `queue_depth{queue="mail"}`, model.MetricResolutionMinute, base, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 2 {
+		t.Fatalf("expected 2 minute points, got %d", len(points))
+	}
+	if p := points[0]; p.Timestamp != base.Unix() || p.Samples != 2 || p.Value != 20 || p.ValueMax != 30 {
+		t.Fatalf("unexpected minute point: %+v", p)
+	}
+
+	if err := m.rollup(model.MetricResolutionMinute, model.MetricResolutionHour, now); err != nil {
+		t.Fatal(err)
+	}
+	points, _ = m.QueryCustom(1, `queue_depth{queue="sms"}`, model.MetricResolutionHour, base, now)
+	if len(points) != 1 || points[0].Samples != 3 || points[0].Value != 2 || points[0].ValueMax != 3 {
+		t.Fatalf("unexpected hour points: %+v", points)
+	}
+}
+
+func TestAutoMetricResolution(t *testing.T) {
+	setupMetricsTest(t)
+
+	now := time.Now()
+	cases := []struct {
+		start time.Time
+		want  int
+	}{
+		{now.Add(-30 * time.Minute), model.MetricResolutionRaw},
+		{now.Add(-12 * time.Hour), model.MetricResolutionMinute},
+		{now.Add(-30 * 24 * time.Hour), model.MetricResolutionHour},
+		{now.Add(-365 * 24 * time.Hour), model.MetricResolutionDay},
+	}
+	for _, c := range cases {
+		if got := autoMetricResolution(c.start, now, now); got != c.want {
+			t.Errorf("span %v: got %d, want %d", now.Sub(c.start), got, c.want)
+		}
+	}
+	// 原始数据已过期时使用分钟数据
+	if got := autoMetricResolution(now.Add(-8*time.Hour), now.Add(-7*time.Hour), now); got != model.MetricResolutionMinute {
+		t.Errorf("expected minute resolution for expired raw data, got %d", got)
+	}
+}
@@ -0,0 +1,205 @@
+package singleton
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"log"
+	"net"
+	"net/netip"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/pkg/geoip"
+)
+
+const (
+	NATSessionCookie = "nz-nat-session"
+	NATTicketQuery   = "nz-nat-ticket"
+
+	// 面板登录后跳转回 NAT 域名时携带的一次性凭据
+	natTicketTTL = time.Minute
+)
+
+const (
+	natTokenTicket  = "ticket"
+	natTokenSession = "session"
+)
+
+type natToken struct {
+	Kind   string `json:"k"`
+	NATID  uint64 `json:"n"`
+	UserID uint64 `json:"u"`
+	IP     string `json:"i,omitempty"`
+	Expire int64  `json:"e"`
+}
+
+var (
+	natAccessMu sync.Mutex
+	// 已使用的 ticket，过期后清理
+	natUsedTickets = make(map[string]time.Time)
+	// 通过校验的 Basic Auth 凭据，避免每个请求都计算 bcrypt
+	natBasicAuthCache = make(map[uint64]string)
+)
+
+func natTokenSign(payload string) string {
+	mac := hmac.New(sha256.New, []byte("nat-access:"+Conf.JWTSecretKey))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func issueNATToken(t natToken) string {
+	data, _ := json.Marshal(t)
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + natTokenSign(payload)
+}
+
+func parseNATToken(token, kind string, natID uint64) (*natToken, bool) {
+	payload, sign, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(sign), []byte(natTokenSign(payload))) {
+		return nil, false
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, false
+	}
+	var t natToken
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, false
+	}
+	if t.Kind != kind || t.NATID != natID || time.Now().Unix() > t.Expire {
+		return nil, false
+	}
+	return &t, true
+}
+
+// IssueNATTicket 用户在面板上通过校验后签发，NAT 域名用它换取会话
+func IssueNATTicket(natID, userID uint64) string {
+	return issueNATToken(natToken{
+		Kind:   natTokenTicket,
+		NATID:  natID,
+		UserID: userID,
+		Expire: time.Now().Add(natTicketTTL).Unix(),
+	})
+}
+
+// RedeemNATTicket 校验 ticket 并签发绑定来源 IP 的会话，每个 ticket 只能使用一次
+func RedeemNATTicket(ticket string, natID uint64, ip string) (session string, expire time.Time, ok bool) {
+	t, ok := parseNATToken(ticket, natTokenTicket, natID)
+	if !ok {
+		return "", time.Time{}, false
+	}
+
+	natAccessMu.Lock()
+	now := time.Now()
+	for k, exp := range natUsedTickets {
+		if now.After(exp) {
+			delete(natUsedTickets, k)
+		}
+	}
+	if _, used := natUsedTickets[ticket]; used {
+		natAccessMu.Unlock()
+		return "", time.Time{}, false
+	}
+	natUsedTickets[ticket] = time.Unix(t.Expire, 0)
+	natAccessMu.Unlock()
+
+	expire = now.Add(time.Hour * time.Duration(max(Conf.JWTTimeout, 1)))
+	return issueNATToken(natToken{
+		Kind:   natTokenSession,
+		NATID:  natID,
+		UserID: t.UserID,
+		IP:     ip,
+		Expire: expire.Unix(),
+	}), expire, true
+}
+
+// VerifyNATSession 返回会话对应的用户，用户需仍有权访问此 NAT
+func VerifyNATSession(session string, n *model.NAT, ip string) (uint64, bool) {
+	t, ok := parseNATToken(session, natTokenSession, n.ID)
+	if !ok || t.IP != ip {
+		return 0, false
+	}
+	var user model.User
+	if err := DB.Select("id", "role").First(&user, t.UserID).Error; err != nil {
+		return 0, false
+	}
+	if user.Role != model.RoleAdmin && user.ID != n.UserID {
+		return 0, false
+	}
+	return user.ID, true
+}
+
+// CheckNATSource 来源白名单为空时允许所有来源
+func CheckNATSource(n *model.NAT, ip string) bool {
+	if len(n.AllowedSources) == 0 {
+		return true
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	prefixes, err := model.ParseAllowedSources(n.AllowedSources)
+	if err != nil {
+		return false
+	}
+	return model.SourceAllowed(prefixes, addr)
+}
+
+// CheckNATCountry 返回来源的国家/地区代码以及是否允许访问，无法识别来源时仅在 deny 模式下放行
+func CheckNATCountry(n *model.NAT, ip string) (string, bool) {
+	var country string
+	if parsed := net.ParseIP(ip); parsed != nil {
+		country, _ = geoip.Lookup(parsed)
+	}
+
+	switch n.CountryFilterMode {
+	case model.NATCountryFilterAllow:
+		return country, country != "" && slices.Contains(n.Countries, country)
+	case model.NATCountryFilterDeny:
+		return country, country == "" || !slices.Contains(n.Countries, country)
+	}
+	return country, true
+}
+
+// CheckNATBasicAuth 未配置 Basic Auth 时直接通过
+func CheckNATBasicAuth(n *model.NAT, username, password string, ok bool) bool {
+	if n.BasicAuthUsername == "" {
+		return true
+	}
+	if !ok || username != n.BasicAuthUsername {
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(n.BasicAuthPassword + "\x00" + username + ":" + password))
+	digest := hex.EncodeToString(sum[:])
+
+	natAccessMu.Lock()
+	cached := natBasicAuthCache[n.ID]
+	natAccessMu.Unlock()
+	if hmac.Equal([]byte(cached), []byte(digest)) {
+		return true
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(n.BasicAuthPassword), []byte(password)) != nil {
+		return false
+	}
+	natAccessMu.Lock()
+	natBasicAuthCache[n.ID] = digest
+	natAccessMu.Unlock()
+	return true
+}
+
+// RecordNATRequest 写入 NAT 请求日志
+func RecordNATRequest(l *model.NATRequestLog) {
+	if err := DB.Create(l).Error; err != nil {
+		log.Printf("NEZHA>> NAT request log error: %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package singleton
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/nezhahq/nezha/model"
+)
+
+func TestNATTicket(t *testing.T) {
+	Conf = &ConfigClass{Config: &model.Config{}}
+	Conf.JWTSecretKey = "secret"
+
+	ticket := IssueNATTicket(1, 2)
+	if _, _, ok := RedeemNATTicket(ticket, 3, "1.1.1.1"); ok {
+		t.Fatal("ticket should be bound to its NAT")
+	}
+	session, _, ok := RedeemNATTicket(ticket, 1, "1.1.1.1")
+	if !ok {
+		t.Fatal("expected ticket to be redeemed")
+	}
+	if _, _, ok := RedeemNATTicket(ticket, 1, "1.1.1.1"); ok {
+		t.Fatal("ticket should only be used once")
+	}
+	if _, _, ok := RedeemNATTicket(session, 1, "1.1.1.1"); ok {
+		t.Fatal("session should not be accepted as a ticket")
+	}
+
+	tok, ok := parseNATToken(session, natTokenSession, 1)
+	if !ok || tok.UserID != 2 || tok.IP != "1.1.1.1" {
+		t.Fatalf("unexpected session: %+v", tok)
+	}
+	if _, ok := parseNATToken(session[:len(session)-1]+"x", natTokenSession, 1); ok {
+		t.Fatal("tampered session should be rejected")
+	}
+
+	Conf.JWTSecretKey = "rotated"
+	if _, ok := parseNATToken(session, natTokenSession, 1); ok {
+		t.Fatal("session signed with an old key should be rejected")
+	}
+}
+
+func TestNATSourceAndCountry(t *testing.T) {
+	n := &model.NAT{AllowedSources: []string{"10.0.0.0/8", "192.168.1.1"}}
+	for ip, want := range map[string]bool{
+		"10.1.2.3":    true,
+		"192.168.1.1": true,
+		"192.168.1.2": false,
+		"invalid":     false,
+	} {
+		if got := CheckNATSource(n, ip); got != want {
+			t.Errorf("CheckNATSource(%s) = %v, want %v", ip, got, want)
+		}
+	}
+
+	// 内网地址无法识别国家/地区
+	n = &model.NAT{CountryFilterMode: model.NATCountryFilterAllow, Countries: []string{"us"}}
+	if _, ok := CheckNATCountry(n, "10.1.2.3"); ok {
+		t.Error("unknown country should be denied in allow mode")
+	}
+	n.CountryFilterMode = model.NATCountryFilterDeny
+	if _, ok := CheckNATCountry(n, "10.1.2.3"); !ok {
+		t.Error("unknown country should be allowed in deny mode")
+	}
+}
+
+func TestNATBasicAuth(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("pass"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := &model.NAT{Common: model.Common{ID: 9}, BasicAuthUsername: "user", BasicAuthPassword: string(hash)}
+
+	if CheckNATBasicAuth(n, "", "", false) {
+		t.Fatal("missing credentials should be rejected")
+	}
+	if CheckNATBasicAuth(n, "user", "wrong", true) {
+		t.Fatal("wrong password should be rejected")
+	}
+	for range 2 {
+		if !CheckNATBasicAuth(n, "user", "pass", true) {
+			t.Fatal("expected credentials to be accepted")
+		}
+	}
+
+	// 修改密码后缓存失效
+	hash, _ = bcrypt.GenerateFromPassword([]byte("new"), bcrypt.MinCost)
+	n.BasicAuthPassword = string(hash)
+	if CheckNATBasicAuth(n, "user", "pass", true) {
+		t.Fatal("old password should be rejected after change")
+	}
+}
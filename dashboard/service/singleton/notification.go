@@ -292,3 +292,7 @@ func (_NotificationMuteLabel) ServiceStateChanged(serviceId uint64) string {
 func (_NotificationMuteLabel) ServiceTLS(serviceId uint64, extraInfo string) string {
 	return fmt.Sprintf("bf::stls-%d-%s", serviceId, extraInfo)
 }
+
+func (_NotificationMuteLabel) AutoSSHHostKeyMismatch(mappingId uint64) string {
+	return fmt.Sprintf("bf::ahk-%d", mappingId)
+}
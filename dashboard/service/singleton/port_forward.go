@@ -0,0 +1,60 @@
+package singleton
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/nezhahq/nezha/model"
+	"github.com/nezhahq/nezha/pkg/utils"
+)
+
+type PortForwardClass struct {
+	class[uint64, *model.PortForward]
+}
+
+func NewPortForwardClass() *PortForwardClass {
+	var sortedList []*model.PortForward
+
+	DB.Find(&sortedList)
+	list := make(map[uint64]*model.PortForward, len(sortedList))
+	for _, pf := range sortedList {
+		list[pf.ID] = pf
+	}
+
+	return &PortForwardClass{
+		class: class[uint64, *model.PortForward]{
+			list:       list,
+			sortedList: sortedList,
+		},
+	}
+}
+
+func (c *PortForwardClass) Update(pf *model.PortForward) {
+	c.listMu.Lock()
+	c.list[pf.ID] = pf
+	c.listMu.Unlock()
+	c.sortList()
+}
+
+func (c *PortForwardClass) Delete(idList []uint64) {
+	c.listMu.Lock()
+	for _, id := range idList {
+		delete(c.list, id)
+	}
+	c.listMu.Unlock()
+	c.sortList()
+}
+
+func (c *PortForwardClass) sortList() {
+	c.listMu.RLock()
+	defer c.listMu.RUnlock()
+
+	sortedList := utils.MapValuesToSlice(c.list)
+	slices.SortFunc(sortedList, func(a, b *model.PortForward) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	c.sortedListMu.Lock()
+	defer c.sortedListMu.Unlock()
+	c.sortedList = sortedList
+}
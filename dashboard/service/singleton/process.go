@@ -0,0 +1,45 @@
+package singleton
+
+import (
+	"github.com/goccy/go-json"
+
+	"github.com/nezhahq/nezha/model"
+)
+
+// ProcessClass 按需向 agent 查询进程并转发信号
+type ProcessClass struct {
+	*taskCaller
+}
+
+func NewProcessClass() *ProcessClass {
+	return &ProcessClass{
+		taskCaller: newTaskCaller(),
+	}
+}
+
+// Top 获取 CPU 或内存占用最高的进程
+func (p *ProcessClass) Top(server *model.Server, sort string, limit int) ([]*model.Process, error) {
+	data, err := p.call(server, model.TaskTypeProcess, &model.TaskProcess{
+		Action: model.ProcessActionList,
+		Sort:   sort,
+		Limit:  limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var procs []*model.Process
+	if err := json.Unmarshal([]byte(data), &procs); err != nil {
+		return nil, err
+	}
+	return procs, nil
+}
+
+// Signal 由 agent 检查 DisableCommandExecute
+func (p *ProcessClass) Signal(server *model.Server, pid int32, signal string) error {
+	_, err := p.call(server, model.TaskTypeProcess, &model.TaskProcess{
+		Action: model.ProcessActionSignal,
+		PID:    pid,
+		Signal: signal,
+	})
+	return err
+}
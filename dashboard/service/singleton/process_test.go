@@ -0,0 +1,47 @@
+package singleton
+
+import (
+	"testing"
+
+	"github.com/goccy/go-json"
+
+	"github.com/nezhahq/nezha/model"
+	pb "github.com/nezhahq/nezha/proto"
+)
+
+func TestProcessCall(t *testing.T) {
+	p := NewProcessClass()
+	stream := &fakeTaskStream{sent: make(chan *pb.Task, 1)}
+	server := &model.Server{Common: model.Common{ID: 1}, TaskStream: stream}
+
+	go func() {
+		task := <-stream.sent
+		var tp model.TaskProcess
+		if err := json.Unmarshal([]byte(task.GetData()), &tp); err != nil || task.GetType() != model.TaskTypeProcess ||
+			tp.Action != model.ProcessActionList || tp.Sort != model.ProcessSortMemory || tp.Limit != 5 {
+			t.Errorf("unexpected task: %v", task)
+		}
+		p.ApplyResult(1, &pb.TaskResult{Id: task.GetId(), Successful: true,
+			Data: `[{"pid":42,"name":"nginx","user":"www","rss":1048576,"cpu":12.5}]`})
+	}()
+
+	procs, err := p.Top(server, model.ProcessSortMemory, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(procs) != 1 || procs[0].PID != 42 || procs[0].RSS != 1<<20 || procs[0].CPU != 12.5 {
+		t.Fatalf("unexpected processes: %+v", procs[0])
+	}
+
+	go func() {
+		task := <-stream.sent
+		var tp model.TaskProcess
+		if err := json.Unmarshal([]byte(task.GetData()), &tp); err != nil || tp.PID != 42 || tp.Signal != "TERM" {
+			t.Errorf("unexpected task: %v", task)
+		}
+		p.ApplyResult(1, &pb.TaskResult{Id: task.GetId(), Data: "此 Agent 已禁止命令执行"})
+	}()
+	if err := p.Signal(server, 42, "TERM"); err == nil {
+		t.Fatal("refused signal should return an error")
+	}
+}
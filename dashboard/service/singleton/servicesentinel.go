@@ -72,7 +72,8 @@ type ServiceSentinel struct {
 	serviceCurrentStatusData     map[uint64]*serviceTaskStatus    // 当前任务结果缓存
 	serviceResponseDataStore     map[uint64]serviceResponseData   // 当前数据
 
-	serviceResponsePing map[uint64]map[uint64]*pingStore // [service_id] -> ClientID -> delay
+	serviceResponsePing map[uint64]map[uint64]*pingStore            // [service_id] -> ClientID -> delay
+	serviceLastResult   map[uint64]map[uint64]ServiceReporterResult // [service_id] -> ClientID -> 最近一次结果
 	tlsCertCache        map[uint64]string
 
 	servicesLock    sync.RWMutex
@@ -93,6 +94,7 @@ func NewServiceSentinel(serviceSentinelDispatchBus chan<- *model.Service) (*Serv
 		serviceCurrentStatusData: make(map[uint64]*serviceTaskStatus),
 		serviceResponseDataStore: make(map[uint64]serviceResponseData),
 		serviceResponsePing:      make(map[uint64]map[uint64]*pingStore),
+		serviceLastResult:        make(map[uint64]map[uint64]ServiceReporterResult),
 		services:                 make(map[uint64]*model.Service),
 		tlsCertCache:             make(map[uint64]string),
 		// 30天数据缓存
@@ -293,6 +295,7 @@ func (ss *ServiceSentinel) Delete(ids []uint64) {
 	for _, id := range ids {
 		delete(ss.serviceCurrentStatusData, id)
 		delete(ss.serviceResponseDataStore, id)
+		delete(ss.serviceLastResult, id)
 		delete(ss.tlsCertCache, id)
 		delete(ss.serviceStatusToday, id)
 
@@ -357,6 +360,61 @@ func (ss *ServiceSentinel) CopyStats() map[uint64]model.ServiceResponseItem {
 	return sri
 }
 
+// ServiceReporterResult 一个监测点最近一次的监控结果
+type ServiceReporterResult struct {
+	Successful bool
+	Delay      float32
+	At         time.Time
+}
+
+// ServiceSnapshot 服务监控的当前状态
+type ServiceSnapshot struct {
+	Service     *model.Service
+	Status      uint8 // 最近 15 分钟的状态，见 StatusGood 等
+	CurrentUp   uint64
+	CurrentDown uint64
+	Delay       float32 // 最近 15 分钟的平均延迟
+	Reporters   map[uint64]ServiceReporterResult
+}
+
+// Up 按最近 15 分钟的检测结果判断服务是否在线，没有检测结果时 ok 为 false
+func (s *ServiceSnapshot) Up() (up, ok bool) {
+	total := s.CurrentUp + s.CurrentDown
+	if total == 0 {
+		return false, false
+	}
+	// 全部失败时可用率为 0，GetStatusCode 会将其视为无数据
+	if s.CurrentUp == 0 {
+		return false, true
+	}
+	return GetStatusCode(s.CurrentUp*100/total) != StatusDown, true
+}
+
+// Snapshot 返回所有服务监控的当前状态
+func (ss *ServiceSentinel) Snapshot() []ServiceSnapshot {
+	services := ss.GetSortedList()
+
+	ss.serviceResponseDataStoreLock.RLock()
+	defer ss.serviceResponseDataStoreLock.RUnlock()
+
+	snapshots := make([]ServiceSnapshot, 0, len(services))
+	for _, service := range services {
+		rd := ss.serviceResponseDataStore[service.ID]
+		snapshot := ServiceSnapshot{
+			Service:     service,
+			CurrentUp:   rd.Up,
+			CurrentDown: rd.Down,
+			Delay:       rd.Delay,
+			Reporters:   maps.Clone(ss.serviceLastResult[service.ID]),
+		}
+		if status := ss.serviceCurrentStatusData[service.ID]; status != nil {
+			snapshot.Status = status.lastStatus
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots
+}
+
 func (ss *ServiceSentinel) Get(id uint64) (s *model.Service, ok bool) {
 	ss.servicesLock.RLock()
 	defer ss.servicesLock.RUnlock()
@@ -433,6 +491,13 @@ func (ss *ServiceSentinel) worker() {
 		}
 
 		ss.serviceResponseDataStoreLock.Lock()
+		lastResult, ok := ss.serviceLastResult[mh.GetId()]
+		if !ok {
+			lastResult = make(map[uint64]ServiceReporterResult)
+			ss.serviceLastResult[mh.GetId()] = lastResult
+		}
+		lastResult[r.Reporter] = ServiceReporterResult{Successful: mh.Successful, Delay: mh.Delay, At: time.Now()}
+
 		// 写入当天状态
 		if mh.Successful {
 			ss.serviceStatusToday[mh.GetId()].Delay = (ss.serviceStatusToday[mh.
@@ -0,0 +1,42 @@
+package singleton
+
+import (
+	"testing"
+
+	"github.com/nezhahq/nezha/model"
+)
+
+func TestServiceSnapshotUp(t *testing.T) {
+	cases := []struct {
+		up, down uint64
+		wantUp   bool
+		wantOK   bool
+	}{
+		{0, 0, false, false},
+		{0, 30, false, true},
+		{30, 0, true, true},
+		{29, 1, true, true},
+		{20, 10, false, true},
+	}
+
+	ss := &ServiceSentinel{
+		serviceCurrentStatusData: make(map[uint64]*serviceTaskStatus),
+		serviceResponseDataStore: make(map[uint64]serviceResponseData),
+	}
+	for i, c := range cases {
+		service := &model.Service{Common: model.Common{ID: uint64(i + 1)}}
+		ss.serviceList = append(ss.serviceList, service)
+		ss.serviceResponseDataStore[service.ID] = serviceResponseData{Up: c.up, Down: c.down}
+	}
+
+	snapshots := ss.Snapshot()
+	if len(snapshots) != len(cases) {
+		t.Fatalf("expected %d snapshots, got %d", len(cases), len(snapshots))
+	}
+	for i, c := range cases {
+		up, ok := snapshots[i].Up()
+		if up != c.wantUp || ok != c.wantOK {
+			t.Errorf("up=%d down=%d: got (%v, %v), want (%v, %v)", c.up, c.down, up, ok, c.wantUp, c.wantOK)
+		}
+	}
+}
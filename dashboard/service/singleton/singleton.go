@@ -34,6 +34,13 @@ var (
 	NotificationShared    *NotificationClass
 	NATShared             *NATClass
 	AutoSSHShared         *AutoSSHClass
+	TunnelStatsShared     *TunnelStatsClass
+	PortForwardShared     *PortForwardClass
+	MetricsShared         *MetricsClass
+	DockerShared          *DockerClass
+	ProcessShared         *ProcessClass
+	SystemdShared         *SystemdClass
+	LogShared             *LogClass
 	CronShared            *CronClass
 )
 
@@ -58,6 +65,13 @@ func LoadSingleton(bus chan<- *model.Service) (err error) {
 	NATShared = NewNATClass()
 	DDNSShared = NewDDNSClass()
 	AutoSSHShared = NewAutoSSHClass()
+	TunnelStatsShared = NewTunnelStatsClass()
+	PortForwardShared = NewPortForwardClass()
+	MetricsShared = NewMetricsClass()
+	DockerShared = NewDockerClass()
+	ProcessShared = NewProcessClass()
+	SystemdShared = NewSystemdClass()
+	LogShared = NewLogClass()
 	NotificationShared = NewNotificationClass()
 	ServerShared = NewServerClass()
 	CronShared = NewCronClass()
@@ -92,7 +106,11 @@ func InitDBFromPath(path string) error {
 		model.ServiceHistory{}, model.Cron{}, model.Transfer{}, model.ServerGroupServer{},
 		model.NAT{}, model.DDNSProfile{}, model.NotificationGroupNotification{},
 		model.WAF{}, model.Oauth2Bind{}, model.AutoSSH{}, model.UserServer{},
-		model.TerminalSession{}, model.TerminalCommand{}, model.TerminalBlacklist{})
+		model.TerminalSession{}, model.TerminalCommand{}, model.TerminalBlacklist{},
+		model.TerminalUserMapping{}, model.TerminalTransfer{}, model.AutoSSHKey{},
+		model.TunnelTransfer{}, model.PortForward{}, model.NATRequestLog{},
+		model.MetricPoint{}, model.CustomMetricPoint{},
+		model.LogAccessRule{})
 	if err != nil {
 		return err
 	}
@@ -149,6 +167,13 @@ func RecordTransferHourlyUsage(servers ...*model.Server) {
 		txs = append(txs, tx)
 	}
 
+	// 隧道流量与服务器流量同时打点
+	if len(servers) == 0 {
+		if ttxs := TunnelStatsShared.Record(nowTrimSeconds); len(ttxs) > 0 {
+			log.Printf("NEZHA>> Saved tunnel traffic metrics to database. Affected %d row(s), Error: %v", len(ttxs), DB.Create(ttxs).Error)
+		}
+	}
+
 	if len(txs) == 0 {
 		return
 	}
@@ -164,6 +189,17 @@ func CleanServiceHistory() {
 	// server_id = 0 的数据会用于/service页面的可用性展示
 	DB.Unscoped().Delete(&model.ServiceHistory{}, "(created_at < ? AND server_id != 0) OR service_id NOT IN (SELECT `id` FROM services)", time.Now().AddDate(0, 0, -1))
 	DB.Unscoped().Delete(&model.Transfer{}, "server_id NOT IN (SELECT `id` FROM servers)")
+	DB.Unscoped().Delete(&model.MetricPoint{}, "server_id NOT IN (SELECT `id` FROM servers)")
+	DB.Unscoped().Delete(&model.CustomMetricPoint{}, "server_id NOT IN (SELECT `id` FROM servers)")
+	// 隧道流量记录保留 30 天
+	DB.Unscoped().Delete(&model.TunnelTransfer{}, "created_at < ? OR (kind = ? AND tunnel_id NOT IN (?)) OR (kind = ? AND tunnel_id NOT IN (?)) OR (kind = ? AND tunnel_id NOT IN (?))",
+		time.Now().AddDate(0, 0, -30),
+		model.TunnelKindAutoSSH, DB.Model(&model.AutoSSH{}).Select("id"),
+		model.TunnelKindNAT, DB.Model(&model.NAT{}).Select("id"),
+		model.TunnelKindPortForward, DB.Model(&model.PortForward{}).Select("id"))
+	// NAT 请求日志保留 7 天
+	DB.Unscoped().Delete(&model.NATRequestLog{}, "created_at < ? OR nat_id NOT IN (?)",
+		time.Now().AddDate(0, 0, -7), DB.Model(&model.NAT{}).Select("id"))
 	// 计算可清理流量记录的时长
 	var allServerKeep time.Time
 	specialServerKeep := make(map[uint64]time.Time)
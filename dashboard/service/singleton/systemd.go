@@ -0,0 +1,38 @@
+package singleton
+
+import (
+	"time"
+
+	"github.com/nezhahq/nezha/model"
+	pb "github.com/nezhahq/nezha/proto"
+)
+
+// SystemdClass 保存 agent 上报的 systemd 单元状态，并将单元操作的结果交给等待的请求
+type SystemdClass struct {
+	*taskCaller
+}
+
+func NewSystemdClass() *SystemdClass {
+	return &SystemdClass{
+		taskCaller: newTaskCaller(),
+	}
+}
+
+// Report 更新服务器的单元状态，采集失败时保留上次的单元列表
+func (s *SystemdClass) Report(server *model.Server, r *pb.SystemdReport, now time.Time) {
+	state := model.PB2SystemdState(r)
+	state.UpdatedAt = now
+	if prev := server.Systemd; prev != nil && state.Error != "" && len(state.Units) == 0 {
+		state.Units = prev.Units
+	}
+	server.Systemd = &state
+}
+
+// Action 由 agent 检查 DisableCommandExecute
+func (s *SystemdClass) Action(server *model.Server, unit, action string) error {
+	_, err := s.call(server, model.TaskTypeSystemd, &model.TaskSystemd{
+		Action: action,
+		Unit:   unit,
+	})
+	return err
+}
@@ -0,0 +1,61 @@
+package singleton
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/nezhahq/nezha/model"
+	pb "github.com/nezhahq/nezha/proto"
+)
+
+func TestSystemdReport(t *testing.T) {
+	s := NewSystemdClass()
+	server := &model.Server{}
+	now := time.Now()
+
+	s.Report(server, &pb.SystemdReport{Units: []*pb.SystemdUnit{
+		{Name: "nginx.service", ActiveState: "failed", SubState: "failed", UnitFileState: "enabled"},
+		{Name: "sshd.service", ActiveState: "active", SubState: "running"},
+	}}, now)
+
+	cases := []struct {
+		rule model.Rule
+		pass bool
+	}{
+		{model.Rule{Type: "systemd_failed", Max: 0.5}, false},
+		{model.Rule{Type: "systemd_failed", Target: "nginx.service", Max: 0.5}, false},
+		{model.Rule{Type: "systemd_failed", Target: "sshd.service", Max: 0.5}, true},
+	}
+	for _, c := range cases {
+		if got := c.rule.Snapshot(nil, server, nil); got != c.pass {
+			t.Errorf("%s(%s) = %v, want %v", c.rule.Type, c.rule.Target, got, c.pass)
+		}
+	}
+
+	// 采集失败时保留上次的单元
+	s.Report(server, &pb.SystemdReport{Error: "systemctl: not found"}, now.Add(time.Minute))
+	if server.Systemd.Error == "" || server.Systemd.Unit("nginx.service") == nil {
+		t.Fatalf("unexpected state: %+v", server.Systemd)
+	}
+}
+
+func TestSystemdAction(t *testing.T) {
+	s := NewSystemdClass()
+	stream := &fakeTaskStream{sent: make(chan *pb.Task, 1)}
+	server := &model.Server{Common: model.Common{ID: 1}, TaskStream: stream}
+
+	go func() {
+		task := <-stream.sent
+		var ts model.TaskSystemd
+		if err := json.Unmarshal([]byte(task.GetData()), &ts); err != nil || task.GetType() != model.TaskTypeSystemd ||
+			ts.Action != model.SystemdActionRestart || ts.Unit != "nginx.service" {
+			t.Errorf("unexpected task: %v", task)
+		}
+		s.ApplyResult(1, &pb.TaskResult{Id: task.GetId(), Data: "此 Agent 已禁止命令执行"})
+	}()
+	if err := s.Action(server, "nginx.service", model.SystemdActionRestart); err == nil {
+		t.Fatal("expected error from agent")
+	}
+}
@@ -0,0 +1,88 @@
+package singleton
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/nezhahq/nezha/model"
+	pb "github.com/nezhahq/nezha/proto"
+)
+
+// 等待 agent 执行任务的超时时间
+const taskCallTimeout = time.Second * 30
+
+// taskCaller 向 agent 下发任务，并将任务流回报的结果交给等待的请求
+type taskCaller struct {
+	seq     atomic.Uint64
+	mu      sync.Mutex
+	pending map[uint64]*pendingCall
+}
+
+type pendingCall struct {
+	serverID uint64
+	ch       chan *pb.TaskResult
+}
+
+func newTaskCaller() *taskCaller {
+	return &taskCaller{
+		pending: make(map[uint64]*pendingCall),
+	}
+}
+
+// call 以自增序号作为任务 ID，结果由 ApplyResult 按 ID 送回
+func (t *taskCaller) call(server *model.Server, taskType uint64, task any) (string, error) {
+	if server.TaskStream == nil {
+		return "", Localizer.ErrorT("server not found or not connected")
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		return "", err
+	}
+
+	id := t.seq.Add(1)
+	call := &pendingCall{serverID: server.ID, ch: make(chan *pb.TaskResult, 1)}
+	t.mu.Lock()
+	t.pending[id] = call
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.pending, id)
+		t.mu.Unlock()
+	}()
+
+	if err := server.TaskStream.Send(&pb.Task{
+		Id:   id,
+		Type: taskType,
+		Data: string(data),
+	}); err != nil {
+		return "", err
+	}
+
+	select {
+	case result := <-call.ch:
+		if !result.GetSuccessful() {
+			return "", errors.New(result.GetData())
+		}
+		return result.GetData(), nil
+	case <-time.After(taskCallTimeout):
+		return "", Localizer.ErrorT("timeout waiting for agent")
+	}
+}
+
+// ApplyResult 处理 agent 回报的任务结果，请求已超时或不属于该服务器的结果被丢弃
+func (t *taskCaller) ApplyResult(serverID uint64, result *pb.TaskResult) {
+	t.mu.Lock()
+	call, ok := t.pending[result.GetId()]
+	t.mu.Unlock()
+	if !ok || call.serverID != serverID {
+		return
+	}
+	select {
+	case call.ch <- result:
+	default:
+	}
+}
@@ -2,6 +2,7 @@ package singleton
 
 import (
 	"log"
+	"os"
 	"time"
 
 	"github.com/nezhahq/nezha/model"
@@ -37,6 +38,21 @@ func CleanupTerminalAuditData() {
 	} else if result.RowsAffected > 0 {
 		log.Printf("NEZHA>> Cleaned up %d terminal commands older than %d days", result.RowsAffected, retentionDays)
 	}
+
+	// Delete old terminal file transfers and the files received by the dashboard
+	var files []string
+	DB.Model(&model.TerminalTransfer{}).Where("started_at < ? AND file_path <> ''", cutoffTime).Pluck("file_path", &files)
+	for _, f := range files {
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			log.Printf("NEZHA>> Failed to remove terminal transfer file %s: %v", f, err)
+		}
+	}
+	result = DB.Where("started_at < ?", cutoffTime).Delete(&model.TerminalTransfer{})
+	if result.Error != nil {
+		log.Printf("NEZHA>> Failed to cleanup terminal transfers: %v", result.Error)
+	} else if result.RowsAffected > 0 {
+		log.Printf("NEZHA>> Cleaned up %d terminal transfers older than %d days", result.RowsAffected, retentionDays)
+	}
 }
 
 // StartTerminalAuditCleanupTask starts a background task to periodically clean up old audit data
@@ -0,0 +1,224 @@
+package singleton
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/nezhahq/nezha/model"
+)
+
+type tunnelKey struct {
+	kind string
+	id   uint64
+}
+
+type tunnelCounter struct {
+	serverID uint64
+
+	// 自面板启动以来的累计值
+	in, out, connections uint64
+
+	// agent 回报的原始计数，用于计算增量
+	reportIn, reportOut, reportConns uint64
+	// 上次写入 TunnelTransfer 时的累计值
+	recordIn, recordOut, recordConns uint64
+	// 上次计算速率时的累计值
+	sampleIn, sampleOut uint64
+	sampleAt            time.Time
+	inSpeed, outSpeed   uint64
+
+	limit             uint64
+	limitIn, limitOut *rate.Limiter
+}
+
+// TunnelStatsClass AutoSSH 映射与 NAT 的流量统计
+type TunnelStatsClass struct {
+	mu       sync.Mutex
+	counters map[tunnelKey]*tunnelCounter
+}
+
+func NewTunnelStatsClass() *TunnelStatsClass {
+	return &TunnelStatsClass{
+		counters: make(map[tunnelKey]*tunnelCounter),
+	}
+}
+
+func (c *TunnelStatsClass) counter(kind string, id, serverID uint64) *tunnelCounter {
+	key := tunnelKey{kind, id}
+	tc, ok := c.counters[key]
+	if !ok {
+		tc = &tunnelCounter{sampleAt: time.Now()}
+		c.counters[key] = tc
+	}
+	tc.serverID = serverID
+	return tc
+}
+
+// reportDelta agent 重启隧道后计数从 0 开始
+func reportDelta(cur, last uint64) uint64 {
+	if cur >= last {
+		return cur - last
+	}
+	return cur
+}
+
+// ApplyAutoSSHReport 根据 agent 回报的累计计数更新映射流量
+func (c *TunnelStatsClass) ApplyAutoSSHReport(serverID uint64, report *model.AutoSSHStatusReport) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tc := c.counter(model.TunnelKindAutoSSH, report.MappingID, serverID)
+	tc.in += reportDelta(report.BytesIn, tc.reportIn)
+	tc.out += reportDelta(report.BytesOut, tc.reportOut)
+	tc.connections += reportDelta(report.Connections, tc.reportConns)
+	tc.reportIn, tc.reportOut, tc.reportConns = report.BytesIn, report.BytesOut, report.Connections
+}
+
+// Meter 统计经面板转发的连接的流量，limit 为每个方向的限速（字节/秒）
+func (c *TunnelStatsClass) Meter(kind string, id, serverID uint64, rwc io.ReadWriteCloser, limit uint64) io.ReadWriteCloser {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tc := c.counter(kind, id, serverID)
+	tc.connections++
+	if tc.limit != limit || (limit > 0 && tc.limitIn == nil) {
+		tc.limit = limit
+		tc.limitIn, tc.limitOut = newTunnelLimiter(limit), newTunnelLimiter(limit)
+	}
+	return &meteredTunnel{ReadWriteCloser: rwc, stats: c, counter: tc, limitIn: tc.limitIn, limitOut: tc.limitOut}
+}
+
+func newTunnelLimiter(bytesPerSecond uint64) *rate.Limiter {
+	if bytesPerSecond == 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(bytesPerSecond), int(min(bytesPerSecond, 1<<30)))
+}
+
+// meteredTunnel 读为用户发往隧道的流量，写为隧道返回给用户的流量
+type meteredTunnel struct {
+	io.ReadWriteCloser
+	stats             *TunnelStatsClass
+	counter           *tunnelCounter
+	limitIn, limitOut *rate.Limiter
+}
+
+func (m *meteredTunnel) Read(p []byte) (int, error) {
+	if m.limitIn != nil && len(p) > m.limitIn.Burst() {
+		p = p[:m.limitIn.Burst()]
+	}
+	n, err := m.ReadWriteCloser.Read(p)
+	if n > 0 {
+		if m.limitIn != nil {
+			m.limitIn.WaitN(context.Background(), n)
+		}
+		m.stats.mu.Lock()
+		m.counter.in += uint64(n)
+		m.stats.mu.Unlock()
+	}
+	return n, err
+}
+
+func (m *meteredTunnel) Write(p []byte) (int, error) {
+	var written int
+	for len(p) > 0 {
+		chunk := p
+		if m.limitOut != nil {
+			if burst := m.limitOut.Burst(); len(chunk) > burst {
+				chunk = chunk[:burst]
+			}
+			m.limitOut.WaitN(context.Background(), len(chunk))
+		}
+		n, err := m.ReadWriteCloser.Write(chunk)
+		written += n
+		m.stats.mu.Lock()
+		m.counter.out += uint64(n)
+		m.stats.mu.Unlock()
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// Sample 计算各隧道的速率，并汇总到服务器上供告警规则使用
+func (c *TunnelStatsClass) Sample() {
+	now := time.Now()
+	serverIn := make(map[uint64]uint64)
+	serverOut := make(map[uint64]uint64)
+
+	c.mu.Lock()
+	for _, tc := range c.counters {
+		if elapsed := now.Sub(tc.sampleAt).Seconds(); elapsed >= 1 {
+			tc.inSpeed = uint64(float64(tc.in-tc.sampleIn) / elapsed)
+			tc.outSpeed = uint64(float64(tc.out-tc.sampleOut) / elapsed)
+			tc.sampleIn, tc.sampleOut, tc.sampleAt = tc.in, tc.out, now
+		}
+		serverIn[tc.serverID] += tc.inSpeed
+		serverOut[tc.serverID] += tc.outSpeed
+	}
+	c.mu.Unlock()
+
+	for id, server := range ServerShared.Range {
+		server.TunnelInSpeed = serverIn[id]
+		server.TunnelOutSpeed = serverOut[id]
+	}
+}
+
+// Traffic 返回隧道的实时流量，没有记录时返回 nil
+func (c *TunnelStatsClass) Traffic(kind string, id uint64) *model.TunnelTraffic {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tc, ok := c.counters[tunnelKey{kind, id}]
+	if !ok {
+		return nil
+	}
+	return &model.TunnelTraffic{
+		In:          tc.in,
+		Out:         tc.out,
+		Connections: tc.connections,
+		InSpeed:     tc.inSpeed,
+		OutSpeed:    tc.outSpeed,
+	}
+}
+
+// Delete 隧道删除后不再统计
+func (c *TunnelStatsClass) Delete(kind string, idList []uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, id := range idList {
+		delete(c.counters, tunnelKey{kind, id})
+	}
+}
+
+// Record 生成自上次记录以来的流量数据点
+func (c *TunnelStatsClass) Record(at time.Time) []model.TunnelTransfer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var txs []model.TunnelTransfer
+	for key, tc := range c.counters {
+		tx := model.TunnelTransfer{
+			Kind:        key.kind,
+			TunnelID:    key.id,
+			ServerID:    tc.serverID,
+			In:          tc.in - tc.recordIn,
+			Out:         tc.out - tc.recordOut,
+			Connections: tc.connections - tc.recordConns,
+		}
+		if tx.In == 0 && tx.Out == 0 && tx.Connections == 0 {
+			continue
+		}
+		tc.recordIn, tc.recordOut, tc.recordConns = tc.in, tc.out, tc.connections
+		tx.CreatedAt = at
+		txs = append(txs, tx)
+	}
+	return txs
+}
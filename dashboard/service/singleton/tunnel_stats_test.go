@@ -0,0 +1,65 @@
+package singleton
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/nezhahq/nezha/model"
+)
+
+type nopReadWriteCloser struct {
+	io.Reader
+	io.Writer
+}
+
+func (nopReadWriteCloser) Close() error { return nil }
+
+func TestTunnelStatsAutoSSHReport(t *testing.T) {
+	c := NewTunnelStatsClass()
+	c.ApplyAutoSSHReport(1, &model.AutoSSHStatusReport{MappingID: 7, BytesIn: 100, BytesOut: 50, Connections: 2})
+	c.ApplyAutoSSHReport(1, &model.AutoSSHStatusReport{MappingID: 7, BytesIn: 300, BytesOut: 80, Connections: 3})
+	// agent 重启隧道后计数重新开始
+	c.ApplyAutoSSHReport(1, &model.AutoSSHStatusReport{MappingID: 7, BytesIn: 20, BytesOut: 10, Connections: 1})
+
+	traffic := c.Traffic(model.TunnelKindAutoSSH, 7)
+	if traffic == nil || traffic.In != 320 || traffic.Out != 90 || traffic.Connections != 4 {
+		t.Fatalf("unexpected traffic: %+v", traffic)
+	}
+
+	txs := c.Record(time.Now())
+	if len(txs) != 1 || txs[0].In != 320 || txs[0].ServerID != 1 {
+		t.Fatalf("unexpected records: %+v", txs)
+	}
+	if txs = c.Record(time.Now()); len(txs) != 0 {
+		t.Fatalf("expected no records without new traffic, but got %+v", txs)
+	}
+
+	c.Delete(model.TunnelKindAutoSSH, []uint64{7})
+	if c.Traffic(model.TunnelKindAutoSSH, 7) != nil {
+		t.Fatal("expected traffic to be removed")
+	}
+}
+
+func TestTunnelStatsMeter(t *testing.T) {
+	c := NewTunnelStatsClass()
+	var out bytes.Buffer
+	rwc := c.Meter(model.TunnelKindNAT, 3, 1, nopReadWriteCloser{Reader: bytes.NewReader(make([]byte, 1500)), Writer: &out}, 1000)
+
+	start := time.Now()
+	if n, err := io.Copy(io.Discard, rwc); err != nil || n != 1500 {
+		t.Fatalf("read %d bytes, error: %v", n, err)
+	}
+	if n, err := rwc.Write(make([]byte, 600)); err != nil || n != 600 {
+		t.Fatalf("write %d bytes, error: %v", n, err)
+	}
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Fatalf("expected rate limit to slow down reads, but took %s", elapsed)
+	}
+
+	traffic := c.Traffic(model.TunnelKindNAT, 3)
+	if traffic == nil || traffic.In != 1500 || traffic.Out != 600 || traffic.Connections != 1 {
+		t.Fatalf("unexpected traffic: %+v", traffic)
+	}
+}